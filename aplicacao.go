@@ -0,0 +1,355 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"gorm.io/gorm"
+
+	"tvtec/config"
+	"tvtec/controller"
+	"tvtec/docs"
+	"tvtec/erros"
+	"tvtec/middleware"
+	"tvtec/repository"
+	"tvtec/service"
+)
+
+// aplicacao reúne o roteador e os laços de segundo plano montados a partir da configuração
+type aplicacao struct {
+	router         *gin.Engine
+	cursoRepo      repository.CursoRepository
+	despachante    service.DespachanteEventos
+	webhookService service.WebhookService
+	saudeService   service.SaudeService
+}
+
+// novaAplicacao instancia repositórios, serviços e controllers sobre o banco informado e registra
+// todas as rotas. Não inicia laços nem servidores, o que permite usá-la nos testes de ponta a ponta.
+func novaAplicacao(cfg *config.Config, db *gorm.DB) (*aplicacao, error) {
+	// Inicializar configurações de autenticação
+	middleware.InitAuthConfig(cfg.Admin.Usuario, cfg.Admin.Senha, cfg.ChaveJWT)
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao acessar o pool de conexões: %w", err)
+	}
+
+	// Cache das rotas públicas de cursos, descartado a cada escrita confirmada em cursos, inscrições e catálogo
+	cacheCursos := middleware.NewCacheRespostas(1000)
+	if err := repository.ObservarAlteracoesCatalogo(db, cacheCursos.Invalidar); err != nil {
+		return nil, fmt.Errorf("erro ao registrar a invalidação do cache de cursos: %w", err)
+	}
+
+	// Instancia os repositórios
+	alunoRepo := repository.NewAlunoRepository(db)
+	cursoRepo := repository.NewCursoRepository(db)
+	categoriaRepo := repository.NewCategoriaRepository(db)
+	localRepo := repository.NewLocalRepository(db)
+	inscricaoRepo := repository.NewInscricaoRepository(db)
+	solicitacaoLGPDRepo := repository.NewSolicitacaoLGPDRepository(db)
+	consentimentoRepo := repository.NewConsentimentoRepository(db)
+	professorRepo := repository.NewProfessorRepository(db)
+	presencaRepo := repository.NewPresencaRepository(db)
+	duplicidadeRepo := repository.NewDuplicidadeRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	transacao := repository.NewTransacao(db)
+
+	// Notificações por email (apenas log quando SMTP_HOST não está definido)
+	notificador := service.NewNotificador(service.ConfigSMTP{
+		Host:      cfg.SMTP.Host,
+		Porta:     cfg.SMTP.Porta,
+		Usuario:   cfg.SMTP.Usuario,
+		Senha:     cfg.SMTP.Senha,
+		Remetente: cfg.SMTP.Remetente,
+	})
+
+	// Limites de inscrições simultâneas por aluno (0 = sem limite global)
+	limitesInscricao := service.LimitesInscricao{
+		MaximoAtivas:            cfg.Inscricoes.LimiteAtivas,
+		BloquearConflitoHorario: cfg.Inscricoes.BloquearConflitoHorario,
+	}
+
+	// Instancia os serviços, injetando os repositórios necessários
+	// Os eventos de inscrições e cursos são gravados na outbox junto com a alteração
+	// e despachados em segundo plano para os assinantes registrados abaixo
+	despachante := service.NewDespachanteEventos(outboxRepo)
+	webhookService := service.NewWebhookService(webhookRepo)
+	acessibilidadeService := service.NewAcessibilidadeService(cursoRepo, inscricaoRepo, notificador,
+		cfg.EmailCoordenacao, cfg.DiasAlertaAcessibilidade)
+	consentimentoService := service.NewConsentimentoService(consentimentoRepo, cfg.ChaveJWT, cfg.URLPublica)
+	cursoService := service.NewCursoService(cursoRepo, inscricaoRepo, categoriaRepo, localRepo, professorRepo, notificador, transacao, despachante)
+	categoriaService := service.NewCategoriaService(categoriaRepo)
+	localService := service.NewLocalService(localRepo, cursoRepo)
+	elegibilidadeService := service.NewElegibilidadeService(cursoRepo, alunoRepo, inscricaoRepo)
+	alunoService := service.NewAlunoService(alunoRepo, cursoRepo, inscricaoRepo, duplicidadeRepo, transacao, despachante, limitesInscricao)
+	inscricaoService := service.NewInscricaoService(inscricaoRepo, cursoRepo, alunoRepo, transacao, despachante, limitesInscricao)
+	lgpdService := service.NewLGPDService(alunoRepo, inscricaoRepo, solicitacaoLGPDRepo, duplicidadeRepo, consentimentoService, transacao, notificador, cfg.ChaveJWT)
+	professorService := service.NewProfessorService(professorRepo, cursoRepo)
+	presencaService := service.NewPresencaService(presencaRepo, cursoRepo, inscricaoRepo)
+	calendarioService := service.NewCalendarioService(cursoRepo, inscricaoRepo, alunoRepo, notificador, cfg.URLPublica)
+	importacaoService := service.NewImportacaoService(alunoService, cursoRepo)
+	duplicidadeService := service.NewDuplicidadeService(alunoRepo, duplicidadeRepo, transacao, despachante)
+
+	despachante.Assinar(service.AssinanteAuditoria())
+	despachante.Assinar(service.AssinanteAcessibilidade(acessibilidadeService, cursoRepo, inscricaoRepo))
+	despachante.Assinar(service.AssinanteCancelamentoCurso(cursoService, cursoRepo, alunoRepo))
+	despachante.Assinar(service.AssinanteWebhooks(webhookService))
+
+	saudeService := service.NewSaudeService(sqlDB,
+		service.TrabalhoMonitorado{Nome: "despachante_eventos", Trabalho: despachante},
+		service.TrabalhoMonitorado{Nome: "entregas_webhooks", Trabalho: webhookService},
+	)
+	if err := consentimentoService.GarantirTermoInicial(); err != nil {
+		return nil, fmt.Errorf("erro ao publicar termos de consentimento iniciais: %w", err)
+	}
+	//powerBIService := service.NewPowerBIService() // Novo serviço Power BI
+
+	// Instancia os controllers
+	alunoController := controller.NewAlunoController(alunoService, consentimentoService)
+	cursoController := controller.NewCursoController(cursoService)
+	categoriaController := controller.NewCategoriaController(categoriaService)
+	localController := controller.NewLocalController(localService)
+	acessibilidadeController := controller.NewAcessibilidadeController(acessibilidadeService)
+	elegibilidadeController := controller.NewElegibilidadeController(elegibilidadeService)
+	authController := controller.NewAuthController(professorService)
+	inscricaoController := controller.NewInscricaoController(inscricaoService)
+	lgpdController := controller.NewLGPDController(lgpdService)
+	consentimentoController := controller.NewConsentimentoController(consentimentoService, lgpdService)
+	professorController := controller.NewProfessorController(professorService)
+	areaProfessorController := controller.NewAreaProfessorController(presencaService)
+	calendarioController := controller.NewCalendarioController(calendarioService, lgpdService)
+	importacaoController := controller.NewImportacaoController(importacaoService)
+	duplicidadeController := controller.NewDuplicidadeController(duplicidadeService)
+	webhookController := controller.NewWebhookController(webhookService)
+	saudeController := controller.NewSaudeController(saudeService)
+	//powerBIController := controller.NewPowerBIController(powerBIService) // Novo controller Power BI
+
+	// Especificação OpenAPI usada na documentação e na validação das requisições
+	especificacao, err := docs.Carregar()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao carregar especificação OpenAPI: %w", err)
+	}
+
+	// Inicializa o roteador Gin no modo configurado
+	gin.SetMode(cfg.Modo)
+	router := gin.New()
+
+	// Identificador da requisição, devolvido em X-Request-ID e nas respostas de erro
+	router.Use(middleware.IDRequisicao())
+
+	// Idioma das mensagens (pt-BR, es ou en) escolhido pelo Accept-Language
+	router.Use(middleware.Idioma())
+
+	// Middleware personalizado para evitar redirecionamentos
+	router.Use(noRedirectMiddleware())
+
+	// Contagem e tempo das requisições por rota, expostos em /metrics
+	router.Use(middleware.MetricasHTTP())
+
+	// Middleware de log de requisições (para debugging)
+	router.Use(requestLoggerMiddleware())
+
+	// Configuração CORS
+	corsConfig := cors.DefaultConfig()
+	corsConfig.AllowAllOrigins = true
+	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
+	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "Accept-Language", "If-None-Match", "If-Modified-Since"}
+	corsConfig.ExposeHeaders = []string{"Content-Length", "ETag", "Last-Modified", middleware.CabecalhoIDRequisicao}
+	corsConfig.AllowCredentials = true
+	router.Use(cors.New(corsConfig))
+
+	// Adiciona middleware de recuperação; um panic vira erro interno no formato padrão
+	router.Use(gin.CustomRecovery(func(c *gin.Context, recuperado interface{}) {
+		middleware.ResponderErro(c, erros.ErrInterno.Envolver(fmt.Errorf("panic: %v", recuperado)))
+	}))
+
+	log.Println("Configuração CORS aplicada. Todos os origens permitidas.")
+
+	// Requisições fora da especificação são recusadas; no modo de teste as respostas também são conferidas
+	router.Use(middleware.ValidacaoOpenAPI(especificacao.Rotas, gin.Mode() == gin.TestMode))
+
+	// Os controllers registram os erros com c.Error; este middleware escreve a resposta {code, message, details, requestId}
+	router.Use(middleware.TratarErros())
+
+	// Documentação da API
+	router.GET("/openapi.json", especificacao.ServirJSON)
+	router.GET("/docs", especificacao.ServirPagina)
+
+	// Verificações de saúde: /health e /health/live indicam que o processo responde;
+	// /health/ready também confere o banco e os laços de segundo plano
+	router.GET("/health", saudeController.Vivo)
+	router.GET("/health/live", saudeController.Vivo)
+	router.GET("/health/ready", saudeController.Pronto)
+
+	// Métricas do Prometheus nesta porta com token (METRICS_TOKEN); com METRICS_ADDR o main as serve numa porta interna
+	if cfg.Metricas.Endereco == "" && cfg.Metricas.Token != "" {
+		router.GET("/metrics", middleware.TokenMetricasMiddleware(cfg.Metricas.Token), gin.WrapH(promhttp.Handler()))
+	}
+
+	// Rotas de autenticação
+	auth := router.Group("/auth")
+	{
+		auth.POST("/login", authController.Login)
+		auth.GET("/validate", middleware.AuthMiddleware(), authController.ValidateToken)
+	}
+
+	// Rotas públicas (sem autenticação)
+	// Rotas para Curso (apenas visualização); as respostas ficam em cache até a próxima alteração
+	// em cursos ou inscrições, e as vagas têm validade curta porque mudam a cada inscrição
+	router.GET("/curso", middleware.CacheHTTP(cacheCursos, 30*time.Second), cursoController.ListarCursos)
+	router.GET("/curso/:id", middleware.CacheHTTP(cacheCursos, time.Minute), cursoController.ObterCursoPorID)
+	router.GET("/curso/:id/vagas", middleware.CacheHTTP(cacheCursos, 5*time.Second), cursoController.VerificarDisponibilidadeVagas)
+	router.GET("/curso/:id/calendar.ics", calendarioController.CalendarioCurso)
+	router.GET("/curso/calendar.ics", calendarioController.CalendarioPublico)
+	router.GET("/categoria", categoriaController.ListarCategorias)
+	router.GET("/local", localController.ListarLocais)
+	router.GET("/local/:id", localController.ObterLocalPorID)
+	router.GET("/professor/:id", professorController.PerfilPublico)
+
+	// Rotas para inscrição de alunos (acessível sem autenticação)
+	router.POST("/aluno/inscricao", alunoController.CadastrarAlunoEInscrever)
+
+	// Rotas de autoatendimento LGPD (o titular se identifica por CPF, email e data de nascimento)
+	router.POST("/aluno/lgpd/exportacao", lgpdController.ExportarMeusDados)
+	router.POST("/aluno/lgpd/eliminacao", lgpdController.EliminarMeusDados)
+
+	// Rotas de consentimento (termos vigentes, autoatendimento e link de descadastro)
+	router.GET("/consentimento/termos", consentimentoController.ObterTermoVigente)
+	router.GET("/consentimento/descadastro", consentimentoController.Descadastrar)
+	router.POST("/aluno/consentimentos", consentimentoController.AlterarMeuConsentimento)
+
+	// Calendário privado do aluno (link com token) e solicitação do link pelo próprio aluno
+	router.GET("/aluno/:id/calendar.ics", calendarioController.CalendarioAluno)
+	router.POST("/aluno/calendario", calendarioController.MeuLinkCalendario)
+
+	//// Rotas para o Power BI (requerem autenticação)
+	//powerbi := router.Group("/powerbi")
+	//powerbi.Use(middleware.AuthMiddleware())
+	//{
+	//	powerbi.GET("/token", powerBIController.GetEmbedToken)
+	//
+	//	// Rota de teste para verificar se o endpoint está funcionando
+	//	powerbi.GET("/teste", func(c *gin.Context) {
+	//		c.JSON(http.StatusOK, gin.H{
+	//			"status":    "ok",
+	//			"message":   "Endpoint do PowerBI funcionando",
+	//			"timestamp": time.Now().Format(time.RFC3339),
+	//		})
+	//	})
+	//}
+
+	// Área do professor (apenas os próprios cursos)
+	areaProfessor := router.Group("/professor-area")
+	areaProfessor.Use(middleware.ProfessorAuthMiddleware())
+	{
+		areaProfessor.GET("/cursos", areaProfessorController.MeusCursos)
+		areaProfessor.GET("/curso/:id/turma", areaProfessorController.Turma)
+		areaProfessor.GET("/curso/:id/presencas", areaProfessorController.ListarPresencas)
+		areaProfessor.POST("/curso/:id/presencas", areaProfessorController.RegistrarPresencas)
+	}
+
+	// Rotas protegidas (requerem autenticação de admin)
+	admin := router.Group("/admin")
+	admin.Use(middleware.AdminAuthMiddleware())
+	{
+		// Administração de Cursos
+		admin.GET("/curso", cursoController.ListarCursosAdmin)
+		admin.GET("/curso/:id", cursoController.ObterCursoAdmin)
+		admin.POST("/curso", cursoController.CriarCurso)
+		admin.PUT("/curso/:id", cursoController.AtualizarCurso)
+		admin.DELETE("/curso/:id", cursoController.RemoverCurso)
+		admin.PUT("/curso/:id/status", cursoController.MudarStatus)
+		admin.POST("/curso/:id/cancelar", cursoController.CancelarCurso)
+		admin.GET("/curso/:id/inscricoes", cursoController.ListarInscricoesCurso)
+		admin.GET("/curso/:id/avisos-acessibilidade", cursoController.AvisosAcessibilidade)
+		admin.GET("/curso/:id/acessibilidade", acessibilidadeController.RelatorioCurso)
+		admin.GET("/curso/:id/elegibilidade", elegibilidadeController.ListarRegras)
+		admin.PUT("/curso/:id/elegibilidade", elegibilidadeController.DefinirRegras)
+		admin.POST("/curso/:id/elegibilidade/previa", elegibilidadeController.Previa)
+
+		// Administração de Categorias
+		admin.POST("/categoria", categoriaController.CriarCategoria)
+		admin.PUT("/categoria/:id", categoriaController.AtualizarCategoria)
+		admin.DELETE("/categoria/:id", categoriaController.RemoverCategoria)
+
+		// Administração de Professores
+		admin.GET("/professor", professorController.ListarProfessores)
+		admin.GET("/professor/:id", professorController.ObterProfessorPorID)
+		admin.POST("/professor", professorController.CriarProfessor)
+		admin.PUT("/professor/:id", professorController.AtualizarProfessor)
+		admin.DELETE("/professor/:id", professorController.RemoverProfessor)
+		admin.PUT("/professor/:id/senha", professorController.DefinirSenha)
+
+		// Administração de Locais e Salas
+		admin.POST("/local", localController.CriarLocal)
+		admin.PUT("/local/:id", localController.AtualizarLocal)
+		admin.DELETE("/local/:id", localController.RemoverLocal)
+		admin.POST("/local/:id/sala", localController.CriarSala)
+		admin.PUT("/local/:id/sala/:salaId", localController.AtualizarSala)
+		admin.DELETE("/local/:id/sala/:salaId", localController.RemoverSala)
+
+		// Administração de Alunos
+		admin.GET("/aluno", alunoController.ListarAlunos)
+		admin.GET("/aluno/:id", alunoController.ObterAlunoPorID)
+		admin.PUT("/aluno/:id", alunoController.AtualizarAluno)
+		admin.DELETE("/aluno/:id", alunoController.RemoverAluno)
+		admin.POST("/aluno/:id/curso/:cursoId", alunoController.AdicionarAlunoCurso)
+		admin.GET("/aluno/:id/inscricoes", alunoController.ListarInscricoesAluno)
+		admin.GET("/aluno/:id/calendario", calendarioController.LinkCalendarioAluno)
+		admin.DELETE("/aluno/:id/calendario", calendarioController.RevogarLinkCalendario)
+
+		// Cadastros duplicados e divergentes
+		admin.GET("/aluno/duplicados", duplicidadeController.ListarCandidatos)
+		admin.POST("/aluno/:id/mesclar", duplicidadeController.MesclarAlunos)
+		admin.GET("/aluno/fusoes", duplicidadeController.ListarFusoes)
+		admin.GET("/aluno/conflitos", duplicidadeController.ListarConflitos)
+		admin.PUT("/aluno/conflitos/:id/resolver", duplicidadeController.ResolverConflito)
+
+		// NOVAS ROTAS: Administração de Inscrições
+		admin.GET("/inscricoes", inscricaoController.ListarInscricoes)
+		admin.GET("/inscricoes/:id", inscricaoController.ObterInscricaoPorID)
+		admin.POST("/relatorio", inscricaoController.GerarRelatorio)
+		admin.DELETE("inscricoes/:id", inscricaoController.CancelarInscricao)
+		admin.PUT("/inscricoes/:id/concluir", inscricaoController.ConcluirInscricao)
+
+		// Direitos do titular (LGPD)
+		admin.GET("/aluno/:id/lgpd/exportacao", lgpdController.ExportarDadosAluno)
+		admin.POST("/aluno/:id/lgpd/eliminacao", lgpdController.EliminarDadosAluno)
+		admin.GET("/lgpd/solicitacoes", lgpdController.ListarSolicitacoes)
+
+		// Consentimentos
+		admin.GET("/aluno/:id/consentimentos", consentimentoController.ListarConsentimentosAluno)
+		admin.POST("/consentimento/termos", consentimentoController.PublicarTermo)
+
+		// Importação de alunos por planilha
+		admin.POST("/import", importacaoController.ImportarAlunos)
+
+		// Webhooks de sistemas parceiros
+		admin.GET("/webhooks", webhookController.ListarWebhooks)
+		admin.GET("/webhooks/:id", webhookController.ObterWebhook)
+		admin.POST("/webhooks", webhookController.CriarWebhook)
+		admin.PUT("/webhooks/:id", webhookController.AtualizarWebhook)
+		admin.DELETE("/webhooks/:id", webhookController.RemoverWebhook)
+		admin.GET("/webhooks/:id/entregas", webhookController.ListarEntregas)
+		admin.POST("/webhooks/:id/teste", webhookController.EnviarTeste)
+	}
+
+	// Toda rota registrada precisa estar descrita em docs/openapi.yaml
+	for _, rota := range especificacao.RotasNaoDocumentadas(router.Routes()) {
+		log.Printf("AVISO: rota sem documentação OpenAPI: %s", rota)
+	}
+
+	return &aplicacao{
+		router:         router,
+		cursoRepo:      cursoRepo,
+		despachante:    despachante,
+		webhookService: webhookService,
+		saudeService:   saudeService,
+	}, nil
+}
@@ -0,0 +1,554 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"tvtec/config"
+	"tvtec/repository"
+	"tvtec/service"
+)
+
+const (
+	senhaAdminTeste   = "senha-do-admin"
+	tokenMetricaTeste = "token-das-metricas"
+)
+
+// clienteTeste envia requisições ao roteador completo e anota as rotas exercitadas
+type clienteTeste struct {
+	t        *testing.T
+	router   *gin.Engine
+	cobertas map[string]bool
+}
+
+// formulario é um corpo multipart/form-data já montado
+type formulario struct {
+	tipo  string
+	dados []byte
+}
+
+type resposta struct {
+	*httptest.ResponseRecorder
+	t *testing.T
+}
+
+// decodificar lê o corpo JSON da resposta
+func (r resposta) decodificar(destino interface{}) {
+	r.t.Helper()
+	if err := json.Unmarshal(r.Body.Bytes(), destino); err != nil {
+		r.t.Fatalf("corpo não é o JSON esperado: %v\n%s", err, r.Body.String())
+	}
+}
+
+// chamar requisita a rota (no formato do Gin, com consulta opcional após "?"), preenchendo os
+// parâmetros na ordem em que aparecem, e confere o status. O corpo pode ser nil, texto JSON,
+// um formulario ou qualquer valor convertido para JSON.
+func (c *clienteTeste) chamar(token, metodo, rota string, corpo interface{}, esperado int, parametros ...interface{}) resposta {
+	c.t.Helper()
+	padrao, consulta, _ := strings.Cut(rota, "?")
+	c.cobertas[metodo+" "+padrao] = true
+
+	segmentos := strings.Split(padrao, "/")
+	for i, segmento := range segmentos {
+		if strings.HasPrefix(segmento, ":") {
+			if len(parametros) == 0 {
+				c.t.Fatalf("%s %s: faltou o valor de %s", metodo, rota, segmento)
+			}
+			segmentos[i] = fmt.Sprint(parametros[0])
+			parametros = parametros[1:]
+		}
+	}
+	caminho := strings.Join(segmentos, "/")
+	if consulta != "" {
+		caminho += "?" + consulta
+	}
+
+	var leitor *bytes.Reader
+	tipo := "application/json"
+	switch valor := corpo.(type) {
+	case nil:
+		leitor = bytes.NewReader(nil)
+	case string:
+		leitor = bytes.NewReader([]byte(valor))
+	case formulario:
+		leitor = bytes.NewReader(valor.dados)
+		tipo = valor.tipo
+	default:
+		dados, err := json.Marshal(valor)
+		if err != nil {
+			c.t.Fatal(err)
+		}
+		leitor = bytes.NewReader(dados)
+	}
+
+	req := httptest.NewRequest(metodo, caminho, leitor)
+	if corpo != nil {
+		req.Header.Set("Content-Type", tipo)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	c.router.ServeHTTP(w, req)
+	if w.Code != esperado {
+		c.t.Fatalf("%s %s: esperava %d, recebeu %d: %s", metodo, caminho, esperado, w.Code, w.Body.String())
+	}
+	return resposta{ResponseRecorder: w, t: c.t}
+}
+
+// verificarCobertura falha se alguma rota registrada não foi exercitada
+func (c *clienteTeste) verificarCobertura() {
+	c.t.Helper()
+	var faltantes []string
+	for _, rota := range c.router.Routes() {
+		if !c.cobertas[rota.Method+" "+rota.Path] {
+			faltantes = append(faltantes, rota.Method+" "+rota.Path)
+		}
+	}
+	sort.Strings(faltantes)
+	if len(faltantes) > 0 {
+		c.t.Errorf("rotas sem teste de ponta a ponta:\n  %s", strings.Join(faltantes, "\n  "))
+	}
+}
+
+// emailsCapturados guarda as mensagens em vez de enviá-las
+type emailsCapturados struct {
+	mensagens []string
+}
+
+func (e *emailsCapturados) Enviar(_, _, mensagem string) error {
+	e.mensagens = append(e.mensagens, mensagem)
+	return nil
+}
+
+// codigoTitular emite um novo código de confirmação LGPD, que substitui o enviado pela API, e o lê
+// do email que o titular receberia
+func codigoTitular(t *testing.T, db *gorm.DB, alunoID uint, tipo, formato string) string {
+	t.Helper()
+	emails := &emailsCapturados{}
+	segredo := configuracaoTeste(t).ChaveJWT
+	lgpd := service.NewLGPDService(repository.NewAlunoRepository(db), repository.NewInscricaoRepository(db),
+		repository.NewSolicitacaoLGPDRepository(db), repository.NewDuplicidadeRepository(db),
+		service.NewConsentimentoService(repository.NewConsentimentoRepository(db), segredo, ""), repository.NewTransacao(db), emails, segredo)
+	if err := lgpd.SolicitarConfirmacao(alunoID, tipo, formato, "127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	codigo := regexp.MustCompile(`\b\d{6}\b`).FindString(strings.Join(emails.mensagens, "\n"))
+	if codigo == "" {
+		t.Fatalf("o email não trouxe o código de confirmação: %q", emails.mensagens)
+	}
+	return codigo
+}
+
+func comCodigo(titular map[string]string, codigo string) map[string]string {
+	pedido := map[string]string{"codigo": codigo}
+	for campo, valor := range titular {
+		pedido[campo] = valor
+	}
+	return pedido
+}
+
+func configuracaoTeste(t *testing.T) *config.Config {
+	return &config.Config{
+		Modo:        gin.TestMode,
+		DriverBanco: config.DriverSQLite,
+		BancoURL:    filepath.Join(t.TempDir(), "tvtec.db"),
+		URLPublica:  "http://tvtec.test",
+		Admin:       config.Admin{Usuario: "admin", Senha: senhaAdminTeste},
+		ChaveJWT:    strings.Repeat("k", config.TamanhoMinimoChaveJWT),
+		// Sem SMTP_HOST as mensagens só vão para o log
+		EmailCoordenacao:         "coordenacao@example.com",
+		DiasAlertaAcessibilidade: 7,
+		Inscricoes:               config.Inscricoes{LimiteAtivas: 5, BloquearConflitoHorario: true},
+		Metricas:                 config.Metricas{Token: tokenMetricaTeste},
+	}
+}
+
+// aplicacaoTeste monta a API sobre um SQLite temporário, com os laços de segundo plano
+// rodando como em produção
+func aplicacaoTeste(t *testing.T) (*clienteTeste, *gorm.DB) {
+	t.Helper()
+	cfg := configuracaoTeste(t)
+	db, err := repository.AbrirBanco(cfg.DriverBanco, cfg.BancoURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repository.Migrar(db); err != nil {
+		t.Fatal(err)
+	}
+	app, err := novaAplicacao(cfg, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contexto, parar := context.WithCancel(context.Background())
+	var trabalhos sync.WaitGroup
+	trabalhos.Add(2)
+	go func() {
+		defer trabalhos.Done()
+		app.despachante.Executar(contexto, time.Minute)
+	}()
+	go func() {
+		defer trabalhos.Done()
+		app.webhookService.Executar(contexto, time.Minute)
+	}()
+	t.Cleanup(func() {
+		parar()
+		trabalhos.Wait()
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	// Espera o primeiro ciclo dos laços para a prontidão refletir a aplicação em funcionamento
+	for limite := time.Now().Add(2 * time.Second); !app.saudeService.Prontidao(contexto).Pronto; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(limite) {
+			t.Fatal("a aplicação não ficou pronta")
+		}
+	}
+
+	return &clienteTeste{t: t, router: app.router, cobertas: map[string]bool{}}, db
+}
+
+// gerarCPF completa os nove primeiros dígitos com os dígitos verificadores
+func gerarCPF(base string) string {
+	digitos := []byte(base)
+	for _, tamanho := range []int{9, 10} {
+		soma := 0
+		for i := 0; i < tamanho; i++ {
+			soma += int(digitos[i]-'0') * (tamanho + 1 - i)
+		}
+		resto := soma * 10 % 11
+		if resto == 10 {
+			resto = 0
+		}
+		digitos = append(digitos, byte('0'+resto))
+	}
+	d := string(digitos)
+	return d[0:3] + "." + d[3:6] + "." + d[6:9] + "-" + d[9:11]
+}
+
+func planilhaImportacao(t *testing.T, linhas string) formulario {
+	t.Helper()
+	var corpo bytes.Buffer
+	escritor := multipart.NewWriter(&corpo)
+	arquivo, err := escritor.CreateFormFile("arquivo", "alunos.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	arquivo.Write([]byte(linhas))
+	escritor.WriteField("modo", "efetivar")
+	escritor.Close()
+	return formulario{tipo: escritor.FormDataContentType(), dados: corpo.Bytes()}
+}
+
+type idResposta struct {
+	ID uint `json:"id"`
+}
+
+func TestTodasAsRotasDePontaAPonta(t *testing.T) {
+	api, db := aplicacaoTeste(t)
+	daqui := time.Now().In(time.Local).AddDate(0, 1, 0)
+	dataCurso := daqui.Format("02/01/2006")
+	semanaSeguinte := daqui.AddDate(0, 0, 7).Format("02/01/2006")
+
+	// Sistema
+	api.chamar("", http.MethodGet, "/health", nil, http.StatusOK)
+	api.chamar("", http.MethodGet, "/health/live", nil, http.StatusOK)
+	api.chamar("", http.MethodGet, "/health/ready", nil, http.StatusOK)
+	api.chamar("", http.MethodGet, "/openapi.json", nil, http.StatusOK)
+	api.chamar("", http.MethodGet, "/docs", nil, http.StatusOK)
+	api.chamar("", http.MethodGet, "/metrics", nil, http.StatusUnauthorized)
+	if corpo := api.chamar(tokenMetricaTeste, http.MethodGet, "/metrics", nil, http.StatusOK).Body.String(); !strings.Contains(corpo, "tvtec_http_requisicoes_total") {
+		t.Errorf("métricas HTTP ausentes em /metrics")
+	}
+
+	// Autenticação
+	api.chamar("", http.MethodPost, "/auth/login", map[string]string{"username": "admin", "password": "errada"}, http.StatusUnauthorized)
+	var login struct {
+		Token string `json:"token"`
+	}
+	api.chamar("", http.MethodPost, "/auth/login", map[string]string{"username": "admin", "password": senhaAdminTeste}, http.StatusOK).decodificar(&login)
+	admin := login.Token
+	api.chamar(admin, http.MethodGet, "/auth/validate", nil, http.StatusOK)
+	api.chamar("", http.MethodGet, "/admin/curso", nil, http.StatusUnauthorized)
+
+	// Catálogo: categorias, locais e salas
+	var categoria, categoriaRemovida, local, localRemovido, sala, salaRemovida idResposta
+	api.chamar(admin, http.MethodPost, "/admin/categoria", map[string]interface{}{"nome": "Tecnologia"}, http.StatusCreated).decodificar(&categoria)
+	api.chamar(admin, http.MethodPost, "/admin/categoria", map[string]interface{}{"nome": "Temporária"}, http.StatusCreated).decodificar(&categoriaRemovida)
+	api.chamar(admin, http.MethodPut, "/admin/categoria/:id", map[string]interface{}{"nome": "Tecnologia", "limiteInscricoesAtivas": 3}, http.StatusOK, categoria.ID)
+	api.chamar("", http.MethodGet, "/categoria", nil, http.StatusOK)
+
+	api.chamar(admin, http.MethodPost, "/admin/local", map[string]interface{}{"nome": "Sede", "endereco": "Rua A, 1", "bairro": "Centro", "temElevador": false, "banheiroAcessivel": true}, http.StatusCreated).decodificar(&local)
+	api.chamar(admin, http.MethodPost, "/admin/local", map[string]interface{}{"nome": "Anexo", "endereco": "Rua B, 2", "bairro": "Centro"}, http.StatusCreated).decodificar(&localRemovido)
+	api.chamar(admin, http.MethodPut, "/admin/local/:id", map[string]interface{}{"nome": "Sede", "endereco": "Rua A, 10", "bairro": "Centro", "banheiroAcessivel": true}, http.StatusOK, local.ID)
+	api.chamar(admin, http.MethodPost, "/admin/local/:id/sala", map[string]interface{}{"nome": "Laboratório", "andar": 2, "capacidade": 20}, http.StatusCreated, local.ID).decodificar(&sala)
+	api.chamar(admin, http.MethodPost, "/admin/local/:id/sala", map[string]interface{}{"nome": "Depósito", "andar": 0, "capacidade": 5}, http.StatusCreated, local.ID).decodificar(&salaRemovida)
+	api.chamar(admin, http.MethodPut, "/admin/local/:id/sala/:salaId", map[string]interface{}{"nome": "Laboratório 1", "andar": 2, "capacidade": 20}, http.StatusOK, local.ID, sala.ID)
+	api.chamar("", http.MethodGet, "/local", nil, http.StatusOK)
+	api.chamar("", http.MethodGet, "/local/:id", nil, http.StatusOK, local.ID)
+
+	// Professores
+	var professor, professorRemovido idResposta
+	api.chamar(admin, http.MethodPost, "/admin/professor", map[string]interface{}{"nome": "João Lima", "email": "joao@example.com", "telefone": "(21) 98888-0000", "ativo": true}, http.StatusCreated).decodificar(&professor)
+	api.chamar(admin, http.MethodPost, "/admin/professor", map[string]interface{}{"nome": "Ana Souza", "email": "ana@example.com", "ativo": true}, http.StatusCreated).decodificar(&professorRemovido)
+	api.chamar(admin, http.MethodGet, "/admin/professor", nil, http.StatusOK)
+	api.chamar(admin, http.MethodGet, "/admin/professor/:id", nil, http.StatusOK, professor.ID)
+	api.chamar(admin, http.MethodPut, "/admin/professor/:id", map[string]interface{}{"nome": "João Lima", "email": "joao@example.com", "bio": "Técnico em informática", "ativo": true}, http.StatusOK, professor.ID)
+	api.chamar(admin, http.MethodPut, "/admin/professor/:id/senha", map[string]string{"senha": "senha-do-professor"}, http.StatusOK, professor.ID)
+	api.chamar("", http.MethodPost, "/auth/login", map[string]string{"username": "joao@example.com", "password": "senha-do-professor"}, http.StatusOK).decodificar(&login)
+	docente := login.Token
+
+	// Cursos
+	var curso, rascunho, preRequisito idResposta
+	api.chamar(admin, http.MethodPost, "/admin/curso", map[string]interface{}{
+		"nome": "Fundamentos de informática", "professorId": professor.ID, "data": dataCurso,
+		"cargaHoraria": 20, "certificado": "Sim", "vagasTotais": 2, "horaInicio": "09:00", "horaFim": "12:00",
+	}, http.StatusCreated).decodificar(&preRequisito)
+	api.chamar(admin, http.MethodPost, "/admin/curso", map[string]interface{}{
+		"nome": "Informática básica", "professorId": professor.ID, "data": dataCurso,
+		"cargaHoraria": 40, "certificado": "Sim", "vagasTotais": 10, "horaInicio": "14:00", "horaFim": "17:00",
+		"categoriaId": categoria.ID, "salaId": sala.ID, "tags": []string{"Computação", "iniciante"},
+	}, http.StatusCreated).decodificar(&curso)
+	api.chamar(admin, http.MethodPost, "/admin/curso", map[string]interface{}{
+		"nome": "Excel avançado", "professor": "Convidado", "data": semanaSeguinte, "cargaHoraria": 8,
+		"certificado": "Não", "vagasTotais": 5, "status": "rascunho", "preRequisitos": []uint{preRequisito.ID},
+	}, http.StatusCreated).decodificar(&rascunho)
+	api.chamar(admin, http.MethodGet, "/admin/curso?ordenar=nome", nil, http.StatusOK)
+	api.chamar(admin, http.MethodGet, "/admin/curso/:id", nil, http.StatusOK, curso.ID)
+	api.chamar(admin, http.MethodPut, "/admin/curso/:id", map[string]interface{}{"vagasTotais": 12, "tags": []string{"computação", "básico"}}, http.StatusOK, curso.ID)
+	api.chamar(admin, http.MethodPut, "/admin/curso/:id/status", map[string]string{"status": "publicado"}, http.StatusOK, rascunho.ID)
+
+	var encontrados []idResposta
+	api.chamar("", http.MethodGet, "/curso?q=informatica%20basica&tag=computação&comVagas=true", nil, http.StatusOK).decodificar(&encontrados)
+	api.chamar("", http.MethodGet, "/curso?q=Básica", nil, http.StatusOK).decodificar(&encontrados)
+	if len(encontrados) != 1 || encontrados[0].ID != curso.ID {
+		t.Errorf("a busca textual deveria encontrar só o curso %d: %+v", curso.ID, encontrados)
+	}
+	api.chamar("", http.MethodGet, "/curso/:id", nil, http.StatusOK, curso.ID)
+	api.chamar("", http.MethodGet, "/curso/:id/vagas", nil, http.StatusOK, curso.ID)
+	api.chamar("", http.MethodGet, "/curso/calendar.ics", nil, http.StatusOK)
+	api.chamar("", http.MethodGet, "/curso/:id/calendar.ics", nil, http.StatusOK, curso.ID)
+	api.chamar("", http.MethodGet, "/professor/:id", nil, http.StatusOK, professor.ID)
+
+	// Regras de elegibilidade
+	regras := []map[string]interface{}{{"campo": "idade", "operador": "min", "valores": []string{"16"}}}
+	api.chamar(admin, http.MethodPut, "/admin/curso/:id/elegibilidade", regras, http.StatusOK, curso.ID)
+	api.chamar(admin, http.MethodGet, "/admin/curso/:id/elegibilidade", nil, http.StatusOK, curso.ID)
+
+	// Inscrições públicas: o mesmo CPF com outro email gera um conflito de cadastro
+	cpfMaria, cpfPedro, cpfMariaDuplicada := gerarCPF("529982247"), gerarCPF("111444777"), gerarCPF("935411347")
+	inscricao := func(nome, cpf, email, cursoID interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"nome": nome, "cpf": cpf, "email": email, "telefone": "(21) 99999-0000", "sexo": "F",
+			"dataNascto": "17/05/1990", "curso": cursoID, "bairro": "Centro", "escolaridade": "Médio completo",
+			"trabalhando": "N", "ehPCD": "S", "tipoPCD": "Física", "necessitaElevador": "S", "autorizaWhatsApp": "S",
+		}
+	}
+	var inscrita struct {
+		Aluno     idResposta `json:"aluno"`
+		Inscricao idResposta `json:"inscricao"`
+	}
+	api.chamar("", http.MethodPost, "/aluno/inscricao", inscricao("Maria da Silva", cpfMaria, "maria@example.com", curso.ID), http.StatusCreated).decodificar(&inscrita)
+	maria, inscricaoMaria := inscrita.Aluno.ID, inscrita.Inscricao.ID
+	api.chamar("", http.MethodPost, "/aluno/inscricao", inscricao("Maria da Silva", cpfMaria, "maria.silva@example.com", preRequisito.ID), http.StatusCreated)
+	api.chamar("", http.MethodPost, "/aluno/inscricao", inscricao("Pedro Santos", cpfPedro, "pedro@example.com", curso.ID), http.StatusCreated).decodificar(&inscrita)
+	pedro, inscricaoPedro := inscrita.Aluno.ID, inscrita.Inscricao.ID
+	api.chamar("", http.MethodPost, "/aluno/inscricao", inscricao("Maria da Silva", cpfMariaDuplicada, "maria2@example.com", preRequisito.ID), http.StatusCreated).decodificar(&inscrita)
+	mariaDuplicada := inscrita.Aluno.ID
+	// Inscrição repetida é recusada
+	api.chamar("", http.MethodPost, "/aluno/inscricao", inscricao("Pedro Santos", cpfPedro, "pedro@example.com", curso.ID), http.StatusConflict)
+	// As vagas consultadas antes das inscrições estavam em cache; as inscrições precisam descartá-lo
+	var vagas struct {
+		VagasDisponiveis int `json:"vagasDisponiveis"`
+	}
+	api.chamar("", http.MethodGet, "/curso/:id/vagas", nil, http.StatusOK, curso.ID).decodificar(&vagas)
+	if vagas.VagasDisponiveis != 10 {
+		t.Errorf("esperava 10 vagas depois de duas inscrições, recebeu %d", vagas.VagasDisponiveis)
+	}
+	api.chamar(admin, http.MethodPost, "/admin/curso/:id/elegibilidade/previa", nil, http.StatusOK, curso.ID)
+
+	// Administração de alunos
+	api.chamar(admin, http.MethodGet, "/admin/aluno", nil, http.StatusOK)
+	api.chamar(admin, http.MethodGet, "/admin/aluno/:id", nil, http.StatusOK, pedro)
+	api.chamar(admin, http.MethodPut, "/admin/aluno/:id", map[string]interface{}{
+		"nome": "Pedro Santos", "cpf": cpfPedro, "email": "pedro@example.com", "sexo": "M",
+		"telefone": "(21) 97777-0000", "dataNascto": "1995-03-10T00:00:00Z",
+	}, http.StatusOK, pedro)
+	api.chamar(admin, http.MethodPost, "/admin/aluno/:id/curso/:cursoId?ignorarPreRequisitos=true", map[string]string{"bairro": "Centro"}, http.StatusOK, pedro, rascunho.ID)
+	api.chamar(admin, http.MethodGet, "/admin/aluno/:id/inscricoes", nil, http.StatusOK, pedro)
+	api.chamar(admin, http.MethodGet, "/admin/curso/:id/inscricoes", nil, http.StatusOK, curso.ID)
+
+	// Calendário do aluno
+	var link struct {
+		URL string `json:"url"`
+	}
+	api.chamar(admin, http.MethodGet, "/admin/aluno/:id/calendario", nil, http.StatusOK, maria).decodificar(&link)
+	endereco, err := url.Parse(link.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	feed := "/aluno/:id/calendar.ics?token=" + url.QueryEscape(endereco.Query().Get("token"))
+	api.chamar("", http.MethodGet, feed, nil, http.StatusOK, maria)
+	api.chamar("", http.MethodGet, "/aluno/:id/calendar.ics?token=invalido", nil, http.StatusForbidden, maria)
+	// O pedido do próprio aluno envia um novo link por email e desativa o anterior
+	titularMaria := map[string]string{"cpf": cpfMaria, "email": "maria@example.com", "dataNascto": "17/05/1990"}
+	api.chamar("", http.MethodPost, "/aluno/calendario", titularMaria, http.StatusAccepted)
+	api.chamar("", http.MethodGet, feed, nil, http.StatusForbidden, maria)
+	api.chamar(admin, http.MethodDelete, "/admin/aluno/:id/calendario", nil, http.StatusOK, maria)
+	api.chamar(admin, http.MethodGet, "/admin/aluno/:id/calendario", nil, http.StatusOK, maria).decodificar(&link)
+	if endereco, err = url.Parse(link.URL); err != nil {
+		t.Fatal(err)
+	}
+	api.chamar("", http.MethodGet, "/aluno/:id/calendar.ics?token="+url.QueryEscape(endereco.Query().Get("token")), nil, http.StatusOK, maria)
+
+	// Conflitos e duplicidades de cadastro
+	var conflitos []idResposta
+	api.chamar(admin, http.MethodGet, "/admin/aluno/conflitos?situacao=pendente", nil, http.StatusOK).decodificar(&conflitos)
+	if len(conflitos) == 0 {
+		t.Fatal("a segunda inscrição de Maria deveria registrar um conflito de email")
+	}
+	api.chamar(admin, http.MethodPut, "/admin/aluno/conflitos/:id/resolver", map[string]bool{"aplicar": false}, http.StatusOK, conflitos[0].ID)
+	api.chamar(admin, http.MethodGet, "/admin/aluno/duplicados", nil, http.StatusOK)
+	api.chamar(admin, http.MethodPost, "/admin/aluno/:id/mesclar", map[string]interface{}{"alunoDuplicadoId": mariaDuplicada, "motivo": "mesma pessoa"}, http.StatusOK, maria)
+	api.chamar(admin, http.MethodGet, "/admin/aluno/fusoes", nil, http.StatusOK)
+
+	// Administração de inscrições
+	api.chamar(admin, http.MethodGet, "/admin/inscricoes", nil, http.StatusOK)
+	api.chamar(admin, http.MethodGet, "/admin/inscricoes/:id", nil, http.StatusOK, inscricaoMaria)
+	api.chamar(admin, http.MethodPost, "/admin/relatorio", []map[string]interface{}{{"curso": "Informática básica", "inscritos": 2}}, http.StatusOK)
+
+	// Importação de planilha
+	var relatorio struct {
+		Importadas int `json:"importadas"`
+	}
+	planilha := fmt.Sprintf("nome;cpf;email;dataNascto;curso\nCarla Dias;%s;carla@example.com;02/02/1992;%d\n", gerarCPF("390533447"), curso.ID)
+	api.chamar(admin, http.MethodPost, "/admin/import", planilhaImportacao(t, planilha), http.StatusOK).decodificar(&relatorio)
+	if relatorio.Importadas != 1 {
+		t.Errorf("esperava 1 linha importada: %+v", relatorio)
+	}
+
+	// Área do professor
+	api.chamar(docente, http.MethodGet, "/professor-area/cursos", nil, http.StatusOK)
+	api.chamar(docente, http.MethodGet, "/professor-area/curso/:id/turma", nil, http.StatusOK, curso.ID)
+	// A chamada é feita no dia da aula
+	hoje := time.Now().Format("02/01/2006")
+	api.chamar(admin, http.MethodPut, "/admin/curso/:id", map[string]interface{}{"data": hoje}, http.StatusOK, curso.ID)
+	api.chamar(admin, http.MethodPut, "/admin/curso/:id/status", map[string]string{"status": "em_andamento"}, http.StatusOK, curso.ID)
+	api.chamar(docente, http.MethodPost, "/professor-area/curso/:id/presencas", map[string]interface{}{
+		"data": hoje, "presencas": []map[string]interface{}{{"inscricaoId": inscricaoMaria, "presente": true}, {"inscricaoId": inscricaoPedro, "presente": false}},
+	}, http.StatusOK, curso.ID)
+	api.chamar(docente, http.MethodGet, "/professor-area/curso/:id/presencas", nil, http.StatusOK, curso.ID)
+	api.chamar(admin, http.MethodGet, "/professor-area/cursos", nil, http.StatusForbidden)
+
+	// Acessibilidade: a sala fica no 2º andar de um local sem elevador
+	var avisos struct {
+		Avisos []string `json:"avisos"`
+	}
+	api.chamar(admin, http.MethodGet, "/admin/curso/:id/avisos-acessibilidade", nil, http.StatusOK, curso.ID).decodificar(&avisos)
+	if len(avisos.Avisos) == 0 {
+		t.Errorf("esperava aviso de acessibilidade para a sala sem elevador")
+	}
+	api.chamar(admin, http.MethodGet, "/admin/curso/:id/acessibilidade", nil, http.StatusOK, curso.ID)
+
+	// Consentimentos
+	api.chamar("", http.MethodGet, "/consentimento/termos", nil, http.StatusOK)
+	api.chamar(admin, http.MethodPost, "/admin/consentimento/termos", map[string]string{"versao": "2.0", "texto": "Novos termos"}, http.StatusCreated)
+	alteracao := map[string]interface{}{
+		"cpf": cpfMaria, "email": "maria@example.com", "dataNascto": "17/05/1990",
+		"canal": "email", "finalidade": "divulgacao", "concedido": true, "versaoTermos": "2.0",
+	}
+	api.chamar("", http.MethodPost, "/aluno/consentimentos", alteracao, http.StatusAccepted)
+	alteracao["codigo"] = "000000"
+	api.chamar("", http.MethodPost, "/aluno/consentimentos", alteracao, http.StatusForbidden)
+	alteracao["codigo"] = codigoTitular(t, db, maria, "consentimento", "")
+	api.chamar("", http.MethodPost, "/aluno/consentimentos", alteracao, http.StatusOK)
+	consentimentos := service.NewConsentimentoService(repository.NewConsentimentoRepository(db), configuracaoTeste(t).ChaveJWT, "")
+	descadastro := fmt.Sprintf("/consentimento/descadastro?aluno=%d&canal=email&token=%s", maria, consentimentos.GerarTokenDescadastro(maria, "email"))
+	api.chamar("", http.MethodGet, descadastro, nil, http.StatusOK)
+	api.chamar(admin, http.MethodGet, "/admin/aluno/:id/consentimentos", nil, http.StatusOK, maria)
+
+	// Webhooks: a entrega de teste vai para um servidor local
+	parceiro := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-TVTEC-Assinatura") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer parceiro.Close()
+	var webhook idResposta
+	api.chamar(admin, http.MethodPost, "/admin/webhooks", map[string]interface{}{"url": parceiro.URL, "eventos": []string{"inscricao.criada"}}, http.StatusCreated).decodificar(&webhook)
+	api.chamar(admin, http.MethodGet, "/admin/webhooks", nil, http.StatusOK)
+	api.chamar(admin, http.MethodGet, "/admin/webhooks/:id", nil, http.StatusOK, webhook.ID)
+	api.chamar(admin, http.MethodPut, "/admin/webhooks/:id", map[string]interface{}{"url": parceiro.URL, "eventos": []string{"inscricao.criada", "curso.alterado"}, "descricao": "Parceiro"}, http.StatusOK, webhook.ID)
+	var entrega struct {
+		Situacao string `json:"situacao"`
+	}
+	api.chamar(admin, http.MethodPost, "/admin/webhooks/:id/teste", nil, http.StatusOK, webhook.ID).decodificar(&entrega)
+	if entrega.Situacao != "entregue" {
+		t.Errorf("a entrega de teste deveria ter sido aceita pelo parceiro: %+v", entrega)
+	}
+	api.chamar(admin, http.MethodGet, "/admin/webhooks/:id/entregas", nil, http.StatusOK, webhook.ID)
+
+	// LGPD: o titular confirma o pedido com o código recebido por email
+	api.chamar("", http.MethodPost, "/aluno/lgpd/exportacao", titularMaria, http.StatusAccepted)
+	api.chamar("", http.MethodPost, "/aluno/lgpd/exportacao", comCodigo(titularMaria, "000000"), http.StatusForbidden)
+	exportacao := comCodigo(titularMaria, codigoTitular(t, db, maria, "exportacao", "json"))
+	api.chamar("", http.MethodPost, "/aluno/lgpd/exportacao", exportacao, http.StatusOK)
+	// O código vale uma única vez
+	api.chamar("", http.MethodPost, "/aluno/lgpd/exportacao", exportacao, http.StatusForbidden)
+	api.chamar(admin, http.MethodGet, "/admin/aluno/:id/lgpd/exportacao", nil, http.StatusOK, maria)
+	api.chamar(admin, http.MethodGet, "/admin/lgpd/solicitacoes", nil, http.StatusOK)
+
+	// Conclusão, cancelamentos e remoções
+	api.chamar(admin, http.MethodPut, "/admin/inscricoes/:id/concluir", nil, http.StatusOK, inscricaoMaria)
+	api.chamar(admin, http.MethodDelete, "/admin/inscricoes/:id", nil, http.StatusOK, inscricaoPedro)
+	var cancelamento struct {
+		InscricoesCanceladas int `json:"inscricoesCanceladas"`
+	}
+	api.chamar(admin, http.MethodPost, "/admin/curso/:id/cancelar", map[string]string{"motivo": "Professor indisponível"}, http.StatusOK, rascunho.ID).decodificar(&cancelamento)
+	if cancelamento.InscricoesCanceladas != 1 {
+		t.Errorf("esperava 1 inscrição cancelada: %+v", cancelamento)
+	}
+	api.chamar("", http.MethodPost, "/aluno/lgpd/eliminacao", titularMaria, http.StatusAccepted)
+	api.chamar("", http.MethodPost, "/aluno/lgpd/eliminacao", comCodigo(titularMaria, codigoTitular(t, db, maria, "eliminacao", "")), http.StatusOK)
+	api.chamar(admin, http.MethodPost, "/admin/aluno/:id/lgpd/eliminacao", nil, http.StatusOK, pedro)
+	// O aluno só é removido depois de sair de todos os cursos
+	api.chamar(admin, http.MethodDelete, "/admin/aluno/:id", nil, http.StatusConflict, pedro)
+	var restantes []idResposta
+	api.chamar(admin, http.MethodGet, "/admin/aluno/:id/inscricoes", nil, http.StatusOK, pedro).decodificar(&restantes)
+	for _, restante := range restantes {
+		api.chamar(admin, http.MethodDelete, "/admin/inscricoes/:id", nil, http.StatusOK, restante.ID)
+	}
+	api.chamar(admin, http.MethodDelete, "/admin/aluno/:id", nil, http.StatusOK, pedro)
+	api.chamar(admin, http.MethodDelete, "/admin/webhooks/:id", nil, http.StatusOK, webhook.ID)
+	// Curso com inscrições não pode ser removido, apenas cancelado
+	api.chamar(admin, http.MethodDelete, "/admin/curso/:id", nil, http.StatusConflict, curso.ID)
+	var vazio idResposta
+	api.chamar(admin, http.MethodPost, "/admin/curso", map[string]interface{}{
+		"nome": "Curso sem inscritos", "professor": "Convidado", "data": dataCurso, "cargaHoraria": 4, "certificado": "Não", "vagasTotais": 5,
+	}, http.StatusCreated).decodificar(&vazio)
+	api.chamar(admin, http.MethodDelete, "/admin/curso/:id", nil, http.StatusOK, vazio.ID)
+	api.chamar(admin, http.MethodDelete, "/admin/local/:id/sala/:salaId", nil, http.StatusOK, local.ID, salaRemovida.ID)
+	api.chamar(admin, http.MethodDelete, "/admin/local/:id", nil, http.StatusOK, localRemovido.ID)
+	api.chamar(admin, http.MethodDelete, "/admin/categoria/:id", nil, http.StatusOK, categoriaRemovida.ID)
+	api.chamar(admin, http.MethodDelete, "/admin/professor/:id", nil, http.StatusOK, professorRemovido.ID)
+
+	api.verificarCobertura()
+}
@@ -0,0 +1,374 @@
+// Package config reúne toda a configuração da API, lida uma única vez na inicialização.
+//
+// Cada chave é procurada, nesta ordem, em: variável de ambiente; arquivo indicado por
+// <CHAVE>_FILE (segredos montados pelo Docker/Kubernetes); arquivo de configuração
+// (CONFIG_FILE, por padrão .env, no formato CHAVE=valor); valor padrão.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// Valores padrão que servem apenas para desenvolvimento; no modo release a API não inicia com eles
+const (
+	SenhaAdminPadrao = "admin123"
+	ChaveJWTPadrao   = "chave-secreta-padrao-mudar-em-producao"
+	// Tamanho mínimo da chave JWT no modo release
+	TamanhoMinimoChaveJWT = 32
+)
+
+// Bancos de dados suportados
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite" // arquivo local ou memória, para desenvolvimento e testes
+)
+
+// Modos de execução, os mesmos do Gin
+const (
+	ModoDebug   = "debug"
+	ModoRelease = "release"
+	ModoTeste   = "test"
+)
+
+type Admin struct {
+	Usuario string
+	Senha   string
+}
+
+type SMTP struct {
+	Host      string
+	Porta     string
+	Usuario   string
+	Senha     string
+	Remetente string
+}
+
+type Inscricoes struct {
+	// Máximo de inscrições ativas por aluno; 0 significa sem limite
+	LimiteAtivas            int
+	BloquearConflitoHorario bool
+}
+
+type Metricas struct {
+	Endereco string // porta interna exclusiva para o /metrics
+	Token    string // exigido no /metrics da porta principal quando não há Endereco
+}
+
+type HTTP struct {
+	TempoLeitura      time.Duration
+	TempoEscrita      time.Duration
+	TempoOcioso       time.Duration
+	TempoEncerramento time.Duration // prazo para terminar requisições e laços ao encerrar
+}
+
+// Config é a configuração completa da API
+type Config struct {
+	Modo                     string
+	Porta                    int
+	DriverBanco              string
+	BancoURL                 string // connection string do PostgreSQL ou caminho do arquivo SQLite
+	URLPublica               string
+	Admin                    Admin
+	ChaveJWT                 string
+	SMTP                     SMTP
+	EmailCoordenacao         string
+	DiasAlertaAcessibilidade int
+	Inscricoes               Inscricoes
+	Metricas                 Metricas
+	HTTP                     HTTP
+}
+
+// ErroConfiguracao lista todos os problemas encontrados, para corrigi-los de uma vez
+type ErroConfiguracao struct {
+	Problemas []string
+}
+
+func (e *ErroConfiguracao) Error() string {
+	return "configuração inválida:\n  - " + strings.Join(e.Problemas, "\n  - ")
+}
+
+// Carregar lê a configuração do ambiente e do arquivo de configuração e a valida
+func Carregar() (*Config, error) {
+	return carregar(os.LookupEnv)
+}
+
+func carregar(ambiente func(string) (string, bool)) (*Config, error) {
+	f := &fonte{ambiente: ambiente, arquivo: map[string]string{}}
+
+	caminho, explicito := ambiente("CONFIG_FILE")
+	if !explicito || caminho == "" {
+		caminho = ".env"
+	}
+	arquivo, err := godotenv.Read(caminho)
+	switch {
+	case err == nil:
+		f.arquivo = arquivo
+	case explicito || !errors.Is(err, os.ErrNotExist):
+		f.problema("CONFIG_FILE: não foi possível ler %s: %v", caminho, err)
+	}
+
+	cfg := &Config{
+		Modo:        f.texto("GIN_MODE", ModoDebug),
+		Porta:       f.inteiro("PORT", 8080),
+		DriverBanco: f.texto("DATABASE_DRIVER", DriverPostgres),
+		BancoURL:    f.texto("DATABASE_URL", ""),
+		URLPublica:  f.texto("PUBLIC_BASE_URL", ""),
+		Admin: Admin{
+			Usuario: f.texto("ADMIN_USERNAME", "admin"),
+			Senha:   f.texto("ADMIN_PASSWORD", SenhaAdminPadrao),
+		},
+		ChaveJWT: f.texto("JWT_SECRET_KEY", ChaveJWTPadrao),
+		SMTP: SMTP{
+			Host:      f.texto("SMTP_HOST", ""),
+			Porta:     f.texto("SMTP_PORT", ""),
+			Usuario:   f.texto("SMTP_USER", ""),
+			Senha:     f.texto("SMTP_PASSWORD", ""),
+			Remetente: f.texto("SMTP_FROM", ""),
+		},
+		EmailCoordenacao:         f.texto("EMAIL_COORDENACAO", ""),
+		DiasAlertaAcessibilidade: f.inteiro("ALERTA_ACESSIBILIDADE_DIAS", 7),
+		Inscricoes: Inscricoes{
+			LimiteAtivas:            f.inteiro("LIMITE_INSCRICOES_ATIVAS", 0),
+			BloquearConflitoHorario: f.booleano("BLOQUEAR_CONFLITO_HORARIO", true),
+		},
+		Metricas: Metricas{
+			Endereco: f.texto("METRICS_ADDR", ""),
+			Token:    f.texto("METRICS_TOKEN", ""),
+		},
+		HTTP: HTTP{
+			TempoLeitura:      f.duracao("HTTP_READ_TIMEOUT", 30*time.Second),
+			TempoEscrita:      f.duracao("HTTP_WRITE_TIMEOUT", 60*time.Second),
+			TempoOcioso:       f.duracao("HTTP_IDLE_TIMEOUT", 120*time.Second),
+			TempoEncerramento: f.duracao("SHUTDOWN_TIMEOUT", 30*time.Second),
+		},
+	}
+
+	f.problemas = append(f.problemas, cfg.validar()...)
+	if len(f.problemas) > 0 {
+		return nil, &ErroConfiguracao{Problemas: f.problemas}
+	}
+	return cfg, nil
+}
+
+// validar confere os valores já convertidos; os erros de conversão são apontados pela fonte
+func (c *Config) validar() []string {
+	var problemas []string
+	if c.Modo != ModoDebug && c.Modo != ModoRelease && c.Modo != ModoTeste {
+		problemas = append(problemas, fmt.Sprintf("GIN_MODE: use %s, %s ou %s (recebido %q)", ModoDebug, ModoRelease, ModoTeste, c.Modo))
+	}
+	if c.Porta < 1 || c.Porta > 65535 {
+		problemas = append(problemas, fmt.Sprintf("PORT: deve estar entre 1 e 65535 (recebido %d)", c.Porta))
+	}
+	if c.DriverBanco != DriverPostgres && c.DriverBanco != DriverSQLite {
+		problemas = append(problemas, fmt.Sprintf("DATABASE_DRIVER: use %s ou %s (recebido %q)", DriverPostgres, DriverSQLite, c.DriverBanco))
+	}
+	if c.BancoURL == "" {
+		problemas = append(problemas, "DATABASE_URL: obrigatória")
+	}
+	if c.URLPublica != "" {
+		if endereco, err := url.Parse(c.URLPublica); err != nil || (endereco.Scheme != "http" && endereco.Scheme != "https") || endereco.Host == "" {
+			problemas = append(problemas, "PUBLIC_BASE_URL: use um endereço http ou https completo")
+		}
+	}
+	if c.Admin.Usuario == "" {
+		problemas = append(problemas, "ADMIN_USERNAME: não pode ser vazio")
+	}
+	if c.SMTP.Host != "" {
+		if _, err := strconv.Atoi(c.SMTP.Porta); err != nil {
+			problemas = append(problemas, "SMTP_PORT: obrigatória e numérica quando SMTP_HOST está definido")
+		}
+		if c.SMTP.Remetente == "" {
+			problemas = append(problemas, "SMTP_FROM: obrigatório quando SMTP_HOST está definido")
+		}
+	}
+	if c.DiasAlertaAcessibilidade < 0 {
+		problemas = append(problemas, "ALERTA_ACESSIBILIDADE_DIAS: não pode ser negativo")
+	}
+	if c.Inscricoes.LimiteAtivas < 0 {
+		problemas = append(problemas, "LIMITE_INSCRICOES_ATIVAS: não pode ser negativo")
+	}
+	duracoes := []struct {
+		chave   string
+		duracao time.Duration
+	}{
+		{"HTTP_READ_TIMEOUT", c.HTTP.TempoLeitura},
+		{"HTTP_WRITE_TIMEOUT", c.HTTP.TempoEscrita},
+		{"HTTP_IDLE_TIMEOUT", c.HTTP.TempoOcioso},
+		{"SHUTDOWN_TIMEOUT", c.HTTP.TempoEncerramento},
+	}
+	for _, d := range duracoes {
+		if d.duracao <= 0 {
+			problemas = append(problemas, d.chave+": deve ser maior que zero")
+		}
+	}
+
+	// Em produção os segredos de desenvolvimento não são aceitos
+	if c.Modo == ModoRelease {
+		if c.Admin.Senha == SenhaAdminPadrao {
+			problemas = append(problemas, "ADMIN_PASSWORD: a senha padrão não pode ser usada no modo release")
+		}
+		if c.ChaveJWT == ChaveJWTPadrao {
+			problemas = append(problemas, "JWT_SECRET_KEY: a chave padrão não pode ser usada no modo release")
+		} else if len(c.ChaveJWT) < TamanhoMinimoChaveJWT {
+			problemas = append(problemas, fmt.Sprintf("JWT_SECRET_KEY: use ao menos %d caracteres no modo release", TamanhoMinimoChaveJWT))
+		}
+	}
+	return problemas
+}
+
+// String mostra a configuração com os segredos mascarados, para conferência nos logs
+func (c *Config) String() string {
+	linhas := []string{
+		"GIN_MODE=" + c.Modo,
+		"PORT=" + strconv.Itoa(c.Porta),
+		"DATABASE_DRIVER=" + c.DriverBanco,
+		"DATABASE_URL=" + mascararURLBanco(c.BancoURL),
+		"PUBLIC_BASE_URL=" + c.URLPublica,
+		"ADMIN_USERNAME=" + c.Admin.Usuario,
+		"ADMIN_PASSWORD=" + mascarar(c.Admin.Senha),
+		"JWT_SECRET_KEY=" + mascarar(c.ChaveJWT),
+		"SMTP_HOST=" + c.SMTP.Host,
+		"SMTP_PORT=" + c.SMTP.Porta,
+		"SMTP_USER=" + c.SMTP.Usuario,
+		"SMTP_PASSWORD=" + mascarar(c.SMTP.Senha),
+		"SMTP_FROM=" + c.SMTP.Remetente,
+		"EMAIL_COORDENACAO=" + c.EmailCoordenacao,
+		"ALERTA_ACESSIBILIDADE_DIAS=" + strconv.Itoa(c.DiasAlertaAcessibilidade),
+		"LIMITE_INSCRICOES_ATIVAS=" + strconv.Itoa(c.Inscricoes.LimiteAtivas),
+		"BLOQUEAR_CONFLITO_HORARIO=" + strconv.FormatBool(c.Inscricoes.BloquearConflitoHorario),
+		"METRICS_ADDR=" + c.Metricas.Endereco,
+		"METRICS_TOKEN=" + mascarar(c.Metricas.Token),
+		"HTTP_READ_TIMEOUT=" + c.HTTP.TempoLeitura.String(),
+		"HTTP_WRITE_TIMEOUT=" + c.HTTP.TempoEscrita.String(),
+		"HTTP_IDLE_TIMEOUT=" + c.HTTP.TempoOcioso.String(),
+		"SHUTDOWN_TIMEOUT=" + c.HTTP.TempoEncerramento.String(),
+	}
+	return strings.Join(linhas, "\n")
+}
+
+func mascarar(segredo string) string {
+	if segredo == "" {
+		return ""
+	}
+	return "xxxxx"
+}
+
+var (
+	senhaConsultaDSN = regexp.MustCompile(`([?&]password=)[^&]*`)
+	senhaDSN         = regexp.MustCompile(`(password\s*=\s*)('(?:[^'\\]|\\.)*'|\S+)`)
+)
+
+// mascararURLBanco esconde a senha tanto no formato URL quanto no formato chave=valor do
+// PostgreSQL. Não usa url.Parse: uma senha com # ou [ não é uma URL válida e acabaria
+// inteira no fragmento; por isso tudo entre :// e o último @ é tratado como credencial.
+func mascararURLBanco(dsn string) string {
+	inicio := strings.Index(dsn, "://")
+	if inicio < 0 {
+		return senhaDSN.ReplaceAllString(dsn, "${1}xxxxx")
+	}
+	inicio += len("://")
+	resto := dsn[inicio:]
+	if fim := strings.LastIndex(resto, "@"); fim >= 0 {
+		usuario, _, comSenha := strings.Cut(resto[:fim], ":")
+		if comSenha {
+			usuario += ":xxxxx"
+		}
+		dsn = dsn[:inicio] + usuario + resto[fim:]
+	}
+	return senhaConsultaDSN.ReplaceAllString(dsn, "${1}xxxxx")
+}
+
+// fonte procura os valores e acumula os problemas de conversão
+type fonte struct {
+	ambiente  func(string) (string, bool)
+	arquivo   map[string]string
+	problemas []string
+}
+
+func (f *fonte) problema(formato string, args ...interface{}) {
+	f.problemas = append(f.problemas, fmt.Sprintf(formato, args...))
+}
+
+func (f *fonte) bruto(chave string) (string, bool) {
+	if valor, ok := f.ambiente(chave); ok && valor != "" {
+		return valor, true
+	}
+	if valor, ok := f.arquivo[chave]; ok && valor != "" {
+		return valor, true
+	}
+	return "", false
+}
+
+// valor segue a ordem do pacote: ambiente, <CHAVE>_FILE e só então o arquivo de configuração
+func (f *fonte) valor(chave string) (string, bool) {
+	if valor, ok := f.ambiente(chave); ok && valor != "" {
+		return strings.TrimSpace(valor), true
+	}
+	if caminho, ok := f.bruto(chave + "_FILE"); ok {
+		conteudo, err := os.ReadFile(caminho)
+		if err != nil {
+			f.problema("%s_FILE: não foi possível ler %s: %v", chave, caminho, err)
+			return "", false
+		}
+		return strings.TrimSpace(string(conteudo)), true
+	}
+	if valor, ok := f.arquivo[chave]; ok && valor != "" {
+		return strings.TrimSpace(valor), true
+	}
+	return "", false
+}
+
+func (f *fonte) texto(chave, padrao string) string {
+	if valor, ok := f.valor(chave); ok {
+		return valor
+	}
+	return padrao
+}
+
+func (f *fonte) inteiro(chave string, padrao int) int {
+	valor, ok := f.valor(chave)
+	if !ok {
+		return padrao
+	}
+	numero, err := strconv.Atoi(valor)
+	if err != nil {
+		f.problema("%s: deve ser um número inteiro (recebido %q)", chave, valor)
+		return padrao
+	}
+	return numero
+}
+
+func (f *fonte) booleano(chave string, padrao bool) bool {
+	valor, ok := f.valor(chave)
+	if !ok {
+		return padrao
+	}
+	logico, err := strconv.ParseBool(valor)
+	if err != nil {
+		f.problema("%s: use true ou false (recebido %q)", chave, valor)
+		return padrao
+	}
+	return logico
+}
+
+func (f *fonte) duracao(chave string, padrao time.Duration) time.Duration {
+	valor, ok := f.valor(chave)
+	if !ok {
+		return padrao
+	}
+	tempo, err := time.ParseDuration(valor)
+	if err != nil {
+		f.problema("%s: use uma duração como 30s ou 2m (recebido %q)", chave, valor)
+		return padrao
+	}
+	return tempo
+}
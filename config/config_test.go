@@ -0,0 +1,135 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func ambienteDe(variaveis map[string]string) func(string) (string, bool) {
+	return func(chave string) (string, bool) {
+		valor, ok := variaveis[chave]
+		return valor, ok
+	}
+}
+
+func arquivoTemporario(t *testing.T, nome, conteudo string) string {
+	t.Helper()
+	caminho := filepath.Join(t.TempDir(), nome)
+	if err := os.WriteFile(caminho, []byte(conteudo), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return caminho
+}
+
+func TestCarregarCombinaAmbienteArquivoESegredos(t *testing.T) {
+	arquivo := arquivoTemporario(t, "app.env", "PORT=9090\nSMTP_HOST=smtp.example.com\nSMTP_PORT=587\nSMTP_FROM=cursos@example.com\n")
+	senha := arquivoTemporario(t, "senha", "segredo-do-arquivo\n")
+
+	cfg, err := carregar(ambienteDe(map[string]string{
+		"CONFIG_FILE":         arquivo,
+		"DATABASE_URL":        "postgres://tvtec:senha-do-banco@db:5432/tvtec",
+		"ADMIN_PASSWORD_FILE": senha,
+		"PORT":                "8081", // o ambiente prevalece sobre o arquivo
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Porta != 8081 || cfg.SMTP.Host != "smtp.example.com" || cfg.Admin.Senha != "segredo-do-arquivo" {
+		t.Errorf("configuração inesperada: %+v", cfg)
+	}
+	if !cfg.Inscricoes.BloquearConflitoHorario || cfg.DiasAlertaAcessibilidade != 7 {
+		t.Errorf("valores padrão não aplicados: %+v", cfg)
+	}
+
+	texto := cfg.String()
+	for _, segredo := range []string{"senha-do-banco", "segredo-do-arquivo", ChaveJWTPadrao} {
+		if strings.Contains(texto, segredo) {
+			t.Errorf("a configuração impressa expõe %q:\n%s", segredo, texto)
+		}
+	}
+	if !strings.Contains(texto, "DATABASE_URL=postgres://tvtec:xxxxx@db:5432/tvtec") {
+		t.Errorf("DATABASE_URL deveria mostrar o endereço sem a senha:\n%s", texto)
+	}
+}
+
+func TestCarregarListaTodosOsProblemas(t *testing.T) {
+	_, err := carregar(ambienteDe(map[string]string{
+		"CONFIG_FILE":               arquivoTemporario(t, "vazio.env", ""),
+		"PORT":                      "oitenta",
+		"BLOQUEAR_CONFLITO_HORARIO": "talvez",
+		"HTTP_WRITE_TIMEOUT":        "60",
+		"DATABASE_DRIVER":           "mysql",
+	}))
+	erro, ok := err.(*ErroConfiguracao)
+	if !ok {
+		t.Fatalf("esperava ErroConfiguracao, recebeu %v", err)
+	}
+	mensagem := erro.Error()
+	for _, chave := range []string{"PORT", "BLOQUEAR_CONFLITO_HORARIO", "HTTP_WRITE_TIMEOUT", "DATABASE_DRIVER", "DATABASE_URL"} {
+		if !strings.Contains(mensagem, chave) {
+			t.Errorf("faltou o problema de %s:\n%s", chave, mensagem)
+		}
+	}
+}
+
+func TestModoReleaseRecusaSegredosPadrao(t *testing.T) {
+	variaveis := map[string]string{
+		"CONFIG_FILE":  arquivoTemporario(t, "vazio.env", ""),
+		"GIN_MODE":     "release",
+		"DATABASE_URL": "host=db user=tvtec password=senha dbname=tvtec",
+	}
+	_, err := carregar(ambienteDe(variaveis))
+	if err == nil || !strings.Contains(err.Error(), "ADMIN_PASSWORD") || !strings.Contains(err.Error(), "JWT_SECRET_KEY") {
+		t.Fatalf("esperava recusa da senha e da chave padrão, recebeu %v", err)
+	}
+
+	variaveis["ADMIN_PASSWORD"] = "uma-senha-forte"
+	variaveis["JWT_SECRET_KEY"] = strings.Repeat("k", TamanhoMinimoChaveJWT)
+	cfg, err := carregar(ambienteDe(variaveis))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(cfg.String(), "password=senha") {
+		t.Errorf("a senha do DSN apareceu na configuração impressa:\n%s", cfg)
+	}
+}
+
+func TestArquivoDeSegredoPrevaleceSobreArquivoDeConfiguracao(t *testing.T) {
+	arquivo := arquivoTemporario(t, "app.env", "ADMIN_PASSWORD=senha-do-env\nJWT_SECRET_KEY=chave-do-env\n")
+	senha := arquivoTemporario(t, "senha", "senha-do-segredo\n")
+
+	cfg, err := carregar(ambienteDe(map[string]string{
+		"CONFIG_FILE":         arquivo,
+		"DATABASE_URL":        "postgres://tvtec@db/tvtec",
+		"ADMIN_PASSWORD_FILE": senha,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Admin.Senha != "senha-do-segredo" {
+		t.Errorf("ADMIN_PASSWORD_FILE deveria prevalecer sobre o arquivo de configuração, recebido %q", cfg.Admin.Senha)
+	}
+	if cfg.ChaveJWT != "chave-do-env" {
+		t.Errorf("sem _FILE o arquivo de configuração deveria ser usado, recebido %q", cfg.ChaveJWT)
+	}
+}
+
+func TestMascararURLBancoNaoDependeDeURLValida(t *testing.T) {
+	casos := []struct {
+		dsn, esperado string
+	}{
+		{"postgres://tvtec:a#b[c]d@e@db:5432/tvtec?sslmode=disable", "postgres://tvtec:xxxxx@db:5432/tvtec?sslmode=disable"},
+		{"postgresql://tvtec:s3nh@[::1]:5432/tvtec", "postgresql://tvtec:xxxxx@[::1]:5432/tvtec"},
+		{"postgres://db/tvtec?user=tvtec&password=a#b[c]&sslmode=disable", "postgres://db/tvtec?user=tvtec&password=xxxxx&sslmode=disable"},
+		{"postgres://tvtec@db/tvtec", "postgres://tvtec@db/tvtec"},
+		{"host=db user=tvtec password=a#b[c]@d dbname=tvtec", "host=db user=tvtec password=xxxxx dbname=tvtec"},
+		{"host=db password='a #b[c]' dbname=tvtec", "host=db password=xxxxx dbname=tvtec"},
+	}
+	for _, caso := range casos {
+		if mascarado := mascararURLBanco(caso.dsn); mascarado != caso.esperado {
+			t.Errorf("mascararURLBanco(%q) = %q, esperado %q", caso.dsn, mascarado, caso.esperado)
+		}
+	}
+}
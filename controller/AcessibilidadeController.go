@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"tvtec/erros"
+	"tvtec/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AcessibilidadeController interface {
+	RelatorioCurso(c *gin.Context)
+}
+
+type acessibilidadeController struct {
+	acessibilidadeService service.AcessibilidadeService
+}
+
+func NewAcessibilidadeController(acessibilidadeService service.AcessibilidadeService) AcessibilidadeController {
+	return &acessibilidadeController{acessibilidadeService: acessibilidadeService}
+}
+
+// RelatorioCurso retorna o checklist de acessibilidade do curso em JSON ou, com ?formato=pdf, em PDF
+func (ctrl *acessibilidadeController) RelatorioCurso(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	relatorio, err := ctrl.acessibilidadeService.GerarRelatorio(uint(id))
+	if err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	if c.Query("formato") == "pdf" {
+		conteudo, err := ctrl.acessibilidadeService.GerarPDF(relatorio)
+		if err != nil {
+			falhar(c, err, erros.ErrInterno.ComMensagem("Erro ao gerar PDF"))
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="acessibilidade-curso-%d.pdf"`, relatorio.CursoID))
+		c.Data(http.StatusOK, "application/pdf", conteudo)
+		return
+	}
+
+	c.JSON(http.StatusOK, relatorio)
+}
@@ -6,18 +6,23 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"tvtec/dto"
+	"tvtec/erros"
+	"tvtec/i18n"
+	"tvtec/middleware"
 	"tvtec/models"
 	"tvtec/service"
 )
 
 // AlunoController gerencia as rotas e lógica HTTP para alunos
 type AlunoController struct {
-	service service.AlunoService
+	service              service.AlunoService
+	consentimentoService service.ConsentimentoService
 }
 
 // NewAlunoController cria uma nova instância do controlador de alunos
-func NewAlunoController(service service.AlunoService) *AlunoController {
-	return &AlunoController{service: service}
+func NewAlunoController(service service.AlunoService, consentimentoService service.ConsentimentoService) *AlunoController {
+	return &AlunoController{service: service, consentimentoService: consentimentoService}
 }
 
 // Estrutura para receber os dados do formulário
@@ -38,6 +43,8 @@ type InscricaoRequest struct {
 	NecessitaElevador string `json:"necessitaElevador"`
 	ComoSoube         string `json:"comoSoube"`
 	AutorizaWhatsApp  string `json:"autorizaWhatsApp"`
+	VersaoTermos      string `json:"versaoTermos"` // versão dos termos exibida no formulário
+	Idioma            string `json:"idioma"`       // idioma das notificações; sem ele vale o Accept-Language
 }
 
 // CadastrarAlunoEInscrever cadastra um novo aluno e o inscreve em um curso
@@ -45,38 +52,33 @@ func (c *AlunoController) CadastrarAlunoEInscrever(ctx *gin.Context) {
 	var request InscricaoRequest
 
 	if err := ctx.ShouldBindJSON(&request); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Dados de formulário inválidos",
-			"details": err.Error(),
-		})
+		ctx.Error(erros.ErrDadosInvalidos.ComMensagem("Dados de formulário inválidos").Envolver(err))
 		return
 	}
 
 	// Validações básicas
 	if request.Nome == "" || request.CPF == "" || request.Email == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": "Nome, CPF e email são campos obrigatórios",
-		})
+		ctx.Error(erros.ErrDadosInvalidos.ComMensagem("Nome, CPF e email são campos obrigatórios"))
 		return
 	}
 
 	if request.Curso == 0 {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": "É necessário selecionar um curso",
-		})
+		ctx.Error(erros.ErrDadosInvalidos.ComMensagem("É necessário selecionar um curso"))
 		return
 	}
 
 	// Converter a data de nascimento de string para time.Time
 	dataNascto, err := time.Parse("02/01/2006", request.DataNascto)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Formato de data inválido. Use DD/MM/AAAA",
-			"details": err.Error(),
-		})
+		ctx.Error(erros.ErrDadosInvalidos.ComMensagem("Formato de data inválido. Use DD/MM/AAAA").Envolver(err))
 		return
 	}
 
+	idioma := middleware.IdiomaDe(ctx)
+	if request.Idioma != "" {
+		idioma = i18n.Normalizar(request.Idioma)
+	}
+
 	// Criar o objeto Aluno
 	aluno := &models.Aluno{
 		Nome:       request.Nome,
@@ -85,6 +87,7 @@ func (c *AlunoController) CadastrarAlunoEInscrever(ctx *gin.Context) {
 		Sexo:       request.Sexo,
 		Telefone:   request.Telefone,
 		DataNascto: dataNascto,
+		Idioma:     string(idioma),
 	}
 
 	// Criar o objeto de inscrição com os novos campos
@@ -102,33 +105,42 @@ func (c *AlunoController) CadastrarAlunoEInscrever(ctx *gin.Context) {
 		AutorizaWhatsApp:  request.AutorizaWhatsApp,
 	}
 
+	// A resposta sobre o WhatsApp é validada antes da inscrição e gravada junto com ela
+	autorizacao, err := c.consentimentoService.AutorizacaoInscricao(request.AutorizaWhatsApp, request.VersaoTermos, ctx.ClientIP())
+	if err != nil {
+		falhar(ctx, err, nil)
+		return
+	}
+
 	// Chama o serviço para cadastrar o aluno e inscrevê-lo no curso
-	if err := c.service.CadastrarAlunoEInscrever(aluno, inscricao); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Falha ao cadastrar aluno e inscrever no curso",
-			"details": err.Error(),
-		})
+	conflitos, err := c.service.CadastrarAlunoEInscrever(aluno, inscricao, autorizacao, models.OrigemConflitoInscricao)
+	if err != nil {
+		falhar(ctx, err, erros.ErrInterno.ComMensagem("Falha ao cadastrar aluno e inscrever no curso"))
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, gin.H{
-		"message": "Aluno cadastrado e inscrito com sucesso",
-		"aluno":   aluno,
-	})
+	resposta := gin.H{
+		"message":   middleware.Traduzir(ctx, "Aluno cadastrado e inscrito com sucesso"),
+		"aluno":     dto.ParaAluno(aluno, dto.PapelPublico),
+		"inscricao": dto.ParaInscricao(inscricao, dto.PapelPublico),
+	}
+	// Os valores já cadastrados não são devolvidos, apenas os campos que divergem
+	if len(conflitos) > 0 {
+		resposta["conflitosCadastro"] = service.CamposEmConflito(conflitos)
+		resposta["aviso"] = "Alguns dados informados diferem do cadastro existente e serão revisados pela coordenação"
+	}
+	ctx.JSON(http.StatusCreated, resposta)
 }
 
 // ListarAlunos recupera todos os alunos
 func (c *AlunoController) ListarAlunos(ctx *gin.Context) {
 	alunos, err := c.service.ListarAlunos()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Falha ao recuperar alunos",
-			"details": err.Error(),
-		})
+		falhar(ctx, err, erros.ErrInterno.ComMensagem("Falha ao recuperar alunos"))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, alunos)
+	ctx.JSON(http.StatusOK, dto.ParaAlunos(alunos, dto.PapelAdmin))
 }
 
 // ObterAlunoPorID busca um aluno específico
@@ -138,22 +150,17 @@ func (c *AlunoController) ObterAlunoPorID(ctx *gin.Context) {
 	// Converte o ID para uint
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": "ID de aluno inválido",
-		})
+		ctx.Error(erros.ErrDadosInvalidos.ComMensagem("ID de aluno inválido"))
 		return
 	}
 
 	aluno, err := c.service.ObterAlunoPorID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{
-			"error":   "Aluno não encontrado",
-			"details": err.Error(),
-		})
+		falhar(ctx, err, nil)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, aluno)
+	ctx.JSON(http.StatusOK, dto.ParaAluno(aluno, dto.PapelAdmin))
 }
 
 // AtualizarAluno atualiza informações de um aluno
@@ -162,10 +169,7 @@ func (c *AlunoController) AtualizarAluno(ctx *gin.Context) {
 
 	// Vincula os dados JSON da requisição ao modelo Aluno
 	if err := ctx.ShouldBindJSON(&aluno); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Dados inválidos",
-			"details": err.Error(),
-		})
+		ctx.Error(erros.ErrDadosInvalidos.ComMensagem("Dados inválidos").Envolver(err))
 		return
 	}
 
@@ -173,9 +177,7 @@ func (c *AlunoController) AtualizarAluno(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": "ID de aluno inválido",
-		})
+		ctx.Error(erros.ErrDadosInvalidos.ComMensagem("ID de aluno inválido"))
 		return
 	}
 
@@ -184,14 +186,11 @@ func (c *AlunoController) AtualizarAluno(ctx *gin.Context) {
 
 	// Chama o serviço para atualizar o aluno
 	if err := c.service.AtualizarAluno(&aluno); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Falha ao atualizar aluno",
-			"details": err.Error(),
-		})
+		falhar(ctx, err, erros.ErrInterno.ComMensagem("Falha ao atualizar aluno"))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, aluno)
+	ctx.JSON(http.StatusOK, dto.ParaAluno(&aluno, dto.PapelAdmin))
 }
 
 // RemoverAluno exclui um aluno
@@ -201,23 +200,18 @@ func (c *AlunoController) RemoverAluno(ctx *gin.Context) {
 	// Converte o ID para uint
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": "ID de aluno inválido",
-		})
+		ctx.Error(erros.ErrDadosInvalidos.ComMensagem("ID de aluno inválido"))
 		return
 	}
 
 	// Chama o serviço para remover o aluno
 	if err := c.service.RemoverAluno(uint(id)); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Falha ao remover aluno",
-			"details": err.Error(),
-		})
+		falhar(ctx, err, erros.ErrInterno.ComMensagem("Falha ao remover aluno"))
 		return
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{
-		"message": "Aluno removido com sucesso",
+		"message": middleware.Traduzir(ctx, "Aluno removido com sucesso"),
 	})
 }
 
@@ -229,20 +223,18 @@ func (c *AlunoController) AdicionarAlunoCurso(ctx *gin.Context) {
 	// Converte os IDs para uint
 	alunoID, err := strconv.ParseUint(alunoIDStr, 10, 64)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": "ID de aluno inválido",
-		})
+		ctx.Error(erros.ErrDadosInvalidos.ComMensagem("ID de aluno inválido"))
 		return
 	}
 
 	cursoID, err := strconv.ParseUint(cursoIDStr, 10, 64)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": "ID de curso inválido",
-		})
+		ctx.Error(erros.ErrDadosInvalidos.ComMensagem("ID de curso inválido"))
 		return
 	}
 
+	opcoes := opcoesInscricaoAdmin(ctx)
+
 	// Dados adicionais para a inscrição
 	var inscricaoData models.Inscricao
 	if err := ctx.ShouldBindJSON(&inscricaoData); err == nil {
@@ -252,26 +244,20 @@ func (c *AlunoController) AdicionarAlunoCurso(ctx *gin.Context) {
 		inscricaoData.DataInscricao = time.Now()
 
 		// Chama o serviço para criar a inscrição com detalhes
-		if err := c.service.CriarInscricaoDetalhada(&inscricaoData); err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"error":   "Falha ao adicionar aluno ao curso",
-				"details": err.Error(),
-			})
+		if err := c.service.CriarInscricaoDetalhada(&inscricaoData, opcoes); err != nil {
+			falhar(ctx, err, erros.ErrInterno.ComMensagem("Falha ao adicionar aluno ao curso"))
 			return
 		}
 	} else {
 		// Se não foram fornecidos dados adicionais, usa o método básico
-		if err := c.service.AdicionarAlunoCurso(uint(alunoID), uint(cursoID)); err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"error":   "Falha ao adicionar aluno ao curso",
-				"details": err.Error(),
-			})
+		if err := c.service.AdicionarAlunoCurso(uint(alunoID), uint(cursoID), opcoes); err != nil {
+			falhar(ctx, err, erros.ErrInterno.ComMensagem("Falha ao adicionar aluno ao curso"))
 			return
 		}
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{
-		"message": "Aluno adicionado ao curso com sucesso",
+		"message": middleware.Traduzir(ctx, "Aluno adicionado ao curso com sucesso"),
 	})
 }
 
@@ -282,20 +268,24 @@ func (c *AlunoController) ListarInscricoesAluno(ctx *gin.Context) {
 	// Converte o ID para uint
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": "ID de aluno inválido",
-		})
+		ctx.Error(erros.ErrDadosInvalidos.ComMensagem("ID de aluno inválido"))
 		return
 	}
 
 	inscricoes, err := c.service.ListarInscricoesAluno(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Falha ao recuperar inscrições",
-			"details": err.Error(),
-		})
+		falhar(ctx, err, erros.ErrInterno.ComMensagem("Falha ao recuperar inscrições"))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, inscricoes)
+	ctx.JSON(http.StatusOK, dto.ParaInscricoes(inscricoes, dto.PapelAdmin))
+}
+
+// opcoesInscricaoAdmin lê os parâmetros que permitem ao administrador ignorar regras de inscrição
+func opcoesInscricaoAdmin(ctx *gin.Context) service.OpcoesInscricao {
+	return service.OpcoesInscricao{
+		IgnorarPreRequisitos: ctx.Query("ignorarPreRequisitos") == "true",
+		IgnorarElegibilidade: ctx.Query("ignorarElegibilidade") == "true",
+		IgnorarLimites:       ctx.Query("ignorarLimites") == "true",
+	}
 }
@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"tvtec/dto"
+	"tvtec/erros"
+	"tvtec/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AreaProfessorController atende o professor autenticado, sempre limitado aos próprios cursos
+type AreaProfessorController interface {
+	MeusCursos(c *gin.Context)
+	Turma(c *gin.Context)
+	ListarPresencas(c *gin.Context)
+	RegistrarPresencas(c *gin.Context)
+}
+
+type areaProfessorController struct {
+	presencaService service.PresencaService
+}
+
+func NewAreaProfessorController(presencaService service.PresencaService) AreaProfessorController {
+	return &areaProfessorController{presencaService: presencaService}
+}
+
+func (ctrl *areaProfessorController) MeusCursos(c *gin.Context) {
+	cursos, err := ctrl.presencaService.CursosDoProfessor(professorLogado(c))
+	if err != nil {
+		falhar(c, err, erros.ErrInterno.ComMensagem("Erro ao listar cursos"))
+		return
+	}
+
+	for i := range cursos {
+		traduzirSituacaoInscricoes(c, &cursos[i])
+	}
+	c.JSON(http.StatusOK, dto.ParaCursos(cursos, dto.PapelProfessor))
+}
+
+func (ctrl *areaProfessorController) Turma(c *gin.Context) {
+	cursoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	turma, err := ctrl.presencaService.Turma(professorLogado(c), uint(cursoID))
+	if err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, turma)
+}
+
+func (ctrl *areaProfessorController) ListarPresencas(c *gin.Context) {
+	cursoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	presencas, err := ctrl.presencaService.ListarPresencas(professorLogado(c), uint(cursoID))
+	if err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, presencas)
+}
+
+// RegistrarPresencas recebe a chamada de um dia: {"data": "DD/MM/AAAA", "presencas": [{"inscricaoId": 1, "presente": true}]}
+func (ctrl *areaProfessorController) RegistrarPresencas(c *gin.Context) {
+	cursoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	var req struct {
+		Data      string                 `json:"data" binding:"required"`
+		Presencas []service.ItemPresenca `json:"presencas" binding:"required,dive"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("Dados inválidos").Envolver(err))
+		return
+	}
+
+	data, err := time.Parse("02/01/2006", req.Data)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("Formato de data inválido. Use DD/MM/AAAA").Envolver(err))
+		return
+	}
+
+	username, _ := c.Get("username")
+	presencas, err := ctrl.presencaService.RegistrarPresencas(professorLogado(c), uint(cursoID), data, req.Presencas, username.(string))
+	if err != nil {
+		falhar(c, err, erros.ErrInterno.ComMensagem("Erro ao registrar presenças"))
+		return
+	}
+
+	c.JSON(http.StatusOK, presencas)
+}
+
+// professorLogado lê o ID gravado pelo ProfessorAuthMiddleware
+func professorLogado(c *gin.Context) uint {
+	return c.GetUint("professorId")
+}
@@ -2,7 +2,9 @@ package controller
 
 import (
 	"net/http"
+	"tvtec/erros"
 	"tvtec/middleware"
+	"tvtec/service"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,10 +15,11 @@ type AuthController interface {
 }
 
 type authController struct {
+	professorService service.ProfessorService
 }
 
-func NewAuthController() AuthController {
-	return &authController{}
+func NewAuthController(professorService service.ProfessorService) AuthController {
+	return &authController{professorService: professorService}
 }
 
 // Estrutura para receber os dados de login
@@ -30,13 +33,15 @@ type LoginResponse struct {
 	Token    string `json:"token"`
 	Username string `json:"username"`
 	Role     string `json:"role"`
+	// Preenchido apenas no login de professores
+	ProfessorID uint `json:"professorId,omitempty"`
 }
 
 // Login autentica um usuário e retorna um token JWT
 func (ctrl *authController) Login(c *gin.Context) {
 	var loginRequest LoginRequest
 	if err := c.ShouldBindJSON(&loginRequest); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados de login inválidos"})
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("Dados de login inválidos").Envolver(err))
 		return
 	}
 
@@ -45,7 +50,7 @@ func (ctrl *authController) Login(c *gin.Context) {
 		// Gerar token
 		token, err := middleware.GenerateToken(loginRequest.Username, "admin")
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao gerar token"})
+			falhar(c, err, erros.ErrInterno.ComMensagem("Falha ao gerar token"))
 			return
 		}
 
@@ -58,8 +63,25 @@ func (ctrl *authController) Login(c *gin.Context) {
 		return
 	}
 
+	// Professores entram com o email do cadastro
+	if professor, err := ctrl.professorService.Autenticar(loginRequest.Username, loginRequest.Password); err == nil {
+		token, err := middleware.GenerateProfessorToken(professor.Email, professor.ID)
+		if err != nil {
+			falhar(c, err, erros.ErrInterno.ComMensagem("Falha ao gerar token"))
+			return
+		}
+
+		c.JSON(http.StatusOK, LoginResponse{
+			Token:       token,
+			Username:    professor.Email,
+			Role:        middleware.RoleProfessor,
+			ProfessorID: professor.ID,
+		})
+		return
+	}
+
 	// Credenciais inválidas
-	c.JSON(http.StatusUnauthorized, gin.H{"error": "Credenciais inválidas"})
+	c.Error(erros.ErrCredenciaisInvalidas)
 }
 
 // ValidateToken verifica se um token é válido
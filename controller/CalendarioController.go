@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"tvtec/erros"
+	"tvtec/middleware"
+	"tvtec/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CalendarioController interface {
+	CalendarioCurso(c *gin.Context)
+	CalendarioPublico(c *gin.Context)
+	CalendarioAluno(c *gin.Context)
+	LinkCalendarioAluno(c *gin.Context)
+	MeuLinkCalendario(c *gin.Context)
+	RevogarLinkCalendario(c *gin.Context)
+}
+
+type calendarioController struct {
+	calendarioService service.CalendarioService
+	lgpdService       service.LGPDService
+}
+
+func NewCalendarioController(calendarioService service.CalendarioService, lgpdService service.LGPDService) CalendarioController {
+	return &calendarioController{
+		calendarioService: calendarioService,
+		lgpdService:       lgpdService,
+	}
+}
+
+func (ctrl *calendarioController) CalendarioCurso(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	calendario, err := ctrl.calendarioService.CalendarioCurso(uint(id))
+	if err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	responderCalendario(c, calendario)
+}
+
+func (ctrl *calendarioController) CalendarioPublico(c *gin.Context) {
+	calendario, err := ctrl.calendarioService.CalendarioPublico()
+	if err != nil {
+		falhar(c, err, erros.ErrInterno.ComMensagem("Erro ao gerar calendário"))
+		return
+	}
+
+	responderCalendario(c, calendario)
+}
+
+// CalendarioAluno atende o link privado que o aluno assina no aplicativo de agenda
+func (ctrl *calendarioController) CalendarioAluno(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	calendario, err := ctrl.calendarioService.CalendarioAluno(uint(id), c.Query("token"))
+	if err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	responderCalendario(c, calendario)
+}
+
+func (ctrl *calendarioController) LinkCalendarioAluno(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	link, err := ctrl.calendarioService.LinkCalendarioAluno(uint(id))
+	if err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"url": link})
+}
+
+// RevogarLinkCalendario desativa o link privado do aluno, por exemplo quando ele foi compartilhado
+func (ctrl *calendarioController) RevogarLinkCalendario(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	if err := ctrl.calendarioService.RevogarLinkCalendario(uint(id)); err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": middleware.Traduzir(c, "Link do calendário revogado")})
+}
+
+// MeuLinkCalendario envia um novo link privado ao email do próprio aluno, identificado por CPF, email e
+// data de nascimento; o link nunca volta na resposta
+func (ctrl *calendarioController) MeuLinkCalendario(c *gin.Context) {
+	var request TitularRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("Dados inválidos").Envolver(err))
+		return
+	}
+
+	alunoID, ok := confirmarTitular(c, ctrl.lgpdService, &request)
+	if !ok {
+		return
+	}
+
+	if err := ctrl.calendarioService.EnviarLinkCalendario(alunoID); err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": middleware.Traduzir(c, "Enviamos o link do calendário para o email cadastrado")})
+}
+
+func responderCalendario(c *gin.Context, calendario string) {
+	c.Header("Content-Disposition", `inline; filename="calendar.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(calendario))
+}
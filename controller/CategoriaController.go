@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"tvtec/erros"
+	"tvtec/middleware"
+	"tvtec/models"
+	"tvtec/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CategoriaController interface {
+	ListarCategorias(c *gin.Context)
+	CriarCategoria(c *gin.Context)
+	AtualizarCategoria(c *gin.Context)
+	RemoverCategoria(c *gin.Context)
+}
+
+type categoriaController struct {
+	categoriaService service.CategoriaService
+}
+
+func NewCategoriaController(categoriaService service.CategoriaService) CategoriaController {
+	return &categoriaController{categoriaService: categoriaService}
+}
+
+func (ctrl *categoriaController) ListarCategorias(c *gin.Context) {
+	categorias, err := ctrl.categoriaService.ListarCategorias()
+	if err != nil {
+		falhar(c, err, erros.ErrInterno.ComMensagem("Erro ao listar categorias"))
+		return
+	}
+
+	c.JSON(http.StatusOK, categorias)
+}
+
+func (ctrl *categoriaController) CriarCategoria(c *gin.Context) {
+	var categoria models.Categoria
+	if err := c.ShouldBindJSON(&categoria); err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("Dados inválidos").Envolver(err))
+		return
+	}
+
+	if err := ctrl.categoriaService.CriarCategoria(&categoria); err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusCreated, categoria)
+}
+
+func (ctrl *categoriaController) AtualizarCategoria(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	var categoria models.Categoria
+	if err := c.ShouldBindJSON(&categoria); err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("Dados inválidos").Envolver(err))
+		return
+	}
+	categoria.ID = uint(id)
+
+	if err := ctrl.categoriaService.AtualizarCategoria(&categoria); err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, categoria)
+}
+
+func (ctrl *categoriaController) RemoverCategoria(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	if err := ctrl.categoriaService.RemoverCategoria(uint(id)); err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": middleware.Traduzir(c, "Categoria removida com sucesso")})
+}
@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"tvtec/erros"
+	"tvtec/middleware"
+	"tvtec/models"
+	"tvtec/service"
+)
+
+// ConsentimentoController expõe o histórico de consentimentos e as formas de revogação
+type ConsentimentoController struct {
+	service        service.ConsentimentoService
+	titularService service.LGPDService
+}
+
+// NewConsentimentoController cria uma nova instância do controlador de consentimentos
+func NewConsentimentoController(service service.ConsentimentoService, titularService service.LGPDService) *ConsentimentoController {
+	return &ConsentimentoController{service: service, titularService: titularService}
+}
+
+// Estrutura para o aluno alterar um consentimento no autoatendimento
+type ConsentimentoRequest struct {
+	TitularRequest
+	Canal        string `json:"canal" binding:"required"`
+	Finalidade   string `json:"finalidade" binding:"required"`
+	Concedido    *bool  `json:"concedido" binding:"required"`
+	VersaoTermos string `json:"versaoTermos"`
+}
+
+// ObterTermoVigente retorna o texto e a versão atual dos termos de consentimento
+func (c *ConsentimentoController) ObterTermoVigente(ctx *gin.Context) {
+	termo, err := c.service.TermoVigente()
+	if err != nil {
+		falhar(ctx, err, nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, termo)
+}
+
+// PublicarTermo registra uma nova versão dos termos de consentimento
+func (c *ConsentimentoController) PublicarTermo(ctx *gin.Context) {
+	var termo models.TermoConsentimento
+	if err := ctx.ShouldBindJSON(&termo); err != nil {
+		ctx.Error(erros.ErrDadosInvalidos.ComMensagem("Dados inválidos").Envolver(err))
+		return
+	}
+
+	if err := c.service.PublicarTermo(&termo); err != nil {
+		falhar(ctx, err, erros.ErrInterno.ComMensagem("Falha ao publicar termo"))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, termo)
+}
+
+// ListarConsentimentosAluno mostra o estado atual e o histórico completo de um aluno
+func (c *ConsentimentoController) ListarConsentimentosAluno(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.Error(erros.ErrDadosInvalidos.ComMensagem("ID de aluno inválido"))
+		return
+	}
+
+	estado, err := c.service.EstadoAtual(uint(id))
+	if err != nil {
+		falhar(ctx, err, erros.ErrInterno.ComMensagem("Falha ao recuperar consentimentos"))
+		return
+	}
+
+	historico, err := c.service.HistoricoAluno(uint(id))
+	if err != nil {
+		falhar(ctx, err, erros.ErrInterno.ComMensagem("Falha ao recuperar consentimentos"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"estadoAtual": estado,
+		"historico":   historico,
+	})
+}
+
+// AlterarMeuConsentimento permite ao aluno conceder ou revogar um consentimento. Sem código, envia o
+// código de confirmação ao email cadastrado; com o código, registra a alteração.
+func (c *ConsentimentoController) AlterarMeuConsentimento(ctx *gin.Context) {
+	var request ConsentimentoRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.Error(erros.ErrDadosInvalidos.ComMensagem("Dados inválidos").Envolver(err))
+		return
+	}
+
+	alunoID, ok := confirmarTitular(ctx, c.titularService, &request.TitularRequest)
+	if !ok {
+		return
+	}
+
+	if request.Codigo == "" {
+		solicitarConfirmacao(ctx, c.titularService, alunoID, models.SolicitacaoLGPDConsentimento, "")
+		return
+	}
+
+	consentimento := &models.Consentimento{
+		Canal:        request.Canal,
+		Finalidade:   request.Finalidade,
+		Concedido:    *request.Concedido,
+		VersaoTermos: request.VersaoTermos,
+		Origem:       models.OrigemConsentimentoAutoatendimento,
+		IP:           ctx.ClientIP(),
+	}
+
+	if err := c.titularService.ConfirmarConsentimento(alunoID, request.Codigo, consentimento); err != nil {
+		falhar(ctx, err, erros.ErrInterno.ComMensagem("Falha ao registrar consentimento"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, consentimento)
+}
+
+// Descadastrar atende o link de descadastro enviado junto das mensagens
+func (c *ConsentimentoController) Descadastrar(ctx *gin.Context) {
+	alunoID, err := strconv.ParseUint(ctx.Query("aluno"), 10, 64)
+	canal := ctx.Query("canal")
+	if err != nil || !c.service.ValidarTokenDescadastro(uint(alunoID), canal, ctx.Query("token")) {
+		ctx.Error(erros.ErrSemPermissao.ComMensagem("Link de descadastro inválido"))
+		return
+	}
+
+	if err := c.service.RevogarCanal(uint(alunoID), canal, models.OrigemConsentimentoDescadastro, ctx.ClientIP()); err != nil {
+		falhar(ctx, err, erros.ErrInterno.ComMensagem("Falha ao processar descadastro"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": middleware.Traduzir(ctx, "Você não receberá mais mensagens por este canal"),
+	})
+}
@@ -4,7 +4,12 @@ import (
 	"log"
 	"net/http"
 	"strconv"
+	"time"
+	"tvtec/dto"
+	"tvtec/erros"
+	"tvtec/middleware"
 	"tvtec/models"
+	"tvtec/repository"
 	"tvtec/service"
 
 	"github.com/gin-gonic/gin"
@@ -18,6 +23,11 @@ type CursoController interface {
 	RemoverCurso(c *gin.Context)
 	VerificarDisponibilidadeVagas(c *gin.Context)
 	ListarInscricoesCurso(c *gin.Context)
+	AvisosAcessibilidade(c *gin.Context)
+	ListarCursosAdmin(c *gin.Context)
+	ObterCursoAdmin(c *gin.Context)
+	MudarStatus(c *gin.Context)
+	CancelarCurso(c *gin.Context)
 }
 
 type cursoController struct {
@@ -28,47 +38,127 @@ func NewCursoController(cursoService service.CursoService) CursoController {
 	return &cursoController{cursoService: cursoService}
 }
 
+// ListarCursos aceita os filtros q, categoria, tag, dataInicio, dataFim (DD/MM/AAAA), comVagas e ordenar
 func (ctrl *cursoController) ListarCursos(c *gin.Context) {
-	cursos, err := ctrl.cursoService.ListarCursos()
+	ctrl.listarCursos(c, false)
+}
+
+// ListarCursosAdmin aceita os mesmos filtros da listagem pública e inclui os rascunhos
+func (ctrl *cursoController) ListarCursosAdmin(c *gin.Context) {
+	ctrl.listarCursos(c, true)
+}
+
+func (ctrl *cursoController) listarCursos(c *gin.Context, incluirRascunhos bool) {
+	filtro := repository.FiltroCurso{
+		Texto:            c.Query("q"),
+		Tag:              c.Query("tag"),
+		ApenasComVagas:   c.Query("comVagas") == "true",
+		Ordenacao:        c.Query("ordenar"),
+		IncluirRascunhos: incluirRascunhos,
+	}
+
+	if categoria := c.Query("categoria"); categoria != "" {
+		categoriaID, err := strconv.ParseUint(categoria, 10, 32)
+		if err != nil {
+			c.Error(erros.ErrDadosInvalidos.ComMensagem("Categoria inválida").Envolver(err))
+			return
+		}
+		filtro.CategoriaID = uint(categoriaID)
+	}
+
+	var ok bool
+	if filtro.DataInicio, ok = parseDataFiltro(c, "dataInicio"); !ok {
+		return
+	}
+	if filtro.DataFim, ok = parseDataFiltro(c, "dataFim"); !ok {
+		return
+	}
+	if filtro.DataFim != nil {
+		// Inclui o dia inteiro informado
+		fim := filtro.DataFim.Add(24*time.Hour - time.Nanosecond)
+		filtro.DataFim = &fim
+	}
+
+	if !repository.OrdenacaoCursoValida(filtro.Ordenacao) {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("Ordenação inválida. Use data, -data, nome, -nome ou vagas"))
+		return
+	}
+
+	cursos, err := ctrl.cursoService.BuscarCursos(filtro)
 	if err != nil {
 		log.Printf("Erro ao listar cursos: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar cursos"})
+		falhar(c, err, erros.ErrInterno.ComMensagem("Erro ao listar cursos"))
 		return
 	}
 
-	c.JSON(http.StatusOK, cursos)
+	papel := dto.PapelPublico
+	if incluirRascunhos {
+		papel = dto.PapelAdmin
+	}
+	for i := range cursos {
+		traduzirSituacaoInscricoes(c, &cursos[i])
+	}
+	c.JSON(http.StatusOK, dto.ParaCursos(cursos, papel))
 }
 
 func (ctrl *cursoController) ObterCursoPorID(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	curso, err := ctrl.cursoService.ObterCursoPublico(uint(id))
+	if err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	traduzirSituacaoInscricoes(c, curso)
+	c.JSON(http.StatusOK, dto.ParaCurso(curso, dto.PapelPublico))
+}
+
+func (ctrl *cursoController) ObterCursoAdmin(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
 		return
 	}
 
 	curso, err := ctrl.cursoService.ObterCursoPorID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		falhar(c, err, nil)
 		return
 	}
 
-	c.JSON(http.StatusOK, curso)
+	traduzirSituacaoInscricoes(c, curso)
+	c.JSON(http.StatusOK, dto.ParaCurso(curso, dto.PapelAdmin))
 }
 
 func (ctrl *cursoController) CriarCurso(c *gin.Context) {
 	var cursoDTO struct {
-		Nome         string `json:"nome" binding:"required"`
-		Professor    string `json:"professor" binding:"required"`
-		Data         string `json:"data" binding:"required"`
-		CargaHoraria int32  `json:"cargaHoraria" binding:"required"`
-		Certificado  string `json:"certificado" binding:"required"`
-		VagasTotais  int32  `json:"vagasTotais" binding:"required"`
+		Nome          string   `json:"nome" binding:"required"`
+		Professor     string   `json:"professor"`
+		ProfessorID   *uint    `json:"professorId"`
+		Data          string   `json:"data" binding:"required"`
+		CargaHoraria  int32    `json:"cargaHoraria" binding:"required"`
+		Certificado   string   `json:"certificado" binding:"required"`
+		VagasTotais   int32    `json:"vagasTotais" binding:"required"`
+		HoraInicio    string   `json:"horaInicio"`
+		HoraFim       string   `json:"horaFim"`
+		Abertura      string   `json:"inscricoesAbertura"`
+		Encerramento  string   `json:"inscricoesEncerramento"`
+		Status        string   `json:"status"`
+		CategoriaID   *uint    `json:"categoriaId"`
+		SalaID        *uint    `json:"salaId"`
+		Tags          []string `json:"tags"`
+		PreRequisitos []uint   `json:"preRequisitos"`
 	}
 
 	if err := c.ShouldBindJSON(&cursoDTO); err != nil {
 		log.Printf("Erro ao fazer bind do JSON: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos"})
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("Dados inválidos").Envolver(err))
 		return
 	}
 
@@ -76,54 +166,84 @@ func (ctrl *cursoController) CriarCurso(c *gin.Context) {
 	var data models.CustomTime
 	if err := data.UnmarshalJSON([]byte(`"` + cursoDTO.Data + `"`)); err != nil {
 		log.Printf("Erro ao converter data: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Formato de data inválido. Use DD/MM/AAAA"})
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("Formato de data inválido. Use DD/MM/AAAA").Envolver(err))
+		return
+	}
+
+	abertura, err := parseDataHora(cursoDTO.Abertura)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("Formato inválido em inscricoesAbertura. Use DD/MM/AAAA HH:MM").Envolver(err))
+		return
+	}
+	encerramento, err := parseDataHora(cursoDTO.Encerramento)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("Formato inválido em inscricoesEncerramento. Use DD/MM/AAAA HH:MM").Envolver(err))
 		return
 	}
 
 	curso := &models.Curso{
 		Nome:         cursoDTO.Nome,
 		Professor:    cursoDTO.Professor,
+		ProfessorID:  cursoDTO.ProfessorID,
 		Data:         data,
 		CargaHoraria: cursoDTO.CargaHoraria,
 		Certificado:  cursoDTO.Certificado,
 		VagasTotais:  cursoDTO.VagasTotais,
+		HoraInicio:   cursoDTO.HoraInicio,
+		HoraFim:      cursoDTO.HoraFim,
+		CategoriaID:  cursoDTO.CategoriaID,
+		SalaID:       cursoDTO.SalaID,
+		Status:       cursoDTO.Status,
+
+		InscricoesAbertura:     abertura,
+		InscricoesEncerramento: encerramento,
 	}
 
-	if err := ctrl.cursoService.CriarCurso(curso); err != nil {
+	if err := ctrl.cursoService.CriarCurso(curso, cursoDTO.Tags, cursoDTO.PreRequisitos); err != nil {
 		log.Printf("Erro ao criar curso: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar curso"})
+		falhar(c, err, erros.ErrInterno.ComMensagem("Erro ao criar curso"))
 		return
 	}
 
-	c.JSON(http.StatusCreated, curso)
+	traduzirSituacaoInscricoes(c, curso)
+	c.JSON(http.StatusCreated, dto.ParaCurso(curso, dto.PapelAdmin))
 }
 
 func (ctrl *cursoController) AtualizarCurso(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
 		return
 	}
 
 	// Verificar se o curso existe
 	existingCurso, err := ctrl.cursoService.ObterCursoPorID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		falhar(c, err, nil)
 		return
 	}
 
 	var cursoDTO struct {
-		Nome         string `json:"nome"`
-		Professor    string `json:"professor"`
-		Data         string `json:"data"`
-		CargaHoraria *int32 `json:"cargaHoraria"`
-		Certificado  string `json:"certificado"`
-		VagasTotais  *int32 `json:"vagasTotais"`
+		Nome          string    `json:"nome"`
+		Professor     string    `json:"professor"`
+		ProfessorID   *uint     `json:"professorId"`
+		Data          string    `json:"data"`
+		CargaHoraria  *int32    `json:"cargaHoraria"`
+		Certificado   string    `json:"certificado"`
+		VagasTotais   *int32    `json:"vagasTotais"`
+		HoraInicio    *string   `json:"horaInicio"`
+		HoraFim       *string   `json:"horaFim"`
+		Abertura      *string   `json:"inscricoesAbertura"`
+		Encerramento  *string   `json:"inscricoesEncerramento"`
+		CategoriaID   *uint     `json:"categoriaId"`
+		SalaID        *uint     `json:"salaId"`
+		Tags          *[]string `json:"tags"`
+		PreRequisitos *[]uint   `json:"preRequisitos"`
 	}
 
 	if err := c.ShouldBindJSON(&cursoDTO); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos"})
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("Dados inválidos").Envolver(err))
 		return
 	}
 
@@ -137,7 +257,7 @@ func (ctrl *cursoController) AtualizarCurso(c *gin.Context) {
 	if cursoDTO.Data != "" {
 		var data models.CustomTime
 		if err := data.UnmarshalJSON([]byte(`"` + cursoDTO.Data + `"`)); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Formato de data inválido. Use DD/MM/AAAA"})
+			c.Error(erros.ErrDadosInvalidos.ComMensagem("Formato de data inválido. Use DD/MM/AAAA").Envolver(err))
 			return
 		}
 		existingCurso.Data = data
@@ -151,47 +271,95 @@ func (ctrl *cursoController) AtualizarCurso(c *gin.Context) {
 	if cursoDTO.VagasTotais != nil {
 		// Verificamos se o novo número de vagas totais é pelo menos o número de vagas já preenchidas
 		if *cursoDTO.VagasTotais < existingCurso.VagasPreenchidas {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "O número de vagas totais não pode ser menor que o número de vagas já preenchidas"})
+			c.Error(erros.ErrDadosInvalidos.ComMensagem("O número de vagas totais não pode ser menor que o número de vagas já preenchidas"))
 			return
 		}
 		existingCurso.VagasTotais = *cursoDTO.VagasTotais
 	}
+	if cursoDTO.HoraInicio != nil {
+		existingCurso.HoraInicio = *cursoDTO.HoraInicio
+	}
+	if cursoDTO.HoraFim != nil {
+		existingCurso.HoraFim = *cursoDTO.HoraFim
+	}
+	// Texto vazio remove a data de abertura ou de encerramento das inscrições
+	if cursoDTO.Abertura != nil {
+		abertura, err := parseDataHora(*cursoDTO.Abertura)
+		if err != nil {
+			c.Error(erros.ErrDadosInvalidos.ComMensagem("Formato inválido em inscricoesAbertura. Use DD/MM/AAAA HH:MM").Envolver(err))
+			return
+		}
+		existingCurso.InscricoesAbertura = abertura
+	}
+	if cursoDTO.Encerramento != nil {
+		encerramento, err := parseDataHora(*cursoDTO.Encerramento)
+		if err != nil {
+			c.Error(erros.ErrDadosInvalidos.ComMensagem("Formato inválido em inscricoesEncerramento. Use DD/MM/AAAA HH:MM").Envolver(err))
+			return
+		}
+		existingCurso.InscricoesEncerramento = encerramento
+	}
+	if cursoDTO.ProfessorID != nil {
+		// Professor 0 desvincula o cadastro e mantém o nome informado
+		if *cursoDTO.ProfessorID == 0 {
+			existingCurso.ProfessorID = nil
+		} else {
+			existingCurso.ProfessorID = cursoDTO.ProfessorID
+		}
+	}
+	if cursoDTO.CategoriaID != nil {
+		// Categoria 0 remove o curso da categoria atual
+		if *cursoDTO.CategoriaID == 0 {
+			existingCurso.CategoriaID = nil
+		} else {
+			existingCurso.CategoriaID = cursoDTO.CategoriaID
+		}
+	}
+	if cursoDTO.SalaID != nil {
+		// Sala 0 desvincula o curso da sala atual
+		if *cursoDTO.SalaID == 0 {
+			existingCurso.SalaID = nil
+		} else {
+			existingCurso.SalaID = cursoDTO.SalaID
+		}
+	}
 
-	if err := ctrl.cursoService.AtualizarCurso(existingCurso); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar curso"})
+	if err := ctrl.cursoService.AtualizarCurso(existingCurso, cursoDTO.Tags, cursoDTO.PreRequisitos); err != nil {
+		falhar(c, err, erros.ErrInterno.ComMensagem("Erro ao atualizar curso"))
 		return
 	}
 
-	c.JSON(http.StatusOK, existingCurso)
+	traduzirSituacaoInscricoes(c, existingCurso)
+	c.JSON(http.StatusOK, dto.ParaCurso(existingCurso, dto.PapelAdmin))
 }
 
 func (ctrl *cursoController) RemoverCurso(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
 		return
 	}
 
 	if err := ctrl.cursoService.RemoverCurso(uint(id)); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		falhar(c, err, nil)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Curso removido com sucesso"})
+	c.JSON(http.StatusOK, gin.H{"message": middleware.Traduzir(c, "Curso removido com sucesso")})
 }
 
 func (ctrl *cursoController) VerificarDisponibilidadeVagas(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
 		return
 	}
 
 	vagasDisponiveis, err := ctrl.cursoService.VerificarDisponibilidadeVagas(uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		falhar(c, err, nil)
 		return
 	}
 
@@ -204,15 +372,122 @@ func (ctrl *cursoController) ListarInscricoesCurso(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
 		return
 	}
 
 	inscricoes, err := ctrl.cursoService.ListarInscricoesCurso(uint(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		falhar(c, err, erros.ErrInterno)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ParaInscricoes(inscricoes, dto.PapelAdmin))
+}
+
+// parseDataFiltro lê um parâmetro de data opcional no formato DD/MM/AAAA
+func parseDataFiltro(c *gin.Context, param string) (*time.Time, bool) {
+	valor := c.Query(param)
+	if valor == "" {
+		return nil, true
+	}
+
+	data, err := time.Parse("02/01/2006", valor)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("Formato de data inválido em %s. Use DD/MM/AAAA", param))
+		return nil, false
+	}
+	return &data, true
+}
+
+// parseDataHora converte DD/MM/AAAA HH:MM no horário de Brasília; texto vazio resulta em nil
+func parseDataHora(valor string) (*time.Time, error) {
+	if valor == "" {
+		return nil, nil
+	}
+	dataHora, err := time.ParseInLocation("02/01/2006 15:04", valor, models.FusoHorario)
+	if err != nil {
+		return nil, err
+	}
+	return &dataHora, nil
+}
+
+func (ctrl *cursoController) AvisosAcessibilidade(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
 		return
 	}
 
-	c.JSON(http.StatusOK, inscricoes)
+	avisos, err := ctrl.cursoService.AvisosAcessibilidade(uint(id))
+	if err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"avisos": avisos})
+}
+
+// MudarStatus aplica uma transição do ciclo de vida do curso (ex.: publicado -> em_andamento)
+func (ctrl *cursoController) MudarStatus(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	var req struct {
+		Status string `json:"status" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("Dados inválidos").Envolver(err))
+		return
+	}
+
+	curso, err := ctrl.cursoService.MudarStatus(uint(id), req.Status)
+	if err != nil {
+		falhar(c, err, erros.ErrInterno.ComMensagem("Erro ao alterar situação do curso"))
+		return
+	}
+
+	traduzirSituacaoInscricoes(c, curso)
+	c.JSON(http.StatusOK, dto.ParaCurso(curso, dto.PapelAdmin))
+}
+
+// CancelarCurso cancela o curso mantendo as inscrições e avisa os alunos inscritos
+func (ctrl *cursoController) CancelarCurso(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	var req struct {
+		Motivo string `json:"motivo"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(erros.ErrDadosInvalidos.ComMensagem("Dados inválidos").Envolver(err))
+			return
+		}
+	}
+
+	resumo, err := ctrl.cursoService.CancelarCurso(uint(id), req.Motivo)
+	if err != nil {
+		falhar(c, err, erros.ErrInterno.ComMensagem("Erro ao cancelar curso"))
+		return
+	}
+
+	traduzirSituacaoInscricoes(c, resumo.Curso)
+	// O curso do resumo sai no formato de resposta da administração
+	c.JSON(http.StatusOK, struct {
+		*service.ResumoCancelamento
+		Curso dto.CursoResposta `json:"curso"`
+	}{resumo, dto.ParaCurso(resumo.Curso, dto.PapelAdmin)})
+}
+
+// traduzirSituacaoInscricoes reescreve a situação das inscrições no idioma da requisição
+func traduzirSituacaoInscricoes(c *gin.Context, curso *models.Curso) {
+	curso.MensagemInscricoes = curso.MensagemInscricoesEm(middleware.IdiomaDe(c))
 }
@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"tvtec/dto"
+	"tvtec/erros"
+	"tvtec/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DuplicidadeController interface {
+	ListarCandidatos(c *gin.Context)
+	MesclarAlunos(c *gin.Context)
+	ListarFusoes(c *gin.Context)
+	ListarConflitos(c *gin.Context)
+	ResolverConflito(c *gin.Context)
+}
+
+type duplicidadeController struct {
+	duplicidadeService service.DuplicidadeService
+}
+
+func NewDuplicidadeController(duplicidadeService service.DuplicidadeService) DuplicidadeController {
+	return &duplicidadeController{duplicidadeService: duplicidadeService}
+}
+
+// CandidatoDuplicidadeResposta mostra os dois cadastros com os dados completos para a comparação
+type CandidatoDuplicidadeResposta struct {
+	AlunoA           dto.AlunoResposta `json:"alunoA"`
+	AlunoB           dto.AlunoResposta `json:"alunoB"`
+	Criterios        []string          `json:"criterios"`
+	SimilaridadeNome float64           `json:"similaridadeNome"`
+}
+
+// MesclarRequest indica o cadastro duplicado que será incorporado ao aluno da rota
+type MesclarRequest struct {
+	AlunoDuplicadoID uint   `json:"alunoDuplicadoId" binding:"required"`
+	Motivo           string `json:"motivo"`
+}
+
+// ResolverConflitoRequest decide se o valor informado substitui o cadastrado
+type ResolverConflitoRequest struct {
+	Aplicar bool `json:"aplicar"`
+}
+
+func (ctrl *duplicidadeController) ListarCandidatos(c *gin.Context) {
+	candidatos, err := ctrl.duplicidadeService.BuscarCandidatos()
+	if err != nil {
+		falhar(c, err, erros.ErrInterno.ComMensagem("Erro ao buscar alunos duplicados"))
+		return
+	}
+
+	respostas := make([]CandidatoDuplicidadeResposta, len(candidatos))
+	for i := range candidatos {
+		respostas[i] = CandidatoDuplicidadeResposta{
+			AlunoA:           dto.ParaAluno(&candidatos[i].AlunoA, dto.PapelAdmin),
+			AlunoB:           dto.ParaAluno(&candidatos[i].AlunoB, dto.PapelAdmin),
+			Criterios:        candidatos[i].Criterios,
+			SimilaridadeNome: candidatos[i].SimilaridadeNome,
+		}
+	}
+	c.JSON(http.StatusOK, respostas)
+}
+
+// MesclarAlunos incorpora o aluno duplicado ao aluno da rota, que é o cadastro mantido
+func (ctrl *duplicidadeController) MesclarAlunos(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	var request MesclarRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("Dados inválidos").Envolver(err))
+		return
+	}
+
+	fusao, err := ctrl.duplicidadeService.Mesclar(uint(id), request.AlunoDuplicadoID, request.Motivo, c.GetString("username"), c.ClientIP())
+	if err != nil {
+		falhar(c, err, erros.ErrInterno.ComMensagem("Falha ao mesclar alunos"))
+		return
+	}
+
+	c.JSON(http.StatusOK, fusao)
+}
+
+func (ctrl *duplicidadeController) ListarFusoes(c *gin.Context) {
+	fusoes, err := ctrl.duplicidadeService.ListarFusoes()
+	if err != nil {
+		falhar(c, err, erros.ErrInterno.ComMensagem("Erro ao listar fusões de alunos"))
+		return
+	}
+
+	c.JSON(http.StatusOK, fusoes)
+}
+
+func (ctrl *duplicidadeController) ListarConflitos(c *gin.Context) {
+	conflitos, err := ctrl.duplicidadeService.ListarConflitos(c.Query("situacao"))
+	if err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, conflitos)
+}
+
+func (ctrl *duplicidadeController) ResolverConflito(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	var request ResolverConflitoRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("Dados inválidos").Envolver(err))
+		return
+	}
+
+	conflito, err := ctrl.duplicidadeService.ResolverConflito(uint(id), request.Aplicar, c.GetString("username"))
+	if err != nil {
+		falhar(c, err, erros.ErrInterno.ComMensagem("Falha ao resolver conflito"))
+		return
+	}
+
+	c.JSON(http.StatusOK, conflito)
+}
@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"tvtec/erros"
+	"tvtec/models"
+	"tvtec/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ElegibilidadeController interface {
+	ListarRegras(c *gin.Context)
+	DefinirRegras(c *gin.Context)
+	Previa(c *gin.Context)
+}
+
+type elegibilidadeController struct {
+	elegibilidadeService service.ElegibilidadeService
+}
+
+func NewElegibilidadeController(elegibilidadeService service.ElegibilidadeService) ElegibilidadeController {
+	return &elegibilidadeController{elegibilidadeService: elegibilidadeService}
+}
+
+func (ctrl *elegibilidadeController) ListarRegras(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	regras, err := ctrl.elegibilidadeService.ListarRegras(uint(id))
+	if err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, regras)
+}
+
+// DefinirRegras substitui todas as regras de elegibilidade do curso; uma lista vazia remove as restrições
+func (ctrl *elegibilidadeController) DefinirRegras(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	var regras []models.RegraElegibilidade
+	if err := c.ShouldBindJSON(&regras); err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("Dados inválidos").Envolver(err))
+		return
+	}
+
+	if err := ctrl.elegibilidadeService.DefinirRegras(uint(id), regras); err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, regras)
+}
+
+// Previa avalia regras (as enviadas no corpo ou as já salvas) contra os alunos cadastrados
+func (ctrl *elegibilidadeController) Previa(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	var regras []models.RegraElegibilidade
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&regras); err != nil {
+			c.Error(erros.ErrDadosInvalidos.ComMensagem("Dados inválidos").Envolver(err))
+			return
+		}
+	}
+
+	previa, err := ctrl.elegibilidadeService.Previa(uint(id), regras)
+	if err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, previa)
+}
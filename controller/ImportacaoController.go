@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"tvtec/erros"
+	"tvtec/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Tamanho máximo da planilha enviada para importação
+const tamanhoMaximoPlanilha = 5 << 20
+
+type ImportacaoController interface {
+	ImportarAlunos(c *gin.Context)
+}
+
+type importacaoController struct {
+	importacaoService service.ImportacaoService
+}
+
+func NewImportacaoController(importacaoService service.ImportacaoService) ImportacaoController {
+	return &importacaoController{importacaoService: importacaoService}
+}
+
+// ImportarAlunos recebe uma planilha CSV/XLSX no campo "arquivo". Por padrão apenas simula a
+// importação; com modo=efetivar grava os alunos e inscrições das linhas válidas.
+func (ctrl *importacaoController) ImportarAlunos(c *gin.Context) {
+	arquivo, err := c.FormFile("arquivo")
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("Arquivo da planilha é obrigatório").Envolver(err))
+		return
+	}
+	if arquivo.Size > tamanhoMaximoPlanilha {
+		c.Error(erros.ErrArquivoMuitoGrande.ComMensagem("Planilha maior que 5MB"))
+		return
+	}
+
+	var opcoes service.OpcoesImportacao
+	modo := c.DefaultPostForm("modo", c.DefaultQuery("modo", "simulacao"))
+	switch modo {
+	case "simulacao":
+	case "efetivar":
+		opcoes.Efetivar = true
+	default:
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("Modo inválido. Use simulacao ou efetivar"))
+		return
+	}
+
+	if mapeamento := c.PostForm("mapeamento"); mapeamento != "" {
+		if err := json.Unmarshal([]byte(mapeamento), &opcoes.Mapeamento); err != nil {
+			c.Error(erros.ErrDadosInvalidos.ComMensagem("Mapeamento de colunas inválido").Envolver(err))
+			return
+		}
+	}
+	if curso := c.PostForm("curso"); curso != "" {
+		cursoID, err := strconv.ParseUint(curso, 10, 32)
+		if err != nil || cursoID == 0 {
+			c.Error(erros.ErrDadosInvalidos.ComMensagem("ID do curso inválido"))
+			return
+		}
+		opcoes.CursoID = uint(cursoID)
+	}
+
+	aberto, err := arquivo.Open()
+	if err != nil {
+		falhar(c, err, erros.ErrInterno.ComMensagem("Erro ao abrir planilha"))
+		return
+	}
+	defer aberto.Close()
+	conteudo, err := io.ReadAll(aberto)
+	if err != nil {
+		falhar(c, err, erros.ErrInterno.ComMensagem("Erro ao ler planilha"))
+		return
+	}
+
+	planilha, err := service.LerPlanilha(arquivo.Filename, conteudo)
+	if err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	relatorio, err := ctrl.importacaoService.Importar(arquivo.Filename, planilha, opcoes)
+	if err != nil {
+		falhar(c, err, erros.ErrInterno.ComMensagem("Erro ao importar planilha"))
+		return
+	}
+
+	c.JSON(http.StatusOK, relatorio)
+}
@@ -5,6 +5,9 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"tvtec/dto"
+	"tvtec/erros"
+	"tvtec/middleware"
 	"tvtec/models"
 	"tvtec/service"
 )
@@ -20,14 +23,11 @@ func NewInscricaoController(service service.InscricaoService) *InscricaoControll
 func (c *InscricaoController) ListarInscricoes(ctx *gin.Context) {
 	inscricoes, err := c.service.ListarInscricoesDetalhadas()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Falha ao recuperar inscrições",
-			"details": err.Error(),
-		})
+		falhar(ctx, err, erros.ErrInterno.ComMensagem("Falha ao recuperar inscrições"))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, inscricoes)
+	ctx.JSON(http.StatusOK, dto.ParaInscricoes(inscricoes, dto.PapelAdmin))
 }
 
 func (c *InscricaoController) ObterInscricaoPorID(ctx *gin.Context) {
@@ -36,44 +36,33 @@ func (c *InscricaoController) ObterInscricaoPorID(ctx *gin.Context) {
 	// Converte o ID para uint
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": "ID de inscrição inválido",
-		})
+		ctx.Error(erros.ErrDadosInvalidos.ComMensagem("ID de inscrição inválido"))
 		return
 	}
 
 	inscricao, err := c.service.ObterInscricaoPorID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{
-			"error":   "Inscrição não encontrada",
-			"details": err.Error(),
-		})
+		falhar(ctx, err, nil)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, inscricao)
+	ctx.JSON(http.StatusOK, dto.ParaInscricao(inscricao, dto.PapelAdmin))
 }
 
 func (c *InscricaoController) CriarInscricao(ctx *gin.Context) {
 	var inscricao models.Inscricao
 
 	if err := ctx.ShouldBindJSON(&inscricao); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Dados inválidos",
-			"details": err.Error(),
-		})
+		ctx.Error(erros.ErrDadosInvalidos.ComMensagem("Dados inválidos").Envolver(err))
 		return
 	}
 
-	if err := c.service.CriarInscricao(&inscricao); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Falha ao criar inscrição",
-			"details": err.Error(),
-		})
+	if err := c.service.CriarInscricao(&inscricao, opcoesInscricaoAdmin(ctx)); err != nil {
+		falhar(ctx, err, erros.ErrInterno.ComMensagem("Falha ao criar inscrição"))
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, inscricao)
+	ctx.JSON(http.StatusCreated, dto.ParaInscricao(&inscricao, dto.PapelAdmin))
 }
 
 func (c *InscricaoController) CancelarInscricao(ctx *gin.Context) {
@@ -82,47 +71,56 @@ func (c *InscricaoController) CancelarInscricao(ctx *gin.Context) {
 	// Converte o ID para uint
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": "ID de inscrição inválido",
-		})
+		ctx.Error(erros.ErrDadosInvalidos.ComMensagem("ID de inscrição inválido"))
 		return
 	}
 
 	if err := c.service.CancelarInscricao(uint(id)); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Falha ao cancelar inscrição",
-			"details": err.Error(),
-		})
+		falhar(ctx, err, erros.ErrInterno.ComMensagem("Falha ao cancelar inscrição"))
 		return
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{
-		"message": "Inscrição cancelada com sucesso",
+		"message": middleware.Traduzir(ctx, "Inscrição cancelada com sucesso"),
 	})
 }
 
+func (c *InscricaoController) ConcluirInscricao(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+
+	// Converte o ID para uint
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		ctx.Error(erros.ErrDadosInvalidos.ComMensagem("ID de inscrição inválido"))
+		return
+	}
+
+	inscricao, err := c.service.ConcluirInscricao(uint(id))
+	if err != nil {
+		falhar(ctx, err, erros.ErrInterno.ComMensagem("Falha ao concluir inscrição"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ParaInscricao(inscricao, dto.PapelAdmin))
+}
+
 func (c *InscricaoController) ListarInscricoesPorAluno(ctx *gin.Context) {
 	idStr := ctx.Param("alunoId")
 
 	// Converte o ID para uint
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": "ID de aluno inválido",
-		})
+		ctx.Error(erros.ErrDadosInvalidos.ComMensagem("ID de aluno inválido"))
 		return
 	}
 
 	inscricoes, err := c.service.ListarInscricoesPorAluno(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Falha ao recuperar inscrições do aluno",
-			"details": err.Error(),
-		})
+		falhar(ctx, err, erros.ErrInterno.ComMensagem("Falha ao recuperar inscrições do aluno"))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, inscricoes)
+	ctx.JSON(http.StatusOK, dto.ParaInscricoes(inscricoes, dto.PapelAdmin))
 }
 
 func (c *InscricaoController) ListarInscricoesPorCurso(ctx *gin.Context) {
@@ -131,44 +129,33 @@ func (c *InscricaoController) ListarInscricoesPorCurso(ctx *gin.Context) {
 	// Converte o ID para uint
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": "ID de curso inválido",
-		})
+		ctx.Error(erros.ErrDadosInvalidos.ComMensagem("ID de curso inválido"))
 		return
 	}
 
 	inscricoes, err := c.service.ListarInscricoesPorCurso(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Falha ao recuperar inscrições do curso",
-			"details": err.Error(),
-		})
+		falhar(ctx, err, erros.ErrInterno.ComMensagem("Falha ao recuperar inscrições do curso"))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, inscricoes)
+	ctx.JSON(http.StatusOK, dto.ParaInscricoes(inscricoes, dto.PapelAdmin))
 }
 
 func (c *InscricaoController) GerarRelatorio(ctx *gin.Context) {
 	var dados []map[string]interface{}
 	if err := ctx.ShouldBindJSON(&dados); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Falha ao processar dados do relatório",
-			"details": err.Error(),
-		})
+		ctx.Error(erros.ErrDadosInvalidos.ComMensagem("Falha ao processar dados do relatório").Envolver(err))
 		return
 	}
 
 	if err := c.service.GerarRelatorio(dados); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Falha ao gerar relatório",
-			"details": err.Error(),
-		})
+		falhar(ctx, err, erros.ErrInterno.ComMensagem("Falha ao gerar relatório"))
 		return
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{
-		"message":   "Relatório gerado com sucesso",
+		"message":   middleware.Traduzir(ctx, "Relatório gerado com sucesso"),
 		"timestamp": ctx.Request.Context().Value("requestTime"),
 	})
 }
@@ -0,0 +1,204 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"tvtec/erros"
+	"tvtec/middleware"
+	"tvtec/models"
+	"tvtec/service"
+)
+
+// LGPDController expõe as operações de exportação e eliminação de dados do titular
+type LGPDController struct {
+	service service.LGPDService
+}
+
+// NewLGPDController cria uma nova instância do controlador LGPD
+func NewLGPDController(service service.LGPDService) *LGPDController {
+	return &LGPDController{service: service}
+}
+
+// Estrutura usada pelo titular para confirmar sua identidade no autoatendimento
+type TitularRequest struct {
+	CPF        string `json:"cpf" binding:"required"`
+	Email      string `json:"email" binding:"required"`
+	DataNascto string `json:"dataNascto" binding:"required"`
+	Formato    string `json:"formato"`
+	Codigo     string `json:"codigo"` // código enviado ao email, exigido para confirmar o pedido
+}
+
+// ExportarDadosAluno gera o pacote de dados de um aluno a pedido de um administrador
+func (c *LGPDController) ExportarDadosAluno(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.Error(erros.ErrDadosInvalidos.ComMensagem("ID de aluno inválido"))
+		return
+	}
+
+	c.exportar(ctx, uint(id), ctx.DefaultQuery("formato", "json"), ctx.GetString("username"))
+}
+
+// EliminarDadosAluno anonimiza um aluno a pedido de um administrador
+func (c *LGPDController) EliminarDadosAluno(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.Error(erros.ErrDadosInvalidos.ComMensagem("ID de aluno inválido"))
+		return
+	}
+
+	c.eliminar(ctx, uint(id), ctx.GetString("username"))
+}
+
+// ListarSolicitacoes lista o registro de todas as solicitações LGPD
+func (c *LGPDController) ListarSolicitacoes(ctx *gin.Context) {
+	solicitacoes, err := c.service.ListarSolicitacoes()
+	if err != nil {
+		falhar(ctx, err, erros.ErrInterno.ComMensagem("Falha ao recuperar solicitações"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, solicitacoes)
+}
+
+// ExportarMeusDados permite ao próprio aluno obter seus dados. Sem código, envia o código de
+// confirmação ao email cadastrado; com o código, entrega o pacote no formato pedido.
+func (c *LGPDController) ExportarMeusDados(ctx *gin.Context) {
+	request, alunoID, ok := c.identificarTitular(ctx)
+	if !ok {
+		return
+	}
+
+	if request.Codigo == "" {
+		formato := request.Formato
+		if formato == "" {
+			formato = "json"
+		}
+		if !formatoValido(ctx, formato) {
+			return
+		}
+		solicitarConfirmacao(ctx, c.service, alunoID, models.SolicitacaoLGPDExportacao, formato)
+		return
+	}
+
+	pacote, formato, err := c.service.ConfirmarExportacao(alunoID, request.Codigo)
+	if err != nil {
+		falhar(ctx, err, erros.ErrInterno.ComMensagem("Falha ao exportar dados do aluno"))
+		return
+	}
+	c.entregar(ctx, alunoID, pacote, formato)
+}
+
+// EliminarMeusDados permite ao próprio aluno solicitar a eliminação de seus dados, confirmada
+// pelo código enviado ao email cadastrado
+func (c *LGPDController) EliminarMeusDados(ctx *gin.Context) {
+	request, alunoID, ok := c.identificarTitular(ctx)
+	if !ok {
+		return
+	}
+
+	if request.Codigo == "" {
+		solicitarConfirmacao(ctx, c.service, alunoID, models.SolicitacaoLGPDEliminacao, "")
+		return
+	}
+
+	if err := c.service.ConfirmarEliminacao(alunoID, request.Codigo); err != nil {
+		falhar(ctx, err, erros.ErrInterno.ComMensagem("Falha ao eliminar dados do aluno"))
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": middleware.Traduzir(ctx, "Dados pessoais do aluno anonimizados com sucesso"),
+	})
+}
+
+// solicitarConfirmacao envia ao email cadastrado o código que confirma um pedido do titular
+func solicitarConfirmacao(ctx *gin.Context, titularService service.LGPDService, alunoID uint, tipo, formato string) {
+	if err := titularService.SolicitarConfirmacao(alunoID, tipo, formato, ctx.ClientIP()); err != nil {
+		falhar(ctx, err, erros.ErrInterno.ComMensagem("Falha ao registrar a solicitação"))
+		return
+	}
+	ctx.JSON(http.StatusAccepted, gin.H{
+		"message": middleware.Traduzir(ctx, "Enviamos um código de confirmação para o email cadastrado"),
+	})
+}
+
+func (c *LGPDController) identificarTitular(ctx *gin.Context) (*TitularRequest, uint, bool) {
+	var request TitularRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.Error(erros.ErrDadosInvalidos.ComMensagem("CPF, email e data de nascimento são obrigatórios").Envolver(err))
+		return nil, 0, false
+	}
+
+	alunoID, ok := confirmarTitular(ctx, c.service, &request)
+	return &request, alunoID, ok
+}
+
+// confirmarTitular valida os dados de identificação enviados pelo próprio aluno nas rotas de autoatendimento
+func confirmarTitular(ctx *gin.Context, titularService service.LGPDService, request *TitularRequest) (uint, bool) {
+	dataNascto, err := time.Parse("02/01/2006", request.DataNascto)
+	if err != nil {
+		ctx.Error(erros.ErrDadosInvalidos.ComMensagem("Formato de data inválido. Use DD/MM/AAAA").Envolver(err))
+		return 0, false
+	}
+
+	aluno, err := titularService.IdentificarTitular(request.CPF, request.Email, dataNascto, ctx.ClientIP())
+	if err != nil {
+		falhar(ctx, err, nil)
+		return 0, false
+	}
+
+	return aluno.ID, true
+}
+
+func formatoValido(ctx *gin.Context, formato string) bool {
+	if formato != "json" && formato != "pdf" {
+		ctx.Error(erros.ErrDadosInvalidos.ComMensagem("Formato inválido. Use json ou pdf"))
+		return false
+	}
+	return true
+}
+
+func (c *LGPDController) exportar(ctx *gin.Context, alunoID uint, formato, solicitante string) {
+	if !formatoValido(ctx, formato) {
+		return
+	}
+
+	pacote, err := c.service.ExportarDados(alunoID, formato, solicitante, ctx.ClientIP())
+	if err != nil {
+		falhar(ctx, err, erros.ErrInterno.ComMensagem("Falha ao exportar dados do aluno"))
+		return
+	}
+	c.entregar(ctx, alunoID, pacote, formato)
+}
+
+// entregar responde com o pacote como anexo JSON ou PDF
+func (c *LGPDController) entregar(ctx *gin.Context, alunoID uint, pacote *service.PacoteDadosAluno, formato string) {
+	if formato == "pdf" {
+		conteudo, err := c.service.GerarPDF(pacote)
+		if err != nil {
+			falhar(ctx, err, erros.ErrInterno.ComMensagem("Falha ao gerar PDF"))
+			return
+		}
+		ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="dados-aluno-%d.pdf"`, alunoID))
+		ctx.Data(http.StatusOK, "application/pdf", conteudo)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="dados-aluno-%d.json"`, alunoID))
+	ctx.JSON(http.StatusOK, pacote)
+}
+
+func (c *LGPDController) eliminar(ctx *gin.Context, alunoID uint, solicitante string) {
+	if err := c.service.EliminarDados(alunoID, solicitante, ctx.ClientIP()); err != nil {
+		falhar(ctx, err, erros.ErrInterno.ComMensagem("Falha ao eliminar dados do aluno"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": middleware.Traduzir(ctx, "Dados pessoais do aluno anonimizados com sucesso"),
+	})
+}
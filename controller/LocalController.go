@@ -0,0 +1,174 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"tvtec/erros"
+	"tvtec/middleware"
+	"tvtec/models"
+	"tvtec/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LocalController interface {
+	ListarLocais(c *gin.Context)
+	ObterLocalPorID(c *gin.Context)
+	CriarLocal(c *gin.Context)
+	AtualizarLocal(c *gin.Context)
+	RemoverLocal(c *gin.Context)
+	CriarSala(c *gin.Context)
+	AtualizarSala(c *gin.Context)
+	RemoverSala(c *gin.Context)
+}
+
+type localController struct {
+	localService service.LocalService
+}
+
+func NewLocalController(localService service.LocalService) LocalController {
+	return &localController{localService: localService}
+}
+
+func (ctrl *localController) ListarLocais(c *gin.Context) {
+	locais, err := ctrl.localService.ListarLocais()
+	if err != nil {
+		falhar(c, err, erros.ErrInterno.ComMensagem("Erro ao listar locais"))
+		return
+	}
+
+	c.JSON(http.StatusOK, locais)
+}
+
+func (ctrl *localController) ObterLocalPorID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	local, err := ctrl.localService.ObterLocalPorID(uint(id))
+	if err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, local)
+}
+
+func (ctrl *localController) CriarLocal(c *gin.Context) {
+	var local models.Local
+	if err := c.ShouldBindJSON(&local); err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("Dados inválidos").Envolver(err))
+		return
+	}
+
+	if err := ctrl.localService.CriarLocal(&local); err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusCreated, local)
+}
+
+func (ctrl *localController) AtualizarLocal(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	var local models.Local
+	if err := c.ShouldBindJSON(&local); err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("Dados inválidos").Envolver(err))
+		return
+	}
+	local.ID = uint(id)
+
+	if err := ctrl.localService.AtualizarLocal(&local); err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, local)
+}
+
+func (ctrl *localController) RemoverLocal(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	if err := ctrl.localService.RemoverLocal(uint(id)); err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": middleware.Traduzir(c, "Local removido com sucesso")})
+}
+
+func (ctrl *localController) CriarSala(c *gin.Context) {
+	localID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	var sala models.Sala
+	if err := c.ShouldBindJSON(&sala); err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("Dados inválidos").Envolver(err))
+		return
+	}
+	sala.LocalID = uint(localID)
+
+	if err := ctrl.localService.CriarSala(&sala); err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusCreated, sala)
+}
+
+func (ctrl *localController) AtualizarSala(c *gin.Context) {
+	localID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+	salaID, err := strconv.ParseUint(c.Param("salaId"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID de sala inválido"))
+		return
+	}
+
+	var sala models.Sala
+	if err := c.ShouldBindJSON(&sala); err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("Dados inválidos").Envolver(err))
+		return
+	}
+	sala.ID = uint(salaID)
+	sala.LocalID = uint(localID)
+
+	if err := ctrl.localService.AtualizarSala(&sala); err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, sala)
+}
+
+func (ctrl *localController) RemoverSala(c *gin.Context) {
+	salaID, err := strconv.ParseUint(c.Param("salaId"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID de sala inválido"))
+		return
+	}
+
+	if err := ctrl.localService.RemoverSala(uint(salaID)); err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": middleware.Traduzir(c, "Sala removida com sucesso")})
+}
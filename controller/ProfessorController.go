@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"tvtec/dto"
+	"tvtec/erros"
+	"tvtec/middleware"
+	"tvtec/models"
+	"tvtec/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProfessorController interface {
+	ListarProfessores(c *gin.Context)
+	ObterProfessorPorID(c *gin.Context)
+	CriarProfessor(c *gin.Context)
+	AtualizarProfessor(c *gin.Context)
+	RemoverProfessor(c *gin.Context)
+	DefinirSenha(c *gin.Context)
+	PerfilPublico(c *gin.Context)
+}
+
+type professorController struct {
+	professorService service.ProfessorService
+}
+
+func NewProfessorController(professorService service.ProfessorService) ProfessorController {
+	return &professorController{professorService: professorService}
+}
+
+func (ctrl *professorController) ListarProfessores(c *gin.Context) {
+	professores, err := ctrl.professorService.ListarProfessores()
+	if err != nil {
+		falhar(c, err, erros.ErrInterno.ComMensagem("Erro ao listar professores"))
+		return
+	}
+
+	c.JSON(http.StatusOK, professores)
+}
+
+func (ctrl *professorController) ObterProfessorPorID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	professor, err := ctrl.professorService.ObterProfessorPorID(uint(id))
+	if err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, professor)
+}
+
+func (ctrl *professorController) CriarProfessor(c *gin.Context) {
+	var professor models.Professor
+	if err := c.ShouldBindJSON(&professor); err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("Dados inválidos").Envolver(err))
+		return
+	}
+
+	if err := ctrl.professorService.CriarProfessor(&professor); err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusCreated, professor)
+}
+
+func (ctrl *professorController) AtualizarProfessor(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	var professor models.Professor
+	if err := c.ShouldBindJSON(&professor); err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("Dados inválidos").Envolver(err))
+		return
+	}
+	professor.ID = uint(id)
+
+	if err := ctrl.professorService.AtualizarProfessor(&professor); err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, professor)
+}
+
+func (ctrl *professorController) RemoverProfessor(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	if err := ctrl.professorService.RemoverProfessor(uint(id)); err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": middleware.Traduzir(c, "Professor removido com sucesso")})
+}
+
+// DefinirSenha cria ou troca a senha de acesso do professor à área restrita
+func (ctrl *professorController) DefinirSenha(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	var req struct {
+		Senha string `json:"senha" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("Dados inválidos").Envolver(err))
+		return
+	}
+
+	if err := ctrl.professorService.DefinirSenha(uint(id), req.Senha); err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": middleware.Traduzir(c, "Senha definida com sucesso")})
+}
+
+func (ctrl *professorController) PerfilPublico(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	professor, cursos, err := ctrl.professorService.PerfilPublico(uint(id))
+	if err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	for i := range cursos {
+		traduzirSituacaoInscricoes(c, &cursos[i])
+	}
+	c.JSON(http.StatusOK, dto.ParaPerfilProfessor(professor, cursos))
+}
@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+	"tvtec/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SaudeController interface {
+	Vivo(c *gin.Context)
+	Pronto(c *gin.Context)
+}
+
+type saudeController struct {
+	saudeService service.SaudeService
+}
+
+func NewSaudeController(saudeService service.SaudeService) SaudeController {
+	return &saudeController{saudeService: saudeService}
+}
+
+// Vivo responde enquanto o processo atende requisições, sem depender do banco
+func (ctrl *saudeController) Vivo(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status": "ok",
+		"time":   time.Now().Format(time.RFC3339),
+	})
+}
+
+// Pronto responde 503 quando o banco ou um laço de segundo plano falha, ou durante o encerramento
+func (ctrl *saudeController) Pronto(c *gin.Context) {
+	relatorio := ctrl.saudeService.Prontidao(c.Request.Context())
+	status := http.StatusOK
+	if !relatorio.Pronto {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, relatorio)
+}
@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"tvtec/erros"
+	"tvtec/middleware"
+	"tvtec/models"
+	"tvtec/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookController interface {
+	ListarWebhooks(c *gin.Context)
+	ObterWebhook(c *gin.Context)
+	CriarWebhook(c *gin.Context)
+	AtualizarWebhook(c *gin.Context)
+	RemoverWebhook(c *gin.Context)
+	ListarEntregas(c *gin.Context)
+	EnviarTeste(c *gin.Context)
+}
+
+type webhookController struct {
+	webhookService service.WebhookService
+}
+
+func NewWebhookController(webhookService service.WebhookService) WebhookController {
+	return &webhookController{webhookService: webhookService}
+}
+
+// WebhookCriadoResposta é a única resposta que traz o segredo de assinatura
+type WebhookCriadoResposta struct {
+	models.Webhook
+	Segredo string `json:"segredo"`
+}
+
+func (ctrl *webhookController) ListarWebhooks(c *gin.Context) {
+	webhooks, err := ctrl.webhookService.ListarWebhooks()
+	if err != nil {
+		falhar(c, err, erros.ErrInterno.ComMensagem("Erro ao listar webhooks"))
+		return
+	}
+
+	c.JSON(http.StatusOK, webhooks)
+}
+
+func (ctrl *webhookController) ObterWebhook(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	webhook, err := ctrl.webhookService.ObterWebhook(uint(id))
+	if err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+func (ctrl *webhookController) CriarWebhook(c *gin.Context) {
+	var webhook models.Webhook
+	webhook.Ativo = true
+	if err := c.ShouldBindJSON(&webhook); err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("Dados inválidos").Envolver(err))
+		return
+	}
+
+	if err := ctrl.webhookService.CriarWebhook(&webhook); err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusCreated, WebhookCriadoResposta{Webhook: webhook, Segredo: webhook.Segredo})
+}
+
+func (ctrl *webhookController) AtualizarWebhook(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	var webhook models.Webhook
+	webhook.Ativo = true
+	if err := c.ShouldBindJSON(&webhook); err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("Dados inválidos").Envolver(err))
+		return
+	}
+
+	webhook.ID = uint(id)
+	if err := ctrl.webhookService.AtualizarWebhook(&webhook); err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+func (ctrl *webhookController) RemoverWebhook(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	if err := ctrl.webhookService.RemoverWebhook(uint(id)); err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": middleware.Traduzir(c, "Webhook removido com sucesso")})
+}
+
+// ListarEntregas mostra as 100 entregas mais recentes do webhook
+func (ctrl *webhookController) ListarEntregas(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	entregas, err := ctrl.webhookService.ListarEntregas(uint(id))
+	if err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, entregas)
+}
+
+// EnviarTeste faz uma entrega de teste imediata e devolve o resultado, com sucesso ou falha
+func (ctrl *webhookController) EnviarTeste(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(erros.ErrDadosInvalidos.ComMensagem("ID inválido"))
+		return
+	}
+
+	entrega, err := ctrl.webhookService.EnviarTeste(uint(id))
+	if err != nil {
+		falhar(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, entrega)
+}
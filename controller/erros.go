@@ -0,0 +1,14 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"tvtec/erros"
+)
+
+// falhar registra err para o middleware de erros, que escreve a resposta. Erros sem código de
+// domínio são falhas inesperadas: viram ErrInterno, ou o erro interno informado em padrao com a
+// mensagem da rota, e a causa só vai para o log. Dados inválidos são classificados na origem.
+func falhar(c *gin.Context, err error, padrao *erros.Erro) {
+	c.Error(erros.Classificar(err, padrao))
+}
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"tvtec/middleware"
+	"tvtec/models"
+	"tvtec/service"
+)
+
+type categoriaServiceStub struct {
+	service.CategoriaService
+	err error
+}
+
+func (s *categoriaServiceStub) CriarCategoria(*models.Categoria) error {
+	return s.err
+}
+
+func criarCategoria(t *testing.T, servico service.CategoriaService, corpo string) (int, middleware.RespostaErro) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.IDRequisicao(), middleware.TratarErros())
+	router.POST("/admin/categorias", NewCategoriaController(servico).CriarCategoria)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/categorias", strings.NewReader(corpo)))
+	var resposta middleware.RespostaErro
+	if err := json.Unmarshal(w.Body.Bytes(), &resposta); err != nil {
+		t.Fatalf("corpo de erro inválido: %v (%s)", err, w.Body.String())
+	}
+	return w.Code, resposta
+}
+
+// Falhas sem classificação (banco, transação) são erros internos e não expõem a causa ao cliente
+func TestFalhaNaoClassificadaViraErroInterno(t *testing.T) {
+	servico := &categoriaServiceStub{err: errors.New("pq: could not serialize access due to concurrent update")}
+	status, resposta := criarCategoria(t, servico, `{"nome":"Tecnologia"}`)
+	if status != http.StatusInternalServerError || resposta.Code != "erro_interno" {
+		t.Errorf("esperava 500 erro_interno, recebeu %d %s", status, resposta.Code)
+	}
+	if resposta.Details != nil {
+		t.Errorf("a causa vazou nos detalhes: %v", resposta.Details)
+	}
+
+	// Erros de leitura da requisição continuam sendo dados inválidos
+	status, resposta = criarCategoria(t, servico, `{"nome":`)
+	if status != http.StatusBadRequest || resposta.Code != "dados_invalidos" {
+		t.Errorf("esperava 400 dados_invalidos, recebeu %d %s", status, resposta.Code)
+	}
+}
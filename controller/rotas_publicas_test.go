@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"tvtec/docs"
+	"tvtec/middleware"
+	"tvtec/models"
+	"tvtec/repository"
+	"tvtec/service"
+)
+
+// Dados pessoais que nunca podem aparecer nas respostas públicas
+var dadosPessoais = []string{"123.456.789-09", "12345678909", "maria@example.com", "99999-0000", "1990-05-17", `"bairro"`, `"escolaridade"`, `"telefone"`}
+
+func cursoComAluno() models.Curso {
+	return models.Curso{
+		ID:          3,
+		Nome:        "Informática básica",
+		Professor:   "João",
+		Data:        models.CustomTime{Time: time.Date(2026, 11, 10, 0, 0, 0, 0, time.UTC)},
+		VagasTotais: 20,
+		Inscricoes: []models.Inscricao{{
+			ID:           1,
+			AlunoID:      7,
+			CursoID:      3,
+			Bairro:       "Centro",
+			Escolaridade: "Médio completo",
+			Aluno: models.Aluno{
+				ID:         7,
+				Nome:       "Maria da Silva",
+				CPF:        "123.456.789-09",
+				Email:      "maria@example.com",
+				Telefone:   "(21) 99999-0000",
+				DataNascto: time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC),
+			},
+		}},
+	}
+}
+
+// Os stubs embutem a interface e implementam só o que as rotas públicas usam
+type cursoServiceStub struct {
+	service.CursoService
+}
+
+func (s *cursoServiceStub) BuscarCursos(repository.FiltroCurso) ([]models.Curso, error) {
+	return []models.Curso{cursoComAluno()}, nil
+}
+
+func (s *cursoServiceStub) ObterCursoPublico(uint) (*models.Curso, error) {
+	curso := cursoComAluno()
+	return &curso, nil
+}
+
+type professorServiceStub struct {
+	service.ProfessorService
+}
+
+func (s *professorServiceStub) PerfilPublico(uint) (*models.Professor, []models.Curso, error) {
+	professor := &models.Professor{ID: 2, Nome: "João", Email: "joao@example.com", Telefone: "(21) 98888-0000"}
+	return professor, []models.Curso{cursoComAluno()}, nil
+}
+
+type alunoServiceStub struct {
+	service.AlunoService
+}
+
+// CadastrarAlunoEInscrever simula um aluno já cadastrado pelo CPF com outro email
+func (s *alunoServiceStub) CadastrarAlunoEInscrever(aluno *models.Aluno, inscricao *models.Inscricao, _ *models.Consentimento, origem string) ([]models.ConflitoCadastro, error) {
+	aluno.ID = 7
+	inscricao.ID = 1
+	inscricao.AlunoID = aluno.ID
+	inscricao.Status = models.StatusInscricaoAtiva
+	return []models.ConflitoCadastro{{
+		AlunoID:         aluno.ID,
+		Campo:           "email",
+		ValorCadastrado: "joao@example.com",
+		ValorInformado:  aluno.Email,
+		Origem:          origem,
+	}}, nil
+}
+
+type consentimentoServiceStub struct {
+	service.ConsentimentoService
+}
+
+func (s *consentimentoServiceStub) AutorizacaoInscricao(autorizaWhatsApp, versaoTermos, ip string) (*models.Consentimento, error) {
+	return &models.Consentimento{Canal: models.CanalWhatsApp, Finalidade: models.FinalidadeAvisosCursos, VersaoTermos: versaoTermos}, nil
+}
+
+func roteadorPublico(t *testing.T) *gin.Engine {
+	especificacao, err := docs.Carregar()
+	if err != nil {
+		t.Fatalf("especificação inválida: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	// As respostas também precisam seguir o contrato publicado em /openapi.json
+	router.Use(middleware.ValidacaoOpenAPI(especificacao.Rotas, true))
+
+	cursoController := NewCursoController(&cursoServiceStub{})
+	professorController := NewProfessorController(&professorServiceStub{})
+	alunoController := NewAlunoController(&alunoServiceStub{}, &consentimentoServiceStub{})
+
+	router.GET("/curso", cursoController.ListarCursos)
+	router.GET("/curso/:id", cursoController.ObterCursoPorID)
+	router.GET("/professor/:id", professorController.PerfilPublico)
+	router.POST("/aluno/inscricao", alunoController.CadastrarAlunoEInscrever)
+	return router
+}
+
+func verificarRespostaPublica(t *testing.T, router *gin.Engine, metodo, caminho, corpo string) {
+	t.Helper()
+	req := httptest.NewRequest(metodo, caminho, strings.NewReader(corpo))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code >= 300 {
+		t.Fatalf("%s %s: status %d, corpo %s", metodo, caminho, w.Code, w.Body.String())
+	}
+	for _, dado := range append(dadosPessoais, "joao@example.com", "98888-0000") {
+		if strings.Contains(w.Body.String(), dado) {
+			t.Errorf("%s %s expõe %q: %s", metodo, caminho, dado, w.Body.String())
+		}
+	}
+}
+
+func TestRotasPublicasDeCursoNaoExpoemAlunos(t *testing.T) {
+	router := roteadorPublico(t)
+	verificarRespostaPublica(t, router, http.MethodGet, "/curso", "")
+	verificarRespostaPublica(t, router, http.MethodGet, "/curso/3", "")
+	verificarRespostaPublica(t, router, http.MethodGet, "/professor/2", "")
+}
+
+func TestInscricaoPublicaNaoDevolveDadosCompletos(t *testing.T) {
+	corpo := `{
+		"nome": "Maria da Silva",
+		"cpf": "123.456.789-09",
+		"email": "maria@example.com",
+		"telefone": "(21) 99999-0000",
+		"dataNascto": "17/05/1990",
+		"curso": 3,
+		"bairro": "Centro",
+		"escolaridade": "Médio completo"
+	}`
+	verificarRespostaPublica(t, roteadorPublico(t), http.MethodPost, "/aluno/inscricao", corpo)
+}
@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"tvtec/docs"
+	"tvtec/middleware"
+	"tvtec/service"
+)
+
+type bancoStub struct {
+	err error
+}
+
+func (b *bancoStub) PingContext(context.Context) error {
+	return b.err
+}
+
+type trabalhoStub struct {
+	situacao service.SituacaoTrabalho
+}
+
+func (t *trabalhoStub) Situacao() service.SituacaoTrabalho {
+	return t.situacao
+}
+
+func roteadorSaude(t *testing.T, saudeService service.SaudeService) *gin.Engine {
+	especificacao, err := docs.Carregar()
+	if err != nil {
+		t.Fatalf("especificação inválida: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ValidacaoOpenAPI(especificacao.Rotas, true))
+
+	saudeController := NewSaudeController(saudeService)
+	router.GET("/health/live", saudeController.Vivo)
+	router.GET("/health/ready", saudeController.Pronto)
+	return router
+}
+
+func verificarStatus(t *testing.T, router *gin.Engine, caminho string, esperado int) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, caminho, nil))
+	if w.Code != esperado {
+		t.Fatalf("%s: esperava %d, recebeu %d: %s", caminho, esperado, w.Code, w.Body.String())
+	}
+	return w.Body.String()
+}
+
+func TestProntidaoDependeDoBancoEDosTrabalhos(t *testing.T) {
+	banco := &bancoStub{}
+	trabalho := &trabalhoStub{situacao: service.SituacaoTrabalho{Executando: true, Saudavel: true}}
+	saudeService := service.NewSaudeService(banco, service.TrabalhoMonitorado{Nome: "despachante_eventos", Trabalho: trabalho})
+	router := roteadorSaude(t, saudeService)
+
+	verificarStatus(t, router, "/health/ready", http.StatusOK)
+
+	banco.err = errors.New("conexão recusada")
+	corpo := verificarStatus(t, router, "/health/ready", http.StatusServiceUnavailable)
+	if !strings.Contains(corpo, "conexão recusada") {
+		t.Errorf("o motivo da falha do banco deveria aparecer: %s", corpo)
+	}
+	// A liveness não depende do banco
+	verificarStatus(t, router, "/health/live", http.StatusOK)
+
+	banco.err = nil
+	trabalho.situacao = service.SituacaoTrabalho{}
+	verificarStatus(t, router, "/health/ready", http.StatusServiceUnavailable)
+}
+
+func TestProntidaoFalhaDuranteEncerramento(t *testing.T) {
+	saudeService := service.NewSaudeService(&bancoStub{})
+	router := roteadorSaude(t, saudeService)
+
+	verificarStatus(t, router, "/health/ready", http.StatusOK)
+	saudeService.IniciarEncerramento()
+	corpo := verificarStatus(t, router, "/health/ready", http.StatusServiceUnavailable)
+	if !strings.Contains(corpo, `"encerrando":true`) {
+		t.Errorf("esperava encerrando=true: %s", corpo)
+	}
+}
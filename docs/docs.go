@@ -0,0 +1,99 @@
+// Package docs publica a especificação OpenAPI da API e a página de documentação.
+// O arquivo openapi.yaml é a fonte única dos contratos de requisição e resposta.
+package docs
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed openapi.yaml
+var especificacaoYAML []byte
+
+// Especificacao guarda o documento carregado, o roteador usado na validação e o JSON servido
+type Especificacao struct {
+	Documento *openapi3.T
+	Rotas     routers.Router
+	json      []byte
+}
+
+// Carregar lê e valida o documento embutido no binário
+func Carregar() (*Especificacao, error) {
+	// Mensagens de validação sem o esquema inteiro, que seria devolvido ao cliente
+	openapi3.SchemaErrorDetailsDisabled = true
+
+	loader := openapi3.NewLoader()
+	documento, err := loader.LoadFromData(especificacaoYAML)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler especificação OpenAPI: %w", err)
+	}
+	if err := documento.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("especificação OpenAPI inválida: %w", err)
+	}
+
+	rotas, err := legacy.NewRouter(documento)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao montar rotas da especificação: %w", err)
+	}
+
+	conteudo, err := json.Marshal(documento)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar JSON da especificação: %w", err)
+	}
+
+	return &Especificacao{Documento: documento, Rotas: rotas, json: conteudo}, nil
+}
+
+// ServirJSON responde o documento OpenAPI em /openapi.json
+func (e *Especificacao) ServirJSON(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", e.json)
+}
+
+// ServirPagina responde a página de documentação interativa, que lê /openapi.json
+func (e *Especificacao) ServirPagina(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(paginaDocumentacao))
+}
+
+// parametroGin converte :id do Gin em {id} do OpenAPI
+var parametroGin = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+
+// RotasNaoDocumentadas lista as rotas registradas no Gin que não constam da especificação
+func (e *Especificacao) RotasNaoDocumentadas(rotas gin.RoutesInfo) []string {
+	var faltantes []string
+	for _, rota := range rotas {
+		caminho := parametroGin.ReplaceAllString(rota.Path, "{$1}")
+		item := e.Documento.Paths.Find(caminho)
+		if item == nil || item.GetOperation(rota.Method) == nil {
+			faltantes = append(faltantes, rota.Method+" "+rota.Path)
+		}
+	}
+	sort.Strings(faltantes)
+	return faltantes
+}
+
+const paginaDocumentacao = `<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+  <meta charset="utf-8">
+  <title>TVTEC - Documentação da API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"time"
+
+	"tvtec/models"
+)
+
+// AlunoResposta traz contato e documentos completos apenas para administradores;
+// os demais papéis veem o nome e o CPF mascarado
+type AlunoResposta struct {
+	ID            uint       `json:"id"`
+	Nome          string     `json:"nome"`
+	CPF           string     `json:"cpf"`
+	Email         string     `json:"email,omitempty"`
+	Sexo          string     `json:"sexo,omitempty"`
+	Telefone      string     `json:"telefone,omitempty"`
+	DataNascto    *time.Time `json:"dataNascto,omitempty"`
+	Idioma        string     `json:"idioma,omitempty"`
+	Anonimizado   bool       `json:"anonimizado,omitempty"`
+	AnonimizadoEm *time.Time `json:"anonimizadoEm,omitempty"`
+}
+
+func ParaAluno(aluno *models.Aluno, papel string) AlunoResposta {
+	resposta := AlunoResposta{
+		ID:   aluno.ID,
+		Nome: aluno.Nome,
+		CPF:  MascararCPF(aluno.CPF),
+	}
+	if ehAdmin(papel) {
+		dataNascto := aluno.DataNascto
+		resposta.CPF = aluno.CPF
+		resposta.Email = aluno.Email
+		resposta.Sexo = aluno.Sexo
+		resposta.Telefone = aluno.Telefone
+		resposta.DataNascto = &dataNascto
+		resposta.Idioma = aluno.Idioma
+		resposta.Anonimizado = aluno.Anonimizado
+		resposta.AnonimizadoEm = aluno.AnonimizadoEm
+	}
+	return resposta
+}
+
+func ParaAlunos(alunos []models.Aluno, papel string) []AlunoResposta {
+	respostas := make([]AlunoResposta, len(alunos))
+	for i := range alunos {
+		respostas[i] = ParaAluno(&alunos[i], papel)
+	}
+	return respostas
+}
@@ -0,0 +1,124 @@
+package dto
+
+import (
+	"time"
+
+	"tvtec/models"
+)
+
+// CursoResumo identifica um curso dentro de outras respostas
+type CursoResumo struct {
+	ID   uint              `json:"id"`
+	Nome string            `json:"nome"`
+	Data models.CustomTime `json:"data"`
+}
+
+// CursoResposta nunca inclui inscritos para o público ou professores;
+// dados de cancelamento e a lista de inscrições são exclusivos da administração
+type CursoResposta struct {
+	ID               uint              `json:"id"`
+	Nome             string            `json:"nome"`
+	Professor        string            `json:"professor"`
+	ProfessorID      *uint             `json:"professorId"`
+	Data             models.CustomTime `json:"data"`
+	CargaHoraria     int32             `json:"cargaHoraria"`
+	Certificado      string            `json:"certificado"`
+	VagasTotais      int32             `json:"vagasTotais"`
+	VagasPreenchidas int32             `json:"vagasPreenchidas"`
+	VagasDisponiveis int32             `json:"vagasDisponiveis"`
+	HoraInicio       string            `json:"horaInicio"`
+	HoraFim          string            `json:"horaFim"`
+	Status           string            `json:"status"`
+
+	InscricoesAbertura     *time.Time `json:"inscricoesAbertura"`
+	InscricoesEncerramento *time.Time `json:"inscricoesEncerramento"`
+	SituacaoInscricoes     string     `json:"situacaoInscricoes,omitempty"`
+	MensagemInscricoes     string     `json:"mensagemInscricoes,omitempty"`
+
+	CategoriaID         *uint                       `json:"categoriaId"`
+	Categoria           *models.Categoria           `json:"categoria,omitempty"`
+	Tags                []models.Tag                `json:"tags"`
+	PreRequisitos       []CursoResumo               `json:"preRequisitos,omitempty"`
+	RegrasElegibilidade []models.RegraElegibilidade `json:"regrasElegibilidade,omitempty"`
+	SalaID              *uint                       `json:"salaId"`
+	Sala                *models.Sala                `json:"sala,omitempty"`
+
+	// Apenas administração
+	CanceladoEm        *time.Time          `json:"canceladoEm,omitempty"`
+	MotivoCancelamento string              `json:"motivoCancelamento,omitempty"`
+	Inscricoes         []InscricaoResposta `json:"inscricoes,omitempty"`
+}
+
+func ParaCursoResumo(curso *models.Curso) CursoResumo {
+	return CursoResumo{ID: curso.ID, Nome: curso.Nome, Data: curso.Data}
+}
+
+func ParaCurso(curso *models.Curso, papel string) CursoResposta {
+	resposta := CursoResposta{
+		ID:                     curso.ID,
+		Nome:                   curso.Nome,
+		Professor:              curso.Professor,
+		ProfessorID:            curso.ProfessorID,
+		Data:                   curso.Data,
+		CargaHoraria:           curso.CargaHoraria,
+		Certificado:            curso.Certificado,
+		VagasTotais:            curso.VagasTotais,
+		VagasPreenchidas:       curso.VagasPreenchidas,
+		VagasDisponiveis:       curso.VagasTotais - curso.VagasPreenchidas,
+		HoraInicio:             curso.HoraInicio,
+		HoraFim:                curso.HoraFim,
+		Status:                 curso.StatusAtual(),
+		InscricoesAbertura:     curso.InscricoesAbertura,
+		InscricoesEncerramento: curso.InscricoesEncerramento,
+		SituacaoInscricoes:     curso.SituacaoInscricoes,
+		MensagemInscricoes:     curso.MensagemInscricoes,
+		CategoriaID:            curso.CategoriaID,
+		Categoria:              curso.Categoria,
+		Tags:                   curso.Tags,
+		RegrasElegibilidade:    curso.RegrasElegibilidade,
+		SalaID:                 curso.SalaID,
+		Sala:                   curso.Sala,
+	}
+	if resposta.Tags == nil {
+		resposta.Tags = []models.Tag{}
+	}
+	for i := range curso.PreRequisitos {
+		resposta.PreRequisitos = append(resposta.PreRequisitos, ParaCursoResumo(&curso.PreRequisitos[i]))
+	}
+
+	if ehAdmin(papel) {
+		resposta.CanceladoEm = curso.CanceladoEm
+		resposta.MotivoCancelamento = curso.MotivoCancelamento
+		if len(curso.Inscricoes) > 0 {
+			resposta.Inscricoes = ParaInscricoes(curso.Inscricoes, papel)
+		}
+	}
+	return resposta
+}
+
+func ParaCursos(cursos []models.Curso, papel string) []CursoResposta {
+	respostas := make([]CursoResposta, len(cursos))
+	for i := range cursos {
+		respostas[i] = ParaCurso(&cursos[i], papel)
+	}
+	return respostas
+}
+
+// PerfilProfessor é a visão pública do instrutor, sem dados de contato
+type PerfilProfessor struct {
+	ID      uint            `json:"id"`
+	Nome    string          `json:"nome"`
+	Bio     string          `json:"bio"`
+	FotoURL string          `json:"fotoUrl"`
+	Cursos  []CursoResposta `json:"cursos"`
+}
+
+func ParaPerfilProfessor(professor *models.Professor, cursos []models.Curso) PerfilProfessor {
+	return PerfilProfessor{
+		ID:      professor.ID,
+		Nome:    professor.Nome,
+		Bio:     professor.Bio,
+		FotoURL: professor.FotoURL,
+		Cursos:  ParaCursos(cursos, PapelPublico),
+	}
+}
@@ -0,0 +1,169 @@
+package dto
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"tvtec/models"
+)
+
+// Chaves que identificam dados pessoais de alunos
+var chavesPessoais = []string{"cpf", "email", "telefone", "dataNascto", "sexo", "aluno", "alunoId", "inscricoes", "bairro", "escolaridade"}
+
+func alunoExemplo() models.Aluno {
+	return models.Aluno{
+		ID:         7,
+		Nome:       "Maria da Silva",
+		CPF:        "123.456.789-09",
+		Email:      "maria@example.com",
+		Sexo:       "F",
+		Telefone:   "(21) 99999-0000",
+		DataNascto: time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func cursoComInscritos() models.Curso {
+	aluno := alunoExemplo()
+	return models.Curso{
+		ID:          3,
+		Nome:        "Informática básica",
+		Professor:   "João",
+		Data:        models.CustomTime{Time: time.Date(2026, 11, 10, 0, 0, 0, 0, time.UTC)},
+		VagasTotais: 20,
+		Inscricoes: []models.Inscricao{{
+			ID:           1,
+			AlunoID:      aluno.ID,
+			CursoID:      3,
+			Status:       models.StatusInscricaoAtiva,
+			Bairro:       "Centro",
+			Escolaridade: "Médio completo",
+			EhPCD:        "sim",
+			Aluno:        aluno,
+		}},
+	}
+}
+
+// chavesJSON percorre o JSON e devolve todas as chaves encontradas em qualquer nível
+func chavesJSON(t *testing.T, valor interface{}) map[string]bool {
+	t.Helper()
+	dados, err := json.Marshal(valor)
+	if err != nil {
+		t.Fatalf("erro ao serializar: %v", err)
+	}
+	var generico interface{}
+	if err := json.Unmarshal(dados, &generico); err != nil {
+		t.Fatalf("erro ao ler JSON: %v", err)
+	}
+
+	chaves := map[string]bool{}
+	var visitar func(v interface{})
+	visitar = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for chave, filho := range v {
+				chaves[chave] = true
+				visitar(filho)
+			}
+		case []interface{}:
+			for _, filho := range v {
+				visitar(filho)
+			}
+		}
+	}
+	visitar(generico)
+	return chaves
+}
+
+func verificarSemDadosPessoais(t *testing.T, valor interface{}) {
+	t.Helper()
+	chaves := chavesJSON(t, valor)
+	for _, chave := range chavesPessoais {
+		if chaves[chave] {
+			t.Errorf("resposta contém a chave pessoal %q", chave)
+		}
+	}
+
+	dados, _ := json.Marshal(valor)
+	aluno := alunoExemplo()
+	for _, valorPessoal := range []string{aluno.CPF, "12345678909", aluno.Email, aluno.Telefone, aluno.Nome} {
+		if strings.Contains(string(dados), valorPessoal) {
+			t.Errorf("resposta contém o dado pessoal %q", valorPessoal)
+		}
+	}
+}
+
+func TestCursoPublicoNaoExpoeInscritos(t *testing.T) {
+	curso := cursoComInscritos()
+	verificarSemDadosPessoais(t, ParaCurso(&curso, PapelPublico))
+	verificarSemDadosPessoais(t, ParaCursos([]models.Curso{curso}, PapelPublico))
+}
+
+func TestCursoProfessorNaoExpoeInscritos(t *testing.T) {
+	curso := cursoComInscritos()
+	verificarSemDadosPessoais(t, ParaCurso(&curso, PapelProfessor))
+}
+
+func TestCursoAdminIncluiInscritos(t *testing.T) {
+	curso := cursoComInscritos()
+	resposta := ParaCurso(&curso, PapelAdmin)
+	if len(resposta.Inscricoes) != 1 || resposta.Inscricoes[0].Aluno == nil {
+		t.Fatalf("administração deveria ver os inscritos, recebeu %+v", resposta.Inscricoes)
+	}
+	if resposta.Inscricoes[0].Aluno.CPF != "123.456.789-09" {
+		t.Errorf("administração deveria ver o CPF completo, recebeu %q", resposta.Inscricoes[0].Aluno.CPF)
+	}
+}
+
+func TestPerfilProfessorSemContato(t *testing.T) {
+	professor := models.Professor{ID: 2, Nome: "João", Email: "joao@example.com", Telefone: "(21) 98888-0000", SenhaHash: "hash"}
+	perfil := ParaPerfilProfessor(&professor, []models.Curso{cursoComInscritos()})
+	verificarSemDadosPessoais(t, perfil)
+
+	dados, _ := json.Marshal(perfil)
+	for _, valor := range []string{professor.Email, professor.Telefone, professor.SenhaHash} {
+		if strings.Contains(string(dados), valor) {
+			t.Errorf("perfil público contém %q", valor)
+		}
+	}
+}
+
+func TestInscricaoPublicaSemRespostasDoFormulario(t *testing.T) {
+	curso := cursoComInscritos()
+	inscricao := curso.Inscricoes[0]
+	verificarSemDadosPessoais(t, ParaInscricao(&inscricao, PapelPublico))
+}
+
+func TestAlunoMascaradoParaNaoAdmin(t *testing.T) {
+	aluno := alunoExemplo()
+	for _, papel := range []string{PapelPublico, PapelProfessor} {
+		resposta := ParaAluno(&aluno, papel)
+		if resposta.CPF != "123.***.***-09" {
+			t.Errorf("papel %q: CPF deveria estar mascarado, recebeu %q", papel, resposta.CPF)
+		}
+		if resposta.Email != "" || resposta.Telefone != "" || resposta.DataNascto != nil {
+			t.Errorf("papel %q: contato não deveria ser exposto: %+v", papel, resposta)
+		}
+	}
+
+	admin := ParaAluno(&aluno, PapelAdmin)
+	if admin.CPF != aluno.CPF || admin.Email != aluno.Email {
+		t.Errorf("administração deveria ver os dados completos: %+v", admin)
+	}
+}
+
+func TestMascararCPF(t *testing.T) {
+	casos := map[string]string{
+		"123.456.789-09": "123.***.***-09",
+		"12345678909":    "123.***.***-09",
+		"anonimizado-15": "***",
+		"":               "***",
+		"123.456.789-0":  "***",
+	}
+	for entrada, esperado := range casos {
+		if obtido := MascararCPF(entrada); obtido != esperado {
+			t.Errorf("MascararCPF(%q) = %q, esperado %q", entrada, obtido, esperado)
+		}
+	}
+}
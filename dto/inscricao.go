@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"time"
+
+	"tvtec/models"
+)
+
+// InscricaoResposta mostra as respostas do formulário só à administração;
+// o professor recebe apenas o necessário para acessibilidade em sala
+type InscricaoResposta struct {
+	ID            uint       `json:"id"`
+	AlunoID       uint       `json:"alunoId,omitempty"`
+	CursoID       uint       `json:"cursoId"`
+	DataInscricao time.Time  `json:"dataInscricao"`
+	Status        string     `json:"status"`
+	DataConclusao *time.Time `json:"dataConclusao,omitempty"`
+
+	// Acessibilidade (administração e professor)
+	EhPCD             string `json:"ehPCD,omitempty"`
+	TipoPCD           string `json:"tipoPCD,omitempty"`
+	NecessitaElevador string `json:"necessitaElevador,omitempty"`
+	LevaNotebook      string `json:"levaNotebook,omitempty"`
+
+	// Perfil socioeconômico (apenas administração)
+	Escolaridade     string `json:"escolaridade,omitempty"`
+	Trabalhando      string `json:"trabalhando,omitempty"`
+	Bairro           string `json:"bairro,omitempty"`
+	EhCuidador       string `json:"ehCuidador,omitempty"`
+	ComoSoube        string `json:"comoSoube,omitempty"`
+	AutorizaWhatsApp string `json:"autorizaWhatsApp,omitempty"`
+
+	Aluno *AlunoResposta `json:"aluno,omitempty"`
+	Curso *CursoResumo   `json:"curso,omitempty"`
+}
+
+func ParaInscricao(inscricao *models.Inscricao, papel string) InscricaoResposta {
+	resposta := InscricaoResposta{
+		ID:            inscricao.ID,
+		CursoID:       inscricao.CursoID,
+		DataInscricao: inscricao.DataInscricao,
+		Status:        inscricao.Status,
+		DataConclusao: inscricao.DataConclusao,
+	}
+	if inscricao.Curso.ID != 0 {
+		curso := ParaCursoResumo(&inscricao.Curso)
+		resposta.Curso = &curso
+	}
+	if papel == PapelPublico {
+		return resposta
+	}
+
+	resposta.AlunoID = inscricao.AlunoID
+	resposta.EhPCD = inscricao.EhPCD
+	resposta.TipoPCD = inscricao.TipoPCD
+	resposta.NecessitaElevador = inscricao.NecessitaElevador
+	resposta.LevaNotebook = inscricao.LevaNotebook
+	if inscricao.Aluno.ID != 0 {
+		aluno := ParaAluno(&inscricao.Aluno, papel)
+		resposta.Aluno = &aluno
+	}
+
+	if ehAdmin(papel) {
+		resposta.Escolaridade = inscricao.Escolaridade
+		resposta.Trabalhando = inscricao.Trabalhando
+		resposta.Bairro = inscricao.Bairro
+		resposta.EhCuidador = inscricao.EhCuidador
+		resposta.ComoSoube = inscricao.ComoSoube
+		resposta.AutorizaWhatsApp = inscricao.AutorizaWhatsApp
+	}
+	return resposta
+}
+
+func ParaInscricoes(inscricoes []models.Inscricao, papel string) []InscricaoResposta {
+	respostas := make([]InscricaoResposta, len(inscricoes))
+	for i := range inscricoes {
+		respostas[i] = ParaInscricao(&inscricoes[i], papel)
+	}
+	return respostas
+}
@@ -0,0 +1,31 @@
+// Package dto define as respostas da API por público, para que dados pessoais
+// só cheguem a quem pode vê-los.
+package dto
+
+import "strings"
+
+// Papéis de quem recebe a resposta; rotas públicas usam PapelPublico
+const (
+	PapelPublico   = ""
+	PapelProfessor = "professor"
+	PapelAdmin     = "admin"
+)
+
+// MascararCPF mantém apenas os três primeiros e os dois últimos dígitos (ex.: 123.***.***-09)
+func MascararCPF(cpf string) string {
+	var digitos []rune
+	for _, r := range cpf {
+		if r >= '0' && r <= '9' {
+			digitos = append(digitos, r)
+		}
+	}
+	if len(digitos) != 11 {
+		// CPF anonimizado ou inválido: nada é exposto
+		return "***"
+	}
+	return string(digitos[:3]) + ".***.***-" + string(digitos[9:])
+}
+
+func ehAdmin(papel string) bool {
+	return strings.EqualFold(papel, PapelAdmin)
+}
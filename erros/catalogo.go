@@ -0,0 +1,59 @@
+package erros
+
+// Erros genéricos, usados quando não há um código mais específico
+var (
+	ErrInterno            = Novo(TipoInterno, "erro_interno", "Erro interno do servidor")
+	ErrDadosInvalidos     = Novo(TipoInvalido, "dados_invalidos", "Dados inválidos")
+	ErrArquivoMuitoGrande = Novo(TipoMuitoGrande, "arquivo_muito_grande", "Arquivo maior que o permitido")
+	ErrNaoEncontrado      = Novo(TipoNaoEncontrado, "nao_encontrado", "Registro não encontrado")
+	ErrConflito           = Novo(TipoConflito, "conflito", "A operação conflita com o estado atual do registro")
+	ErrRegraNegocio       = Novo(TipoRegraNegocio, "regra_negocio", "A operação não é permitida")
+	ErrIndisponivel       = Novo(TipoIndisponivel, "servico_indisponivel", "Serviço temporariamente indisponível")
+)
+
+// Autenticação e acesso
+var (
+	ErrNaoAutenticado          = Novo(TipoNaoAutenticado, "nao_autenticado", "Token de autenticação não fornecido")
+	ErrTokenInvalido           = Novo(TipoNaoAutenticado, "token_invalido", "Token inválido ou expirado")
+	ErrCredenciaisInvalidas    = Novo(TipoNaoAutenticado, "credenciais_invalidas", "Credenciais inválidas")
+	ErrSemPermissao            = Novo(TipoSemPermissao, "sem_permissao", "Acesso não permitido")
+	ErrIdentidadeNaoConfirmada = Novo(TipoSemPermissao, "identidade_nao_confirmada",
+		"Não foi possível confirmar a identidade do titular")
+	ErrCodigoConfirmacao = Novo(TipoSemPermissao, "codigo_confirmacao_invalido",
+		"Código de confirmação inválido ou expirado")
+	ErrMuitasTentativas = Novo(TipoMuitasTentativas, "muitas_tentativas",
+		"Muitas tentativas sem sucesso. Aguarde alguns minutos e tente novamente")
+)
+
+// Registros não encontrados
+var (
+	ErrAlunoNaoEncontrado     = Novo(TipoNaoEncontrado, "aluno_nao_encontrado", "Aluno não encontrado")
+	ErrCursoNaoEncontrado     = Novo(TipoNaoEncontrado, "curso_nao_encontrado", "Curso não encontrado")
+	ErrInscricaoNaoEncontrada = Novo(TipoNaoEncontrado, "inscricao_nao_encontrada", "Inscrição não encontrada")
+	ErrCategoriaNaoEncontrada = Novo(TipoNaoEncontrado, "categoria_nao_encontrada", "Categoria não encontrada")
+	ErrLocalNaoEncontrado     = Novo(TipoNaoEncontrado, "local_nao_encontrado", "Local não encontrado")
+	ErrSalaNaoEncontrada      = Novo(TipoNaoEncontrado, "sala_nao_encontrada", "Sala não encontrada")
+	ErrProfessorNaoEncontrado = Novo(TipoNaoEncontrado, "professor_nao_encontrado", "Professor não encontrado")
+	ErrWebhookNaoEncontrado   = Novo(TipoNaoEncontrado, "webhook_nao_encontrado", "Webhook não encontrado")
+	ErrTermoNaoEncontrado     = Novo(TipoNaoEncontrado, "termo_nao_encontrado", "Nenhum termo de consentimento publicado")
+	ErrConflitoNaoEncontrado  = Novo(TipoNaoEncontrado, "conflito_cadastro_nao_encontrado", "Conflito de cadastro não encontrado")
+)
+
+// Conflitos com o estado atual
+var (
+	ErrCadastroDuplicado  = Novo(TipoConflito, "cadastro_duplicado", "Já existe um cadastro com estes dados")
+	ErrInscricaoDuplicada = Novo(TipoConflito, "inscricao_duplicada", "O aluno já está inscrito neste curso")
+	ErrSemVagas           = Novo(TipoConflito, "sem_vagas", "Não há vagas disponíveis para este curso")
+	ErrRegistroEmUso      = Novo(TipoConflito, "registro_em_uso", "O registro está em uso e não pode ser removido")
+	ErrSituacaoInvalida   = Novo(TipoConflito, "transicao_situacao_invalida", "A situação atual não permite esta operação")
+)
+
+// Regras de inscrição
+var (
+	ErrInscricoesFechadas = Novo(TipoRegraNegocio, "inscricoes_fechadas", "O curso não está recebendo inscrições")
+	ErrLimiteInscricoes   = Novo(TipoRegraNegocio, "limite_inscricoes", "O aluno atingiu o limite de inscrições ativas")
+	ErrConflitoHorario    = Novo(TipoRegraNegocio, "conflito_horario", "O aluno já está inscrito em outro curso no mesmo horário")
+	ErrPreRequisitos      = Novo(TipoRegraNegocio, "pre_requisitos_pendentes", "O aluno ainda não concluiu os cursos exigidos")
+	ErrAlunoInelegivel    = Novo(TipoRegraNegocio, "aluno_inelegivel", "O aluno não atende aos critérios do curso")
+	ErrDadosEliminados    = Novo(TipoRegraNegocio, "dados_eliminados", "Os dados deste aluno foram eliminados (LGPD)")
+)
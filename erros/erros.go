@@ -0,0 +1,111 @@
+// Package erros define os erros de domínio da API. Cada erro tem um código estável, usado
+// pelos clientes para tratar a falha, e um tipo, que o middleware de erros converte no status HTTP.
+package erros
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+
+	"tvtec/i18n"
+)
+
+// Tipo agrupa os erros pela natureza da falha
+type Tipo int
+
+const (
+	TipoInterno          Tipo = iota // falha inesperada (500)
+	TipoInvalido                     // dados enviados inválidos (400)
+	TipoMuitoGrande                  // corpo ou arquivo acima do limite (413)
+	TipoNaoAutenticado               // credenciais ausentes ou inválidas (401)
+	TipoSemPermissao                 // autenticado, mas sem acesso (403)
+	TipoNaoEncontrado                // recurso inexistente (404)
+	TipoConflito                     // conflita com o estado atual: duplicado, sem vagas, em uso (409)
+	TipoRegraNegocio                 // recusado por uma regra do domínio: período, limites, pré-requisitos (422)
+	TipoIndisponivel                 // dependência fora do ar (503)
+	TipoMuitasTentativas             // tentativas demais em pouco tempo (429)
+)
+
+// Erro é um erro de domínio. Os erros do catálogo são valores base: use ComMensagem, ComDetalhes
+// e Envolver para obter uma cópia com o contexto da falha; errors.Is compara pelo código.
+// Mensagem fica em português; MensagemEm traduz para o idioma do cliente.
+type Erro struct {
+	Tipo     Tipo
+	Codigo   string
+	Mensagem string
+	Detalhes interface{}
+	causa    error
+	texto    i18n.Mensagem
+}
+
+// Novo cria um erro de domínio; os códigos usam snake_case e não mudam depois de publicados
+func Novo(tipo Tipo, codigo, mensagem string) *Erro {
+	return &Erro{Tipo: tipo, Codigo: codigo, Mensagem: mensagem, texto: i18n.NovaMensagem(mensagem)}
+}
+
+func (e *Erro) Error() string {
+	if e.causa != nil {
+		return e.Mensagem + ": " + e.causa.Error()
+	}
+	return e.Mensagem
+}
+
+func (e *Erro) Unwrap() error {
+	return e.causa
+}
+
+// Is considera iguais os erros com o mesmo código, de modo que errors.Is(err, erros.ErrSemVagas)
+// reconhece as cópias com outra mensagem ou detalhes
+func (e *Erro) Is(alvo error) bool {
+	outro, ok := alvo.(*Erro)
+	return ok && outro.Codigo == e.Codigo
+}
+
+// ComMensagem devolve uma cópia com a mensagem formatada
+func (e *Erro) ComMensagem(formato string, argumentos ...interface{}) *Erro {
+	copia := *e
+	copia.texto = i18n.NovaMensagem(formato, argumentos...)
+	copia.Mensagem = copia.texto.String()
+	return &copia
+}
+
+// MensagemEm devolve a mensagem traduzida para o idioma pedido
+func (e *Erro) MensagemEm(idioma i18n.Idioma) string {
+	return e.texto.Em(idioma)
+}
+
+// ComDetalhes devolve uma cópia com dados estruturados para o cliente (ex.: regras não atendidas)
+func (e *Erro) ComDetalhes(detalhes interface{}) *Erro {
+	copia := *e
+	copia.Detalhes = detalhes
+	return &copia
+}
+
+// Envolver devolve uma cópia que guarda err como causa. A causa de erros internos só vai para o log;
+// nos demais ela aparece nos detalhes quando não há outros.
+func (e *Erro) Envolver(err error) *Erro {
+	copia := *e
+	copia.causa = err
+	return &copia
+}
+
+// Causa devolve o erro envolvido, se houver
+func (e *Erro) Causa() error {
+	return e.causa
+}
+
+// Classificar devolve o erro de domínio contido em err. Registros não encontrados pelo GORM viram
+// ErrNaoEncontrado; qualquer outro erro é envolvido por padrao, ou por ErrInterno quando padrao é nil.
+func Classificar(err error, padrao *Erro) *Erro {
+	var dominio *Erro
+	if errors.As(err, &dominio) {
+		return dominio
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNaoEncontrado.Envolver(err)
+	}
+	if padrao == nil {
+		padrao = ErrInterno
+	}
+	return padrao.Envolver(err)
+}
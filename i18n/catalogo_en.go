@@ -0,0 +1,271 @@
+package i18n
+
+// ingles traduz para o inglês as mensagens escritas em português
+var ingles = map[string]string{
+	// Erros de domínio (erros/catalogo.go)
+	"Erro interno do servidor":                                                "Internal server error",
+	"Dados inválidos":                                                         "Invalid data",
+	"Arquivo maior que o permitido":                                           "File exceeds the allowed size",
+	"Registro não encontrado":                                                 "Record not found",
+	"A operação conflita com o estado atual do registro":                      "The operation conflicts with the current state of the record",
+	"A operação não é permitida":                                              "The operation is not allowed",
+	"Serviço temporariamente indisponível":                                    "Service temporarily unavailable",
+	"Token de autenticação não fornecido":                                     "Authentication token not provided",
+	"Token inválido ou expirado":                                              "Invalid or expired token",
+	"Credenciais inválidas":                                                   "Invalid credentials",
+	"Acesso não permitido":                                                    "Access not allowed",
+	"Não foi possível confirmar a identidade do titular":                      "Could not confirm the identity of the data subject",
+	"Código de confirmação inválido ou expirado":                              "Invalid or expired confirmation code",
+	"Muitas tentativas sem sucesso. Aguarde alguns minutos e tente novamente": "Too many failed attempts. Wait a few minutes and try again",
+	"Aluno não encontrado":                                                    "Student not found",
+	"Curso não encontrado":                                                    "Course not found",
+	"Inscrição não encontrada":                                                "Enrollment not found",
+	"Categoria não encontrada":                                                "Category not found",
+	"Local não encontrado":                                                    "Venue not found",
+	"Sala não encontrada":                                                     "Room not found",
+	"Professor não encontrado":                                                "Instructor not found",
+	"Webhook não encontrado":                                                  "Webhook not found",
+	"Nenhum termo de consentimento publicado":                                 "No consent terms have been published",
+	"Conflito de cadastro não encontrado":                                     "Registration conflict not found",
+	"Já existe um cadastro com estes dados":                                   "A record with this data already exists",
+	"O aluno já está inscrito neste curso":                                    "The student is already enrolled in this course",
+	"Não há vagas disponíveis para este curso":                                "There are no seats available for this course",
+	"O registro está em uso e não pode ser removido":                          "The record is in use and cannot be removed",
+	"A situação atual não permite esta operação":                              "The current status does not allow this operation",
+	"O curso não está recebendo inscrições":                                   "The course is not accepting enrollments",
+	"O aluno atingiu o limite de inscrições ativas":                           "The student has reached the limit of active enrollments",
+	"O aluno já está inscrito em outro curso no mesmo horário":                "The student is already enrolled in another course at the same time",
+	"O aluno ainda não concluiu os cursos exigidos":                           "The student has not yet completed the required courses",
+	"O aluno não atende aos critérios do curso":                               "The student does not meet the course criteria",
+	"Os dados deste aluno foram eliminados (LGPD)":                            "This student's data has been erased (LGPD)",
+
+	// Autenticação e requisições
+	"Autorização necessária":                             "Authorization required",
+	"Formato de autorização inválido":                    "Invalid authorization format",
+	"Acesso negado: requer perfil de professor":          "Access denied: instructor profile required",
+	"Acesso negado: requer privilégios de administrador": "Access denied: administrator privileges required",
+	"Token de métricas inválido":                         "Invalid metrics token",
+	"Dados de login inválidos":                           "Invalid login data",
+	"Falha ao gerar token":                               "Failed to generate token",
+	"Requisição fora da especificação da API":            "Request does not match the API specification",
+	"Resposta fora da especificação da API":              "Response does not match the API specification",
+	"Link de calendário inválido":                        "Invalid calendar link",
+	"Link de descadastro inválido":                       "Invalid unsubscribe link",
+
+	// Identificadores e formatos
+	"ID inválido":                                                      "Invalid ID",
+	"ID de aluno inválido":                                             "Invalid student ID",
+	"ID de curso inválido":                                             "Invalid course ID",
+	"ID do curso inválido":                                             "Invalid course ID",
+	"ID de inscrição inválido":                                         "Invalid enrollment ID",
+	"ID de sala inválido":                                              "Invalid room ID",
+	"Dados de formulário inválidos":                                    "Invalid form data",
+	"Data inválida":                                                    "Invalid date",
+	"Data de nascimento informada inválida":                            "Invalid date of birth",
+	"Formato de data inválido. Use DD/MM/AAAA":                         "Invalid date format. Use DD/MM/YYYY",
+	"Formato de data inválido em %s. Use DD/MM/AAAA":                   "Invalid date format in %s. Use DD/MM/YYYY",
+	"Formato inválido em inscricoesAbertura. Use DD/MM/AAAA HH:MM":     "Invalid format in inscricoesAbertura. Use DD/MM/YYYY HH:MM",
+	"Formato inválido em inscricoesEncerramento. Use DD/MM/AAAA HH:MM": "Invalid format in inscricoesEncerramento. Use DD/MM/YYYY HH:MM",
+	"Formato inválido. Use json ou pdf":                                "Invalid format. Use json or pdf",
+	"Informe horaInicio e horaFim no formato HH:MM":                    "Provide horaInicio and horaFim in HH:MM format",
+	"Ordenação inválida":                                               "Invalid sort order",
+	"Ordenação inválida. Use data, -data, nome, -nome ou vagas":        "Invalid sort order. Use data, -data, nome, -nome or vagas",
+	"Situação inválida %q":                                             "Invalid status %q",
+	"Categoria inválida":                                               "Invalid category",
+	"Modo inválido. Use simulacao ou efetivar":                         "Invalid mode. Use simulacao or efetivar",
+
+	// Alunos e inscrições
+	"Nome do aluno é obrigatório":                                       "Student name is required",
+	"Email do aluno é obrigatório":                                      "Student email is required",
+	"Nome, CPF e email são campos obrigatórios":                         "Name, CPF and email are required fields",
+	"CPF, email e data de nascimento são obrigatórios":                  "CPF, email and date of birth are required",
+	"CPF já cadastrado":                                                 "CPF already registered",
+	"Email já cadastrado":                                               "Email already registered",
+	"Já existe um aluno cadastrado com este email":                      "A student with this email is already registered",
+	"O CPF informado pertence ao aluno %d; mescle os cadastros":         "The CPF provided belongs to student %d; merge the records",
+	"O email informado pertence ao aluno %d; mescle os cadastros":       "The email provided belongs to student %d; merge the records",
+	"Quando marcado como PCD, o tipo de deficiência deve ser informado": "When marked as a person with disability, the type of disability must be provided",
+	"É necessário selecionar um curso":                                  "A course must be selected",
+	"Aluno e curso são obrigatórios para uma inscrição":                 "Student and course are required for an enrollment",
+	"Aluno é obrigatório para registrar consentimento":                  "A student is required to record consent",
+	"Não é possível remover aluno com inscrições ativas":                "Cannot remove a student with active enrollments",
+	"Não há vagas preenchidas para decrementar":                         "There are no filled seats to decrement",
+	"A inscrição %d não pertence à turma deste curso":                   "Enrollment %d does not belong to this course's class",
+	"Falha ao adicionar aluno ao curso":                                 "Failed to add the student to the course",
+	"Falha ao atualizar aluno":                                          "Failed to update the student",
+	"Falha ao cadastrar aluno e inscrever no curso":                     "Failed to register the student and enroll them in the course",
+	"Falha ao cancelar inscrição":                                       "Failed to cancel the enrollment",
+	"Falha ao concluir inscrição":                                       "Failed to complete the enrollment",
+	"Não é possível concluir uma inscrição com situação %s":             "Cannot complete an enrollment with status %s",
+	"Não é possível concluir a inscrição antes do início do curso":      "Cannot complete the enrollment before the course starts",
+	"Falha ao criar inscrição":                                          "Failed to create the enrollment",
+	"Falha ao remover aluno":                                            "Failed to remove the student",
+	"Falha ao recuperar alunos":                                         "Failed to retrieve students",
+	"Falha ao recuperar inscrições":                                     "Failed to retrieve enrollments",
+	"Falha ao recuperar inscrições detalhadas":                          "Failed to retrieve detailed enrollments",
+	"Falha ao recuperar inscrições do aluno":                            "Failed to retrieve the student's enrollments",
+	"Falha ao recuperar inscrições do curso":                            "Failed to retrieve the course's enrollments",
+
+	// Regras de inscrição
+	"O curso não está recebendo inscrições (situação: %s)":                            "The course is not accepting enrollments (status: %s)",
+	"As inscrições para este curso abrem em %s":                                       "Enrollment for this course opens on %s",
+	"As inscrições para este curso estão encerradas":                                  "Enrollment for this course is closed",
+	"O aluno precisa concluir antes: %s":                                              "The student must first complete: %s",
+	"O aluno já possui %d inscrições ativas, o máximo permitido é %d":                 "The student already has %d active enrollments, the maximum allowed is %d",
+	"O aluno já possui %d inscrições ativas em cursos de %s, o máximo permitido é %d": "The student already has %d active enrollments in %s courses, the maximum allowed is %d",
+	"O aluno não atende aos critérios do curso: %s":                                   "The student does not meet the course criteria: %s",
+	"idade mínima de %s anos na data do curso":                                        "minimum age of %s on the course date",
+	"idade máxima de %s anos na data do curso":                                        "maximum age of %s on the course date",
+	"%s deve ser: %s":     "%s must be: %s",
+	"%s não pode ser: %s": "%s cannot be: %s",
+	"O limite de inscrições ativas não pode ser negativo":   "The active enrollment limit cannot be negative",
+	"Regra %d: ao menos um valor deve ser informado":        "Rule %d: at least one value must be provided",
+	"Regra %d: idade aceita apenas os operadores min e max": "Rule %d: age only accepts the min and max operators",
+	"Regra %d: idade deve ser um número inteiro":            "Rule %d: age must be an integer",
+	"Regra %d: %s aceita apenas os operadores em e nao_em":  "Rule %d: %s only accepts the em and nao_em operators",
+	"Regra %d: campo inválido %q":                           "Rule %d: invalid field %q",
+
+	// Cursos
+	"Informe o professor do curso":                                                     "Provide the course instructor",
+	"A hora de término deve ser posterior à hora de início":                            "The end time must be after the start time",
+	"O encerramento das inscrições deve ser posterior à abertura":                      "Enrollment closing must be after opening",
+	"O encerramento das inscrições não pode ser posterior ao início do curso":          "Enrollment closing cannot be after the course starts",
+	"O número de vagas totais não pode ser menor que o número de vagas já preenchidas": "The total number of seats cannot be lower than the number of seats already filled",
+	"O número de vagas (%d) excede a capacidade da sala %q (%d)":                       "The number of seats (%d) exceeds the capacity of room %q (%d)",
+	"Um curso novo deve ser criado como rascunho ou publicado":                         "A new course must be created as draft or published",
+	"Um curso não pode ser pré-requisito de si mesmo":                                  "A course cannot be a prerequisite of itself",
+	"O curso %d já depende deste curso e não pode ser seu pré-requisito":               "Course %d already depends on this course and cannot be its prerequisite",
+	"Pré-requisito não encontrado":                                                     "Prerequisite not found",
+	"Não é possível cancelar um curso com situação %s":                                 "Cannot cancel a course with status %s",
+	"Não é possível passar o curso de %s para %s":                                      "Cannot change the course from %s to %s",
+	"O curso possui inscrições; cancele o curso em vez de removê-lo":                   "The course has enrollments; cancel the course instead of removing it",
+	"Curso não encontrado entre os cursos do professor":                                "Course not found among the instructor's courses",
+	"Erro ao alterar situação do curso":                                                "Failed to change the course status",
+	"Erro ao atualizar curso":                                                          "Failed to update the course",
+	"Erro ao cancelar curso":                                                           "Failed to cancel the course",
+	"Erro ao criar curso":                                                              "Failed to create the course",
+	"Erro ao listar cursos":                                                            "Failed to list courses",
+	"Erro ao gerar calendário":                                                         "Failed to generate the calendar",
+	"abre em %s":                                                                       "opens on %s",
+	"aberto":                                                                           "open",
+	"aberto até %s":                                                                    "open until %s",
+	"encerrado":                                                                        "closed",
+	"cancelado":                                                                        "canceled",
+
+	// Presença e relatórios
+	"Informe ao menos uma presença":                                 "Provide at least one attendance record",
+	"Não é possível registrar presença em data futura":              "Cannot record attendance for a future date",
+	"Não é possível registrar presença em um curso com situação %s": "Cannot record attendance for a course with status %s",
+	"Erro ao registrar presenças":                                   "Failed to record attendance",
+	"A data final deve ser posterior à data inicial":                "The end date must be after the start date",
+	"Nenhum dado fornecido para gerar relatório":                    "No data provided to generate the report",
+	"Falha ao gerar relatório":                                      "Failed to generate the report",
+	"Falha ao processar dados do relatório":                         "Failed to process the report data",
+	"Erro ao gerar PDF":                                             "Failed to generate the PDF",
+	"Falha ao gerar PDF":                                            "Failed to generate the PDF",
+
+	// Categorias, locais, professores e webhooks
+	"Nome da categoria é obrigatório":                                 "Category name is required",
+	"Já existe uma categoria com este nome":                           "A category with this name already exists",
+	"Erro ao listar categorias":                                       "Failed to list categories",
+	"Nome e endereço do local são obrigatórios":                       "Venue name and address are required",
+	"Nome da sala é obrigatório":                                      "Room name is required",
+	"A capacidade da sala deve ser maior que zero":                    "Room capacity must be greater than zero",
+	"O curso %q oferece %d vagas, acima da nova capacidade da sala":   "Course %q offers %d seats, above the new room capacity",
+	"Não é possível remover local com salas cadastradas":              "Cannot remove a venue with registered rooms",
+	"Não é possível remover sala vinculada a cursos":                  "Cannot remove a room linked to courses",
+	"Erro ao listar locais":                                           "Failed to list venues",
+	"Nome e email do professor são obrigatórios":                      "Instructor name and email are required",
+	"Já existe um professor com este email":                           "An instructor with this email already exists",
+	"A senha deve ter pelo menos 8 caracteres":                        "The password must have at least 8 characters",
+	"A senha deve ter no máximo 72 bytes":                             "The password must be at most 72 bytes",
+	"Erro ao listar professores":                                      "Failed to list instructors",
+	"URL do webhook inválida. Use um endereço http ou https completo": "Invalid webhook URL. Use a complete http or https address",
+	"Informe ao menos um evento para o webhook":                       "Provide at least one event for the webhook",
+	"Evento desconhecido: %s":                                         "Unknown event: %s",
+	"Erro ao listar webhooks":                                         "Failed to list webhooks",
+
+	// Consentimento e LGPD
+	"Canal de comunicação inválido: %s":                 "Invalid communication channel: %s",
+	"Finalidade inválida: %s":                           "Invalid purpose: %s",
+	"Versão e texto dos termos são obrigatórios":        "Terms version and text are required",
+	"Versão dos termos não encontrada":                  "Terms version not found",
+	"Versão dos termos desconhecida: %s":                "Unknown terms version: %s",
+	"Já existe um termo publicado com esta versão":      "Terms with this version have already been published",
+	"Falha ao publicar termo":                           "Failed to publish the terms",
+	"Falha ao registrar consentimento":                  "Failed to record consent",
+	"Falha ao recuperar consentimentos":                 "Failed to retrieve consents",
+	"Falha ao recuperar consentimentos do aluno":        "Failed to retrieve the student's consents",
+	"Falha ao recuperar conflitos de cadastro do aluno": "Failed to retrieve the student's registration conflicts",
+	"Falha ao recuperar fusões de cadastro do aluno":    "Failed to retrieve the student's registration merges",
+	"Falha ao processar descadastro":                    "Failed to process the unsubscribe request",
+	"Falha ao eliminar dados do aluno":                  "Failed to erase the student's data",
+	"Falha ao exportar dados do aluno":                  "Failed to export the student's data",
+	"Falha ao recuperar histórico de solicitações":      "Failed to retrieve the request history",
+	"Falha ao recuperar solicitações":                   "Failed to retrieve requests",
+
+	// Duplicidades e importação
+	"Aluno duplicado não encontrado":                             "Duplicate student not found",
+	"Aluno mantido não encontrado":                               "Kept student not found",
+	"Alunos com dados eliminados (LGPD) não podem ser mesclados": "Students whose data was erased (LGPD) cannot be merged",
+	"Não é possível mesclar um aluno com ele mesmo":              "Cannot merge a student with itself",
+	"Campo de conflito desconhecido: %s":                         "Unknown conflict field: %s",
+	"Conflito já resolvido":                                      "Conflict already resolved",
+	"Situação de conflito inválida":                              "Invalid conflict status",
+	"Erro ao buscar alunos duplicados":                           "Failed to search for duplicate students",
+	"Erro ao listar fusões de alunos":                            "Failed to list student merges",
+	"Falha ao mesclar alunos":                                    "Failed to merge the students",
+	"Falha ao resolver conflito":                                 "Failed to resolve the conflict",
+	"Arquivo da planilha é obrigatório":                          "The spreadsheet file is required",
+	"Formato de arquivo não suportado. Envie CSV ou XLSX":        "Unsupported file format. Upload CSV or XLSX",
+	"Planilha maior que 5MB":                                     "Spreadsheet larger than 5MB",
+	"Arquivo XLSX inválido":                                      "Invalid XLSX file",
+	"Planilha inválida":                                          "Invalid spreadsheet",
+	"Planilha sem abas":                                          "Spreadsheet has no sheets",
+	"Primeira aba da planilha não encontrada":                    "First sheet of the spreadsheet not found",
+	"Planilha com referência de texto inválida":                  "Spreadsheet with an invalid text reference",
+	"A planilha precisa de um cabeçalho e ao menos uma linha":    "The spreadsheet needs a header and at least one row",
+	"A planilha tem mais de %d linhas":                           "The spreadsheet has more than %d rows",
+	"Mapeamento de colunas inválido":                             "Invalid column mapping",
+	"Campo de mapeamento desconhecido: %s":                       "Unknown mapping field: %s",
+	"Coluna %q não encontrada na planilha":                       "Column %q not found in the spreadsheet",
+	"Colunas obrigatórias ausentes: %s":                          "Missing required columns: %s",
+	"Erro ao abrir planilha":                                     "Failed to open the spreadsheet",
+	"Erro ao ler planilha":                                       "Failed to read the spreadsheet",
+	"Erro ao importar planilha":                                  "Failed to import the spreadsheet",
+
+	// Respostas de sucesso
+	"Aluno cadastrado e inscrito com sucesso":                   "Student registered and enrolled successfully",
+	"Aluno removido com sucesso":                                "Student removed successfully",
+	"Aluno adicionado ao curso com sucesso":                     "Student added to the course successfully",
+	"Inscrição cancelada com sucesso":                           "Enrollment canceled successfully",
+	"Relatório gerado com sucesso":                              "Report generated successfully",
+	"Curso removido com sucesso":                                "Course removed successfully",
+	"Categoria removida com sucesso":                            "Category removed successfully",
+	"Local removido com sucesso":                                "Venue removed successfully",
+	"Sala removida com sucesso":                                 "Room removed successfully",
+	"Professor removido com sucesso":                            "Instructor removed successfully",
+	"Senha definida com sucesso":                                "Password set successfully",
+	"Webhook removido com sucesso":                              "Webhook removed successfully",
+	"Dados pessoais do aluno anonimizados com sucesso":          "Student personal data anonymized successfully",
+	"Enviamos um código de confirmação para o email cadastrado": "We sent a confirmation code to the registered email",
+	"Falha ao registrar a solicitação":                          "Failed to record the request",
+	"Não foi possível enviar o código de confirmação":           "Could not send the confirmation code",
+	"Confirmação do pedido sobre seus dados pessoais":           "Confirmation of the request about your personal data",
+	"Olá, %s.\n\nRecebemos um pedido para exportar os seus dados pessoais. Para confirmá-lo, informe o código %s.":               "Hello, %s.\n\nWe received a request to export your personal data. To confirm it, enter the code %s.",
+	"Olá, %s.\n\nRecebemos um pedido para alterar os seus consentimentos de comunicação. Para confirmá-lo, informe o código %s.": "Hello, %s.\n\nWe received a request to change your communication consents. To confirm it, enter the code %s.",
+	"Olá, %s.\n\nRecebemos um pedido para eliminar os seus dados pessoais. Para confirmá-lo, informe o código %s.":               "Hello, %s.\n\nWe received a request to erase your personal data. To confirm it, enter the code %s.",
+	"\nO código vale por %d minutos e só pode ser usado uma vez. Se você não fez este pedido, ignore esta mensagem.":             "\nThe code is valid for %d minutes and can only be used once. If you did not make this request, ignore this message.",
+	"Você não receberá mais mensagens por este canal":                                                                            "You will no longer receive messages through this channel",
+
+	// Notificações
+	"Curso cancelado: %s": "Course canceled: %s",
+	"Olá, %s.\n\nInformamos que o curso %q, previsto para %s, foi cancelado pela organização.": "Hello, %s.\n\nWe would like to inform you that the course %q, scheduled for %s, has been canceled by the organizers.",
+	"\nMotivo: %s": "\nReason: %s",
+	"\n\nSua inscrição continua registrada em nosso histórico. Pedimos desculpas pelo transtorno.": "\n\nYour enrollment remains in our records. We apologize for the inconvenience.",
+	"Link do seu calendário de cursos": "Your course calendar link",
+	"Olá, %s.\n\nEste é o link pessoal do seu calendário de cursos, para assinar no aplicativo de agenda:\n%s\n\nNão compartilhe este link. Um novo pedido gera outro link e desativa este.": "Hello, %s.\n\nThis is the personal link to your course calendar, to subscribe in your calendar app:\n%s\n\nDo not share this link. A new request generates another link and disables this one.",
+	"Não foi possível enviar o link do calendário":          "Could not send the calendar link",
+	"Enviamos o link do calendário para o email cadastrado": "We sent the calendar link to the registered email",
+	"Link do calendário revogado":                           "Calendar link revoked",
+}
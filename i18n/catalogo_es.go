@@ -0,0 +1,271 @@
+package i18n
+
+// espanhol traduz para o espanhol as mensagens escritas em português
+var espanhol = map[string]string{
+	// Erros de domínio (erros/catalogo.go)
+	"Erro interno do servidor":                                                "Error interno del servidor",
+	"Dados inválidos":                                                         "Datos inválidos",
+	"Arquivo maior que o permitido":                                           "Archivo mayor que el permitido",
+	"Registro não encontrado":                                                 "Registro no encontrado",
+	"A operação conflita com o estado atual do registro":                      "La operación entra en conflicto con el estado actual del registro",
+	"A operação não é permitida":                                              "La operación no está permitida",
+	"Serviço temporariamente indisponível":                                    "Servicio temporalmente no disponible",
+	"Token de autenticação não fornecido":                                     "Token de autenticación no proporcionado",
+	"Token inválido ou expirado":                                              "Token inválido o expirado",
+	"Credenciais inválidas":                                                   "Credenciales inválidas",
+	"Acesso não permitido":                                                    "Acceso no permitido",
+	"Não foi possível confirmar a identidade do titular":                      "No fue posible confirmar la identidad del titular",
+	"Código de confirmação inválido ou expirado":                              "Código de confirmación inválido o caducado",
+	"Muitas tentativas sem sucesso. Aguarde alguns minutos e tente novamente": "Demasiados intentos fallidos. Espere unos minutos e inténtelo de nuevo",
+	"Aluno não encontrado":                                                    "Alumno no encontrado",
+	"Curso não encontrado":                                                    "Curso no encontrado",
+	"Inscrição não encontrada":                                                "Inscripción no encontrada",
+	"Categoria não encontrada":                                                "Categoría no encontrada",
+	"Local não encontrado":                                                    "Lugar no encontrado",
+	"Sala não encontrada":                                                     "Sala no encontrada",
+	"Professor não encontrado":                                                "Profesor no encontrado",
+	"Webhook não encontrado":                                                  "Webhook no encontrado",
+	"Nenhum termo de consentimento publicado":                                 "No hay términos de consentimiento publicados",
+	"Conflito de cadastro não encontrado":                                     "Conflicto de registro no encontrado",
+	"Já existe um cadastro com estes dados":                                   "Ya existe un registro con estos datos",
+	"O aluno já está inscrito neste curso":                                    "El alumno ya está inscrito en este curso",
+	"Não há vagas disponíveis para este curso":                                "No hay plazas disponibles para este curso",
+	"O registro está em uso e não pode ser removido":                          "El registro está en uso y no se puede eliminar",
+	"A situação atual não permite esta operação":                              "El estado actual no permite esta operación",
+	"O curso não está recebendo inscrições":                                   "El curso no está recibiendo inscripciones",
+	"O aluno atingiu o limite de inscrições ativas":                           "El alumno alcanzó el límite de inscripciones activas",
+	"O aluno já está inscrito em outro curso no mesmo horário":                "El alumno ya está inscrito en otro curso en el mismo horario",
+	"O aluno ainda não concluiu os cursos exigidos":                           "El alumno aún no completó los cursos requeridos",
+	"O aluno não atende aos critérios do curso":                               "El alumno no cumple los criterios del curso",
+	"Os dados deste aluno foram eliminados (LGPD)":                            "Los datos de este alumno fueron eliminados (LGPD)",
+
+	// Autenticação e requisições
+	"Autorização necessária":                             "Autorización requerida",
+	"Formato de autorização inválido":                    "Formato de autorización inválido",
+	"Acesso negado: requer perfil de professor":          "Acceso denegado: requiere perfil de profesor",
+	"Acesso negado: requer privilégios de administrador": "Acceso denegado: requiere privilegios de administrador",
+	"Token de métricas inválido":                         "Token de métricas inválido",
+	"Dados de login inválidos":                           "Datos de inicio de sesión inválidos",
+	"Falha ao gerar token":                               "Error al generar el token",
+	"Requisição fora da especificação da API":            "Solicitud fuera de la especificación de la API",
+	"Resposta fora da especificação da API":              "Respuesta fuera de la especificación de la API",
+	"Link de calendário inválido":                        "Enlace de calendario inválido",
+	"Link de descadastro inválido":                       "Enlace de baja inválido",
+
+	// Identificadores e formatos
+	"ID inválido":                                                      "ID inválido",
+	"ID de aluno inválido":                                             "ID de alumno inválido",
+	"ID de curso inválido":                                             "ID de curso inválido",
+	"ID do curso inválido":                                             "ID del curso inválido",
+	"ID de inscrição inválido":                                         "ID de inscripción inválido",
+	"ID de sala inválido":                                              "ID de sala inválido",
+	"Dados de formulário inválidos":                                    "Datos de formulario inválidos",
+	"Data inválida":                                                    "Fecha inválida",
+	"Data de nascimento informada inválida":                            "Fecha de nacimiento informada inválida",
+	"Formato de data inválido. Use DD/MM/AAAA":                         "Formato de fecha inválido. Use DD/MM/AAAA",
+	"Formato de data inválido em %s. Use DD/MM/AAAA":                   "Formato de fecha inválido en %s. Use DD/MM/AAAA",
+	"Formato inválido em inscricoesAbertura. Use DD/MM/AAAA HH:MM":     "Formato inválido en inscricoesAbertura. Use DD/MM/AAAA HH:MM",
+	"Formato inválido em inscricoesEncerramento. Use DD/MM/AAAA HH:MM": "Formato inválido en inscricoesEncerramento. Use DD/MM/AAAA HH:MM",
+	"Formato inválido. Use json ou pdf":                                "Formato inválido. Use json o pdf",
+	"Informe horaInicio e horaFim no formato HH:MM":                    "Informe horaInicio y horaFim en el formato HH:MM",
+	"Ordenação inválida":                                               "Orden inválido",
+	"Ordenação inválida. Use data, -data, nome, -nome ou vagas":        "Orden inválido. Use data, -data, nome, -nome o vagas",
+	"Situação inválida %q":                                             "Estado inválido %q",
+	"Categoria inválida":                                               "Categoría inválida",
+	"Modo inválido. Use simulacao ou efetivar":                         "Modo inválido. Use simulacao o efetivar",
+
+	// Alunos e inscrições
+	"Nome do aluno é obrigatório":                                       "El nombre del alumno es obligatorio",
+	"Email do aluno é obrigatório":                                      "El email del alumno es obligatorio",
+	"Nome, CPF e email são campos obrigatórios":                         "Nombre, CPF y email son campos obligatorios",
+	"CPF, email e data de nascimento são obrigatórios":                  "CPF, email y fecha de nacimiento son obligatorios",
+	"CPF já cadastrado":                                                 "CPF ya registrado",
+	"Email já cadastrado":                                               "Email ya registrado",
+	"Já existe um aluno cadastrado com este email":                      "Ya existe un alumno registrado con este email",
+	"O CPF informado pertence ao aluno %d; mescle os cadastros":         "El CPF informado pertenece al alumno %d; fusione los registros",
+	"O email informado pertence ao aluno %d; mescle os cadastros":       "El email informado pertenece al alumno %d; fusione los registros",
+	"Quando marcado como PCD, o tipo de deficiência deve ser informado": "Si se marca como PcD, se debe informar el tipo de discapacidad",
+	"É necessário selecionar um curso":                                  "Es necesario seleccionar un curso",
+	"Aluno e curso são obrigatórios para uma inscrição":                 "Alumno y curso son obligatorios para una inscripción",
+	"Aluno é obrigatório para registrar consentimento":                  "El alumno es obligatorio para registrar el consentimiento",
+	"Não é possível remover aluno com inscrições ativas":                "No es posible eliminar un alumno con inscripciones activas",
+	"Não há vagas preenchidas para decrementar":                         "No hay plazas ocupadas para descontar",
+	"A inscrição %d não pertence à turma deste curso":                   "La inscripción %d no pertenece al grupo de este curso",
+	"Falha ao adicionar aluno ao curso":                                 "Error al agregar el alumno al curso",
+	"Falha ao atualizar aluno":                                          "Error al actualizar el alumno",
+	"Falha ao cadastrar aluno e inscrever no curso":                     "Error al registrar el alumno e inscribirlo en el curso",
+	"Falha ao cancelar inscrição":                                       "Error al cancelar la inscripción",
+	"Falha ao concluir inscrição":                                       "Error al completar la inscripción",
+	"Não é possível concluir uma inscrição com situação %s":             "No es posible completar una inscripción con situación %s",
+	"Não é possível concluir a inscrição antes do início do curso":      "No es posible completar la inscripción antes del inicio del curso",
+	"Falha ao criar inscrição":                                          "Error al crear la inscripción",
+	"Falha ao remover aluno":                                            "Error al eliminar el alumno",
+	"Falha ao recuperar alunos":                                         "Error al obtener los alumnos",
+	"Falha ao recuperar inscrições":                                     "Error al obtener las inscripciones",
+	"Falha ao recuperar inscrições detalhadas":                          "Error al obtener las inscripciones detalladas",
+	"Falha ao recuperar inscrições do aluno":                            "Error al obtener las inscripciones del alumno",
+	"Falha ao recuperar inscrições do curso":                            "Error al obtener las inscripciones del curso",
+
+	// Regras de inscrição
+	"O curso não está recebendo inscrições (situação: %s)":                            "El curso no está recibiendo inscripciones (estado: %s)",
+	"As inscrições para este curso abrem em %s":                                       "Las inscripciones para este curso abren el %s",
+	"As inscrições para este curso estão encerradas":                                  "Las inscripciones para este curso están cerradas",
+	"O aluno precisa concluir antes: %s":                                              "El alumno debe completar antes: %s",
+	"O aluno já possui %d inscrições ativas, o máximo permitido é %d":                 "El alumno ya tiene %d inscripciones activas, el máximo permitido es %d",
+	"O aluno já possui %d inscrições ativas em cursos de %s, o máximo permitido é %d": "El alumno ya tiene %d inscripciones activas en cursos de %s, el máximo permitido es %d",
+	"O aluno não atende aos critérios do curso: %s":                                   "El alumno no cumple los criterios del curso: %s",
+	"idade mínima de %s anos na data do curso":                                        "edad mínima de %s años en la fecha del curso",
+	"idade máxima de %s anos na data do curso":                                        "edad máxima de %s años en la fecha del curso",
+	"%s deve ser: %s":     "%s debe ser: %s",
+	"%s não pode ser: %s": "%s no puede ser: %s",
+	"O limite de inscrições ativas não pode ser negativo":   "El límite de inscripciones activas no puede ser negativo",
+	"Regra %d: ao menos um valor deve ser informado":        "Regla %d: se debe informar al menos un valor",
+	"Regra %d: idade aceita apenas os operadores min e max": "Regla %d: edad solo acepta los operadores min y max",
+	"Regra %d: idade deve ser um número inteiro":            "Regla %d: la edad debe ser un número entero",
+	"Regra %d: %s aceita apenas os operadores em e nao_em":  "Regla %d: %s solo acepta los operadores em y nao_em",
+	"Regra %d: campo inválido %q":                           "Regla %d: campo inválido %q",
+
+	// Cursos
+	"Informe o professor do curso":                                                     "Informe el profesor del curso",
+	"A hora de término deve ser posterior à hora de início":                            "La hora de término debe ser posterior a la hora de inicio",
+	"O encerramento das inscrições deve ser posterior à abertura":                      "El cierre de las inscripciones debe ser posterior a la apertura",
+	"O encerramento das inscrições não pode ser posterior ao início do curso":          "El cierre de las inscripciones no puede ser posterior al inicio del curso",
+	"O número de vagas totais não pode ser menor que o número de vagas já preenchidas": "El número total de plazas no puede ser menor que el número de plazas ya ocupadas",
+	"O número de vagas (%d) excede a capacidade da sala %q (%d)":                       "El número de plazas (%d) excede la capacidad de la sala %q (%d)",
+	"Um curso novo deve ser criado como rascunho ou publicado":                         "Un curso nuevo debe crearse como borrador o publicado",
+	"Um curso não pode ser pré-requisito de si mesmo":                                  "Un curso no puede ser requisito previo de sí mismo",
+	"O curso %d já depende deste curso e não pode ser seu pré-requisito":               "El curso %d ya depende de este curso y no puede ser su requisito previo",
+	"Pré-requisito não encontrado":                                                     "Requisito previo no encontrado",
+	"Não é possível cancelar um curso com situação %s":                                 "No es posible cancelar un curso con estado %s",
+	"Não é possível passar o curso de %s para %s":                                      "No es posible cambiar el curso de %s a %s",
+	"O curso possui inscrições; cancele o curso em vez de removê-lo":                   "El curso tiene inscripciones; cancele el curso en lugar de eliminarlo",
+	"Curso não encontrado entre os cursos do professor":                                "Curso no encontrado entre los cursos del profesor",
+	"Erro ao alterar situação do curso":                                                "Error al cambiar el estado del curso",
+	"Erro ao atualizar curso":                                                          "Error al actualizar el curso",
+	"Erro ao cancelar curso":                                                           "Error al cancelar el curso",
+	"Erro ao criar curso":                                                              "Error al crear el curso",
+	"Erro ao listar cursos":                                                            "Error al listar los cursos",
+	"Erro ao gerar calendário":                                                         "Error al generar el calendario",
+	"abre em %s":                                                                       "abre el %s",
+	"aberto":                                                                           "abierto",
+	"aberto até %s":                                                                    "abierto hasta el %s",
+	"encerrado":                                                                        "cerrado",
+	"cancelado":                                                                        "cancelado",
+
+	// Presença e relatórios
+	"Informe ao menos uma presença":                                 "Informe al menos una asistencia",
+	"Não é possível registrar presença em data futura":              "No es posible registrar asistencia en una fecha futura",
+	"Não é possível registrar presença em um curso com situação %s": "No es posible registrar asistencia en un curso con estado %s",
+	"Erro ao registrar presenças":                                   "Error al registrar las asistencias",
+	"A data final deve ser posterior à data inicial":                "La fecha final debe ser posterior a la fecha inicial",
+	"Nenhum dado fornecido para gerar relatório":                    "No se proporcionaron datos para generar el informe",
+	"Falha ao gerar relatório":                                      "Error al generar el informe",
+	"Falha ao processar dados do relatório":                         "Error al procesar los datos del informe",
+	"Erro ao gerar PDF":                                             "Error al generar el PDF",
+	"Falha ao gerar PDF":                                            "Error al generar el PDF",
+
+	// Categorias, locais, professores e webhooks
+	"Nome da categoria é obrigatório":                                 "El nombre de la categoría es obligatorio",
+	"Já existe uma categoria com este nome":                           "Ya existe una categoría con este nombre",
+	"Erro ao listar categorias":                                       "Error al listar las categorías",
+	"Nome e endereço do local são obrigatórios":                       "Nombre y dirección del lugar son obligatorios",
+	"Nome da sala é obrigatório":                                      "El nombre de la sala es obligatorio",
+	"A capacidade da sala deve ser maior que zero":                    "La capacidad de la sala debe ser mayor que cero",
+	"O curso %q oferece %d vagas, acima da nova capacidade da sala":   "El curso %q ofrece %d plazas, por encima de la nueva capacidad de la sala",
+	"Não é possível remover local com salas cadastradas":              "No es posible eliminar un lugar con salas registradas",
+	"Não é possível remover sala vinculada a cursos":                  "No es posible eliminar una sala vinculada a cursos",
+	"Erro ao listar locais":                                           "Error al listar los lugares",
+	"Nome e email do professor são obrigatórios":                      "Nombre y email del profesor son obligatorios",
+	"Já existe um professor com este email":                           "Ya existe un profesor con este email",
+	"A senha deve ter pelo menos 8 caracteres":                        "La contraseña debe tener al menos 8 caracteres",
+	"A senha deve ter no máximo 72 bytes":                             "La contraseña debe tener como máximo 72 bytes",
+	"Erro ao listar professores":                                      "Error al listar los profesores",
+	"URL do webhook inválida. Use um endereço http ou https completo": "URL del webhook inválida. Use una dirección http o https completa",
+	"Informe ao menos um evento para o webhook":                       "Informe al menos un evento para el webhook",
+	"Evento desconhecido: %s":                                         "Evento desconocido: %s",
+	"Erro ao listar webhooks":                                         "Error al listar los webhooks",
+
+	// Consentimento e LGPD
+	"Canal de comunicação inválido: %s":                 "Canal de comunicación inválido: %s",
+	"Finalidade inválida: %s":                           "Finalidad inválida: %s",
+	"Versão e texto dos termos são obrigatórios":        "La versión y el texto de los términos son obligatorios",
+	"Versão dos termos não encontrada":                  "Versión de los términos no encontrada",
+	"Versão dos termos desconhecida: %s":                "Versión de los términos desconocida: %s",
+	"Já existe um termo publicado com esta versão":      "Ya existen términos publicados con esta versión",
+	"Falha ao publicar termo":                           "Error al publicar los términos",
+	"Falha ao registrar consentimento":                  "Error al registrar el consentimiento",
+	"Falha ao recuperar consentimentos":                 "Error al obtener los consentimientos",
+	"Falha ao recuperar consentimentos do aluno":        "Error al obtener los consentimientos del alumno",
+	"Falha ao recuperar conflitos de cadastro do aluno": "Error al obtener los conflictos de registro del alumno",
+	"Falha ao recuperar fusões de cadastro do aluno":    "Error al obtener las fusiones de registro del alumno",
+	"Falha ao processar descadastro":                    "Error al procesar la baja",
+	"Falha ao eliminar dados do aluno":                  "Error al eliminar los datos del alumno",
+	"Falha ao exportar dados do aluno":                  "Error al exportar los datos del alumno",
+	"Falha ao recuperar histórico de solicitações":      "Error al obtener el historial de solicitudes",
+	"Falha ao recuperar solicitações":                   "Error al obtener las solicitudes",
+
+	// Duplicidades e importação
+	"Aluno duplicado não encontrado":                             "Alumno duplicado no encontrado",
+	"Aluno mantido não encontrado":                               "Alumno conservado no encontrado",
+	"Alunos com dados eliminados (LGPD) não podem ser mesclados": "Los alumnos con datos eliminados (LGPD) no se pueden fusionar",
+	"Não é possível mesclar um aluno com ele mesmo":              "No es posible fusionar un alumno consigo mismo",
+	"Campo de conflito desconhecido: %s":                         "Campo de conflicto desconocido: %s",
+	"Conflito já resolvido":                                      "Conflicto ya resuelto",
+	"Situação de conflito inválida":                              "Estado de conflicto inválido",
+	"Erro ao buscar alunos duplicados":                           "Error al buscar alumnos duplicados",
+	"Erro ao listar fusões de alunos":                            "Error al listar las fusiones de alumnos",
+	"Falha ao mesclar alunos":                                    "Error al fusionar los alumnos",
+	"Falha ao resolver conflito":                                 "Error al resolver el conflicto",
+	"Arquivo da planilha é obrigatório":                          "El archivo de la planilla es obligatorio",
+	"Formato de arquivo não suportado. Envie CSV ou XLSX":        "Formato de archivo no soportado. Envíe CSV o XLSX",
+	"Planilha maior que 5MB":                                     "Planilla mayor que 5MB",
+	"Arquivo XLSX inválido":                                      "Archivo XLSX inválido",
+	"Planilha inválida":                                          "Planilla inválida",
+	"Planilha sem abas":                                          "Planilla sin hojas",
+	"Primeira aba da planilha não encontrada":                    "Primera hoja de la planilla no encontrada",
+	"Planilha com referência de texto inválida":                  "Planilla con referencia de texto inválida",
+	"A planilha precisa de um cabeçalho e ao menos uma linha":    "La planilla necesita un encabezado y al menos una fila",
+	"A planilha tem mais de %d linhas":                           "La planilla tiene más de %d filas",
+	"Mapeamento de colunas inválido":                             "Mapeo de columnas inválido",
+	"Campo de mapeamento desconhecido: %s":                       "Campo de mapeo desconocido: %s",
+	"Coluna %q não encontrada na planilha":                       "Columna %q no encontrada en la planilla",
+	"Colunas obrigatórias ausentes: %s":                          "Faltan columnas obligatorias: %s",
+	"Erro ao abrir planilha":                                     "Error al abrir la planilla",
+	"Erro ao ler planilha":                                       "Error al leer la planilla",
+	"Erro ao importar planilha":                                  "Error al importar la planilla",
+
+	// Respostas de sucesso
+	"Aluno cadastrado e inscrito com sucesso":                   "Alumno registrado e inscrito con éxito",
+	"Aluno removido com sucesso":                                "Alumno eliminado con éxito",
+	"Aluno adicionado ao curso com sucesso":                     "Alumno agregado al curso con éxito",
+	"Inscrição cancelada com sucesso":                           "Inscripción cancelada con éxito",
+	"Relatório gerado com sucesso":                              "Informe generado con éxito",
+	"Curso removido com sucesso":                                "Curso eliminado con éxito",
+	"Categoria removida com sucesso":                            "Categoría eliminada con éxito",
+	"Local removido com sucesso":                                "Lugar eliminado con éxito",
+	"Sala removida com sucesso":                                 "Sala eliminada con éxito",
+	"Professor removido com sucesso":                            "Profesor eliminado con éxito",
+	"Senha definida com sucesso":                                "Contraseña definida con éxito",
+	"Webhook removido com sucesso":                              "Webhook eliminado con éxito",
+	"Dados pessoais do aluno anonimizados com sucesso":          "Datos personales del alumno anonimizados con éxito",
+	"Enviamos um código de confirmação para o email cadastrado": "Enviamos un código de confirmación al correo electrónico registrado",
+	"Falha ao registrar a solicitação":                          "Error al registrar la solicitud",
+	"Não foi possível enviar o código de confirmação":           "No fue posible enviar el código de confirmación",
+	"Confirmação do pedido sobre seus dados pessoais":           "Confirmación de la solicitud sobre sus datos personales",
+	"Olá, %s.\n\nRecebemos um pedido para exportar os seus dados pessoais. Para confirmá-lo, informe o código %s.":               "Hola, %s.\n\nRecibimos una solicitud para exportar sus datos personales. Para confirmarla, informe el código %s.",
+	"Olá, %s.\n\nRecebemos um pedido para alterar os seus consentimentos de comunicação. Para confirmá-lo, informe o código %s.": "Hola, %s.\n\nRecibimos una solicitud para cambiar sus consentimientos de comunicación. Para confirmarla, informe el código %s.",
+	"Olá, %s.\n\nRecebemos um pedido para eliminar os seus dados pessoais. Para confirmá-lo, informe o código %s.":               "Hola, %s.\n\nRecibimos una solicitud para eliminar sus datos personales. Para confirmarla, informe el código %s.",
+	"\nO código vale por %d minutos e só pode ser usado uma vez. Se você não fez este pedido, ignore esta mensagem.":             "\nEl código es válido por %d minutos y solo puede usarse una vez. Si usted no hizo esta solicitud, ignore este mensaje.",
+	"Você não receberá mais mensagens por este canal":                                                                            "Ya no recibirá mensajes por este canal",
+
+	// Notificações
+	"Curso cancelado: %s": "Curso cancelado: %s",
+	"Olá, %s.\n\nInformamos que o curso %q, previsto para %s, foi cancelado pela organização.": "Hola, %s.\n\nLe informamos que el curso %q, previsto para el %s, fue cancelado por la organización.",
+	"\nMotivo: %s": "\nMotivo: %s",
+	"\n\nSua inscrição continua registrada em nosso histórico. Pedimos desculpas pelo transtorno.": "\n\nSu inscripción sigue registrada en nuestro historial. Pedimos disculpas por las molestias.",
+	"Link do seu calendário de cursos": "Enlace de su calendario de cursos",
+	"Olá, %s.\n\nEste é o link pessoal do seu calendário de cursos, para assinar no aplicativo de agenda:\n%s\n\nNão compartilhe este link. Um novo pedido gera outro link e desativa este.": "Hola, %s.\n\nEste es el enlace personal de su calendario de cursos, para suscribirse en la aplicación de agenda:\n%s\n\nNo comparta este enlace. Una nueva solicitud genera otro enlace y desactiva este.",
+	"Não foi possível enviar o link do calendário":          "No fue posible enviar el enlace del calendario",
+	"Enviamos o link do calendário para o email cadastrado": "Enviamos el enlace del calendario al correo registrado",
+	"Link do calendário revogado":                           "Enlace del calendario revocado",
+}
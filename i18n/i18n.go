@@ -0,0 +1,138 @@
+// Package i18n traduz as mensagens exibidas aos usuários. O texto em português é a própria chave
+// dos catálogos: mensagens sem tradução continuam em português, e o código que gera a mensagem não
+// precisa conhecer o idioma de quem vai lê-la.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// Idioma é uma etiqueta BCP 47 entre as suportadas pela API
+type Idioma string
+
+const (
+	Portugues Idioma = "pt-BR"
+	Espanhol  Idioma = "es"
+	Ingles    Idioma = "en"
+
+	// Padrao é usado quando o cliente não informa um idioma suportado
+	Padrao = Portugues
+)
+
+// Suportados lista os idiomas na ordem de preferência do servidor
+var Suportados = []Idioma{Portugues, Espanhol, Ingles}
+
+var catalogos = map[Idioma]map[string]string{
+	Espanhol: espanhol,
+	Ingles:   ingles,
+}
+
+var formatosData = map[Idioma]string{
+	Portugues: "02/01/2006",
+	Espanhol:  "02/01/2006",
+	Ingles:    "01/02/2006",
+}
+
+var correspondencia = language.NewMatcher([]language.Tag{
+	language.BrazilianPortuguese,
+	language.Spanish,
+	language.English,
+})
+
+// Escolher devolve o idioma suportado mais próximo do cabeçalho Accept-Language
+// (ex.: "es-AR,es;q=0.9" escolhe espanhol; "fr" escolhe o padrão)
+func Escolher(acceptLanguage string) Idioma {
+	preferidos, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(preferidos) == 0 {
+		return Padrao
+	}
+	_, indice, confianca := correspondencia.Match(preferidos...)
+	if confianca == language.No {
+		return Padrao
+	}
+	return Suportados[indice]
+}
+
+// Normalizar valida um idioma gravado ou informado pelo cliente, usando o padrão quando não for suportado
+func Normalizar(idioma string) Idioma {
+	for _, suportado := range Suportados {
+		if strings.EqualFold(idioma, string(suportado)) {
+			return suportado
+		}
+	}
+	return Escolher(idioma)
+}
+
+// Traduzir formata a mensagem no idioma pedido. Argumentos Traduzivel também são traduzidos.
+func Traduzir(idioma Idioma, formato string, argumentos ...interface{}) string {
+	if traducao, ok := catalogos[idioma][formato]; ok {
+		formato = traducao
+	}
+	if len(argumentos) == 0 {
+		return formato
+	}
+	traduzidos := make([]interface{}, len(argumentos))
+	for i, argumento := range argumentos {
+		if traduzivel, ok := argumento.(Traduzivel); ok {
+			argumento = traduzivel.Em(idioma)
+		}
+		traduzidos[i] = argumento
+	}
+	return fmt.Sprintf(formato, traduzidos...)
+}
+
+// FormatarData escreve a data no formato usual do idioma
+func FormatarData(idioma Idioma, data time.Time) string {
+	formato, ok := formatosData[idioma]
+	if !ok {
+		formato = formatosData[Padrao]
+	}
+	return data.Format(formato)
+}
+
+// Traduzivel é um texto que só é escrito quando o idioma do leitor é conhecido
+type Traduzivel interface {
+	Em(idioma Idioma) string
+}
+
+// Mensagem guarda o formato e os argumentos de um texto para traduzi-lo depois
+type Mensagem struct {
+	Formato    string
+	Argumentos []interface{}
+}
+
+// NovaMensagem prepara uma mensagem para tradução posterior
+func NovaMensagem(formato string, argumentos ...interface{}) Mensagem {
+	return Mensagem{Formato: formato, Argumentos: argumentos}
+}
+
+func (m Mensagem) Em(idioma Idioma) string {
+	return Traduzir(idioma, m.Formato, m.Argumentos...)
+}
+
+// String escreve a mensagem no idioma padrão
+func (m Mensagem) String() string {
+	return m.Em(Padrao)
+}
+
+// Lista junta mensagens com um separador, traduzindo cada uma
+type Lista struct {
+	Separador string
+	Itens     []Traduzivel
+}
+
+func (l Lista) Em(idioma Idioma) string {
+	textos := make([]string, len(l.Itens))
+	for i, item := range l.Itens {
+		textos[i] = item.Em(idioma)
+	}
+	return strings.Join(textos, l.Separador)
+}
+
+func (l Lista) String() string {
+	return l.Em(Padrao)
+}
@@ -0,0 +1,126 @@
+package i18n
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEscolherIdiomaPeloAcceptLanguage(t *testing.T) {
+	casos := map[string]Idioma{
+		"":                          Portugues,
+		"pt-BR,pt;q=0.9":            Portugues,
+		"pt-PT":                     Portugues,
+		"es-AR,es;q=0.9,en;q=0.5":   Espanhol,
+		"en-US":                     Ingles,
+		"fr-FR,en;q=0.8":            Ingles,
+		"de":                        Portugues,
+		"cabeçalho;;inválido":       Portugues,
+		"en;q=0.4,es;q=0.9":         Espanhol,
+		"*":                         Portugues,
+		"es-419,pt-BR;q=0.8,en;q=0": Espanhol,
+	}
+	for cabecalho, esperado := range casos {
+		if obtido := Escolher(cabecalho); obtido != esperado {
+			t.Errorf("Escolher(%q) = %s, esperava %s", cabecalho, obtido, esperado)
+		}
+	}
+}
+
+func TestTraduzirArgumentosTraduziveis(t *testing.T) {
+	regras := Lista{Separador: "; ", Itens: []Traduzivel{
+		NovaMensagem("idade mínima de %s anos na data do curso", "18"),
+		NovaMensagem("%s deve ser: %s", "bairro", "Centro"),
+	}}
+	obtido := Traduzir(Ingles, "O aluno não atende aos critérios do curso: %s", regras)
+	esperado := "The student does not meet the course criteria: minimum age of 18 on the course date; bairro must be: Centro"
+	if obtido != esperado {
+		t.Errorf("tradução inesperada:\n%s\nesperava\n%s", obtido, esperado)
+	}
+	if obtido := Traduzir(Espanhol, "Mensagem sem tradução %d", 1); obtido != "Mensagem sem tradução 1" {
+		t.Errorf("sem tradução a mensagem deveria ficar em português, recebeu %q", obtido)
+	}
+	data := time.Date(2026, 11, 5, 0, 0, 0, 0, time.UTC)
+	if FormatarData(Ingles, data) != "11/05/2026" || FormatarData(Portugues, data) != "05/11/2026" {
+		t.Errorf("datas formatadas: en=%s pt=%s", FormatarData(Ingles, data), FormatarData(Portugues, data))
+	}
+}
+
+var verbos = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+// Toda mensagem escrita no código precisa de tradução, com os mesmos verbos de formatação
+func TestCatalogosCobremAsMensagensDoCodigo(t *testing.T) {
+	mensagens := mensagensDoCodigo(t, "..")
+	if len(mensagens) < 100 {
+		t.Fatalf("apenas %d mensagens encontradas; a busca no código deixou de funcionar?", len(mensagens))
+	}
+	for idioma, catalogo := range catalogos {
+		for mensagem, origem := range mensagens {
+			traducao, ok := catalogo[mensagem]
+			if !ok {
+				t.Errorf("%s: sem tradução para %q (%s)", idioma, mensagem, origem)
+				continue
+			}
+			if a, b := verbos.FindAllString(mensagem, -1), verbos.FindAllString(traducao, -1); strings.Join(a, " ") != strings.Join(b, " ") {
+				t.Errorf("%s: verbos %v na tradução de %q, esperava %v", idioma, b, mensagem, a)
+			}
+		}
+		for mensagem := range catalogo {
+			if _, usada := mensagens[mensagem]; !usada {
+				t.Errorf("%s: tradução sem uso no código para %q", idioma, mensagem)
+			}
+		}
+	}
+}
+
+// mensagensDoCodigo reúne os textos literais passados a erros.Novo, ComMensagem, NovaMensagem e Traduzir
+func mensagensDoCodigo(t *testing.T, raiz string) map[string]string {
+	t.Helper()
+	posicaoFormato := map[string]int{"Novo": 2, "ComMensagem": 0, "NovaMensagem": 0, "Traduzir": 1}
+	mensagens := map[string]string{}
+	arquivos := token.NewFileSet()
+	err := filepath.WalkDir(raiz, func(caminho string, entrada fs.DirEntry, err error) error {
+		if err != nil || entrada.IsDir() || !strings.HasSuffix(caminho, ".go") || strings.HasSuffix(caminho, "_test.go") {
+			return err
+		}
+		arquivo, err := parser.ParseFile(arquivos, caminho, nil, 0)
+		if err != nil {
+			return err
+		}
+		ast.Inspect(arquivo, func(no ast.Node) bool {
+			chamada, ok := no.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			var nome string
+			switch funcao := chamada.Fun.(type) {
+			case *ast.SelectorExpr:
+				nome = funcao.Sel.Name
+			case *ast.Ident:
+				nome = funcao.Name
+			}
+			posicao, ok := posicaoFormato[nome]
+			if !ok || len(chamada.Args) <= posicao {
+				return true
+			}
+			if literal, ok := chamada.Args[posicao].(*ast.BasicLit); ok && literal.Kind == token.STRING {
+				if texto, err := strconv.Unquote(literal.Value); err == nil {
+					mensagens[texto] = arquivos.Position(literal.Pos()).String()
+				}
+			}
+			return true
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mensagens
+}
@@ -1,25 +1,27 @@
 package main
 
 import (
-	"bytes"
+	"context"
+	"errors"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	"gorm.io/driver/postgres"
-	"gorm.io/gorm"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
-	"tvtec/controller"
+	"tvtec/config"
 	"tvtec/middleware"
-	"tvtec/models"
 	"tvtec/repository"
 	"tvtec/service"
-
-	"github.com/joho/godotenv"
 )
 
 // Middleware para evitar redirecionamentos 307
@@ -49,184 +51,134 @@ func requestLoggerMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
 
-		// Log da requisição
-		log.Printf("Requisição recebida: %s %s", c.Request.Method, c.Request.URL.Path)
-		if c.Request.Method == "POST" || c.Request.Method == "PUT" {
-			bodyBytes, _ := c.GetRawData()
-			if len(bodyBytes) > 0 {
-				log.Printf("Corpo da requisição: %s", string(bodyBytes))
-				// Restaura o corpo para que possa ser lido novamente pelo handler
-				c.Request.Body = &bytesBodyReader{bytes.NewReader(bodyBytes)}
-			}
-		}
+		// Log da requisição; o corpo não é registrado porque leva CPF, email, data de nascimento,
+		// senhas e códigos de confirmação
+		log.Printf("Requisição recebida [%s]: %s %s", c.GetString(middleware.ChaveIDRequisicao), c.Request.Method, c.Request.URL.Path)
 
 		// Processa a requisição
 		c.Next()
 
 		// Log da resposta
 		latency := time.Since(start)
-		log.Printf("Resposta: %d, Tempo: %v", c.Writer.Status(), latency)
+		log.Printf("Resposta [%s]: %d, Tempo: %v", c.GetString(middleware.ChaveIDRequisicao), c.Writer.Status(), latency)
 	}
 }
 
 func main() {
-	// Carregar variáveis de ambiente do arquivo .env
-	err := godotenv.Load()
+	// Carrega e valida a configuração (ambiente, arquivos de segredo e .env); a API não inicia com erros
+	cfg, err := config.Carregar()
 	if err != nil {
-		log.Println("Arquivo .env não encontrado. Usando variáveis de ambiente do sistema.")
+		log.Fatal(err)
 	}
+	log.Printf("Configuração carregada:\n%s", cfg)
 
-	// Inicializar configurações de autenticação
-	middleware.InitAuthConfig()
-
-	// Lê a connection string do banco de dados a partir da variável de ambiente DATABASE_URL
-	dsn := os.Getenv("DATABASE_URL")
-	if dsn == "" {
-		log.Fatal("Variável de ambiente DATABASE_URL não definida")
+	// Abre a conexão com o banco configurado (PostgreSQL em produção, SQLite para desenvolvimento)
+	db, err := repository.AbrirBanco(cfg.DriverBanco, cfg.BancoURL)
+	if err != nil {
+		log.Fatalf("Erro ao conectar ao banco de dados: %v", err)
 	}
-
-	// Abre a conexão com o PostgreSQL usando o driver do GORM
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	sqlDB, err := db.DB()
 	if err != nil {
-		log.Fatalf("Erro ao conectar ao PostgreSQL: %v", err)
+		log.Fatalf("Erro ao acessar o pool de conexões: %v", err)
 	}
-
-	// Configuração de log para debugging
-	log.Println("Conectado ao banco de dados PostgreSQL")
+	log.Printf("Conectado ao banco de dados (%s)", cfg.DriverBanco)
 
 	// Executa o AutoMigrate para criar/atualizar as tabelas no banco de dados
-	if err := db.AutoMigrate(&models.Aluno{}, &models.Curso{}, &models.Inscricao{}); err != nil {
+	if err := repository.Migrar(db); err != nil {
 		log.Fatalf("Erro ao migrar o banco de dados: %v", err)
 	}
 	log.Println("Migração de banco de dados concluída com sucesso")
 
-	// Instancia os repositórios
-	alunoRepo := repository.NewAlunoRepository(db)
-	cursoRepo := repository.NewCursoRepository(db)
-	inscricaoRepo := repository.NewInscricaoRepository(db)
-
-	// Instancia os serviços, injetando os repositórios necessários
-	cursoService := service.NewCursoService(cursoRepo, inscricaoRepo)
-	alunoService := service.NewAlunoService(alunoRepo, cursoRepo, inscricaoRepo)
-	inscricaoService := service.NewInscricaoService(inscricaoRepo)
-	//powerBIService := service.NewPowerBIService() // Novo serviço Power BI
-
-	// Instancia os controllers
-	alunoController := controller.NewAlunoController(alunoService)
-	cursoController := controller.NewCursoController(cursoService)
-	authController := controller.NewAuthController()
-	inscricaoController := controller.NewInscricaoController(inscricaoService)
-	//powerBIController := controller.NewPowerBIController(powerBIService) // Novo controller Power BI
-
-	// Inicializa o roteador Gin (modo baseado em variável de ambiente)
-	ginMode := os.Getenv("GIN_MODE")
-	if ginMode != "" {
-		gin.SetMode(ginMode)
-	} else {
-		gin.SetMode(gin.DebugMode)
+	app, err := novaAplicacao(cfg, db)
+	if err != nil {
+		log.Fatal(err)
 	}
-	router := gin.New()
-
-	// Middleware personalizado para evitar redirecionamentos
-	router.Use(noRedirectMiddleware())
-
-	// Middleware de log de requisições (para debugging)
-	router.Use(requestLoggerMiddleware())
-
-	// Configuração CORS
-	corsConfig := cors.DefaultConfig()
-	corsConfig.AllowAllOrigins = true
-	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
-	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
-	corsConfig.ExposeHeaders = []string{"Content-Length"}
-	corsConfig.AllowCredentials = true
-	router.Use(cors.New(corsConfig))
-
-	// Adiciona middleware de recuperação
-	router.Use(gin.Recovery())
-
-	log.Println("Configuração CORS aplicada. Todos os origens permitidas.")
-
-	// Rota de verificação de saúde da API
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"status": "ok",
-			"time":   time.Now().Format(time.RFC3339),
-		})
-	})
-
-	// Rotas de autenticação
-	auth := router.Group("/auth")
-	{
-		auth.POST("/login", authController.Login)
-		auth.GET("/validate", middleware.AuthMiddleware(), authController.ValidateToken)
+
+	// Os laços de segundo plano param depois que as requisições em andamento terminam
+	contextoTrabalhos, pararTrabalhos := context.WithCancel(context.Background())
+	var trabalhos sync.WaitGroup
+	trabalhos.Add(2)
+	go func() {
+		defer trabalhos.Done()
+		app.despachante.Executar(contextoTrabalhos, 5*time.Second)
+	}()
+	go func() {
+		defer trabalhos.Done()
+		app.webhookService.Executar(contextoTrabalhos, 30*time.Second)
+	}()
+
+	// Métricas do Prometheus: numa porta interna (METRICS_ADDR) ou na porta da API com token (METRICS_TOKEN)
+	prometheus.MustRegister(collectors.NewDBStatsCollector(sqlDB, "tvtec"))
+	prometheus.MustRegister(service.NewColetorCursos(app.cursoRepo))
+	var servidorMetricas *http.Server
+	if endereco := cfg.Metricas.Endereco; endereco != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.Handler())
+		servidorMetricas = &http.Server{Addr: endereco, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+		go func() {
+			log.Printf("Métricas disponíveis em %s/metrics", endereco)
+			if err := servidorMetricas.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("Erro no servidor de métricas: %v", err)
+			}
+		}()
+	} else if cfg.Metricas.Token == "" {
+		log.Println("AVISO: métricas desativadas. Defina METRICS_ADDR ou METRICS_TOKEN")
 	}
 
-	// Rotas públicas (sem autenticação)
-	// Rotas para Curso (apenas visualização)
-	router.GET("/curso", cursoController.ListarCursos)
-	router.GET("/curso/:id", cursoController.ObterCursoPorID)
-	router.GET("/curso/:id/vagas", cursoController.VerificarDisponibilidadeVagas)
-
-	// Rotas para inscrição de alunos (acessível sem autenticação)
-	router.POST("/aluno/inscricao", alunoController.CadastrarAlunoEInscrever)
-
-	//// Rotas para o Power BI (requerem autenticação)
-	//powerbi := router.Group("/powerbi")
-	//powerbi.Use(middleware.AuthMiddleware())
-	//{
-	//	powerbi.GET("/token", powerBIController.GetEmbedToken)
-	//
-	//	// Rota de teste para verificar se o endpoint está funcionando
-	//	powerbi.GET("/teste", func(c *gin.Context) {
-	//		c.JSON(http.StatusOK, gin.H{
-	//			"status":    "ok",
-	//			"message":   "Endpoint do PowerBI funcionando",
-	//			"timestamp": time.Now().Format(time.RFC3339),
-	//		})
-	//	})
-	//}
-
-	// Rotas protegidas (requerem autenticação de admin)
-	admin := router.Group("/admin")
-	admin.Use(middleware.AdminAuthMiddleware())
-	{
-		// Administração de Cursos
-		admin.POST("/curso", cursoController.CriarCurso)
-		admin.PUT("/curso/:id", cursoController.AtualizarCurso)
-		admin.DELETE("/curso/:id", cursoController.RemoverCurso)
-		admin.GET("/curso/:id/inscricoes", cursoController.ListarInscricoesCurso)
-
-		// Administração de Alunos
-		admin.GET("/aluno", alunoController.ListarAlunos)
-		admin.GET("/aluno/:id", alunoController.ObterAlunoPorID)
-		admin.PUT("/aluno/:id", alunoController.AtualizarAluno)
-		admin.DELETE("/aluno/:id", alunoController.RemoverAluno)
-		admin.POST("/aluno/:id/curso/:cursoId", alunoController.AdicionarAlunoCurso)
-		admin.GET("/aluno/:id/inscricoes", alunoController.ListarInscricoesAluno)
-
-		// NOVAS ROTAS: Administração de Inscrições
-		admin.GET("/inscricoes", inscricaoController.ListarInscricoes)
-		admin.GET("/inscricoes/:id", inscricaoController.ObterInscricaoPorID)
-		admin.POST("/relatorio", inscricaoController.GerarRelatorio)
-		admin.DELETE("inscricoes/:id", inscricaoController.CancelarInscricao)
+	// Os limites de tempo evitam que conexões lentas prendam o servidor; a escrita comporta relatórios em PDF
+	servidor := &http.Server{
+		Addr:              ":" + strconv.Itoa(cfg.Porta),
+		Handler:           app.router,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       cfg.HTTP.TempoLeitura,
+		WriteTimeout:      cfg.HTTP.TempoEscrita,
+		IdleTimeout:       cfg.HTTP.TempoOcioso,
 	}
 
-	// Define a porta a partir da variável de ambiente PORT ou utiliza 8080 como padrão
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	sinal, pararSinais := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer pararSinais()
+
+	erroServidor := make(chan error, 1)
+	go func() {
+		log.Printf("API iniciada na porta %d", cfg.Porta)
+		if err := servidor.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			erroServidor <- err
+		}
+	}()
+
+	select {
+	case <-sinal.Done():
+		log.Println("Sinal de encerramento recebido. Aguardando as requisições em andamento...")
+	case err := <-erroServidor:
+		log.Printf("Erro no servidor HTTP: %v", err)
 	}
 
-	log.Printf("API iniciada na porta %s", port)
-	router.Run(":" + port)
-}
+	// A prontidão passa a falhar e o servidor para de aceitar conexões, terminando as requisições em andamento
+	app.saudeService.IniciarEncerramento()
+	contextoEncerramento, cancelarEncerramento := context.WithTimeout(context.Background(), cfg.HTTP.TempoEncerramento)
+	defer cancelarEncerramento()
+	if err := servidor.Shutdown(contextoEncerramento); err != nil {
+		log.Printf("Requisições interrompidas no encerramento: %v", err)
+	}
+	if servidorMetricas != nil {
+		servidorMetricas.Shutdown(contextoEncerramento)
+	}
 
-// bytesBodyReader é um helper para restaurar o corpo da requisição após leitura
-type bytesBodyReader struct {
-	*bytes.Reader
-}
+	// Os laços terminam o ciclo em andamento; eventos ainda não despachados ficam na outbox para o próximo início
+	pararTrabalhos()
+	concluidos := make(chan struct{})
+	go func() {
+		trabalhos.Wait()
+		close(concluidos)
+	}()
+	select {
+	case <-concluidos:
+	case <-contextoEncerramento.Done():
+		log.Println("AVISO: laços de segundo plano não terminaram dentro do prazo de encerramento")
+	}
 
-func (r *bytesBodyReader) Close() error {
-	return nil
+	if err := sqlDB.Close(); err != nil {
+		log.Printf("Erro ao fechar conexões com o banco: %v", err)
+	}
+	log.Println("API encerrada")
 }
@@ -3,13 +3,13 @@ package middleware
 import (
 	"fmt"
 	"log"
-	"net/http"
-	"os"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v4"
+
+	"tvtec/erros"
 )
 
 // Variáveis para armazenar credenciais e chave JWT
@@ -19,108 +19,117 @@ var (
 	SecretKey     string
 )
 
-// InitAuthConfig inicializa as configurações de autenticação a partir de variáveis de ambiente
-func InitAuthConfig() {
-	// Carregar variáveis de ambiente
-	AdminUsername = getEnvWithDefault("ADMIN_USERNAME", "admin")
-	AdminPassword = getEnvWithDefault("ADMIN_PASSWORD", "admin123") // Valor padrão somente para fallback
-	SecretKey = getEnvWithDefault("JWT_SECRET_KEY", "chave-secreta-padrao-mudar-em-producao")
-
-	// Avisar se valores padrão estão sendo usados em produção
-	if gin.Mode() == gin.ReleaseMode {
-		if AdminPassword == "admin123" {
-			log.Println("AVISO: Senha de administrador padrão sendo usada em ambiente de produção!")
-		}
-		if SecretKey == "chave-secreta-padrao-mudar-em-producao" {
-			log.Println("AVISO: Chave JWT padrão sendo usada em ambiente de produção!")
-		}
-	}
-
+// InitAuthConfig define as credenciais do administrador e a chave JWT, já validadas pela configuração
+func InitAuthConfig(usuario, senha, chave string) {
+	AdminUsername = usuario
+	AdminPassword = senha
+	SecretKey = chave
 	log.Println("Configurações de autenticação inicializadas com sucesso")
 }
 
-// Helper para obter variável de ambiente com valor padrão
-func getEnvWithDefault(key, defaultValue string) string {
-	value := os.Getenv(key)
-	if value == "" {
-		return defaultValue
-	}
-	return value
-}
+// Papéis aceitos nos tokens
+const (
+	RoleAdmin     = "admin"
+	RoleProfessor = "professor"
+)
 
 // UserClaims define os dados armazenados no token JWT
 type UserClaims struct {
-	Username string `json:"username"`
-	Role     string `json:"role"`
+	Username    string `json:"username"`
+	Role        string `json:"role"`
+	ProfessorID uint   `json:"professorId,omitempty"`
 	jwt.RegisteredClaims
 }
 
 // AuthMiddleware é o middleware para verificar autenticação
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Extrair token do cabeçalho Authorization
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Autorização necessária"})
-			c.Abort()
-			return
+		if autenticar(c) {
+			c.Next()
 		}
+	}
+}
 
-		// Formato esperado: "Bearer TOKEN"
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Formato de autorização inválido"})
-			c.Abort()
-			return
-		}
+// autenticar valida o token e guarda os dados do usuário no contexto, sem seguir para os
+// próximos handlers, para que os middlewares de papel confiram o papel antes do handler.
+// Responde 401 e retorna false quando o token falta ou é inválido.
+func autenticar(c *gin.Context) bool {
+	// Extrair token do cabeçalho Authorization
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		ResponderErro(c, erros.ErrNaoAutenticado.ComMensagem("Autorização necessária"))
+		return false
+	}
 
-		tokenString := parts[1]
+	// Formato esperado: "Bearer TOKEN"
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		ResponderErro(c, erros.ErrNaoAutenticado.ComMensagem("Formato de autorização inválido"))
+		return false
+	}
 
-		// Verificar e validar o token
-		token, err := jwt.ParseWithClaims(tokenString, &UserClaims{}, func(token *jwt.Token) (interface{}, error) {
-			// Verificar algoritmo de assinatura
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("método de assinatura inesperado: %v", token.Header["alg"])
-			}
-			return []byte(SecretKey), nil
-		})
+	tokenString := parts[1]
 
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
-			c.Abort()
-			return
+	// Verificar e validar o token
+	token, err := jwt.ParseWithClaims(tokenString, &UserClaims{}, func(token *jwt.Token) (interface{}, error) {
+		// Verificar algoritmo de assinatura
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("método de assinatura inesperado: %v", token.Header["alg"])
 		}
+		return []byte(SecretKey), nil
+	})
 
-		// Verificar se o token é válido
-		if claims, ok := token.Claims.(*UserClaims); ok && token.Valid {
-			// Armazenar informações do usuário no contexto
-			c.Set("username", claims.Username)
-			c.Set("role", claims.Role)
-			c.Next()
-		} else {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
-			c.Abort()
-			return
-		}
+	if err != nil {
+		ResponderErro(c, erros.ErrTokenInvalido)
+		return false
 	}
+
+	// Verificar se o token é válido
+	claims, ok := token.Claims.(*UserClaims)
+	if !ok || !token.Valid {
+		ResponderErro(c, erros.ErrTokenInvalido)
+		return false
+	}
+
+	// Armazenar informações do usuário no contexto
+	c.Set("username", claims.Username)
+	c.Set("role", claims.Role)
+	if claims.ProfessorID != 0 {
+		c.Set("professorId", claims.ProfessorID)
+	}
+	return true
 }
 
 // AdminAuthMiddleware verifica se o usuário é um administrador
 func AdminAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Primeiro verifica se está autenticado
-		AuthMiddleware()(c)
-
-		// Se a requisição foi abortada pelo middleware anterior, retorne
-		if c.IsAborted() {
+		if !autenticar(c) {
 			return
 		}
 
 		// Verifica se o usuário tem o papel de admin
 		role, exists := c.Get("role")
-		if !exists || role != "admin" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Acesso negado: requer privilégios de administrador"})
-			c.Abort()
+		if !exists || role != RoleAdmin {
+			ResponderErro(c, erros.ErrSemPermissao.ComMensagem("Acesso negado: requer privilégios de administrador"))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ProfessorAuthMiddleware libera apenas professores; o ID do professor fica em "professorId" no contexto
+func ProfessorAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !autenticar(c) {
+			return
+		}
+
+		role, _ := c.Get("role")
+		_, temProfessor := c.Get("professorId")
+		if role != RoleProfessor || !temProfessor {
+			ResponderErro(c, erros.ErrSemPermissao.ComMensagem("Acesso negado: requer perfil de professor"))
 			return
 		}
 
@@ -130,14 +139,19 @@ func AdminAuthMiddleware() gin.HandlerFunc {
 
 // GenerateToken gera um token JWT para o usuário
 func GenerateToken(username, role string) (string, error) {
-	// Define os claims do token
-	claims := UserClaims{
-		Username: username,
-		Role:     role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)), // Token válido por 24 horas
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+	return gerarToken(UserClaims{Username: username, Role: role})
+}
+
+// GenerateProfessorToken gera um token com perfil de professor, restrito aos próprios cursos
+func GenerateProfessorToken(username string, professorID uint) (string, error) {
+	return gerarToken(UserClaims{Username: username, Role: RoleProfessor, ProfessorID: professorID})
+}
+
+func gerarToken(claims UserClaims) (string, error) {
+	// Define a validade do token
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)), // Token válido por 24 horas
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
 	}
 
 	// Cria o token com os claims
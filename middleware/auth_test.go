@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// roteadorComPapeis registra uma rota de escrita para cada papel e anota se o handler rodou
+func roteadorComPapeis(executou *bool) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	InitAuthConfig("admin", "senha", strings.Repeat("k", 32))
+	router := gin.New()
+	marcar := func(c *gin.Context) {
+		*executou = true
+		c.Status(http.StatusNoContent)
+	}
+	router.DELETE("/admin/curso/:id", AdminAuthMiddleware(), marcar)
+	router.POST("/professor-area/curso/:id/presencas", ProfessorAuthMiddleware(), marcar)
+	return router
+}
+
+func requisitarComToken(router *gin.Engine, metodo, caminho, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(metodo, caminho, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// O papel é conferido antes do handler: um token de professor não executa nenhuma rota /admin
+func TestTokenDeProfessorNaoAlcancaRotasAdmin(t *testing.T) {
+	executou := false
+	router := roteadorComPapeis(&executou)
+	token, err := GenerateProfessorToken("joao@example.com", 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := requisitarComToken(router, http.MethodDelete, "/admin/curso/1", token)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("esperava 403, recebeu %d", w.Code)
+	}
+	if executou {
+		t.Error("o handler de administração rodou com token de professor")
+	}
+
+	if w := requisitarComToken(router, http.MethodPost, "/professor-area/curso/1/presencas", token); w.Code != http.StatusNoContent || !executou {
+		t.Errorf("o professor deveria acessar a própria área, recebeu %d", w.Code)
+	}
+}
+
+func TestTokenDeAdminNaoAlcancaAreaDoProfessor(t *testing.T) {
+	executou := false
+	router := roteadorComPapeis(&executou)
+	token, err := GenerateToken("admin", RoleAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if w := requisitarComToken(router, http.MethodPost, "/professor-area/curso/1/presencas", token); w.Code != http.StatusForbidden || executou {
+		t.Errorf("esperava 403 sem executar o handler, recebeu %d (executou=%v)", w.Code, executou)
+	}
+	if w := requisitarComToken(router, http.MethodDelete, "/admin/curso/1", token); w.Code != http.StatusNoContent || !executou {
+		t.Errorf("o administrador deveria acessar /admin, recebeu %d", w.Code)
+	}
+}
@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var respostasCache = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "tvtec_cache_respostas_total",
+	Help: "Requisições às rotas com cache, por rota e resultado (acerto, falta, nao_modificado).",
+}, []string{"rota", "resultado"})
+
+// CacheRespostas guarda em memória as respostas das rotas públicas de leitura. Invalidar descarta
+// tudo e deve ser chamado a cada alteração nos dados exibidos; a validade de cada rota limita o
+// tempo de uma resposta que dependa apenas do relógio (ex.: período de inscrições encerrado).
+type CacheRespostas struct {
+	mu       sync.Mutex
+	geracao  uint64
+	limite   int
+	entradas map[string]*respostaGuardada
+}
+
+type respostaGuardada struct {
+	corpo      []byte
+	tipo       string
+	etag       string
+	modificado time.Time
+	expira     time.Time
+}
+
+// NewCacheRespostas cria o cache com no máximo limite respostas (uma por URL com consulta)
+func NewCacheRespostas(limite int) *CacheRespostas {
+	return &CacheRespostas{limite: limite, entradas: make(map[string]*respostaGuardada)}
+}
+
+// Invalidar descarta as respostas guardadas, inclusive as que estão sendo geradas neste momento
+func (c *CacheRespostas) Invalidar() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.geracao++
+	c.entradas = make(map[string]*respostaGuardada)
+}
+
+func (c *CacheRespostas) buscar(chave string, agora time.Time) (*respostaGuardada, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entrada := c.entradas[chave]
+	if entrada != nil && agora.After(entrada.expira) {
+		entrada = nil
+	}
+	return entrada, c.geracao
+}
+
+// guardar registra a resposta gerada, a menos que os dados tenham mudado durante a geração
+func (c *CacheRespostas) guardar(chave string, geracao uint64, nova *respostaGuardada) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if geracao != c.geracao {
+		return
+	}
+	// Uma resposta que expirou sem mudar mantém a data de modificação original
+	if anterior := c.entradas[chave]; anterior != nil && anterior.etag == nova.etag {
+		nova.modificado = anterior.modificado
+	}
+	if _, existe := c.entradas[chave]; !existe && len(c.entradas) >= c.limite {
+		agora := time.Now()
+		for outra, entrada := range c.entradas {
+			if agora.After(entrada.expira) {
+				delete(c.entradas, outra)
+			}
+		}
+		if len(c.entradas) >= c.limite {
+			return
+		}
+	}
+	c.entradas[chave] = nova
+}
+
+// CacheHTTP atende GETs a partir do cache e responde com ETag, Last-Modified e
+// Cache-Control público pela validade informada. Requisições condicionais
+// (If-None-Match, If-Modified-Since) ainda válidas recebem 304 sem corpo.
+// Apenas respostas 200 são guardadas.
+func CacheHTTP(cache *CacheRespostas, validade time.Duration) gin.HandlerFunc {
+	controle := "public, max-age=" + strconv.Itoa(int(validade.Seconds()))
+
+	return func(c *gin.Context) {
+		agora := time.Now()
+		// A mesma rota tem uma entrada por idioma, pois os textos de exibição são traduzidos
+		chave := string(IdiomaDe(c)) + " " + c.Request.URL.RequestURI()
+		entrada, geracao := cache.buscar(chave, agora)
+		resultado := "acerto"
+
+		if entrada == nil {
+			resultado = "falta"
+			original := c.Writer
+			retida := &respostaRetida{ResponseWriter: original, status: http.StatusOK}
+			c.Writer = retida
+			c.Next()
+			c.Writer = original
+
+			if retida.status != http.StatusOK {
+				original.WriteHeader(retida.status)
+				original.Write(retida.corpo.Bytes())
+				return
+			}
+
+			soma := sha256.Sum256(retida.corpo.Bytes())
+			entrada = &respostaGuardada{
+				corpo:      retida.corpo.Bytes(),
+				tipo:       original.Header().Get("Content-Type"),
+				etag:       `"` + hex.EncodeToString(soma[:16]) + `"`,
+				modificado: agora.UTC().Truncate(time.Second),
+				expira:     agora.Add(validade),
+			}
+			cache.guardar(chave, geracao, entrada)
+		}
+
+		cabecalho := c.Writer.Header()
+		cabecalho.Set("ETag", entrada.etag)
+		cabecalho.Set("Last-Modified", entrada.modificado.Format(http.TimeFormat))
+		cabecalho.Set("Cache-Control", controle)
+
+		if naoModificado(c.Request, entrada) {
+			respostasCache.WithLabelValues(c.FullPath(), "nao_modificado").Inc()
+			c.Writer.WriteHeader(http.StatusNotModified)
+			c.Writer.WriteHeaderNow()
+			c.Abort()
+			return
+		}
+
+		respostasCache.WithLabelValues(c.FullPath(), resultado).Inc()
+		c.Data(http.StatusOK, entrada.tipo, entrada.corpo)
+		c.Abort()
+	}
+}
+
+// naoModificado aplica as regras das requisições condicionais: If-None-Match, quando
+// enviado, prevalece sobre If-Modified-Since
+func naoModificado(req *http.Request, entrada *respostaGuardada) bool {
+	if valores := req.Header.Get("If-None-Match"); valores != "" {
+		for _, etag := range strings.Split(valores, ",") {
+			etag = strings.TrimPrefix(strings.TrimSpace(etag), "W/")
+			if etag == "*" || etag == entrada.etag {
+				return true
+			}
+		}
+		return false
+	}
+	if valor := req.Header.Get("If-Modified-Since"); valor != "" {
+		desde, err := http.ParseTime(valor)
+		return err == nil && !entrada.modificado.After(desde)
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func roteadorComCache(cache *CacheRespostas, chamadas *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/curso/:id/vagas", CacheHTTP(cache, 5*time.Second), func(c *gin.Context) {
+		*chamadas++
+		if c.Param("id") == "0" {
+			c.JSON(http.StatusNotFound, gin.H{"code": "curso_nao_encontrado"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"vagasDisponiveis": 10 - *chamadas})
+	})
+	return router
+}
+
+func requisitarCondicional(router *gin.Engine, caminho, cabecalho, valor string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, caminho, nil)
+	if cabecalho != "" {
+		req.Header.Set(cabecalho, valor)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCacheHTTPRespondeNaoModificadoAteInvalidar(t *testing.T) {
+	cache := NewCacheRespostas(10)
+	chamadas := 0
+	router := roteadorComCache(cache, &chamadas)
+
+	primeira := requisitarCondicional(router, "/curso/1/vagas", "", "")
+	etag := primeira.Header().Get("ETag")
+	if primeira.Code != http.StatusOK || etag == "" || primeira.Header().Get("Last-Modified") == "" {
+		t.Fatalf("primeira resposta sem validadores: %d %v", primeira.Code, primeira.Header())
+	}
+	if controle := primeira.Header().Get("Cache-Control"); controle != "public, max-age=5" {
+		t.Errorf("Cache-Control inesperado: %q", controle)
+	}
+
+	segunda := requisitarCondicional(router, "/curso/1/vagas", "", "")
+	if segunda.Body.String() != primeira.Body.String() || chamadas != 1 {
+		t.Fatalf("a segunda resposta deveria vir do cache (handler chamado %d vezes)", chamadas)
+	}
+
+	if w := requisitarCondicional(router, "/curso/1/vagas", "If-None-Match", `"outra", W/`+etag); w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Fatalf("esperava 304 sem corpo com If-None-Match, recebeu %d: %s", w.Code, w.Body.String())
+	}
+	if w := requisitarCondicional(router, "/curso/1/vagas", "If-Modified-Since", primeira.Header().Get("Last-Modified")); w.Code != http.StatusNotModified {
+		t.Fatalf("esperava 304 com If-Modified-Since, recebeu %d", w.Code)
+	}
+
+	cache.Invalidar()
+	w := requisitarCondicional(router, "/curso/1/vagas", "If-None-Match", etag)
+	if w.Code != http.StatusOK || w.Header().Get("ETag") == etag || chamadas != 2 {
+		t.Fatalf("depois de invalidar esperava conteúdo novo, recebeu %d com ETag %s", w.Code, w.Header().Get("ETag"))
+	}
+}
+
+func TestCacheHTTPNaoGuardaErros(t *testing.T) {
+	cache := NewCacheRespostas(10)
+	chamadas := 0
+	router := roteadorComCache(cache, &chamadas)
+
+	for i := 0; i < 2; i++ {
+		w := requisitarCondicional(router, "/curso/0/vagas", "", "")
+		if w.Code != http.StatusNotFound || w.Header().Get("ETag") != "" {
+			t.Fatalf("erro não deveria ter ETag: %d %v", w.Code, w.Header())
+		}
+	}
+	if chamadas != 2 {
+		t.Errorf("respostas de erro não devem ser guardadas (handler chamado %d vezes)", chamadas)
+	}
+}
+
+func TestCacheHTTPRespeitaLimiteDeRespostas(t *testing.T) {
+	cache := NewCacheRespostas(1)
+	chamadas := 0
+	router := roteadorComCache(cache, &chamadas)
+
+	requisitarCondicional(router, "/curso/1/vagas", "", "")
+	requisitarCondicional(router, "/curso/2/vagas", "", "")
+	requisitarCondicional(router, "/curso/2/vagas", "", "")
+	requisitarCondicional(router, "/curso/1/vagas", "", "")
+	if chamadas != 3 {
+		t.Errorf("com limite 1 só a primeira URL deveria ficar guardada (handler chamado %d vezes)", chamadas)
+	}
+}
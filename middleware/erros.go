@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+
+	"tvtec/erros"
+)
+
+// ChaveIDRequisicao é a chave do contexto Gin com o identificador da requisição
+const ChaveIDRequisicao = "requestId"
+
+// CabecalhoIDRequisicao é enviado em todas as respostas; um valor recebido do proxy é mantido
+const CabecalhoIDRequisicao = "X-Request-ID"
+
+var idRequisicaoValido = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RespostaErro é o corpo de todas as respostas de erro da API
+type RespostaErro struct {
+	Code      string      `json:"code"`
+	Message   string      `json:"message"`
+	Details   interface{} `json:"details,omitempty"`
+	RequestID string      `json:"requestId"`
+}
+
+var statusPorTipo = map[erros.Tipo]int{
+	erros.TipoInterno:          http.StatusInternalServerError,
+	erros.TipoInvalido:         http.StatusBadRequest,
+	erros.TipoMuitoGrande:      http.StatusRequestEntityTooLarge,
+	erros.TipoNaoAutenticado:   http.StatusUnauthorized,
+	erros.TipoSemPermissao:     http.StatusForbidden,
+	erros.TipoNaoEncontrado:    http.StatusNotFound,
+	erros.TipoConflito:         http.StatusConflict,
+	erros.TipoRegraNegocio:     http.StatusUnprocessableEntity,
+	erros.TipoIndisponivel:     http.StatusServiceUnavailable,
+	erros.TipoMuitasTentativas: http.StatusTooManyRequests,
+}
+
+// IDRequisicao identifica cada requisição para correlacionar a resposta de erro com o log
+func IDRequisicao() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(CabecalhoIDRequisicao)
+		if !idRequisicaoValido.MatchString(id) {
+			aleatorio := make([]byte, 12)
+			rand.Read(aleatorio)
+			id = hex.EncodeToString(aleatorio)
+		}
+		c.Set(ChaveIDRequisicao, id)
+		c.Header(CabecalhoIDRequisicao, id)
+		c.Next()
+	}
+}
+
+// TratarErros responde com o último erro registrado por c.Error quando o handler não escreveu
+// uma resposta. Os controllers apenas registram o erro e retornam.
+func TratarErros() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		ResponderErro(c, c.Errors.Last().Err)
+	}
+}
+
+// ResponderErro escreve o erro no formato padrão e interrompe a cadeia. Erros sem classificação
+// viram erro interno; a causa de erros internos vai apenas para o log.
+func ResponderErro(c *gin.Context, err error) {
+	dominio := erros.Classificar(err, nil)
+	resposta := RespostaErro{
+		Code:      dominio.Codigo,
+		Message:   dominio.MensagemEm(IdiomaDe(c)),
+		Details:   dominio.Detalhes,
+		RequestID: c.GetString(ChaveIDRequisicao),
+	}
+
+	if dominio.Tipo == erros.TipoInterno {
+		log.Printf("Erro interno na requisição %s (%s %s): %v", resposta.RequestID, c.Request.Method, c.Request.URL.Path, err)
+		resposta.Details = nil
+	} else if resposta.Details == nil && dominio.Causa() != nil {
+		resposta.Details = dominio.Causa().Error()
+	}
+
+	status, ok := statusPorTipo[dominio.Tipo]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+	c.AbortWithStatusJSON(status, resposta)
+}
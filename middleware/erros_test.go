@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"tvtec/erros"
+)
+
+func roteadorComErros(handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(IDRequisicao(), TratarErros())
+	router.GET("/teste", handler)
+	return router
+}
+
+func requisitarErro(t *testing.T, router *gin.Engine, idRecebido string) (*httptest.ResponseRecorder, RespostaErro) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/teste", nil)
+	if idRecebido != "" {
+		req.Header.Set(CabecalhoIDRequisicao, idRecebido)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var resposta RespostaErro
+	if err := json.Unmarshal(w.Body.Bytes(), &resposta); err != nil {
+		t.Fatalf("corpo de erro inválido: %v (%s)", err, w.Body.String())
+	}
+	return w, resposta
+}
+
+func TestTratarErrosConverteOTipoEmStatus(t *testing.T) {
+	casos := []struct {
+		erro   error
+		status int
+		codigo string
+	}{
+		{erros.ErrDadosInvalidos, http.StatusBadRequest, "dados_invalidos"},
+		{erros.ErrTokenInvalido, http.StatusUnauthorized, "token_invalido"},
+		{erros.ErrCursoNaoEncontrado, http.StatusNotFound, "curso_nao_encontrado"},
+		{erros.ErrSemVagas, http.StatusConflict, "sem_vagas"},
+		{erros.ErrLimiteInscricoes.ComMensagem("Limite de %d inscrições", 3), http.StatusUnprocessableEntity, "limite_inscricoes"},
+		{erros.ErrMuitasTentativas, http.StatusTooManyRequests, "muitas_tentativas"},
+		{errors.New("falha sem classificação"), http.StatusInternalServerError, "erro_interno"},
+	}
+	for _, caso := range casos {
+		router := roteadorComErros(func(c *gin.Context) { c.Error(caso.erro) })
+		w, resposta := requisitarErro(t, router, "")
+		if w.Code != caso.status || resposta.Code != caso.codigo {
+			t.Errorf("%v: esperava %d/%s, recebeu %d/%s", caso.erro, caso.status, caso.codigo, w.Code, resposta.Code)
+		}
+		if resposta.Message == "" || resposta.RequestID == "" || resposta.RequestID != w.Header().Get(CabecalhoIDRequisicao) {
+			t.Errorf("%v: resposta incompleta %+v", caso.erro, resposta)
+		}
+	}
+}
+
+func TestTratarErrosOcultaACausaDeErrosInternos(t *testing.T) {
+	router := roteadorComErros(func(c *gin.Context) {
+		c.Error(erros.ErrInterno.Envolver(errors.New("senha=segredo")))
+	})
+	_, resposta := requisitarErro(t, router, "proxy-123")
+	if resposta.Details != nil {
+		t.Errorf("detalhes de erro interno não devem ser expostos: %v", resposta.Details)
+	}
+	if resposta.RequestID != "proxy-123" {
+		t.Errorf("o identificador recebido deveria ser mantido, recebeu %q", resposta.RequestID)
+	}
+
+	router = roteadorComErros(func(c *gin.Context) {
+		c.Error(erros.ErrDadosInvalidos.Envolver(errors.New("campo nome obrigatório")))
+	})
+	_, resposta = requisitarErro(t, router, "id inválido com espaços")
+	if resposta.Details != "campo nome obrigatório" {
+		t.Errorf("esperava a causa nos detalhes, recebeu %v", resposta.Details)
+	}
+	if resposta.RequestID == "id inválido com espaços" {
+		t.Error("um identificador fora do formato deveria ser substituído")
+	}
+}
+
+func TestTratarErrosNaoSobrescreveRespostaEscrita(t *testing.T) {
+	router := roteadorComErros(func(c *gin.Context) {
+		c.Error(erros.ErrInterno)
+		c.JSON(http.StatusOK, gin.H{"message": "ok"})
+	})
+	req := httptest.NewRequest(http.MethodGet, "/teste", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("a resposta do handler deveria ser mantida, recebeu %d", w.Code)
+	}
+}
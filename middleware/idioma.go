@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"tvtec/i18n"
+)
+
+// ChaveIdioma é a chave do contexto Gin com o idioma escolhido para a resposta
+const ChaveIdioma = "idioma"
+
+// Idioma escolhe o idioma das mensagens pelo cabeçalho Accept-Language e o informa em Content-Language
+func Idioma() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idioma := i18n.Escolher(c.GetHeader("Accept-Language"))
+		c.Set(ChaveIdioma, idioma)
+		c.Header("Content-Language", string(idioma))
+		c.Writer.Header().Add("Vary", "Accept-Language")
+		c.Next()
+	}
+}
+
+// IdiomaDe devolve o idioma da requisição, ou o padrão quando o middleware Idioma não foi usado
+func IdiomaDe(c *gin.Context) i18n.Idioma {
+	if valor, ok := c.Get(ChaveIdioma); ok {
+		if idioma, ok := valor.(i18n.Idioma); ok {
+			return idioma
+		}
+	}
+	return i18n.Padrao
+}
+
+// Traduzir escreve a mensagem no idioma da requisição
+func Traduzir(c *gin.Context, formato string, argumentos ...interface{}) string {
+	return i18n.Traduzir(IdiomaDe(c), formato, argumentos...)
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"tvtec/erros"
+)
+
+func TestErrosSeguemOAcceptLanguage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(IDRequisicao(), Idioma(), TratarErros())
+	router.GET("/teste", func(c *gin.Context) {
+		c.Error(erros.ErrLimiteInscricoes.ComMensagem("O aluno já possui %d inscrições ativas, o máximo permitido é %d", 3, 3))
+	})
+
+	esperadas := map[string]string{
+		"":               "O aluno já possui 3 inscrições ativas, o máximo permitido é 3",
+		"es-MX,es;q=0.9": "El alumno ya tiene 3 inscripciones activas, el máximo permitido es 3",
+		"en-GB":          "The student already has 3 active enrollments, the maximum allowed is 3",
+	}
+	for cabecalho, esperada := range esperadas {
+		req := httptest.NewRequest(http.MethodGet, "/teste", nil)
+		req.Header.Set("Accept-Language", cabecalho)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var resposta RespostaErro
+		if err := json.Unmarshal(w.Body.Bytes(), &resposta); err != nil {
+			t.Fatal(err)
+		}
+		if resposta.Message != esperada || resposta.Code != "limite_inscricoes" {
+			t.Errorf("Accept-Language %q: recebeu %q (%s)", cabecalho, resposta.Message, resposta.Code)
+		}
+		if w.Header().Get("Vary") != "Accept-Language" || w.Header().Get("Content-Language") == "" {
+			t.Errorf("Accept-Language %q: cabeçalhos Vary=%q Content-Language=%q", cabecalho, w.Header().Get("Vary"), w.Header().Get("Content-Language"))
+		}
+	}
+}
+
+func TestCacheGuardaUmaRespostaPorIdioma(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	chamadas := 0
+	router := gin.New()
+	router.Use(Idioma())
+	router.GET("/curso", CacheHTTP(NewCacheRespostas(10), time.Minute), func(c *gin.Context) {
+		chamadas++
+		c.JSON(http.StatusOK, gin.H{"mensagemInscricoes": Traduzir(c, "aberto")})
+	})
+
+	corpos := map[string]string{}
+	for _, idioma := range []string{"pt-BR", "en", "pt-BR", "en"} {
+		req := httptest.NewRequest(http.MethodGet, "/curso", nil)
+		req.Header.Set("Accept-Language", idioma)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		corpos[idioma] = w.Body.String()
+	}
+	if chamadas != 2 {
+		t.Errorf("esperava uma chamada por idioma, foram %d", chamadas)
+	}
+	if corpos["pt-BR"] != `{"mensagemInscricoes":"aberto"}` || corpos["en"] != `{"mensagemInscricoes":"open"}` {
+		t.Errorf("respostas em cache misturaram idiomas: %v", corpos)
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"tvtec/erros"
+)
+
+var (
+	requisicoesHTTP = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "tvtec_http_requisicoes_total",
+		Help: "Requisições HTTP atendidas, por método, rota e status.",
+	}, []string{"metodo", "rota", "status"})
+	duracaoHTTP = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "tvtec_http_duracao_segundos",
+		Help:    "Tempo de atendimento das requisições HTTP, por método e rota.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"metodo", "rota"})
+)
+
+// MetricasHTTP conta as requisições e mede o tempo de resposta. O rótulo da rota é o padrão
+// registrado no Gin (ex.: /curso/:id), para que IDs não criem uma série por valor.
+func MetricasHTTP() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		inicio := time.Now()
+		c.Next()
+
+		rota := c.FullPath()
+		if rota == "" {
+			rota = "nao_encontrada"
+		}
+		metodo := c.Request.Method
+		requisicoesHTTP.WithLabelValues(metodo, rota, strconv.Itoa(c.Writer.Status())).Inc()
+		duracaoHTTP.WithLabelValues(metodo, rota).Observe(time.Since(inicio).Seconds())
+	}
+}
+
+// TokenMetricasMiddleware libera o /metrics apenas para quem envia "Bearer <token>"
+func TokenMetricasMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		recebido := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if token == "" || subtle.ConstantTimeCompare([]byte(recebido), []byte(token)) != 1 {
+			ResponderErro(c, erros.ErrTokenInvalido.ComMensagem("Token de métricas inválido"))
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func TestMetricasUsamRotaRegistradaEExigemToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(MetricasHTTP())
+	router.GET("/curso/:id", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	router.GET("/metrics", TokenMetricasMiddleware("segredo"), gin.WrapH(promhttp.Handler()))
+
+	requisitar(router, http.MethodGet, "/curso/42", "")
+
+	if w := requisitar(router, http.MethodGet, "/metrics", ""); w.Code != http.StatusUnauthorized {
+		t.Fatalf("esperava 401 sem token, recebeu %d", w.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer segredo")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("esperava 200 com token, recebeu %d", w.Code)
+	}
+
+	corpo := w.Body.String()
+	if !strings.Contains(corpo, `tvtec_http_requisicoes_total{metodo="GET",rota="/curso/:id",status="204"} 1`) {
+		t.Errorf("contador da rota ausente:\n%s", corpo)
+	}
+	if strings.Contains(corpo, "/curso/42") {
+		t.Errorf("o ID da URL não deve virar rótulo")
+	}
+}
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/gin-gonic/gin"
+
+	"tvtec/erros"
+)
+
+// ValidacaoOpenAPI recusa requisições fora da especificação OpenAPI. Com validarRespostas,
+// usado nos testes, a resposta fica retida até ser conferida e uma resposta fora do
+// contrato vira um erro 500 com o motivo.
+func ValidacaoOpenAPI(rotas routers.Router, validarRespostas bool) gin.HandlerFunc {
+	opcoes := &openapi3filter.Options{
+		// A autenticação é feita pelos middlewares de cada grupo de rotas
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+
+	return func(c *gin.Context) {
+		rota, parametros, err := rotas.FindRoute(c.Request)
+		if err != nil {
+			// Rotas fora da especificação (ex.: 404 do próprio Gin) seguem sem validação
+			c.Next()
+			return
+		}
+
+		entrada := &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: parametros,
+			Route:      rota,
+			Options:    opcoes,
+		}
+		if err := openapi3filter.ValidateRequest(c.Request.Context(), entrada); err != nil {
+			ResponderErro(c, erros.ErrDadosInvalidos.ComMensagem("Requisição fora da especificação da API").Envolver(err))
+			return
+		}
+
+		if !validarRespostas {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		retida := &respostaRetida{ResponseWriter: original, status: http.StatusOK}
+		c.Writer = retida
+		c.Next()
+		c.Writer = original
+
+		saida := &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: entrada,
+			Status:                 retida.status,
+			Header:                 original.Header(),
+			Options: &openapi3filter.Options{
+				IncludeResponseStatus: true,
+				// Apenas corpos JSON são conferidos; PDFs e calendários só têm o status verificado
+				ExcludeResponseBody: !strings.Contains(original.Header().Get("Content-Type"), "json"),
+			},
+		}
+		saida.SetBodyBytes(retida.corpo.Bytes())
+		if err := openapi3filter.ValidateResponse(c.Request.Context(), saida); err != nil {
+			log.Printf("Resposta de %s %s fora da especificação: %v", c.Request.Method, c.Request.URL.Path, err)
+			corpo, _ := json.Marshal(RespostaErro{
+				Code:      erros.ErrInterno.Codigo,
+				Message:   Traduzir(c, "Resposta fora da especificação da API"),
+				Details:   err.Error(),
+				RequestID: c.GetString(ChaveIDRequisicao),
+			})
+			original.Header().Set("Content-Type", "application/json; charset=utf-8")
+			original.WriteHeader(http.StatusInternalServerError)
+			original.Write(corpo)
+			return
+		}
+
+		original.WriteHeader(retida.status)
+		original.Write(retida.corpo.Bytes())
+	}
+}
+
+// respostaRetida guarda status e corpo em memória até a resposta ser validada
+type respostaRetida struct {
+	gin.ResponseWriter
+	status int
+	corpo  bytes.Buffer
+}
+
+func (r *respostaRetida) WriteHeader(status int) {
+	r.status = status
+}
+
+func (r *respostaRetida) WriteHeaderNow() {}
+
+func (r *respostaRetida) Write(dados []byte) (int, error) {
+	return r.corpo.Write(dados)
+}
+
+func (r *respostaRetida) WriteString(texto string) (int, error) {
+	return r.corpo.WriteString(texto)
+}
+
+func (r *respostaRetida) Status() int {
+	return r.status
+}
+
+func (r *respostaRetida) Size() int {
+	return r.corpo.Len()
+}
+
+func (r *respostaRetida) Written() bool {
+	return r.corpo.Len() > 0
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"tvtec/docs"
+)
+
+func roteadorValidado(t *testing.T) *gin.Engine {
+	t.Helper()
+	especificacao, err := docs.Carregar()
+	if err != nil {
+		t.Fatalf("especificação inválida: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ValidacaoOpenAPI(especificacao.Rotas, true))
+	router.POST("/aluno/inscricao", func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{
+			"message":   "ok",
+			"aluno":     gin.H{"id": 7, "nome": "Maria", "cpf": "123.***.***-09"},
+			"inscricao": gin.H{"id": 1, "cursoId": 3, "dataInscricao": "2026-10-01T10:00:00Z", "status": "ativa"},
+		})
+	})
+	router.GET("/curso/calendar.ics", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"))
+	})
+	router.GET("/curso/:id", func(c *gin.Context) {
+		// Falta a maior parte dos campos obrigatórios do curso
+		c.JSON(http.StatusOK, gin.H{"id": 3, "nome": "Informática básica"})
+	})
+	return router
+}
+
+func requisitar(router *gin.Engine, metodo, caminho, corpo string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(metodo, caminho, strings.NewReader(corpo))
+	if corpo != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestValidacaoOpenAPIRecusaRequisicaoInvalida(t *testing.T) {
+	router := roteadorValidado(t)
+
+	// curso deve ser numérico e a data de nascimento deve estar em DD/MM/AAAA
+	w := requisitar(router, http.MethodPost, "/aluno/inscricao",
+		`{"nome":"Maria","cpf":"123.456.789-09","email":"maria@example.com","curso":"3","dataNascto":"1990-05-17"}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("esperava 400, recebeu %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "especificação") {
+		t.Errorf("resposta sem o motivo da recusa: %s", w.Body.String())
+	}
+
+	w = requisitar(router, http.MethodGet, "/curso/abc", "")
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("ID não numérico deveria ser recusado, recebeu %d", w.Code)
+	}
+}
+
+func TestValidacaoOpenAPIAceitaRequisicaoValida(t *testing.T) {
+	router := roteadorValidado(t)
+
+	w := requisitar(router, http.MethodPost, "/aluno/inscricao",
+		`{"nome":"Maria","cpf":"123.456.789-09","email":"maria@example.com","curso":3,"dataNascto":"17/05/1990","bairro":"Centro"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("esperava 201, recebeu %d: %s", w.Code, w.Body.String())
+	}
+
+	// A rota fixa do calendário não pode ser confundida com /curso/{id}
+	w = requisitar(router, http.MethodGet, "/curso/calendar.ics", "")
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Body.String(), "BEGIN:VCALENDAR") {
+		t.Errorf("calendário público recusado: %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestValidacaoOpenAPIConfereRespostasNoModoTeste(t *testing.T) {
+	router := roteadorValidado(t)
+
+	w := requisitar(router, http.MethodGet, "/curso/3", "")
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("resposta fora do contrato deveria virar 500, recebeu %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "Resposta fora da especificação") {
+		t.Errorf("corpo inesperado: %s", w.Body.String())
+	}
+}
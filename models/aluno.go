@@ -13,6 +13,14 @@ type Aluno struct {
 	Sexo       string    `gorm:"not null" json:"sexo"`
 	Telefone   string    `json:"telefone"`
 	DataNascto time.Time `gorm:"not null" json:"dataNascto"`
+	Idioma     string    `gorm:"size:5;not null;default:'pt-BR'" json:"idioma"` // idioma das notificações (pt-BR, es ou en)
+
+	// Chave do link privado do calendário; trocá-la ou apagá-la invalida os links já enviados
+	ChaveCalendario string `json:"-"`
+
+	// Marcação de eliminação de dados pessoais (LGPD)
+	Anonimizado   bool       `gorm:"not null;default:false" json:"anonimizado"`
+	AnonimizadoEm *time.Time `json:"anonimizadoEm,omitempty"`
 
 	// Remover relação direta com Curso
 	// Em vez disso, podemos adicionar relação com Inscrições (se necessário)
@@ -0,0 +1,16 @@
+package models
+
+// Categoria agrupa cursos por área (ex.: informática, gastronomia).
+type Categoria struct {
+	ID   uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	Nome string `gorm:"not null;uniqueIndex" json:"nome"`
+
+	// Máximo de inscrições ativas por aluno em cursos da categoria; 0 significa sem limite
+	LimiteInscricoesAtivas int32 `gorm:"not null;default:0" json:"limiteInscricoesAtivas"`
+}
+
+// Tag é um rótulo livre usado na busca de cursos.
+type Tag struct {
+	ID   uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	Nome string `gorm:"not null;uniqueIndex" json:"nome"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Canais de comunicação sujeitos a consentimento
+const (
+	CanalWhatsApp = "whatsapp"
+	CanalEmail    = "email"
+	CanalSMS      = "sms"
+)
+
+// Finalidades de uso dos dados de contato
+const (
+	FinalidadeAvisosCursos = "avisos_cursos" // comunicações sobre cursos em que o aluno está inscrito
+	FinalidadeDivulgacao   = "divulgacao"    // divulgação de novos cursos
+)
+
+// Origens possíveis de um registro de consentimento
+const (
+	OrigemConsentimentoInscricao       = "inscricao"
+	OrigemConsentimentoAutoatendimento = "autoatendimento"
+	OrigemConsentimentoDescadastro     = "link_descadastro"
+	OrigemConsentimentoAdmin           = "admin"
+	OrigemConsentimentoEliminacao      = "eliminacao_lgpd"
+)
+
+// TermoConsentimento guarda cada versão publicada do texto dos termos de consentimento.
+type TermoConsentimento struct {
+	ID          uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Versao      string    `gorm:"not null;uniqueIndex" json:"versao"`
+	Texto       string    `gorm:"not null" json:"texto"`
+	PublicadoEm time.Time `gorm:"not null" json:"publicadoEm"`
+}
+
+// Consentimento é uma entrada do histórico de consentimentos de um aluno.
+// O registro nunca é alterado: uma revogação gera uma nova entrada.
+type Consentimento struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	AlunoID      uint      `gorm:"not null;index:idx_consentimento_aluno_canal" json:"alunoId"`
+	Canal        string    `gorm:"not null;index:idx_consentimento_aluno_canal" json:"canal"`
+	Finalidade   string    `gorm:"not null;index:idx_consentimento_aluno_canal" json:"finalidade"`
+	Concedido    bool      `gorm:"not null" json:"concedido"`
+	VersaoTermos string    `gorm:"not null" json:"versaoTermos"`
+	Origem       string    `gorm:"not null" json:"origem"`
+	IP           string    `json:"ip"`
+	DataRegistro time.Time `gorm:"not null" json:"dataRegistro"`
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"tvtec/i18n"
 )
 
 // CustomTime define um tipo customizado para datas.
@@ -44,18 +46,197 @@ func (ct *CustomTime) Scan(value interface{}) error {
 	}
 }
 
+// FusoHorario é o fuso usado para interpretar e exibir datas com horário
+var FusoHorario = carregarFusoHorario()
+
+func carregarFusoHorario() *time.Location {
+	if fuso, err := time.LoadLocation("America/Sao_Paulo"); err == nil {
+		return fuso
+	}
+	return time.FixedZone("BRT", -3*60*60)
+}
+
+// Situações do ciclo de vida de um curso
+const (
+	StatusCursoRascunho             = "rascunho"
+	StatusCursoPublicado            = "publicado"
+	StatusCursoInscricoesEncerradas = "inscricoes_encerradas"
+	StatusCursoEmAndamento          = "em_andamento"
+	StatusCursoConcluido            = "concluido"
+	StatusCursoCancelado            = "cancelado"
+)
+
+// transicoesCurso lista, para cada situação, as situações seguintes permitidas
+var transicoesCurso = map[string][]string{
+	StatusCursoRascunho:             {StatusCursoPublicado, StatusCursoCancelado},
+	StatusCursoPublicado:            {StatusCursoInscricoesEncerradas, StatusCursoEmAndamento, StatusCursoCancelado},
+	StatusCursoInscricoesEncerradas: {StatusCursoPublicado, StatusCursoEmAndamento, StatusCursoCancelado},
+	StatusCursoEmAndamento:          {StatusCursoConcluido, StatusCursoCancelado},
+	StatusCursoConcluido:            {},
+	StatusCursoCancelado:            {},
+}
+
+// StatusCursoValido indica se o texto corresponde a uma situação conhecida
+func StatusCursoValido(status string) bool {
+	_, ok := transicoesCurso[status]
+	return ok
+}
+
+// Situações do período de inscrições de um curso
+const (
+	InscricoesAguardando = "aguardando"
+	InscricoesAbertas    = "abertas"
+	InscricoesEncerradas = "encerradas"
+)
+
 // Curso representa um curso com data, carga horária, certificado e controle de vagas.
 type Curso struct {
 	ID               uint       `gorm:"primaryKey;autoIncrement" json:"id"`
 	Nome             string     `gorm:"not null" json:"nome"`
 	Professor        string     `gorm:"not null" json:"professor"`
-	Data             CustomTime `gorm:"not null" json:"data"`
+	ProfessorID      *uint      `gorm:"index" json:"professorId"`
+	Data             CustomTime `gorm:"not null;index" json:"data"`
 	CargaHoraria     int32      `gorm:"not null" json:"cargaHoraria"`
 	Certificado      string     `gorm:"not null" json:"certificado"`
 	VagasTotais      int32      `gorm:"not null" json:"vagasTotais"`
 	VagasPreenchidas int32      `gorm:"not null" json:"vagasPreenchidas"`
 
+	// Ciclo de vida; cursos em rascunho não aparecem nas rotas públicas
+	Status             string     `gorm:"not null;default:publicado;index" json:"status"`
+	CanceladoEm        *time.Time `json:"canceladoEm,omitempty"`
+	MotivoCancelamento string     `json:"motivoCancelamento,omitempty"`
+
+	// Horário das aulas no formato HH:MM; vazio significa que o curso ocupa o dia todo no
+	// calendário, mas não entra na verificação de conflito de horário
+	HoraInicio string `json:"horaInicio"`
+	HoraFim    string `json:"horaFim"`
+
+	// Período de inscrições; sem abertura, as inscrições abrem na criação do curso
+	// e, sem encerramento, fecham no início do curso
+	InscricoesAbertura     *time.Time `json:"inscricoesAbertura"`
+	InscricoesEncerramento *time.Time `json:"inscricoesEncerramento"`
+
+	// Calculados na consulta para exibição pública
+	SituacaoInscricoes string `gorm:"-" json:"situacaoInscricoes,omitempty"`
+	MensagemInscricoes string `gorm:"-" json:"mensagemInscricoes,omitempty"`
+
+	// Classificação usada na busca pública
+	CategoriaID *uint      `gorm:"index" json:"categoriaId"`
+	Categoria   *Categoria `gorm:"foreignKey:CategoriaID" json:"categoria,omitempty"`
+	Tags        []Tag      `gorm:"many2many:curso_tags" json:"tags"`
+
+	// Cursos que o aluno precisa ter concluído antes de se inscrever
+	PreRequisitos []Curso `gorm:"many2many:curso_prerequisitos;joinForeignKey:CursoID;joinReferences:PreRequisitoID" json:"preRequisitos,omitempty"`
+
+	// Restrições de público (idade, bairro, escolaridade, situação de trabalho)
+	RegrasElegibilidade []RegraElegibilidade `gorm:"foreignKey:CursoID" json:"regrasElegibilidade,omitempty"`
+
+	// Sala onde o curso acontece
+	SalaID *uint `gorm:"index" json:"salaId"`
+	Sala   *Sala `gorm:"foreignKey:SalaID" json:"sala,omitempty"`
+
 	// Remover referência direta a Aluno
 	// Em vez disso, podemos adicionar relação com Inscrições
 	Inscricoes []Inscricao `gorm:"foreignKey:CursoID" json:"inscricoes,omitempty"`
 }
+
+// PodeMudarPara indica se a transição da situação atual para a informada é permitida
+func (c *Curso) PodeMudarPara(status string) bool {
+	for _, permitido := range transicoesCurso[c.StatusAtual()] {
+		if permitido == status {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusAtual trata cursos sem situação gravada como publicados
+func (c *Curso) StatusAtual() string {
+	if c.Status == "" {
+		return StatusCursoPublicado
+	}
+	return c.Status
+}
+
+// DiaLocal devolve o dia de instante no FusoHorario no formato em que Data é gravada, à
+// meia-noite UTC, para comparar com as datas dos cursos
+func DiaLocal(instante time.Time) time.Time {
+	ano, mes, dia := instante.In(FusoHorario).Date()
+	return time.Date(ano, mes, dia, 0, 0, 0, 0, time.UTC)
+}
+
+// Inicio retorna o momento em que o curso começa no FusoHorario: o dia de Data na hora de
+// início ou, sem horário definido, à meia-noite
+func (c *Curso) Inicio() time.Time {
+	// A data do curso é gravada sem fuso, e o driver pode devolvê-la no fuso local do servidor
+	ano, mes, dia := c.Data.Time.UTC().Date()
+	if hora, err := time.Parse("15:04", c.HoraInicio); err == nil {
+		return time.Date(ano, mes, dia, hora.Hour(), hora.Minute(), 0, 0, FusoHorario)
+	}
+	return time.Date(ano, mes, dia, 0, 0, 0, 0, FusoHorario)
+}
+
+// FimInscricoes retorna o momento em que as inscrições deixam de ser aceitas
+func (c *Curso) FimInscricoes() time.Time {
+	if c.InscricoesEncerramento != nil {
+		return *c.InscricoesEncerramento
+	}
+	return c.Inicio()
+}
+
+// IniciadoEm indica se o curso já começou: pela situação, quando ela já foi avançada,
+// ou pelo horário de início
+func (c *Curso) IniciadoEm(agora time.Time) bool {
+	switch c.StatusAtual() {
+	case StatusCursoEmAndamento, StatusCursoConcluido:
+		return true
+	case StatusCursoRascunho, StatusCursoCancelado:
+		return false
+	}
+	return !agora.Before(c.Inicio())
+}
+
+// SituacaoInscricoesEm indica se as inscrições estão aguardando abertura, abertas ou encerradas
+func (c *Curso) SituacaoInscricoesEm(agora time.Time) string {
+	if c.StatusAtual() != StatusCursoPublicado {
+		return InscricoesEncerradas
+	}
+	if c.InscricoesAbertura != nil && agora.Before(*c.InscricoesAbertura) {
+		return InscricoesAguardando
+	}
+	if !agora.Before(c.FimInscricoes()) {
+		return InscricoesEncerradas
+	}
+	return InscricoesAbertas
+}
+
+// PreencherSituacaoInscricoes calcula os campos de exibição do período de inscrições
+func (c *Curso) PreencherSituacaoInscricoes(agora time.Time) {
+	c.SituacaoInscricoes = c.SituacaoInscricoesEm(agora)
+	c.MensagemInscricoes = c.MensagemInscricoesEm(i18n.Padrao)
+}
+
+// MensagemInscricoesEm descreve a situação calculada por PreencherSituacaoInscricoes no idioma pedido
+func (c *Curso) MensagemInscricoesEm(idioma i18n.Idioma) string {
+	switch c.SituacaoInscricoes {
+	case "":
+		return ""
+	case InscricoesAguardando:
+		return i18n.Traduzir(idioma, "abre em %s", formatarMomentoInscricoes(idioma, *c.InscricoesAbertura))
+	case InscricoesEncerradas:
+		if c.StatusAtual() == StatusCursoCancelado {
+			return i18n.Traduzir(idioma, "cancelado")
+		}
+		return i18n.Traduzir(idioma, "encerrado")
+	default:
+		if c.InscricoesEncerramento != nil {
+			return i18n.Traduzir(idioma, "aberto até %s", formatarMomentoInscricoes(idioma, *c.InscricoesEncerramento))
+		}
+		return i18n.Traduzir(idioma, "aberto")
+	}
+}
+
+func formatarMomentoInscricoes(idioma i18n.Idioma, momento time.Time) string {
+	momento = momento.In(FusoHorario)
+	return i18n.FormatarData(idioma, momento) + " " + momento.Format("15:04")
+}
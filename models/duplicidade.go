@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Situações de um conflito de cadastro
+const (
+	ConflitoPendente   = "pendente"
+	ConflitoAplicado   = "aplicado"   // o valor informado substituiu o cadastrado
+	ConflitoDescartado = "descartado" // o cadastro foi mantido
+)
+
+// Origens possíveis de um conflito de cadastro
+const (
+	OrigemConflitoInscricao  = "inscricao"
+	OrigemConflitoImportacao = "importacao"
+)
+
+// ConflitoCadastro registra um dado informado em uma nova inscrição que diverge do cadastro
+// existente do aluno. O cadastro não é alterado até que um administrador resolva o conflito.
+type ConflitoCadastro struct {
+	ID              uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	AlunoID         uint       `gorm:"not null;index" json:"alunoId"`
+	InscricaoID     uint       `json:"inscricaoId,omitempty"`
+	Campo           string     `gorm:"not null" json:"campo"`
+	ValorCadastrado string     `json:"valorCadastrado"`
+	ValorInformado  string     `json:"valorInformado"`
+	Origem          string     `gorm:"not null" json:"origem"`
+	Situacao        string     `gorm:"not null;default:pendente;index" json:"situacao"`
+	DataRegistro    time.Time  `gorm:"not null" json:"dataRegistro"`
+	ResolvidoPor    string     `json:"resolvidoPor,omitempty"`
+	ResolvidoEm     *time.Time `json:"resolvidoEm,omitempty"`
+}
+
+// FusaoAlunos é a trilha de auditoria de uma mesclagem de cadastros duplicados.
+// Os dados do aluno removido ficam guardados como estavam antes da fusão.
+type FusaoAlunos struct {
+	ID                    uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	AlunoMantidoID        uint      `gorm:"not null;index" json:"alunoMantidoId"`
+	AlunoRemovidoID       uint      `gorm:"not null;index" json:"alunoRemovidoId"`
+	DadosAlunoRemovido    string    `gorm:"type:text;not null" json:"dadosAlunoRemovido"` // JSON do cadastro removido
+	InscricoesMovidas     int       `gorm:"not null" json:"inscricoesMovidas"`
+	InscricoesDescartadas int       `gorm:"not null" json:"inscricoesDescartadas"` // mesmo curso nos dois cadastros
+	Motivo                string    `json:"motivo,omitempty"`
+	Responsavel           string    `gorm:"not null" json:"responsavel"`
+	IP                    string    `json:"ip"`
+	DataFusao             time.Time `gorm:"not null" json:"dataFusao"`
+}
@@ -0,0 +1,27 @@
+package models
+
+// Campos do aluno/inscrição que podem ser usados em regras de elegibilidade
+const (
+	CampoElegibilidadeIdade        = "idade"
+	CampoElegibilidadeBairro       = "bairro"
+	CampoElegibilidadeEscolaridade = "escolaridade"
+	CampoElegibilidadeTrabalhando  = "trabalhando"
+)
+
+// Operadores aceitos nas regras de elegibilidade
+const (
+	OperadorElegibilidadeMinimo = "min"    // idade mínima na data do curso
+	OperadorElegibilidadeMaximo = "max"    // idade máxima na data do curso
+	OperadorElegibilidadeEm     = "em"     // valor deve estar na lista
+	OperadorElegibilidadeNaoEm  = "nao_em" // valor não pode estar na lista
+)
+
+// RegraElegibilidade restringe quem pode se inscrever em um curso.
+// Todas as regras do curso precisam ser atendidas.
+type RegraElegibilidade struct {
+	ID       uint     `gorm:"primaryKey;autoIncrement" json:"id"`
+	CursoID  uint     `gorm:"not null;index" json:"cursoId"`
+	Campo    string   `gorm:"not null" json:"campo"`
+	Operador string   `gorm:"not null" json:"operador"`
+	Valores  []string `gorm:"serializer:json;not null" json:"valores"`
+}
@@ -4,6 +4,16 @@ import (
 	"time"
 )
 
+// Situações possíveis de uma inscrição
+const (
+	StatusInscricaoAtiva     = "ativa"
+	StatusInscricaoConcluida = "concluida"
+	// A organização cancelou o curso; a inscrição é mantida para histórico
+	StatusInscricaoCanceladaOrganizacao = "cancelada_organizacao"
+	// Situação publicada no evento inscricao.cancelada quando a inscrição é removida; não é gravada
+	StatusInscricaoCancelada = "cancelada"
+)
+
 // Inscricao representa o registro de inscrição de um aluno em um curso.
 type Inscricao struct {
 	ID            uint      `gorm:"primaryKey;autoIncrement" json:"id"`
@@ -11,6 +21,10 @@ type Inscricao struct {
 	CursoID       uint      `gorm:"not null" json:"cursoId"`
 	DataInscricao time.Time `gorm:"not null" json:"dataInscricao"`
 
+	// Situação da inscrição; "concluida" é usada na verificação de pré-requisitos
+	Status        string     `gorm:"not null;default:ativa;index" json:"status"`
+	DataConclusao *time.Time `json:"dataConclusao,omitempty"`
+
 	// Novos campos adicionados
 	Escolaridade      string `json:"escolaridade"`
 	Trabalhando       string `json:"trabalhando"`
@@ -0,0 +1,30 @@
+package models
+
+// Local representa o prédio onde os cursos acontecem.
+// Elevador e banheiro acessível são características do prédio como um todo.
+type Local struct {
+	ID                uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	Nome              string `gorm:"not null" json:"nome"`
+	Endereco          string `gorm:"not null" json:"endereco"`
+	Bairro            string `json:"bairro"`
+	TemElevador       bool   `gorm:"not null;default:false" json:"temElevador"`
+	BanheiroAcessivel bool   `gorm:"not null;default:false" json:"banheiroAcessivel"`
+
+	Salas []Sala `gorm:"foreignKey:LocalID" json:"salas,omitempty"`
+}
+
+// Sala é um espaço dentro de um local, com capacidade e andar próprios.
+type Sala struct {
+	ID         uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	LocalID    uint   `gorm:"not null;index" json:"localId"`
+	Nome       string `gorm:"not null" json:"nome"`
+	Andar      int    `gorm:"not null;default:0" json:"andar"` // 0 é o térreo
+	Capacidade int32  `gorm:"not null" json:"capacidade"`
+
+	Local *Local `gorm:"foreignKey:LocalID" json:"local,omitempty"`
+}
+
+// AcessivelSemElevador indica se a sala pode ser alcançada por quem não usa escadas
+func (s *Sala) AcessivelSemElevador() bool {
+	return s.Andar == 0 || (s.Local != nil && s.Local.TemElevador)
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Situações de um evento da outbox
+const (
+	EventoOutboxPendente   = "pendente"
+	EventoOutboxProcessado = "processado" // todos os assinantes trataram o evento
+	EventoOutboxFalhou     = "falhou"     // tentativas esgotadas; fica guardado para análise
+)
+
+// EventoOutbox é um evento de domínio gravado na mesma transação da alteração que o gerou.
+// O despachante o entrega a cada assinante pelo menos uma vez, lembrando quem já o tratou.
+type EventoOutbox struct {
+	ID                   uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Evento               string     `gorm:"not null;index" json:"evento"`
+	Payload              string     `gorm:"type:text;not null" json:"payload"` // JSON dos dados do evento
+	Situacao             string     `gorm:"not null;default:pendente;index:idx_outbox_pendentes" json:"situacao"`
+	Tentativas           int        `gorm:"not null" json:"tentativas"`
+	AssinantesConcluidos []string   `gorm:"serializer:json" json:"assinantesConcluidos"`
+	ProximaTentativa     time.Time  `gorm:"not null;index:idx_outbox_pendentes" json:"proximaTentativa"`
+	UltimoErro           string     `json:"ultimoErro,omitempty"`
+	CriadoEm             time.Time  `gorm:"not null" json:"criadoEm"`
+	ProcessadoEm         *time.Time `json:"processadoEm,omitempty"`
+}
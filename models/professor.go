@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Professor é o instrutor responsável por cursos; pode entrar no sistema com perfil restrito.
+type Professor struct {
+	ID       uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	Nome     string `gorm:"not null" json:"nome"`
+	Email    string `gorm:"not null;uniqueIndex" json:"email"`
+	Telefone string `json:"telefone"`
+	Bio      string `gorm:"type:text" json:"bio"`
+	FotoURL  string `json:"fotoUrl"`
+
+	// Acesso à área do professor; sem senha definida o login fica bloqueado
+	SenhaHash string `json:"-"`
+	Ativo     bool   `gorm:"not null;default:true" json:"ativo"`
+}
+
+// Presenca registra a frequência de um inscrito em um dia de aula.
+type Presenca struct {
+	ID            uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	InscricaoID   uint      `gorm:"not null;uniqueIndex:idx_presenca_inscricao_data" json:"inscricaoId"`
+	Data          time.Time `gorm:"type:date;not null;uniqueIndex:idx_presenca_inscricao_data" json:"data"`
+	Presente      bool      `gorm:"not null" json:"presente"`
+	RegistradoPor string    `gorm:"not null" json:"registradoPor"`
+	RegistradoEm  time.Time `gorm:"not null" json:"registradoEm"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Tipos de solicitação de titular previstos na LGPD
+const (
+	SolicitacaoLGPDExportacao = "exportacao"
+	SolicitacaoLGPDEliminacao = "eliminacao"
+	// Concessão ou revogação de consentimento feita pelo próprio aluno
+	SolicitacaoLGPDConsentimento = "consentimento"
+)
+
+// SolicitacaoLGPD registra cada pedido de exportação, eliminação ou alteração de consentimento de um aluno.
+// Os pedidos do próprio titular só são atendidos depois de confirmados pelo código enviado
+// ao email cadastrado; o código é guardado apenas como hash e vale uma única vez.
+type SolicitacaoLGPD struct {
+	ID                uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	AlunoID           uint       `gorm:"not null;index" json:"alunoId"`
+	Tipo              string     `gorm:"not null" json:"tipo"`
+	Formato           string     `json:"formato,omitempty"`
+	Solicitante       string     `gorm:"not null" json:"solicitante"` // "titular" ou o usuário administrador
+	IP                string     `json:"ip"`
+	DataSolicitacao   time.Time  `gorm:"not null" json:"dataSolicitacao"`
+	CodigoConfirmacao string     `json:"-"`
+	CodigoExpiraEm    *time.Time `json:"-"`
+	TentativasCodigo  int        `gorm:"not null;default:0" json:"-"`
+	ConfirmadaEm      *time.Time `json:"confirmadaEm,omitempty"`
+	Concluida         bool       `gorm:"not null;default:false" json:"concluida"`
+	Erro              string     `json:"erro,omitempty"`
+}
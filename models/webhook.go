@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// Eventos que podem ser assinados por webhooks
+const (
+	EventoInscricaoCriada    = "inscricao.criada"
+	EventoInscricaoCancelada = "inscricao.cancelada"
+	EventoCursoCriado        = "curso.criado"
+	EventoCursoAlterado      = "curso.alterado"
+	EventoCursoLotado        = "curso.lotado"
+	// Enviado apenas pela entrega de teste, não pode ser assinado
+	EventoWebhookTeste = "webhook.teste"
+)
+
+// EventosWebhook lista os eventos que podem ser assinados
+var EventosWebhook = []string{
+	EventoInscricaoCriada,
+	EventoInscricaoCancelada,
+	EventoCursoCriado,
+	EventoCursoAlterado,
+	EventoCursoLotado,
+}
+
+func EventoWebhookValido(evento string) bool {
+	for _, valido := range EventosWebhook {
+		if valido == evento {
+			return true
+		}
+	}
+	return false
+}
+
+// Webhook é um endereço de um sistema parceiro que recebe os eventos assinados.
+// O segredo assina o corpo de cada entrega e só é mostrado na criação.
+type Webhook struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	URL       string    `gorm:"not null" json:"url"`
+	Descricao string    `json:"descricao"`
+	Eventos   []string  `gorm:"serializer:json;not null" json:"eventos"`
+	Segredo   string    `gorm:"not null" json:"-"`
+	Ativo     bool      `gorm:"not null;default:true" json:"ativo"`
+	CriadoEm  time.Time `gorm:"not null" json:"criadoEm"`
+}
+
+// Situações de uma entrega de webhook
+const (
+	EntregaWebhookPendente = "pendente"
+	EntregaWebhookEntregue = "entregue"
+	EntregaWebhookFalhou   = "falhou" // tentativas esgotadas
+)
+
+// EntregaWebhook é o registro de envio de um evento para um webhook, com o resultado da última tentativa
+type EntregaWebhook struct {
+	ID               uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	WebhookID        uint       `gorm:"not null;index;uniqueIndex:idx_entrega_evento_webhook,where:evento_id <> 0" json:"webhookId"`
+	EventoID         uint       `gorm:"index;uniqueIndex:idx_entrega_evento_webhook,where:evento_id <> 0" json:"eventoId,omitempty"` // evento da outbox; zero nas entregas de teste
+	Evento           string     `gorm:"not null" json:"evento"`
+	Payload          string     `gorm:"type:text;not null" json:"payload"`
+	Situacao         string     `gorm:"not null;default:pendente;index" json:"situacao"`
+	Tentativas       int        `gorm:"not null" json:"tentativas"`
+	ProximaTentativa *time.Time `gorm:"index" json:"proximaTentativa,omitempty"`
+	UltimoStatus     int        `json:"ultimoStatus,omitempty"`
+	UltimoErro       string     `json:"ultimoErro,omitempty"`
+	RespostaResumo   string     `json:"respostaResumo,omitempty"` // início do corpo da última resposta
+	DuracaoMs        int64      `json:"duracaoMs"`
+	CriadaEm         time.Time  `gorm:"not null" json:"criadaEm"`
+	EntregueEm       *time.Time `json:"entregueEm,omitempty"`
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+
+	"tvtec/models"
+)
+
+const nomeObservadorCatalogo = "tvtec:alteracao_catalogo"
+
+// observadorCatalogo adia o aviso das escritas feitas dentro de uma transação até a confirmação;
+// as transações são identificadas pela conexão que o GORM entrega às instruções
+type observadorCatalogo struct {
+	aviso      func()
+	mu         sync.Mutex
+	pendencias map[gorm.ConnPool]bool
+}
+
+func (o *observadorCatalogo) Name() string {
+	return nomeObservadorCatalogo
+}
+
+func (o *observadorCatalogo) Initialize(*gorm.DB) error {
+	return nil
+}
+
+// alterou avisa de imediato as escritas fora de transação, já confirmadas pelo banco
+func (o *observadorCatalogo) alterou(tx *gorm.DB) {
+	if _, emTransacao := tx.Statement.ConnPool.(gorm.TxCommitter); !emTransacao {
+		o.aviso()
+		return
+	}
+	o.mu.Lock()
+	o.pendencias[tx.Statement.ConnPool] = true
+	o.mu.Unlock()
+}
+
+// encerrou descarta a pendência da transação e avisa apenas se ela foi confirmada
+func (o *observadorCatalogo) encerrou(conexao gorm.ConnPool, confirmada bool) {
+	o.mu.Lock()
+	pendente := o.pendencias[conexao]
+	delete(o.pendencias, conexao)
+	o.mu.Unlock()
+	if pendente && confirmada {
+		o.aviso()
+	}
+}
+
+// ObservarAlteracoesCatalogo chama aviso depois de cada escrita confirmada nas tabelas exibidas nas
+// rotas públicas de cursos: cursos e suas associações, inscrições (vagas), categorias, locais e salas.
+// Os callbacks valem para toda sessão aberta a partir de db, inclusive comandos Exec; escritas em
+// transação só avisam depois do commit, por isso as transações passam por transacionar.
+func ObservarAlteracoesCatalogo(db *gorm.DB, aviso func()) error {
+	tabelas, err := tabelasCatalogo(db)
+	if err != nil {
+		return err
+	}
+	observador := &observadorCatalogo{aviso: aviso, pendencias: make(map[gorm.ConnPool]bool)}
+	if err := db.Use(observador); err != nil {
+		return err
+	}
+
+	observar := func(tx *gorm.DB) {
+		if tx.Error != nil {
+			return
+		}
+		if tabelas[tx.Statement.Table] {
+			observador.alterou(tx)
+			return
+		}
+		// Comandos Exec não informam a tabela; basta o SQL citar uma delas
+		if tx.Statement.Table == "" {
+			sql := tx.Statement.SQL.String()
+			for tabela := range tabelas {
+				if strings.Contains(sql, tabela) {
+					observador.alterou(tx)
+					return
+				}
+			}
+		}
+	}
+
+	callbacks := db.Callback()
+	if err := callbacks.Create().After("gorm:create").Register(nomeObservadorCatalogo, observar); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("gorm:update").Register(nomeObservadorCatalogo, observar); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().After("gorm:delete").Register(nomeObservadorCatalogo, observar); err != nil {
+		return err
+	}
+	return callbacks.Raw().After("gorm:raw").Register(nomeObservadorCatalogo, observar)
+}
+
+// tabelasCatalogo resolve os nomes das tabelas pelo GORM, incluindo as tabelas de junção (tags, pré-requisitos)
+func tabelasCatalogo(db *gorm.DB) (map[string]bool, error) {
+	tabelas := make(map[string]bool)
+	for _, modelo := range []interface{}{
+		&models.Curso{},
+		&models.Inscricao{},
+		&models.Tag{},
+		&models.Categoria{},
+		&models.Local{},
+		&models.Sala{},
+		&models.RegraElegibilidade{},
+	} {
+		instrucao := &gorm.Statement{DB: db}
+		if err := instrucao.Parse(modelo); err != nil {
+			return nil, err
+		}
+		tabelas[instrucao.Schema.Table] = true
+		for _, relacao := range instrucao.Schema.Relationships.Relations {
+			if relacao.JoinTable != nil {
+				tabelas[relacao.JoinTable.Table] = true
+			}
+		}
+	}
+	return tabelas, nil
+}
+
+// transacionar executa operacao em uma transação e, na transação mais externa, libera os avisos de
+// alteração do catálogo depois do commit ou os descarta no rollback. Transações aninhadas viram
+// savepoints e são encerradas junto com a externa.
+func transacionar(db *gorm.DB, operacao func(tx *gorm.DB) error) error {
+	observador, ok := db.Config.Plugins[nomeObservadorCatalogo].(*observadorCatalogo)
+	if _, aninhada := db.Statement.ConnPool.(gorm.TxCommitter); !ok || aninhada {
+		return db.Transaction(operacao)
+	}
+
+	var conexao gorm.ConnPool
+	err := db.Transaction(func(tx *gorm.DB) error {
+		conexao = tx.Statement.ConnPool
+		return operacao(tx)
+	})
+	observador.encerrou(conexao, err == nil)
+	return err
+}
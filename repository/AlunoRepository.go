@@ -2,6 +2,7 @@ package repository
 
 import (
 	"errors"
+	"tvtec/erros"
 	"tvtec/models"
 
 	"gorm.io/gorm"
@@ -15,6 +16,7 @@ type AlunoRepository interface {
 	Delete(id uint) error
 	FindByCPF(cpf string) (*models.Aluno, error)
 	FindByEmail(email string) (*models.Aluno, error)
+	AtualizarChaveCalendario(id uint, chave string) error
 }
 
 type alunoRepository struct {
@@ -36,7 +38,7 @@ func (r *alunoRepository) FindByID(id uint) (*models.Aluno, error) {
 	result := r.db.First(&aluno, id)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, errors.New("aluno não encontrado")
+			return nil, erros.ErrAlunoNaoEncontrado
 		}
 		return nil, result.Error
 	}
@@ -51,10 +53,22 @@ func (r *alunoRepository) Update(aluno *models.Aluno) error {
 	return r.db.Save(aluno).Error
 }
 
+// AtualizarChaveCalendario grava apenas a chave do link do calendário, sem tocar no restante do cadastro
+func (r *alunoRepository) AtualizarChaveCalendario(id uint, chave string) error {
+	result := r.db.Model(&models.Aluno{}).Where("id = ?", id).Update("chave_calendario", chave)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return erros.ErrAlunoNaoEncontrado
+	}
+	return nil
+}
+
 func (r *alunoRepository) Delete(id uint) error {
 	result := r.db.Delete(&models.Aluno{}, id)
 	if result.RowsAffected == 0 {
-		return errors.New("aluno não encontrado")
+		return erros.ErrAlunoNaoEncontrado
 	}
 	return result.Error
 }
@@ -64,7 +78,7 @@ func (r *alunoRepository) FindByCPF(cpf string) (*models.Aluno, error) {
 	result := r.db.Where("cpf = ?", cpf).First(&aluno)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, errors.New("aluno não encontrado")
+			return nil, erros.ErrAlunoNaoEncontrado
 		}
 		return nil, result.Error
 	}
@@ -75,7 +89,7 @@ func (r *alunoRepository) FindByEmail(email string) (*models.Aluno, error) {
 	result := r.db.Where("email = ?", email).First(&aluno)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, errors.New("aluno não encontrado")
+			return nil, erros.ErrAlunoNaoEncontrado
 		}
 		return nil, result.Error
 	}
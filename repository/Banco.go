@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"tvtec/config"
+	"tvtec/models"
+)
+
+// AbrirBanco conecta ao banco do driver configurado. No SQLite o dsn é o caminho do arquivo,
+// ou "file::memory:" para um banco temporário.
+func AbrirBanco(driver, dsn string) (*gorm.DB, error) {
+	switch driver {
+	case config.DriverPostgres:
+		return gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	case config.DriverSQLite:
+		db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+		if err != nil {
+			return nil, err
+		}
+		// Uma única conexão: o SQLite não aceita escritas concorrentes e cada conexão
+		// com "file::memory:" abriria um banco vazio diferente
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		sqlDB.SetMaxOpenConns(1)
+		if err := db.Exec("PRAGMA foreign_keys = ON").Error; err != nil {
+			return nil, err
+		}
+		return db, nil
+	default:
+		return nil, fmt.Errorf("driver de banco não suportado: %s", driver)
+	}
+}
+
+// Migrar cria/atualiza as tabelas de todos os modelos e os índices de busca
+func Migrar(db *gorm.DB) error {
+	if err := removerEntregasDuplicadas(db); err != nil {
+		return err
+	}
+	if err := db.AutoMigrate(
+		&models.Aluno{},
+		&models.Categoria{},
+		&models.Tag{},
+		&models.Local{},
+		&models.Sala{},
+		&models.Professor{},
+		&models.Curso{},
+		&models.RegraElegibilidade{},
+		&models.Presenca{},
+		&models.Inscricao{},
+		&models.SolicitacaoLGPD{},
+		&models.TermoConsentimento{},
+		&models.Consentimento{},
+		&models.ConflitoCadastro{},
+		&models.FusaoAlunos{},
+		&models.Webhook{},
+		&models.EntregaWebhook{},
+		&models.EventoOutbox{},
+	); err != nil {
+		return err
+	}
+	return NewCursoRepository(db).CriarIndicesBusca()
+}
+
+// removerEntregasDuplicadas apaga as entregas repetidas de um mesmo evento e webhook, mantendo a mais antiga,
+// para que o índice único idx_entrega_evento_webhook possa ser criado em bancos anteriores a ele
+func removerEntregasDuplicadas(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&models.EntregaWebhook{}) || migrator.HasIndex(&models.EntregaWebhook{}, "idx_entrega_evento_webhook") {
+		return nil
+	}
+	return db.Exec(`DELETE FROM entrega_webhooks WHERE evento_id <> 0 AND id NOT IN (
+		SELECT MIN(id) FROM entrega_webhooks WHERE evento_id <> 0 GROUP BY webhook_id, evento_id)`).Error
+}
+
+// usaPostgres indica se recursos específicos do PostgreSQL (busca textual, índices GIN) estão disponíveis
+func usaPostgres(db *gorm.DB) bool {
+	return db.Dialector.Name() == "postgres"
+}
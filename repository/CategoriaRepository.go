@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"errors"
+	"tvtec/erros"
+	"tvtec/models"
+
+	"gorm.io/gorm"
+)
+
+type CategoriaRepository interface {
+	FindAll() ([]models.Categoria, error)
+	FindByID(id uint) (*models.Categoria, error)
+	FindByNome(nome string) (*models.Categoria, error)
+	Save(categoria *models.Categoria) error
+	Delete(id uint) error
+}
+
+type categoriaRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoriaRepository(db *gorm.DB) CategoriaRepository {
+	return &categoriaRepository{db: db}
+}
+
+func (r *categoriaRepository) FindAll() ([]models.Categoria, error) {
+	var categorias []models.Categoria
+	result := r.db.Order("nome").Find(&categorias)
+	return categorias, result.Error
+}
+
+func (r *categoriaRepository) FindByID(id uint) (*models.Categoria, error) {
+	var categoria models.Categoria
+	result := r.db.First(&categoria, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, erros.ErrCategoriaNaoEncontrada
+		}
+		return nil, result.Error
+	}
+	return &categoria, nil
+}
+
+func (r *categoriaRepository) FindByNome(nome string) (*models.Categoria, error) {
+	var categoria models.Categoria
+	result := r.db.Where("LOWER(nome) = LOWER(?)", nome).First(&categoria)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, erros.ErrCategoriaNaoEncontrada
+		}
+		return nil, result.Error
+	}
+	return &categoria, nil
+}
+
+func (r *categoriaRepository) Save(categoria *models.Categoria) error {
+	return r.db.Save(categoria).Error
+}
+
+func (r *categoriaRepository) Delete(id uint) error {
+	return transacionar(r.db, func(tx *gorm.DB) error {
+		// Cursos da categoria ficam sem categoria em vez de serem removidos
+		if err := tx.Model(&models.Curso{}).Where("categoria_id = ?", id).Update("categoria_id", nil).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&models.Categoria{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return erros.ErrCategoriaNaoEncontrada
+		}
+		return nil
+	})
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"errors"
+	"tvtec/erros"
+	"tvtec/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrConsentimentoNaoEncontrado indica que o aluno nunca registrou consentimento para o canal/finalidade
+var ErrConsentimentoNaoEncontrado = errors.New("consentimento não encontrado")
+
+type ConsentimentoRepository interface {
+	FindByAluno(alunoID uint) ([]models.Consentimento, error)
+	FindUltimo(alunoID uint, canal, finalidade string) (*models.Consentimento, error)
+	Save(consentimento *models.Consentimento) error
+	FindTermoVigente() (*models.TermoConsentimento, error)
+	FindTermoByVersao(versao string) (*models.TermoConsentimento, error)
+	SaveTermo(termo *models.TermoConsentimento) error
+}
+
+type consentimentoRepository struct {
+	db *gorm.DB
+}
+
+func NewConsentimentoRepository(db *gorm.DB) ConsentimentoRepository {
+	return &consentimentoRepository{db: db}
+}
+
+func (r *consentimentoRepository) FindByAluno(alunoID uint) ([]models.Consentimento, error) {
+	var consentimentos []models.Consentimento
+	result := r.db.Where("aluno_id = ?", alunoID).Order("data_registro, id").Find(&consentimentos)
+	return consentimentos, result.Error
+}
+
+// FindUltimo retorna o registro mais recente, que representa o estado atual do consentimento
+func (r *consentimentoRepository) FindUltimo(alunoID uint, canal, finalidade string) (*models.Consentimento, error) {
+	var consentimento models.Consentimento
+	result := r.db.Where("aluno_id = ? AND canal = ? AND finalidade = ?", alunoID, canal, finalidade).
+		Order("data_registro DESC, id DESC").
+		First(&consentimento)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrConsentimentoNaoEncontrado
+		}
+		return nil, result.Error
+	}
+	return &consentimento, nil
+}
+
+func (r *consentimentoRepository) Save(consentimento *models.Consentimento) error {
+	return r.db.Create(consentimento).Error
+}
+
+func (r *consentimentoRepository) FindTermoVigente() (*models.TermoConsentimento, error) {
+	var termo models.TermoConsentimento
+	result := r.db.Order("publicado_em DESC, id DESC").First(&termo)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, erros.ErrTermoNaoEncontrado
+		}
+		return nil, result.Error
+	}
+	return &termo, nil
+}
+
+func (r *consentimentoRepository) FindTermoByVersao(versao string) (*models.TermoConsentimento, error) {
+	var termo models.TermoConsentimento
+	result := r.db.Where("versao = ?", versao).First(&termo)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, erros.ErrTermoNaoEncontrado.ComMensagem("Versão dos termos não encontrada")
+		}
+		return nil, result.Error
+	}
+	return &termo, nil
+}
+
+func (r *consentimentoRepository) SaveTermo(termo *models.TermoConsentimento) error {
+	return r.db.Create(termo).Error
+}
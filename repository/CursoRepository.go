@@ -2,19 +2,59 @@ package repository
 
 import (
 	"errors"
+	"strings"
+	"time"
+	"tvtec/erros"
 	"tvtec/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// FiltroCurso reúne os critérios da busca pública de cursos
+type FiltroCurso struct {
+	Texto          string // busca textual em nome e professor
+	CategoriaID    uint
+	Tag            string
+	DataInicio     *time.Time
+	DataFim        *time.Time
+	ApenasComVagas bool
+	// Rascunhos só aparecem para a administração
+	IncluirRascunhos bool
+	Ordenacao        string // data, -data, nome, -nome, vagas
+}
+
+// Ordenações aceitas pela busca, mapeadas para cláusulas SQL seguras
+var ordenacoesCurso = map[string]string{
+	"":      "data ASC, id ASC",
+	"data":  "data ASC, id ASC",
+	"-data": "data DESC, id DESC",
+	"nome":  "nome ASC, id ASC",
+	"-nome": "nome DESC, id DESC",
+	"vagas": "(vagas_totais - vagas_preenchidas) DESC, data ASC",
+}
+
+// OrdenacaoCursoValida indica se o valor pode ser usado em FiltroCurso.Ordenacao
+func OrdenacaoCursoValida(ordenacao string) bool {
+	_, ok := ordenacoesCurso[ordenacao]
+	return ok
+}
+
 type CursoRepository interface {
 	FindAll() ([]models.Curso, error)
+	Buscar(filtro FiltroCurso) ([]models.Curso, error)
 	FindByID(id uint) (*models.Curso, error)
 	Save(curso *models.Curso) error
 	Update(curso *models.Curso) error
 	Delete(id uint) error
 	IncrementarVagasPreenchidas(cursoID uint) error
 	DecrementarVagasPreenchidas(cursoID uint) error
+	FindBySala(salaID uint) ([]models.Curso, error)
+	FindByProfessor(professorID uint) ([]models.Curso, error)
+	AtualizarTags(curso *models.Curso, nomes []string) error
+	AtualizarPreRequisitos(curso *models.Curso, ids []uint) error
+	SubstituirRegrasElegibilidade(cursoID uint, regras []models.RegraElegibilidade) error
+	CriarIndicesBusca() error
 }
 
 type cursoRepository struct {
@@ -27,16 +67,62 @@ func NewCursoRepository(db *gorm.DB) CursoRepository {
 
 func (r *cursoRepository) FindAll() ([]models.Curso, error) {
 	var cursos []models.Curso
-	result := r.db.Find(&cursos)
+	result := r.db.Preload("Categoria").Preload("Tags").Preload("Sala.Local").Preload("PreRequisitos").Preload("RegrasElegibilidade").Find(&cursos)
+	return cursos, result.Error
+}
+
+func (r *cursoRepository) Buscar(filtro FiltroCurso) ([]models.Curso, error) {
+	ordem, ok := ordenacoesCurso[filtro.Ordenacao]
+	if !ok {
+		return nil, erros.ErrDadosInvalidos.ComMensagem("Ordenação inválida")
+	}
+
+	query := r.db.Model(&models.Curso{})
+
+	if texto := strings.TrimSpace(filtro.Texto); texto != "" {
+		if usaPostgres(r.db) {
+			// Usa o índice de texto completo criado em CriarIndicesBusca
+			query = query.Where("to_tsvector('portuguese', nome || ' ' || professor) @@ plainto_tsquery('portuguese', ?)", texto)
+		} else {
+			// Sem busca textual no SQLite: cada palavra precisa aparecer no nome ou no professor
+			for _, palavra := range strings.Fields(strings.ToLower(texto)) {
+				query = query.Where("LOWER(nome || ' ' || professor) LIKE ?", "%"+palavra+"%")
+			}
+		}
+	}
+	if filtro.CategoriaID != 0 {
+		query = query.Where("categoria_id = ?", filtro.CategoriaID)
+	}
+	if filtro.Tag != "" {
+		query = query.Where("id IN (?)", r.db.Table("curso_tags").
+			Select("curso_tags.curso_id").
+			Joins("JOIN tags ON tags.id = curso_tags.tag_id").
+			Where("LOWER(tags.nome) = LOWER(?)", filtro.Tag))
+	}
+	if filtro.DataInicio != nil {
+		query = query.Where("data >= ?", *filtro.DataInicio)
+	}
+	if filtro.DataFim != nil {
+		query = query.Where("data <= ?", *filtro.DataFim)
+	}
+	if filtro.ApenasComVagas {
+		query = query.Where("vagas_preenchidas < vagas_totais")
+	}
+	if !filtro.IncluirRascunhos {
+		query = query.Where("status <> ?", models.StatusCursoRascunho)
+	}
+
+	var cursos []models.Curso
+	result := query.Preload("Categoria").Preload("Tags").Preload("Sala.Local").Preload("PreRequisitos").Preload("RegrasElegibilidade").Order(ordem).Find(&cursos)
 	return cursos, result.Error
 }
 
 func (r *cursoRepository) FindByID(id uint) (*models.Curso, error) {
 	var curso models.Curso
-	result := r.db.First(&curso, id)
+	result := r.db.Preload("Categoria").Preload("Tags").Preload("Sala.Local").Preload("PreRequisitos").Preload("RegrasElegibilidade").First(&curso, id)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, errors.New("curso não encontrado")
+			return nil, erros.ErrCursoNaoEncontrado
 		}
 		return nil, result.Error
 	}
@@ -46,24 +132,53 @@ func (r *cursoRepository) FindByID(id uint) (*models.Curso, error) {
 func (r *cursoRepository) Save(curso *models.Curso) error {
 	// Garantir que vagas preenchidas começa com zero
 	curso.VagasPreenchidas = 0
-	return r.db.Create(curso).Error
+	return r.db.Omit(clause.Associations).Create(curso).Error
 }
 
 func (r *cursoRepository) Update(curso *models.Curso) error {
-	return r.db.Save(curso).Error
+	// Categoria e tags são mantidas pelos próprios IDs/AtualizarTags, não pelas associações carregadas
+	return r.db.Omit(clause.Associations).Save(curso).Error
+}
+
+func (r *cursoRepository) FindBySala(salaID uint) ([]models.Curso, error) {
+	var cursos []models.Curso
+	result := r.db.Where("sala_id = ?", salaID).Find(&cursos)
+	return cursos, result.Error
+}
+
+func (r *cursoRepository) FindByProfessor(professorID uint) ([]models.Curso, error) {
+	var cursos []models.Curso
+	result := r.db.Where("professor_id = ?", professorID).Preload("Sala.Local").Order("data").Find(&cursos)
+	return cursos, result.Error
 }
 
 func (r *cursoRepository) Delete(id uint) error {
-	result := r.db.Delete(&models.Curso{}, id)
-	if result.RowsAffected == 0 {
-		return errors.New("curso não encontrado")
-	}
-	return result.Error
+	return transacionar(r.db, func(tx *gorm.DB) error {
+		// Remove os vínculos de tags e pré-requisitos antes do próprio curso
+		if err := tx.Exec("DELETE FROM curso_tags WHERE curso_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM curso_prerequisitos WHERE curso_id = ? OR pre_requisito_id = ?", id, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("curso_id = ?", id).Delete(&models.RegraElegibilidade{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&models.Curso{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return erros.ErrCursoNaoEncontrado
+		}
+		return nil
+	})
 }
 
 func (r *cursoRepository) IncrementarVagasPreenchidas(cursoID uint) error {
 	// Usar uma transação para evitar condições de corrida
-	return r.db.Transaction(func(tx *gorm.DB) error {
+	return transacionar(r.db, func(tx *gorm.DB) error {
 		var curso models.Curso
 		// Primeiro, obter o curso atual com bloqueio para atualização
 		if err := tx.Set("gorm:query_option", "FOR UPDATE").First(&curso, cursoID).Error; err != nil {
@@ -72,7 +187,7 @@ func (r *cursoRepository) IncrementarVagasPreenchidas(cursoID uint) error {
 
 		// Verificar se há vagas disponíveis
 		if curso.VagasPreenchidas >= curso.VagasTotais {
-			return errors.New("não há vagas disponíveis no curso")
+			return erros.ErrSemVagas
 		}
 
 		// Incrementar e salvar
@@ -83,7 +198,7 @@ func (r *cursoRepository) IncrementarVagasPreenchidas(cursoID uint) error {
 
 func (r *cursoRepository) DecrementarVagasPreenchidas(cursoID uint) error {
 	// Usar uma transação para evitar condições de corrida
-	return r.db.Transaction(func(tx *gorm.DB) error {
+	return transacionar(r.db, func(tx *gorm.DB) error {
 		var curso models.Curso
 		// Primeiro, obter o curso atual com bloqueio para atualização
 		if err := tx.Set("gorm:query_option", "FOR UPDATE").First(&curso, cursoID).Error; err != nil {
@@ -92,7 +207,7 @@ func (r *cursoRepository) DecrementarVagasPreenchidas(cursoID uint) error {
 
 		// Verificar se há vagas preenchidas para decrementar
 		if curso.VagasPreenchidas <= 0 {
-			return errors.New("não há vagas preenchidas para decrementar")
+			return erros.ErrConflito.ComMensagem("Não há vagas preenchidas para decrementar")
 		}
 
 		// Decrementar e salvar
@@ -100,3 +215,78 @@ func (r *cursoRepository) DecrementarVagasPreenchidas(cursoID uint) error {
 		return tx.Save(&curso).Error
 	})
 }
+
+// AtualizarTags substitui as tags do curso, criando as que ainda não existem
+func (r *cursoRepository) AtualizarTags(curso *models.Curso, nomes []string) error {
+	return transacionar(r.db, func(tx *gorm.DB) error {
+		tags := make([]models.Tag, 0, len(nomes))
+		vistos := make(map[string]bool)
+		for _, nome := range nomes {
+			nome = strings.ToLower(strings.TrimSpace(nome))
+			if nome == "" || vistos[nome] {
+				continue
+			}
+			vistos[nome] = true
+
+			tag := models.Tag{Nome: nome}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&tag).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("nome = ?", nome).First(&tag).Error; err != nil {
+				return err
+			}
+			tags = append(tags, tag)
+		}
+
+		if err := tx.Model(curso).Association("Tags").Replace(tags); err != nil {
+			return err
+		}
+		curso.Tags = tags
+		return nil
+	})
+}
+
+// AtualizarPreRequisitos substitui a lista de cursos exigidos antes da inscrição
+func (r *cursoRepository) AtualizarPreRequisitos(curso *models.Curso, ids []uint) error {
+	preRequisitos := []models.Curso{}
+	if len(ids) > 0 {
+		if err := r.db.Find(&preRequisitos, ids).Error; err != nil {
+			return err
+		}
+		if len(preRequisitos) != len(ids) {
+			return erros.ErrCursoNaoEncontrado.ComMensagem("Pré-requisito não encontrado")
+		}
+	}
+
+	if err := r.db.Model(curso).Association("PreRequisitos").Replace(preRequisitos); err != nil {
+		return err
+	}
+	curso.PreRequisitos = preRequisitos
+	return nil
+}
+
+// SubstituirRegrasElegibilidade troca todas as regras do curso pelas informadas
+func (r *cursoRepository) SubstituirRegrasElegibilidade(cursoID uint, regras []models.RegraElegibilidade) error {
+	return transacionar(r.db, func(tx *gorm.DB) error {
+		if err := tx.Where("curso_id = ?", cursoID).Delete(&models.RegraElegibilidade{}).Error; err != nil {
+			return err
+		}
+		if len(regras) == 0 {
+			return nil
+		}
+		for i := range regras {
+			regras[i].ID = 0
+			regras[i].CursoID = cursoID
+		}
+		return tx.Create(&regras).Error
+	})
+}
+
+// CriarIndicesBusca cria os índices que o AutoMigrate não consegue expressar
+func (r *cursoRepository) CriarIndicesBusca() error {
+	if !usaPostgres(r.db) {
+		return nil
+	}
+	return r.db.Exec("CREATE INDEX IF NOT EXISTS idx_cursos_busca_texto ON cursos " +
+		"USING GIN (to_tsvector('portuguese', nome || ' ' || professor))").Error
+}
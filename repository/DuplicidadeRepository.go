@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"errors"
+	"time"
+	"tvtec/erros"
+	"tvtec/models"
+
+	"gorm.io/gorm"
+)
+
+type DuplicidadeRepository interface {
+	SaveConflito(conflito *models.ConflitoCadastro) error
+	FindConflitos(situacao string) ([]models.ConflitoCadastro, error)
+	FindConflitoByID(id uint) (*models.ConflitoCadastro, error)
+	ResolverConflito(conflito *models.ConflitoCadastro, aluno *models.Aluno) error
+	FindFusoes() ([]models.FusaoAlunos, error)
+	Mesclar(mantido *models.Aluno, removidoID uint, fusao *models.FusaoAlunos) ([]models.Inscricao, error)
+	FindConflitosByAluno(alunoID uint) ([]models.ConflitoCadastro, error)
+	FindFusoesByAluno(alunoID uint) ([]models.FusaoAlunos, error)
+	AnonimizarPorAluno(alunoID uint, responsavel string) error
+}
+
+type duplicidadeRepository struct {
+	db *gorm.DB
+}
+
+func NewDuplicidadeRepository(db *gorm.DB) DuplicidadeRepository {
+	return &duplicidadeRepository{db: db}
+}
+
+func (r *duplicidadeRepository) SaveConflito(conflito *models.ConflitoCadastro) error {
+	return r.db.Save(conflito).Error
+}
+
+// FindConflitos lista os conflitos mais recentes primeiro; situação vazia traz todos
+func (r *duplicidadeRepository) FindConflitos(situacao string) ([]models.ConflitoCadastro, error) {
+	var conflitos []models.ConflitoCadastro
+	query := r.db.Order("data_registro DESC")
+	if situacao != "" {
+		query = query.Where("situacao = ?", situacao)
+	}
+	result := query.Find(&conflitos)
+	return conflitos, result.Error
+}
+
+func (r *duplicidadeRepository) FindConflitoByID(id uint) (*models.ConflitoCadastro, error) {
+	var conflito models.ConflitoCadastro
+	result := r.db.First(&conflito, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, erros.ErrConflitoNaoEncontrado
+		}
+		return nil, result.Error
+	}
+	return &conflito, nil
+}
+
+// ResolverConflito grava a decisão sobre o conflito e, quando informado, o cadastro corrigido do aluno
+func (r *duplicidadeRepository) ResolverConflito(conflito *models.ConflitoCadastro, aluno *models.Aluno) error {
+	return transacionar(r.db, func(tx *gorm.DB) error {
+		if aluno != nil {
+			if err := tx.Save(aluno).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Save(conflito).Error
+	})
+}
+
+func (r *duplicidadeRepository) FindFusoes() ([]models.FusaoAlunos, error) {
+	var fusoes []models.FusaoAlunos
+	result := r.db.Order("data_fusao DESC").Find(&fusoes)
+	return fusoes, result.Error
+}
+
+// Mesclar transfere para o aluno mantido tudo o que pertence ao aluno removido, apaga o cadastro
+// duplicado e grava a fusão, tudo na mesma transação. Quando os dois cadastros têm inscrição no
+// mesmo curso, fica a do aluno mantido, que recebe as presenças e a conclusão da outra. Devolve as
+// inscrições descartadas, como estavam antes de apagadas.
+func (r *duplicidadeRepository) Mesclar(mantido *models.Aluno, removidoID uint, fusao *models.FusaoAlunos) ([]models.Inscricao, error) {
+	var descartadas []models.Inscricao
+	err := transacionar(r.db, func(tx *gorm.DB) error {
+		var inscricoes []models.Inscricao
+		if err := tx.Where("aluno_id = ?", removidoID).Find(&inscricoes).Error; err != nil {
+			return err
+		}
+
+		for _, inscricao := range inscricoes {
+			var existente models.Inscricao
+			err := tx.Where("aluno_id = ? AND curso_id = ?", mantido.ID, inscricao.CursoID).First(&existente).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				if err := tx.Model(&inscricao).Update("aluno_id", mantido.ID).Error; err != nil {
+					return err
+				}
+				fusao.InscricoesMovidas++
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			if inscricao.Status == models.StatusInscricaoConcluida && existente.Status != models.StatusInscricaoConcluida {
+				existente.Status = inscricao.Status
+				existente.DataConclusao = inscricao.DataConclusao
+				if err := tx.Save(&existente).Error; err != nil {
+					return err
+				}
+			}
+
+			// Presenças em datas que a inscrição mantida ainda não tem são aproveitadas
+			datasMantidas := tx.Model(&models.Presenca{}).Select("data").Where("inscricao_id = ?", existente.ID)
+			if err := tx.Model(&models.Presenca{}).
+				Where("inscricao_id = ? AND data NOT IN (?)", inscricao.ID, datasMantidas).
+				Update("inscricao_id", existente.ID).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("inscricao_id = ?", inscricao.ID).Delete(&models.Presenca{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Delete(&inscricao).Error; err != nil {
+				return err
+			}
+
+			// A inscrição duplicada ocupava uma vaga, a menos que o curso já tenha sido cancelado
+			if inscricao.Status != models.StatusInscricaoCanceladaOrganizacao {
+				if err := tx.Model(&models.Curso{}).
+					Where("id = ? AND vagas_preenchidas > 0", inscricao.CursoID).
+					Update("vagas_preenchidas", gorm.Expr("vagas_preenchidas - 1")).Error; err != nil {
+					return err
+				}
+			}
+			descartadas = append(descartadas, inscricao)
+			fusao.InscricoesDescartadas++
+		}
+
+		// Históricos do aluno removido passam para o aluno mantido
+		for _, modelo := range []interface{}{&models.Consentimento{}, &models.SolicitacaoLGPD{}, &models.ConflitoCadastro{}} {
+			if err := tx.Model(modelo).Where("aluno_id = ?", removidoID).Update("aluno_id", mantido.ID).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Delete(&models.Aluno{}, removidoID).Error; err != nil {
+			return err
+		}
+		if err := tx.Save(mantido).Error; err != nil {
+			return err
+		}
+		return tx.Create(fusao).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return descartadas, nil
+}
+
+func (r *duplicidadeRepository) FindConflitosByAluno(alunoID uint) ([]models.ConflitoCadastro, error) {
+	var conflitos []models.ConflitoCadastro
+	result := r.db.Where("aluno_id = ?", alunoID).Order("data_registro DESC").Find(&conflitos)
+	return conflitos, result.Error
+}
+
+// FindFusoesByAluno lista as fusões em que o aluno foi o cadastro mantido, com os dados do duplicado removido
+func (r *duplicidadeRepository) FindFusoesByAluno(alunoID uint) ([]models.FusaoAlunos, error) {
+	var fusoes []models.FusaoAlunos
+	result := r.db.Where("aluno_mantido_id = ?", alunoID).Order("data_fusao DESC").Find(&fusoes)
+	return fusoes, result.Error
+}
+
+// AnonimizarPorAluno apaga os valores pessoais dos conflitos e das fusões do aluno. Os conflitos
+// pendentes são descartados, já que não há mais cadastro a corrigir.
+func (r *duplicidadeRepository) AnonimizarPorAluno(alunoID uint, responsavel string) error {
+	return transacionar(r.db, func(tx *gorm.DB) error {
+		agora := time.Now()
+		if err := tx.Model(&models.ConflitoCadastro{}).
+			Where("aluno_id = ? AND situacao = ?", alunoID, models.ConflitoPendente).
+			Updates(map[string]interface{}{
+				"situacao":      models.ConflitoDescartado,
+				"resolvido_por": responsavel,
+				"resolvido_em":  agora,
+			}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.ConflitoCadastro{}).
+			Where("aluno_id = ?", alunoID).
+			Updates(map[string]interface{}{"valor_cadastrado": "", "valor_informado": ""}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.FusaoAlunos{}).
+			Where("aluno_mantido_id = ?", alunoID).
+			Update("dados_aluno_removido", "{}").Error
+	})
+}
@@ -2,9 +2,12 @@ package repository
 
 import (
 	"errors"
+	"time"
+	"tvtec/erros"
 	"tvtec/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type InscricaoRepository interface {
@@ -20,6 +23,10 @@ type InscricaoRepository interface {
 	Save(inscricao *models.Inscricao) error
 	Delete(id uint) error
 	CountByAluno(alunoID uint) (int64, error)
+	CountAtivasFuturasByAluno(alunoID uint) (int64, error)
+	CountAtivasFuturasByAlunoECategoria(alunoID, categoriaID uint) (int64, error)
+	CancelarPorCurso(cursoID uint) ([]models.Inscricao, error)
+	AnonimizarPorAluno(alunoID uint) error
 	CountByCurso(cursoID uint) (int64, error)
 }
 
@@ -42,7 +49,7 @@ func (r *inscricaoRepository) FindByID(id uint) (*models.Inscricao, error) {
 	result := r.db.First(&inscricao, id)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, errors.New("inscrição não encontrada")
+			return nil, erros.ErrInscricaoNaoEncontrada
 		}
 		return nil, result.Error
 	}
@@ -60,7 +67,7 @@ func (r *inscricaoRepository) FindByIDWithDetails(id uint) (*models.Inscricao, e
 	result := r.db.Preload("Aluno").Preload("Curso").First(&inscricao, id)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, errors.New("inscrição não encontrada")
+			return nil, erros.ErrInscricaoNaoEncontrada
 		}
 		return nil, result.Error
 	}
@@ -97,7 +104,7 @@ func (r *inscricaoRepository) FindByAlunoECurso(alunoID uint, cursoID uint) (*mo
 	result := r.db.Where("aluno_id = ? AND curso_id = ?", alunoID, cursoID).First(&inscricao)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, errors.New("inscrição não encontrada")
+			return nil, erros.ErrInscricaoNaoEncontrada
 		}
 		return nil, result.Error
 	}
@@ -113,11 +120,11 @@ func (r *inscricaoRepository) Save(inscricao *models.Inscricao) error {
 			Count(&count)
 
 		if count > 0 {
-			return errors.New("aluno já está inscrito neste curso")
+			return erros.ErrInscricaoDuplicada
 		}
 
 		// Iniciar transação para garantir consistência
-		return r.db.Transaction(func(tx *gorm.DB) error {
+		return transacionar(r.db, func(tx *gorm.DB) error {
 			// Verificar disponibilidade de vagas no curso
 			var curso models.Curso
 			if err := tx.First(&curso, inscricao.CursoID).Error; err != nil {
@@ -126,10 +133,13 @@ func (r *inscricaoRepository) Save(inscricao *models.Inscricao) error {
 
 			// Verificar se há vagas disponíveis
 			if curso.VagasPreenchidas >= curso.VagasTotais {
-				return errors.New("não há vagas disponíveis para este curso")
+				return erros.ErrSemVagas
 			}
 
 			// Salvar inscrição
+			if inscricao.Status == "" {
+				inscricao.Status = models.StatusInscricaoAtiva
+			}
 			if err := tx.Create(inscricao).Error; err != nil {
 				return err
 			}
@@ -153,26 +163,30 @@ func (r *inscricaoRepository) Delete(id uint) error {
 	var inscricao models.Inscricao
 	if err := r.db.First(&inscricao, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("inscrição não encontrada")
+			return erros.ErrInscricaoNaoEncontrada
 		}
 		return err
 	}
 
 	// Iniciar transação para garantir consistência
-	return r.db.Transaction(func(tx *gorm.DB) error {
+	return transacionar(r.db, func(tx *gorm.DB) error {
 		// Recuperar o curso para atualizar vagas
 		var curso models.Curso
 		if err := tx.First(&curso, inscricao.CursoID).Error; err != nil {
 			return err
 		}
 
-		// Remover inscrição
+		// Remover as presenças registradas e a inscrição
+		if err := tx.Where("inscricao_id = ?", inscricao.ID).Delete(&models.Presenca{}).Error; err != nil {
+			return err
+		}
 		if err := tx.Delete(&inscricao).Error; err != nil {
 			return err
 		}
 
-		// Atualizar vagas preenchidas (evitar vagas negativas)
-		if curso.VagasPreenchidas > 0 {
+		// Atualizar vagas preenchidas (evitar vagas negativas); o curso cancelado mantém a contagem
+		// do cancelamento, então a inscrição cancelada pela organização não devolve vaga
+		if curso.VagasPreenchidas > 0 && inscricao.Status != models.StatusInscricaoCanceladaOrganizacao {
 			curso.VagasPreenchidas--
 			if err := tx.Save(&curso).Error; err != nil {
 				return err
@@ -189,6 +203,58 @@ func (r *inscricaoRepository) CountByAluno(alunoID uint) (int64, error) {
 	return count, result.Error
 }
 
+// CountAtivasFuturasByAluno conta as inscrições ativas do aluno em cursos que ainda não aconteceram
+func (r *inscricaoRepository) CountAtivasFuturasByAluno(alunoID uint) (int64, error) {
+	var count int64
+	result := r.ativasPorAluno(alunoID).Count(&count)
+	return count, result.Error
+}
+
+// CountAtivasFuturasByAlunoECategoria conta as inscrições ativas do aluno em cursos futuros da categoria
+func (r *inscricaoRepository) CountAtivasFuturasByAlunoECategoria(alunoID, categoriaID uint) (int64, error) {
+	var count int64
+	result := r.ativasPorAluno(alunoID).Where("cursos.categoria_id = ?", categoriaID).Count(&count)
+	return count, result.Error
+}
+
+// CancelarPorCurso marca as inscrições ativas do curso como canceladas pela organização e
+// devolve exatamente as que foram alteradas
+func (r *inscricaoRepository) CancelarPorCurso(cursoID uint) ([]models.Inscricao, error) {
+	var canceladas []models.Inscricao
+	result := r.db.Model(&canceladas).
+		Clauses(clause.Returning{}).
+		Where("curso_id = ? AND status = ?", cursoID, models.StatusInscricaoAtiva).
+		Update("status", models.StatusInscricaoCanceladaOrganizacao)
+	return canceladas, result.Error
+}
+
+// AnonimizarPorAluno apaga as respostas pessoais do formulário de inscrição, mantendo o curso,
+// a situação e as datas usadas nas estatísticas
+func (r *inscricaoRepository) AnonimizarPorAluno(alunoID uint) error {
+	return r.db.Model(&models.Inscricao{}).
+		Where("aluno_id = ?", alunoID).
+		Updates(map[string]interface{}{
+			"escolaridade":       "",
+			"trabalhando":        "",
+			"bairro":             "",
+			"eh_cuidador":        "",
+			"eh_pcd":             "",
+			"tipo_pcd":           "",
+			"necessita_elevador": "",
+			"como_soube":         "",
+			"autoriza_whats_app": "",
+			"leva_notebook":      "",
+		}).Error
+}
+
+// A tabela de models.Inscricao se chama inscricaos, nome gerado pelo GORM
+func (r *inscricaoRepository) ativasPorAluno(alunoID uint) *gorm.DB {
+	hoje := models.DiaLocal(time.Now())
+	return r.db.Model(&models.Inscricao{}).
+		Joins("JOIN cursos ON cursos.id = inscricaos.curso_id").
+		Where("inscricaos.aluno_id = ? AND inscricaos.status = ? AND cursos.data >= ?", alunoID, models.StatusInscricaoAtiva, hoje)
+}
+
 func (r *inscricaoRepository) CountByCurso(cursoID uint) (int64, error) {
 	var count int64
 	result := r.db.Model(&models.Inscricao{}).Where("curso_id = ?", cursoID).Count(&count)
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"errors"
+	"tvtec/erros"
+	"tvtec/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LocalRepository interface {
+	FindAll() ([]models.Local, error)
+	FindByID(id uint) (*models.Local, error)
+	Save(local *models.Local) error
+	Delete(id uint) error
+	FindSalaByID(id uint) (*models.Sala, error)
+	SaveSala(sala *models.Sala) error
+	DeleteSala(id uint) error
+}
+
+type localRepository struct {
+	db *gorm.DB
+}
+
+func NewLocalRepository(db *gorm.DB) LocalRepository {
+	return &localRepository{db: db}
+}
+
+func (r *localRepository) FindAll() ([]models.Local, error) {
+	var locais []models.Local
+	result := r.db.Preload("Salas").Order("nome").Find(&locais)
+	return locais, result.Error
+}
+
+func (r *localRepository) FindByID(id uint) (*models.Local, error) {
+	var local models.Local
+	result := r.db.Preload("Salas").First(&local, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, erros.ErrLocalNaoEncontrado
+		}
+		return nil, result.Error
+	}
+	return &local, nil
+}
+
+func (r *localRepository) Save(local *models.Local) error {
+	// As salas são mantidas pelas operações próprias de sala
+	return r.db.Omit(clause.Associations).Save(local).Error
+}
+
+func (r *localRepository) Delete(id uint) error {
+	var salas int64
+	if err := r.db.Model(&models.Sala{}).Where("local_id = ?", id).Count(&salas).Error; err != nil {
+		return err
+	}
+	if salas > 0 {
+		return erros.ErrRegistroEmUso.ComMensagem("Não é possível remover local com salas cadastradas")
+	}
+
+	result := r.db.Delete(&models.Local{}, id)
+	if result.RowsAffected == 0 {
+		return erros.ErrLocalNaoEncontrado
+	}
+	return result.Error
+}
+
+func (r *localRepository) FindSalaByID(id uint) (*models.Sala, error) {
+	var sala models.Sala
+	result := r.db.Preload("Local").First(&sala, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, erros.ErrSalaNaoEncontrada
+		}
+		return nil, result.Error
+	}
+	return &sala, nil
+}
+
+func (r *localRepository) SaveSala(sala *models.Sala) error {
+	return r.db.Omit(clause.Associations).Save(sala).Error
+}
+
+func (r *localRepository) DeleteSala(id uint) error {
+	var cursos int64
+	if err := r.db.Model(&models.Curso{}).Where("sala_id = ?", id).Count(&cursos).Error; err != nil {
+		return err
+	}
+	if cursos > 0 {
+		return erros.ErrRegistroEmUso.ComMensagem("Não é possível remover sala vinculada a cursos")
+	}
+
+	result := r.db.Delete(&models.Sala{}, id)
+	if result.RowsAffected == 0 {
+		return erros.ErrSalaNaoEncontrada
+	}
+	return result.Error
+}
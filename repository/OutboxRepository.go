@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"time"
+	"tvtec/models"
+
+	"gorm.io/gorm"
+)
+
+type OutboxRepository interface {
+	Save(evento *models.EventoOutbox) error
+	FindPendentes(ate time.Time, limite int) ([]models.EventoOutbox, error)
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) Save(evento *models.EventoOutbox) error {
+	return r.db.Save(evento).Error
+}
+
+// FindPendentes busca os eventos com tentativa vencida na ordem em que foram gravados
+func (r *outboxRepository) FindPendentes(ate time.Time, limite int) ([]models.EventoOutbox, error) {
+	var eventos []models.EventoOutbox
+	result := r.db.Where("situacao = ? AND proxima_tentativa <= ?", models.EventoOutboxPendente, ate).
+		Order("id").Limit(limite).Find(&eventos)
+	return eventos, result.Error
+}
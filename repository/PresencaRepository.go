@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"tvtec/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PresencaRepository interface {
+	FindByCurso(cursoID uint) ([]models.Presenca, error)
+	SaveAll(presencas []models.Presenca) error
+}
+
+type presencaRepository struct {
+	db *gorm.DB
+}
+
+func NewPresencaRepository(db *gorm.DB) PresencaRepository {
+	return &presencaRepository{db: db}
+}
+
+func (r *presencaRepository) FindByCurso(cursoID uint) ([]models.Presenca, error) {
+	var presencas []models.Presenca
+	// A tabela de models.Inscricao se chama inscricaos, nome gerado pelo GORM
+	result := r.db.
+		Joins("JOIN inscricaos ON inscricaos.id = presencas.inscricao_id").
+		Where("inscricaos.curso_id = ?", cursoID).
+		Order("presencas.data, presencas.inscricao_id").
+		Find(&presencas)
+	return presencas, result.Error
+}
+
+// SaveAll grava as presenças do dia; um novo registro para a mesma inscrição e data substitui o anterior
+func (r *presencaRepository) SaveAll(presencas []models.Presenca) error {
+	if len(presencas) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "inscricao_id"}, {Name: "data"}},
+		DoUpdates: clause.AssignmentColumns([]string{"presente", "registrado_por", "registrado_em"}),
+	}).Create(&presencas).Error
+}
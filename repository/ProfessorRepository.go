@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"errors"
+	"tvtec/erros"
+	"tvtec/models"
+
+	"gorm.io/gorm"
+)
+
+type ProfessorRepository interface {
+	FindAll() ([]models.Professor, error)
+	FindByID(id uint) (*models.Professor, error)
+	FindByEmail(email string) (*models.Professor, error)
+	Save(professor *models.Professor) error
+	Delete(id uint) error
+}
+
+type professorRepository struct {
+	db *gorm.DB
+}
+
+func NewProfessorRepository(db *gorm.DB) ProfessorRepository {
+	return &professorRepository{db: db}
+}
+
+func (r *professorRepository) FindAll() ([]models.Professor, error) {
+	var professores []models.Professor
+	result := r.db.Order("nome").Find(&professores)
+	return professores, result.Error
+}
+
+func (r *professorRepository) FindByID(id uint) (*models.Professor, error) {
+	var professor models.Professor
+	result := r.db.First(&professor, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, erros.ErrProfessorNaoEncontrado
+		}
+		return nil, result.Error
+	}
+	return &professor, nil
+}
+
+func (r *professorRepository) FindByEmail(email string) (*models.Professor, error) {
+	var professor models.Professor
+	result := r.db.Where("LOWER(email) = LOWER(?)", email).First(&professor)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, erros.ErrProfessorNaoEncontrado
+		}
+		return nil, result.Error
+	}
+	return &professor, nil
+}
+
+func (r *professorRepository) Save(professor *models.Professor) error {
+	return r.db.Save(professor).Error
+}
+
+func (r *professorRepository) Delete(id uint) error {
+	return transacionar(r.db, func(tx *gorm.DB) error {
+		// Os cursos mantêm o nome do professor, apenas perdem o vínculo com o cadastro
+		if err := tx.Model(&models.Curso{}).Where("professor_id = ?", id).Update("professor_id", nil).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&models.Professor{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return erros.ErrProfessorNaoEncontrado
+		}
+		return nil
+	})
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"errors"
+	"tvtec/erros"
+	"tvtec/models"
+
+	"gorm.io/gorm"
+)
+
+type SolicitacaoLGPDRepository interface {
+	FindAll() ([]models.SolicitacaoLGPD, error)
+	FindByAluno(alunoID uint) ([]models.SolicitacaoLGPD, error)
+	FindAguardandoConfirmacao(alunoID uint, tipo string) (*models.SolicitacaoLGPD, error)
+	Save(solicitacao *models.SolicitacaoLGPD) error
+}
+
+type solicitacaoLGPDRepository struct {
+	db *gorm.DB
+}
+
+func NewSolicitacaoLGPDRepository(db *gorm.DB) SolicitacaoLGPDRepository {
+	return &solicitacaoLGPDRepository{db: db}
+}
+
+func (r *solicitacaoLGPDRepository) FindAll() ([]models.SolicitacaoLGPD, error) {
+	var solicitacoes []models.SolicitacaoLGPD
+	result := r.db.Order("data_solicitacao DESC").Find(&solicitacoes)
+	return solicitacoes, result.Error
+}
+
+func (r *solicitacaoLGPDRepository) FindByAluno(alunoID uint) ([]models.SolicitacaoLGPD, error) {
+	var solicitacoes []models.SolicitacaoLGPD
+	result := r.db.Where("aluno_id = ?", alunoID).Order("data_solicitacao DESC").Find(&solicitacoes)
+	return solicitacoes, result.Error
+}
+
+// FindAguardandoConfirmacao retorna o pedido mais recente do titular que ainda aguarda o código de confirmação
+func (r *solicitacaoLGPDRepository) FindAguardandoConfirmacao(alunoID uint, tipo string) (*models.SolicitacaoLGPD, error) {
+	var solicitacao models.SolicitacaoLGPD
+	result := r.db.Where("aluno_id = ? AND tipo = ? AND codigo_confirmacao <> ''", alunoID, tipo).
+		Order("data_solicitacao DESC").First(&solicitacao)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, erros.ErrCodigoConfirmacao
+		}
+		return nil, result.Error
+	}
+	return &solicitacao, nil
+}
+
+func (r *solicitacaoLGPDRepository) Save(solicitacao *models.SolicitacaoLGPD) error {
+	return r.db.Save(solicitacao).Error
+}
@@ -0,0 +1,42 @@
+package repository
+
+import "gorm.io/gorm"
+
+// Repositorios reúne os repositórios ligados a uma mesma transação
+type Repositorios struct {
+	Alunos         AlunoRepository
+	Cursos         CursoRepository
+	Inscricoes     InscricaoRepository
+	Outbox         OutboxRepository
+	Consentimentos ConsentimentoRepository
+	Duplicidades   DuplicidadeRepository
+}
+
+// Transacao executa uma operação em que todas as gravações são confirmadas juntas ou descartadas juntas.
+// Os eventos gravados na outbox pela operação só existem se a operação for confirmada.
+type Transacao interface {
+	Executar(operacao func(repos Repositorios) error) error
+}
+
+type transacao struct {
+	db *gorm.DB
+}
+
+func NewTransacao(db *gorm.DB) Transacao {
+	return &transacao{db: db}
+}
+
+// Executar confirma a transação quando a operação não retorna erro. As transações internas
+// dos repositórios viram savepoints da transação externa.
+func (t *transacao) Executar(operacao func(repos Repositorios) error) error {
+	return transacionar(t.db, func(tx *gorm.DB) error {
+		return operacao(Repositorios{
+			Alunos:         NewAlunoRepository(tx),
+			Cursos:         NewCursoRepository(tx),
+			Inscricoes:     NewInscricaoRepository(tx),
+			Outbox:         NewOutboxRepository(tx),
+			Consentimentos: NewConsentimentoRepository(tx),
+			Duplicidades:   NewDuplicidadeRepository(tx),
+		})
+	})
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"errors"
+	"time"
+	"tvtec/erros"
+	"tvtec/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository interface {
+	FindAll() ([]models.Webhook, error)
+	FindByID(id uint) (*models.Webhook, error)
+	FindAtivos() ([]models.Webhook, error)
+	Save(webhook *models.Webhook) error
+	Update(webhook *models.Webhook) error
+	Delete(id uint) error
+	SaveEntrega(entrega *models.EntregaWebhook) error
+	RegistrarEntregas(entregas []models.EntregaWebhook) error
+	FindEntregasByWebhook(webhookID uint, limite int) ([]models.EntregaWebhook, error)
+	FindEntregasPendentes(ate time.Time, limite int) ([]models.EntregaWebhook, error)
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) FindAll() ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	result := r.db.Order("id").Find(&webhooks)
+	return webhooks, result.Error
+}
+
+func (r *webhookRepository) FindByID(id uint) (*models.Webhook, error) {
+	var webhook models.Webhook
+	result := r.db.First(&webhook, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, erros.ErrWebhookNaoEncontrado
+		}
+		return nil, result.Error
+	}
+	return &webhook, nil
+}
+
+func (r *webhookRepository) FindAtivos() ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	result := r.db.Where("ativo = ?", true).Find(&webhooks)
+	return webhooks, result.Error
+}
+
+func (r *webhookRepository) Save(webhook *models.Webhook) error {
+	return r.db.Create(webhook).Error
+}
+
+func (r *webhookRepository) Update(webhook *models.Webhook) error {
+	return r.db.Save(webhook).Error
+}
+
+// Delete remove o webhook junto com o histórico de entregas
+func (r *webhookRepository) Delete(id uint) error {
+	return transacionar(r.db, func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", id).Delete(&models.EntregaWebhook{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.Webhook{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return erros.ErrWebhookNaoEncontrado
+		}
+		return nil
+	})
+}
+
+func (r *webhookRepository) SaveEntrega(entrega *models.EntregaWebhook) error {
+	return r.db.Save(entrega).Error
+}
+
+// RegistrarEntregas insere as entregas de um evento num único comando; as que já existem para o mesmo
+// evento e webhook são ignoradas, então reprocessar o evento da outbox não duplica envios
+func (r *webhookRepository) RegistrarEntregas(entregas []models.EntregaWebhook) error {
+	if len(entregas) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&entregas).Error
+}
+
+// FindEntregasByWebhook lista as entregas mais recentes primeiro
+func (r *webhookRepository) FindEntregasByWebhook(webhookID uint, limite int) ([]models.EntregaWebhook, error) {
+	var entregas []models.EntregaWebhook
+	result := r.db.Where("webhook_id = ?", webhookID).Order("criada_em DESC").Limit(limite).Find(&entregas)
+	return entregas, result.Error
+}
+
+// FindEntregasPendentes busca as entregas cuja próxima tentativa já venceu, das mais antigas para as mais novas
+func (r *webhookRepository) FindEntregasPendentes(ate time.Time, limite int) ([]models.EntregaWebhook, error) {
+	var entregas []models.EntregaWebhook
+	result := r.db.Where("situacao = ? AND proxima_tentativa <= ?", models.EntregaWebhookPendente, ate).
+		Order("proxima_tentativa").Limit(limite).Find(&entregas)
+	return entregas, result.Error
+}
@@ -0,0 +1,125 @@
+package memoria
+
+import (
+	"fmt"
+	"sort"
+
+	"tvtec/erros"
+	"tvtec/models"
+)
+
+type alunoRepository struct {
+	b *Banco
+}
+
+func (r *alunoRepository) FindAll() ([]models.Aluno, error) {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	alunos := make([]models.Aluno, 0, len(r.b.alunos))
+	for _, aluno := range r.b.alunos {
+		alunos = append(alunos, aluno)
+	}
+	sort.Slice(alunos, func(i, j int) bool { return alunos[i].ID < alunos[j].ID })
+	return alunos, nil
+}
+
+func (r *alunoRepository) FindByID(id uint) (*models.Aluno, error) {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	aluno, ok := r.b.alunos[id]
+	if !ok {
+		return nil, erros.ErrAlunoNaoEncontrado
+	}
+	return &aluno, nil
+}
+
+func (r *alunoRepository) Save(aluno *models.Aluno) error {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	if err := r.verificarUnicidade(aluno); err != nil {
+		return err
+	}
+	// Como o Create do GORM, Save só insere: um ID existente viola a chave primária
+	if _, existe := r.b.alunos[aluno.ID]; existe && aluno.ID != 0 {
+		return fmt.Errorf("aluno %d já existe", aluno.ID)
+	}
+	if aluno.ID == 0 {
+		aluno.ID = r.b.proximoID("alunos")
+	}
+	r.b.alunos[aluno.ID] = semAssociacoesAluno(*aluno)
+	return nil
+}
+
+func (r *alunoRepository) Update(aluno *models.Aluno) error {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	if err := r.verificarUnicidade(aluno); err != nil {
+		return err
+	}
+	if aluno.ID == 0 {
+		aluno.ID = r.b.proximoID("alunos")
+	}
+	r.b.alunos[aluno.ID] = semAssociacoesAluno(*aluno)
+	return nil
+}
+
+func (r *alunoRepository) AtualizarChaveCalendario(id uint, chave string) error {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	aluno, ok := r.b.alunos[id]
+	if !ok {
+		return erros.ErrAlunoNaoEncontrado
+	}
+	aluno.ChaveCalendario = chave
+	r.b.alunos[id] = aluno
+	return nil
+}
+
+func (r *alunoRepository) Delete(id uint) error {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	if _, ok := r.b.alunos[id]; !ok {
+		return erros.ErrAlunoNaoEncontrado
+	}
+	delete(r.b.alunos, id)
+	return nil
+}
+
+func (r *alunoRepository) FindByCPF(cpf string) (*models.Aluno, error) {
+	return r.buscar(func(aluno models.Aluno) bool { return aluno.CPF == cpf })
+}
+
+func (r *alunoRepository) FindByEmail(email string) (*models.Aluno, error) {
+	return r.buscar(func(aluno models.Aluno) bool { return aluno.Email == email })
+}
+
+func (r *alunoRepository) buscar(criterio func(models.Aluno) bool) (*models.Aluno, error) {
+	alunos, _ := r.FindAll()
+	for _, aluno := range alunos {
+		if criterio(aluno) {
+			return &aluno, nil
+		}
+	}
+	return nil, erros.ErrAlunoNaoEncontrado
+}
+
+// verificarUnicidade reproduz os índices únicos de CPF e email da tabela alunos
+func (r *alunoRepository) verificarUnicidade(aluno *models.Aluno) error {
+	for id, existente := range r.b.alunos {
+		if id == aluno.ID {
+			continue
+		}
+		if existente.CPF == aluno.CPF {
+			return erros.ErrCadastroDuplicado.ComMensagem("CPF já cadastrado")
+		}
+		if existente.Email == aluno.Email {
+			return erros.ErrCadastroDuplicado.ComMensagem("Email já cadastrado")
+		}
+	}
+	return nil
+}
+
+func semAssociacoesAluno(aluno models.Aluno) models.Aluno {
+	aluno.Inscricoes = nil
+	return aluno
+}
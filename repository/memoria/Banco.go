@@ -0,0 +1,155 @@
+// Package memoria implementa em memória os repositórios de alunos, cursos, inscrições,
+// consentimentos e duplicidades, para testar os serviços sem um banco de dados. As mensagens
+// de erro são as mesmas dos repositórios do GORM, porque os serviços as repassam ou as comparam.
+package memoria
+
+import (
+	"sync"
+
+	"tvtec/models"
+	"tvtec/repository"
+)
+
+// Banco guarda os registros compartilhados pelos repositórios em memória
+type Banco struct {
+	mu             sync.Mutex
+	alunos         map[uint]models.Aluno
+	cursos         map[uint]models.Curso
+	inscricoes     map[uint]models.Inscricao
+	tags           map[string]uint
+	eventos        []models.EventoOutbox
+	sequencias     map[string]uint
+	consentimentos []models.Consentimento
+	termos         []models.TermoConsentimento
+	conflitos      []models.ConflitoCadastro
+	fusoes         []models.FusaoAlunos
+}
+
+func NewBanco() *Banco {
+	return &Banco{
+		alunos:     make(map[uint]models.Aluno),
+		cursos:     make(map[uint]models.Curso),
+		inscricoes: make(map[uint]models.Inscricao),
+		tags:       make(map[string]uint),
+		sequencias: make(map[string]uint),
+	}
+}
+
+func (b *Banco) Alunos() repository.AlunoRepository {
+	return &alunoRepository{b: b}
+}
+
+func (b *Banco) Cursos() repository.CursoRepository {
+	return &cursoRepository{b: b}
+}
+
+func (b *Banco) Inscricoes() repository.InscricaoRepository {
+	return &inscricaoRepository{b: b}
+}
+
+func (b *Banco) Consentimentos() repository.ConsentimentoRepository {
+	return &consentimentoRepository{b: b}
+}
+
+func (b *Banco) Duplicidades() repository.DuplicidadeRepository {
+	return &duplicidadeRepository{b: b}
+}
+
+func (b *Banco) Outbox() repository.OutboxRepository {
+	return &outboxRepository{b: b}
+}
+
+// Transacao desfaz as gravações da operação que retorna erro. Não há isolamento:
+// outras operações concorrentes enxergam as gravações antes da confirmação.
+func (b *Banco) Transacao() repository.Transacao {
+	return &transacao{b: b}
+}
+
+// Eventos devolve uma cópia dos eventos gravados na outbox, na ordem de gravação
+func (b *Banco) Eventos() []models.EventoOutbox {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]models.EventoOutbox(nil), b.eventos...)
+}
+
+// proximoID numera os registros de cada tabela; chamar com o mutex travado. Como no banco,
+// os números usados numa transação desfeita não são reaproveitados.
+func (b *Banco) proximoID(tabela string) uint {
+	b.sequencias[tabela]++
+	return b.sequencias[tabela]
+}
+
+type copiaBanco struct {
+	alunos         map[uint]models.Aluno
+	cursos         map[uint]models.Curso
+	inscricoes     map[uint]models.Inscricao
+	tags           map[string]uint
+	eventos        []models.EventoOutbox
+	consentimentos []models.Consentimento
+	termos         []models.TermoConsentimento
+	conflitos      []models.ConflitoCadastro
+	fusoes         []models.FusaoAlunos
+}
+
+func (b *Banco) copiar() copiaBanco {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	copia := copiaBanco{
+		alunos:         make(map[uint]models.Aluno, len(b.alunos)),
+		cursos:         make(map[uint]models.Curso, len(b.cursos)),
+		inscricoes:     make(map[uint]models.Inscricao, len(b.inscricoes)),
+		tags:           make(map[string]uint, len(b.tags)),
+		eventos:        append([]models.EventoOutbox(nil), b.eventos...),
+		consentimentos: append([]models.Consentimento(nil), b.consentimentos...),
+		termos:         append([]models.TermoConsentimento(nil), b.termos...),
+		conflitos:      append([]models.ConflitoCadastro(nil), b.conflitos...),
+		fusoes:         append([]models.FusaoAlunos(nil), b.fusoes...),
+	}
+	for id, aluno := range b.alunos {
+		copia.alunos[id] = aluno
+	}
+	for id, curso := range b.cursos {
+		copia.cursos[id] = copiarCurso(curso)
+	}
+	for id, inscricao := range b.inscricoes {
+		copia.inscricoes[id] = inscricao
+	}
+	for nome, id := range b.tags {
+		copia.tags[nome] = id
+	}
+	return copia
+}
+
+func (b *Banco) restaurar(copia copiaBanco) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.alunos = copia.alunos
+	b.cursos = copia.cursos
+	b.inscricoes = copia.inscricoes
+	b.tags = copia.tags
+	b.eventos = copia.eventos
+	b.consentimentos = copia.consentimentos
+	b.termos = copia.termos
+	b.conflitos = copia.conflitos
+	b.fusoes = copia.fusoes
+}
+
+type transacao struct {
+	b *Banco
+}
+
+func (t *transacao) Executar(operacao func(repos repository.Repositorios) error) error {
+	copia := t.b.copiar()
+	err := operacao(repository.Repositorios{
+		Alunos:         t.b.Alunos(),
+		Cursos:         t.b.Cursos(),
+		Inscricoes:     t.b.Inscricoes(),
+		Outbox:         t.b.Outbox(),
+		Consentimentos: t.b.Consentimentos(),
+		Duplicidades:   t.b.Duplicidades(),
+	})
+	if err != nil {
+		t.b.restaurar(copia)
+	}
+	return err
+}
@@ -0,0 +1,73 @@
+package memoria
+
+import (
+	"tvtec/erros"
+	"tvtec/models"
+	"tvtec/repository"
+)
+
+type consentimentoRepository struct {
+	b *Banco
+}
+
+func (r *consentimentoRepository) FindByAluno(alunoID uint) ([]models.Consentimento, error) {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	var consentimentos []models.Consentimento
+	for _, consentimento := range r.b.consentimentos {
+		if consentimento.AlunoID == alunoID {
+			consentimentos = append(consentimentos, consentimento)
+		}
+	}
+	return consentimentos, nil
+}
+
+// FindUltimo retorna o registro mais recente; os registros ficam na ordem de gravação
+func (r *consentimentoRepository) FindUltimo(alunoID uint, canal, finalidade string) (*models.Consentimento, error) {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	for i := len(r.b.consentimentos) - 1; i >= 0; i-- {
+		consentimento := r.b.consentimentos[i]
+		if consentimento.AlunoID == alunoID && consentimento.Canal == canal && consentimento.Finalidade == finalidade {
+			return &consentimento, nil
+		}
+	}
+	return nil, repository.ErrConsentimentoNaoEncontrado
+}
+
+func (r *consentimentoRepository) Save(consentimento *models.Consentimento) error {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	consentimento.ID = r.b.proximoID("consentimentos")
+	r.b.consentimentos = append(r.b.consentimentos, *consentimento)
+	return nil
+}
+
+func (r *consentimentoRepository) FindTermoVigente() (*models.TermoConsentimento, error) {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	if len(r.b.termos) == 0 {
+		return nil, erros.ErrTermoNaoEncontrado
+	}
+	termo := r.b.termos[len(r.b.termos)-1]
+	return &termo, nil
+}
+
+func (r *consentimentoRepository) FindTermoByVersao(versao string) (*models.TermoConsentimento, error) {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	for _, termo := range r.b.termos {
+		if termo.Versao == versao {
+			return &termo, nil
+		}
+	}
+	return nil, erros.ErrTermoNaoEncontrado.ComMensagem("Versão dos termos não encontrada")
+}
+
+func (r *consentimentoRepository) SaveTermo(termo *models.TermoConsentimento) error {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	termo.ID = r.b.proximoID("termos_consentimento")
+	r.b.termos = append(r.b.termos, *termo)
+	return nil
+}
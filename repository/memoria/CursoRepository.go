@@ -0,0 +1,314 @@
+package memoria
+
+import (
+	"sort"
+	"strings"
+
+	"tvtec/erros"
+	"tvtec/models"
+	"tvtec/repository"
+)
+
+type cursoRepository struct {
+	b *Banco
+}
+
+func (r *cursoRepository) FindAll() ([]models.Curso, error) {
+	return r.filtrar(func(models.Curso) bool { return true }), nil
+}
+
+// Buscar aplica os mesmos critérios do repositório do GORM; o texto é procurado palavra por palavra
+func (r *cursoRepository) Buscar(filtro repository.FiltroCurso) ([]models.Curso, error) {
+	if !repository.OrdenacaoCursoValida(filtro.Ordenacao) {
+		return nil, erros.ErrDadosInvalidos.ComMensagem("Ordenação inválida")
+	}
+	palavras := strings.Fields(strings.ToLower(filtro.Texto))
+
+	cursos := r.filtrar(func(curso models.Curso) bool {
+		texto := strings.ToLower(curso.Nome + " " + curso.Professor)
+		for _, palavra := range palavras {
+			if !strings.Contains(texto, palavra) {
+				return false
+			}
+		}
+		if filtro.CategoriaID != 0 && (curso.CategoriaID == nil || *curso.CategoriaID != filtro.CategoriaID) {
+			return false
+		}
+		if filtro.Tag != "" && !possuiTag(curso, filtro.Tag) {
+			return false
+		}
+		if filtro.DataInicio != nil && curso.Data.Before(*filtro.DataInicio) {
+			return false
+		}
+		if filtro.DataFim != nil && curso.Data.After(*filtro.DataFim) {
+			return false
+		}
+		if filtro.ApenasComVagas && curso.VagasPreenchidas >= curso.VagasTotais {
+			return false
+		}
+		return filtro.IncluirRascunhos || curso.Status != models.StatusCursoRascunho
+	})
+	ordenarCursos(cursos, filtro.Ordenacao)
+	return cursos, nil
+}
+
+func (r *cursoRepository) FindByID(id uint) (*models.Curso, error) {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	curso, ok := r.b.cursos[id]
+	if !ok {
+		return nil, erros.ErrCursoNaoEncontrado
+	}
+	curso = copiarCurso(curso)
+	return &curso, nil
+}
+
+func (r *cursoRepository) Save(curso *models.Curso) error {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	curso.VagasPreenchidas = 0
+	if curso.ID == 0 {
+		curso.ID = r.b.proximoID("cursos")
+	}
+	if curso.Status == "" {
+		curso.Status = models.StatusCursoPublicado
+	}
+	novo := copiarCurso(*curso)
+	novo.Tags, novo.PreRequisitos, novo.RegrasElegibilidade = nil, nil, nil
+	r.b.cursos[curso.ID] = novo
+	return nil
+}
+
+// Update grava os campos do curso; tags, pré-requisitos e regras têm métodos próprios
+func (r *cursoRepository) Update(curso *models.Curso) error {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	atualizado := copiarCurso(*curso)
+	if existente, ok := r.b.cursos[curso.ID]; ok {
+		atualizado.Tags = existente.Tags
+		atualizado.PreRequisitos = existente.PreRequisitos
+		atualizado.RegrasElegibilidade = existente.RegrasElegibilidade
+	} else {
+		if curso.ID == 0 {
+			curso.ID = r.b.proximoID("cursos")
+			atualizado.ID = curso.ID
+		}
+		atualizado.Tags, atualizado.PreRequisitos, atualizado.RegrasElegibilidade = nil, nil, nil
+	}
+	r.b.cursos[curso.ID] = atualizado
+	return nil
+}
+
+func (r *cursoRepository) Delete(id uint) error {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	if _, ok := r.b.cursos[id]; !ok {
+		return erros.ErrCursoNaoEncontrado
+	}
+	delete(r.b.cursos, id)
+	// O curso removido deixa de ser pré-requisito dos demais
+	for outroID, outro := range r.b.cursos {
+		preRequisitos := outro.PreRequisitos[:0:0]
+		for _, preRequisito := range outro.PreRequisitos {
+			if preRequisito.ID != id {
+				preRequisitos = append(preRequisitos, preRequisito)
+			}
+		}
+		outro.PreRequisitos = preRequisitos
+		r.b.cursos[outroID] = outro
+	}
+	return nil
+}
+
+func (r *cursoRepository) IncrementarVagasPreenchidas(cursoID uint) error {
+	return r.alterarVagas(cursoID, func(curso *models.Curso) error {
+		if curso.VagasPreenchidas >= curso.VagasTotais {
+			return erros.ErrSemVagas
+		}
+		curso.VagasPreenchidas++
+		return nil
+	})
+}
+
+func (r *cursoRepository) DecrementarVagasPreenchidas(cursoID uint) error {
+	return r.alterarVagas(cursoID, func(curso *models.Curso) error {
+		if curso.VagasPreenchidas <= 0 {
+			return erros.ErrConflito.ComMensagem("Não há vagas preenchidas para decrementar")
+		}
+		curso.VagasPreenchidas--
+		return nil
+	})
+}
+
+func (r *cursoRepository) alterarVagas(cursoID uint, alterar func(curso *models.Curso) error) error {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	curso, ok := r.b.cursos[cursoID]
+	if !ok {
+		return erros.ErrCursoNaoEncontrado
+	}
+	if err := alterar(&curso); err != nil {
+		return err
+	}
+	r.b.cursos[cursoID] = curso
+	return nil
+}
+
+func (r *cursoRepository) FindBySala(salaID uint) ([]models.Curso, error) {
+	return r.filtrar(func(curso models.Curso) bool {
+		return curso.SalaID != nil && *curso.SalaID == salaID
+	}), nil
+}
+
+func (r *cursoRepository) FindByProfessor(professorID uint) ([]models.Curso, error) {
+	cursos := r.filtrar(func(curso models.Curso) bool {
+		return curso.ProfessorID != nil && *curso.ProfessorID == professorID
+	})
+	ordenarCursos(cursos, "data")
+	return cursos, nil
+}
+
+// AtualizarTags substitui as tags do curso, criando as que ainda não existem
+func (r *cursoRepository) AtualizarTags(curso *models.Curso, nomes []string) error {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	armazenado, ok := r.b.cursos[curso.ID]
+	if !ok {
+		return erros.ErrCursoNaoEncontrado
+	}
+
+	tags := make([]models.Tag, 0, len(nomes))
+	vistos := make(map[string]bool)
+	for _, nome := range nomes {
+		nome = strings.ToLower(strings.TrimSpace(nome))
+		if nome == "" || vistos[nome] {
+			continue
+		}
+		vistos[nome] = true
+		id, existe := r.b.tags[nome]
+		if !existe {
+			id = r.b.proximoID("tags")
+			r.b.tags[nome] = id
+		}
+		tags = append(tags, models.Tag{ID: id, Nome: nome})
+	}
+
+	armazenado.Tags = tags
+	r.b.cursos[curso.ID] = armazenado
+	curso.Tags = append([]models.Tag(nil), tags...)
+	return nil
+}
+
+// AtualizarPreRequisitos substitui a lista de cursos exigidos antes da inscrição
+func (r *cursoRepository) AtualizarPreRequisitos(curso *models.Curso, ids []uint) error {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	armazenado, ok := r.b.cursos[curso.ID]
+	if !ok {
+		return erros.ErrCursoNaoEncontrado
+	}
+
+	preRequisitos := []models.Curso{}
+	for _, id := range ids {
+		preRequisito, existe := r.b.cursos[id]
+		if !existe {
+			return erros.ErrCursoNaoEncontrado.ComMensagem("Pré-requisito não encontrado")
+		}
+		preRequisito.Tags, preRequisito.PreRequisitos, preRequisito.RegrasElegibilidade = nil, nil, nil
+		preRequisitos = append(preRequisitos, preRequisito)
+	}
+
+	armazenado.PreRequisitos = preRequisitos
+	r.b.cursos[curso.ID] = armazenado
+	curso.PreRequisitos = append([]models.Curso(nil), preRequisitos...)
+	return nil
+}
+
+// SubstituirRegrasElegibilidade troca todas as regras do curso pelas informadas
+func (r *cursoRepository) SubstituirRegrasElegibilidade(cursoID uint, regras []models.RegraElegibilidade) error {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	armazenado, ok := r.b.cursos[cursoID]
+	if !ok {
+		return erros.ErrCursoNaoEncontrado
+	}
+	for i := range regras {
+		regras[i].ID = r.b.proximoID("regras_elegibilidade")
+		regras[i].CursoID = cursoID
+	}
+	armazenado.RegrasElegibilidade = append([]models.RegraElegibilidade(nil), regras...)
+	r.b.cursos[cursoID] = armazenado
+	return nil
+}
+
+// CriarIndicesBusca não tem o que fazer em memória
+func (r *cursoRepository) CriarIndicesBusca() error {
+	return nil
+}
+
+func (r *cursoRepository) filtrar(criterio func(models.Curso) bool) []models.Curso {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	cursos := make([]models.Curso, 0)
+	for _, curso := range r.b.cursos {
+		if criterio(curso) {
+			cursos = append(cursos, copiarCurso(curso))
+		}
+	}
+	sort.Slice(cursos, func(i, j int) bool { return cursos[i].ID < cursos[j].ID })
+	return cursos
+}
+
+// ordenarCursos segue as ordenações de repository.FiltroCurso, desempatando pelo ID
+func ordenarCursos(cursos []models.Curso, ordenacao string) {
+	sort.SliceStable(cursos, func(i, j int) bool {
+		a, b := cursos[i], cursos[j]
+		switch ordenacao {
+		case "-data":
+			if !a.Data.Equal(b.Data.Time) {
+				return a.Data.After(b.Data.Time)
+			}
+			return a.ID > b.ID
+		case "nome":
+			if a.Nome != b.Nome {
+				return a.Nome < b.Nome
+			}
+		case "-nome":
+			if a.Nome != b.Nome {
+				return a.Nome > b.Nome
+			}
+			return a.ID > b.ID
+		case "vagas":
+			vagasA, vagasB := a.VagasTotais-a.VagasPreenchidas, b.VagasTotais-b.VagasPreenchidas
+			if vagasA != vagasB {
+				return vagasA > vagasB
+			}
+			if !a.Data.Equal(b.Data.Time) {
+				return a.Data.Before(b.Data.Time)
+			}
+		default:
+			if !a.Data.Equal(b.Data.Time) {
+				return a.Data.Before(b.Data.Time)
+			}
+		}
+		return a.ID < b.ID
+	})
+}
+
+func possuiTag(curso models.Curso, nome string) bool {
+	for _, tag := range curso.Tags {
+		if strings.EqualFold(tag.Nome, nome) {
+			return true
+		}
+	}
+	return false
+}
+
+// copiarCurso evita que quem recebe o curso altere as listas guardadas no banco
+func copiarCurso(curso models.Curso) models.Curso {
+	curso.Tags = append([]models.Tag(nil), curso.Tags...)
+	curso.PreRequisitos = append([]models.Curso(nil), curso.PreRequisitos...)
+	curso.RegrasElegibilidade = append([]models.RegraElegibilidade(nil), curso.RegrasElegibilidade...)
+	curso.Inscricoes = nil
+	return curso
+}
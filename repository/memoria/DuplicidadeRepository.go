@@ -0,0 +1,183 @@
+package memoria
+
+import (
+	"sort"
+	"time"
+
+	"tvtec/erros"
+	"tvtec/models"
+)
+
+type duplicidadeRepository struct {
+	b *Banco
+}
+
+func (r *duplicidadeRepository) SaveConflito(conflito *models.ConflitoCadastro) error {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	r.salvarConflito(conflito)
+	return nil
+}
+
+// salvarConflito insere ou substitui o conflito; chamar com o mutex travado
+func (r *duplicidadeRepository) salvarConflito(conflito *models.ConflitoCadastro) {
+	if conflito.ID != 0 {
+		for i := range r.b.conflitos {
+			if r.b.conflitos[i].ID == conflito.ID {
+				r.b.conflitos[i] = *conflito
+				return
+			}
+		}
+	} else {
+		conflito.ID = r.b.proximoID("conflitos_cadastro")
+	}
+	r.b.conflitos = append(r.b.conflitos, *conflito)
+}
+
+func (r *duplicidadeRepository) FindConflitos(situacao string) ([]models.ConflitoCadastro, error) {
+	return r.conflitos(func(conflito models.ConflitoCadastro) bool {
+		return situacao == "" || conflito.Situacao == situacao
+	}), nil
+}
+
+func (r *duplicidadeRepository) FindConflitoByID(id uint) (*models.ConflitoCadastro, error) {
+	conflitos := r.conflitos(func(conflito models.ConflitoCadastro) bool { return conflito.ID == id })
+	if len(conflitos) == 0 {
+		return nil, erros.ErrConflitoNaoEncontrado
+	}
+	return &conflitos[0], nil
+}
+
+func (r *duplicidadeRepository) FindConflitosByAluno(alunoID uint) ([]models.ConflitoCadastro, error) {
+	return r.conflitos(func(conflito models.ConflitoCadastro) bool { return conflito.AlunoID == alunoID }), nil
+}
+
+// conflitos filtra os conflitos, mais recentes primeiro
+func (r *duplicidadeRepository) conflitos(filtro func(models.ConflitoCadastro) bool) []models.ConflitoCadastro {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	var conflitos []models.ConflitoCadastro
+	for _, conflito := range r.b.conflitos {
+		if filtro(conflito) {
+			conflitos = append(conflitos, conflito)
+		}
+	}
+	sort.SliceStable(conflitos, func(i, j int) bool { return conflitos[i].DataRegistro.After(conflitos[j].DataRegistro) })
+	return conflitos
+}
+
+func (r *duplicidadeRepository) ResolverConflito(conflito *models.ConflitoCadastro, aluno *models.Aluno) error {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	if aluno != nil {
+		r.b.alunos[aluno.ID] = semAssociacoesAluno(*aluno)
+	}
+	r.salvarConflito(conflito)
+	return nil
+}
+
+func (r *duplicidadeRepository) FindFusoes() ([]models.FusaoAlunos, error) {
+	return r.fusoes(func(models.FusaoAlunos) bool { return true }), nil
+}
+
+func (r *duplicidadeRepository) FindFusoesByAluno(alunoID uint) ([]models.FusaoAlunos, error) {
+	return r.fusoes(func(fusao models.FusaoAlunos) bool { return fusao.AlunoMantidoID == alunoID }), nil
+}
+
+// fusoes filtra as fusões, mais recentes primeiro
+func (r *duplicidadeRepository) fusoes(filtro func(models.FusaoAlunos) bool) []models.FusaoAlunos {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	var fusoes []models.FusaoAlunos
+	for _, fusao := range r.b.fusoes {
+		if filtro(fusao) {
+			fusoes = append(fusoes, fusao)
+		}
+	}
+	sort.SliceStable(fusoes, func(i, j int) bool { return fusoes[i].DataFusao.After(fusoes[j].DataFusao) })
+	return fusoes
+}
+
+// Mesclar segue o repositório do GORM, exceto pelas presenças, que não existem em memória
+func (r *duplicidadeRepository) Mesclar(mantido *models.Aluno, removidoID uint, fusao *models.FusaoAlunos) ([]models.Inscricao, error) {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+
+	var descartadas []models.Inscricao
+	for id, inscricao := range r.b.inscricoes {
+		if inscricao.AlunoID != removidoID {
+			continue
+		}
+		existenteID, duplicada := uint(0), false
+		for outroID, outra := range r.b.inscricoes {
+			if outra.AlunoID == mantido.ID && outra.CursoID == inscricao.CursoID {
+				existenteID, duplicada = outroID, true
+				break
+			}
+		}
+		if !duplicada {
+			inscricao.AlunoID = mantido.ID
+			r.b.inscricoes[id] = inscricao
+			fusao.InscricoesMovidas++
+			continue
+		}
+
+		existente := r.b.inscricoes[existenteID]
+		if inscricao.Status == models.StatusInscricaoConcluida && existente.Status != models.StatusInscricaoConcluida {
+			existente.Status = inscricao.Status
+			existente.DataConclusao = inscricao.DataConclusao
+			r.b.inscricoes[existenteID] = existente
+		}
+		delete(r.b.inscricoes, id)
+		if curso, ok := r.b.cursos[inscricao.CursoID]; ok && curso.VagasPreenchidas > 0 &&
+			inscricao.Status != models.StatusInscricaoCanceladaOrganizacao {
+			curso.VagasPreenchidas--
+			r.b.cursos[curso.ID] = curso
+		}
+		descartadas = append(descartadas, inscricao)
+		fusao.InscricoesDescartadas++
+	}
+	sort.Slice(descartadas, func(i, j int) bool { return descartadas[i].ID < descartadas[j].ID })
+
+	for i := range r.b.consentimentos {
+		if r.b.consentimentos[i].AlunoID == removidoID {
+			r.b.consentimentos[i].AlunoID = mantido.ID
+		}
+	}
+	for i := range r.b.conflitos {
+		if r.b.conflitos[i].AlunoID == removidoID {
+			r.b.conflitos[i].AlunoID = mantido.ID
+		}
+	}
+
+	delete(r.b.alunos, removidoID)
+	r.b.alunos[mantido.ID] = semAssociacoesAluno(*mantido)
+	fusao.ID = r.b.proximoID("fusoes_alunos")
+	r.b.fusoes = append(r.b.fusoes, *fusao)
+	return descartadas, nil
+}
+
+func (r *duplicidadeRepository) AnonimizarPorAluno(alunoID uint, responsavel string) error {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	agora := time.Now()
+	for i := range r.b.conflitos {
+		conflito := &r.b.conflitos[i]
+		if conflito.AlunoID != alunoID {
+			continue
+		}
+		if conflito.Situacao == models.ConflitoPendente {
+			conflito.Situacao = models.ConflitoDescartado
+			conflito.ResolvidoPor = responsavel
+			conflito.ResolvidoEm = &agora
+		}
+		conflito.ValorCadastrado = ""
+		conflito.ValorInformado = ""
+	}
+	for i := range r.b.fusoes {
+		if r.b.fusoes[i].AlunoMantidoID == alunoID {
+			r.b.fusoes[i].DadosAlunoRemovido = "{}"
+		}
+	}
+	return nil
+}
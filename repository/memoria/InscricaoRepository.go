@@ -0,0 +1,213 @@
+package memoria
+
+import (
+	"sort"
+	"time"
+
+	"tvtec/erros"
+	"tvtec/models"
+)
+
+type inscricaoRepository struct {
+	b *Banco
+}
+
+func (r *inscricaoRepository) FindAll() ([]models.Inscricao, error) {
+	return r.filtrar(false, func(models.Inscricao) bool { return true }), nil
+}
+
+func (r *inscricaoRepository) FindByID(id uint) (*models.Inscricao, error) {
+	return r.primeira(false, func(inscricao models.Inscricao) bool { return inscricao.ID == id })
+}
+
+func (r *inscricaoRepository) FindAllWithDetails() ([]models.Inscricao, error) {
+	return r.filtrar(true, func(models.Inscricao) bool { return true }), nil
+}
+
+func (r *inscricaoRepository) FindByIDWithDetails(id uint) (*models.Inscricao, error) {
+	return r.primeira(true, func(inscricao models.Inscricao) bool { return inscricao.ID == id })
+}
+
+func (r *inscricaoRepository) FindByAluno(alunoID uint) ([]models.Inscricao, error) {
+	return r.filtrar(false, func(inscricao models.Inscricao) bool { return inscricao.AlunoID == alunoID }), nil
+}
+
+func (r *inscricaoRepository) FindByCurso(cursoID uint) ([]models.Inscricao, error) {
+	return r.filtrar(false, func(inscricao models.Inscricao) bool { return inscricao.CursoID == cursoID }), nil
+}
+
+func (r *inscricaoRepository) FindByAlunoWithDetails(alunoID uint) ([]models.Inscricao, error) {
+	return r.filtrar(true, func(inscricao models.Inscricao) bool { return inscricao.AlunoID == alunoID }), nil
+}
+
+func (r *inscricaoRepository) FindByCursoWithDetails(cursoID uint) ([]models.Inscricao, error) {
+	return r.filtrar(true, func(inscricao models.Inscricao) bool { return inscricao.CursoID == cursoID }), nil
+}
+
+func (r *inscricaoRepository) FindByAlunoECurso(alunoID uint, cursoID uint) (*models.Inscricao, error) {
+	return r.primeira(false, func(inscricao models.Inscricao) bool {
+		return inscricao.AlunoID == alunoID && inscricao.CursoID == cursoID
+	})
+}
+
+// Save cria a inscrição ocupando uma vaga do curso, ou atualiza uma inscrição existente
+func (r *inscricaoRepository) Save(inscricao *models.Inscricao) error {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+
+	if inscricao.ID != 0 {
+		r.b.inscricoes[inscricao.ID] = semAssociacoesInscricao(*inscricao)
+		return nil
+	}
+
+	for _, existente := range r.b.inscricoes {
+		if existente.AlunoID == inscricao.AlunoID && existente.CursoID == inscricao.CursoID {
+			return erros.ErrInscricaoDuplicada
+		}
+	}
+	curso, ok := r.b.cursos[inscricao.CursoID]
+	if !ok {
+		return erros.ErrCursoNaoEncontrado
+	}
+	if curso.VagasPreenchidas >= curso.VagasTotais {
+		return erros.ErrSemVagas
+	}
+
+	if inscricao.Status == "" {
+		inscricao.Status = models.StatusInscricaoAtiva
+	}
+	inscricao.ID = r.b.proximoID("inscricoes")
+	r.b.inscricoes[inscricao.ID] = semAssociacoesInscricao(*inscricao)
+	curso.VagasPreenchidas++
+	r.b.cursos[curso.ID] = curso
+	return nil
+}
+
+// Delete remove a inscrição e libera a vaga do curso
+func (r *inscricaoRepository) Delete(id uint) error {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+
+	inscricao, ok := r.b.inscricoes[id]
+	if !ok {
+		return erros.ErrInscricaoNaoEncontrada
+	}
+	delete(r.b.inscricoes, id)
+	if curso, ok := r.b.cursos[inscricao.CursoID]; ok && curso.VagasPreenchidas > 0 && inscricao.Status != models.StatusInscricaoCanceladaOrganizacao {
+		curso.VagasPreenchidas--
+		r.b.cursos[curso.ID] = curso
+	}
+	return nil
+}
+
+func (r *inscricaoRepository) CountByAluno(alunoID uint) (int64, error) {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	var total int64
+	for _, inscricao := range r.b.inscricoes {
+		if inscricao.AlunoID == alunoID {
+			total++
+		}
+	}
+	return total, nil
+}
+
+// CountAtivasFuturasByAluno conta as inscrições ativas do aluno em cursos que ainda não aconteceram
+func (r *inscricaoRepository) CountAtivasFuturasByAluno(alunoID uint) (int64, error) {
+	return r.contarAtivas(alunoID, func(models.Curso) bool { return true }), nil
+}
+
+// CountAtivasFuturasByAlunoECategoria conta as inscrições ativas do aluno em cursos futuros da categoria
+func (r *inscricaoRepository) CountAtivasFuturasByAlunoECategoria(alunoID, categoriaID uint) (int64, error) {
+	return r.contarAtivas(alunoID, func(curso models.Curso) bool {
+		return curso.CategoriaID != nil && *curso.CategoriaID == categoriaID
+	}), nil
+}
+
+// CancelarPorCurso marca as inscrições ativas do curso como canceladas pela organização e
+// devolve exatamente as que foram alteradas
+func (r *inscricaoRepository) CancelarPorCurso(cursoID uint) ([]models.Inscricao, error) {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	var canceladas []models.Inscricao
+	for id, inscricao := range r.b.inscricoes {
+		if inscricao.CursoID == cursoID && inscricao.Status == models.StatusInscricaoAtiva {
+			inscricao.Status = models.StatusInscricaoCanceladaOrganizacao
+			r.b.inscricoes[id] = inscricao
+			canceladas = append(canceladas, inscricao)
+		}
+	}
+	sort.Slice(canceladas, func(i, j int) bool { return canceladas[i].ID < canceladas[j].ID })
+	return canceladas, nil
+}
+
+func (r *inscricaoRepository) AnonimizarPorAluno(alunoID uint) error {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	for id, inscricao := range r.b.inscricoes {
+		if inscricao.AlunoID == alunoID {
+			r.b.inscricoes[id] = models.Inscricao{
+				ID:            inscricao.ID,
+				AlunoID:       inscricao.AlunoID,
+				CursoID:       inscricao.CursoID,
+				DataInscricao: inscricao.DataInscricao,
+				Status:        inscricao.Status,
+				DataConclusao: inscricao.DataConclusao,
+			}
+		}
+	}
+	return nil
+}
+
+func (r *inscricaoRepository) CountByCurso(cursoID uint) (int64, error) {
+	inscricoes, _ := r.FindByCurso(cursoID)
+	return int64(len(inscricoes)), nil
+}
+
+func (r *inscricaoRepository) contarAtivas(alunoID uint, criterio func(models.Curso) bool) int64 {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	hoje := models.DiaLocal(time.Now())
+	var total int64
+	for _, inscricao := range r.b.inscricoes {
+		curso, ok := r.b.cursos[inscricao.CursoID]
+		if ok && inscricao.AlunoID == alunoID && inscricao.Status == models.StatusInscricaoAtiva &&
+			!curso.Data.Before(hoje) && criterio(curso) {
+			total++
+		}
+	}
+	return total
+}
+
+func (r *inscricaoRepository) primeira(detalhes bool, criterio func(models.Inscricao) bool) (*models.Inscricao, error) {
+	inscricoes := r.filtrar(detalhes, criterio)
+	if len(inscricoes) == 0 {
+		return nil, erros.ErrInscricaoNaoEncontrada
+	}
+	return &inscricoes[0], nil
+}
+
+// filtrar devolve as inscrições em ordem de ID; com detalhes, preenche o aluno e o curso
+func (r *inscricaoRepository) filtrar(detalhes bool, criterio func(models.Inscricao) bool) []models.Inscricao {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	inscricoes := make([]models.Inscricao, 0)
+	for _, inscricao := range r.b.inscricoes {
+		if !criterio(inscricao) {
+			continue
+		}
+		if detalhes {
+			inscricao.Aluno = r.b.alunos[inscricao.AlunoID]
+			inscricao.Curso = copiarCurso(r.b.cursos[inscricao.CursoID])
+		}
+		inscricoes = append(inscricoes, inscricao)
+	}
+	sort.Slice(inscricoes, func(i, j int) bool { return inscricoes[i].ID < inscricoes[j].ID })
+	return inscricoes
+}
+
+func semAssociacoesInscricao(inscricao models.Inscricao) models.Inscricao {
+	inscricao.Aluno = models.Aluno{}
+	inscricao.Curso = models.Curso{}
+	return inscricao
+}
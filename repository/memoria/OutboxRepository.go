@@ -0,0 +1,45 @@
+package memoria
+
+import (
+	"time"
+
+	"tvtec/models"
+)
+
+type outboxRepository struct {
+	b *Banco
+}
+
+func (r *outboxRepository) Save(evento *models.EventoOutbox) error {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	if evento.ID == 0 {
+		evento.ID = r.b.proximoID("eventos_outbox")
+		r.b.eventos = append(r.b.eventos, *evento)
+		return nil
+	}
+	for i := range r.b.eventos {
+		if r.b.eventos[i].ID == evento.ID {
+			r.b.eventos[i] = *evento
+			return nil
+		}
+	}
+	r.b.eventos = append(r.b.eventos, *evento)
+	return nil
+}
+
+// FindPendentes busca os eventos com tentativa vencida na ordem em que foram gravados
+func (r *outboxRepository) FindPendentes(ate time.Time, limite int) ([]models.EventoOutbox, error) {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	var pendentes []models.EventoOutbox
+	for _, evento := range r.b.eventos {
+		if len(pendentes) == limite {
+			break
+		}
+		if evento.Situacao == models.EventoOutboxPendente && !evento.ProximaTentativa.After(ate) {
+			pendentes = append(pendentes, evento)
+		}
+	}
+	return pendentes, nil
+}
@@ -0,0 +1,299 @@
+package repository
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"tvtec/config"
+	"tvtec/models"
+)
+
+// bancoTeste abre um SQLite temporário já migrado; as consultas com SQL escrito à mão
+// (junções, nomes de tabela) só são verificadas contra um banco de verdade
+func bancoTeste(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := AbrirBanco(config.DriverSQLite, filepath.Join(t.TempDir(), "tvtec.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Migrar(db); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func cursoTeste(t *testing.T, db *gorm.DB, data time.Time) *models.Curso {
+	t.Helper()
+	curso := &models.Curso{Nome: "Informática básica", Professor: "João", Data: models.CustomTime{Time: data}, VagasTotais: 10}
+	if err := db.Create(curso).Error; err != nil {
+		t.Fatal(err)
+	}
+	return curso
+}
+
+func alunoTeste(t *testing.T, db *gorm.DB, cpf string) *models.Aluno {
+	t.Helper()
+	aluno := &models.Aluno{Nome: "Aluno " + cpf, CPF: cpf, Email: cpf + "@example.com", Sexo: "F", DataNascto: time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)}
+	if err := db.Create(aluno).Error; err != nil {
+		t.Fatal(err)
+	}
+	return aluno
+}
+
+func inscreverTeste(t *testing.T, db *gorm.DB, aluno *models.Aluno, curso *models.Curso, status string) *models.Inscricao {
+	t.Helper()
+	inscricao := &models.Inscricao{AlunoID: aluno.ID, CursoID: curso.ID, DataInscricao: time.Now(), Status: status}
+	if err := db.Create(inscricao).Error; err != nil {
+		t.Fatal(err)
+	}
+	return inscricao
+}
+
+func TestPresencasPorCurso(t *testing.T) {
+	db := bancoTeste(t)
+	repo := NewPresencaRepository(db)
+	curso := cursoTeste(t, db, time.Now())
+	outro := cursoTeste(t, db, time.Now())
+	aluno := alunoTeste(t, db, "11111111111")
+	inscricao := inscreverTeste(t, db, aluno, curso, models.StatusInscricaoAtiva)
+	daOutroCurso := inscreverTeste(t, db, aluno, outro, models.StatusInscricaoAtiva)
+
+	hoje := time.Now().Truncate(24 * time.Hour)
+	if err := repo.SaveAll([]models.Presenca{
+		{InscricaoID: inscricao.ID, Data: hoje, Presente: true, RegistradoPor: "joao", RegistradoEm: time.Now()},
+		{InscricaoID: daOutroCurso.ID, Data: hoje, Presente: false, RegistradoPor: "joao", RegistradoEm: time.Now()},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	presencas, err := repo.FindByCurso(curso.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(presencas) != 1 || presencas[0].InscricaoID != inscricao.ID || !presencas[0].Presente {
+		t.Errorf("esperava apenas a presença do curso %d: %+v", curso.ID, presencas)
+	}
+}
+
+func TestContagemDeInscricoesDoAluno(t *testing.T) {
+	db := bancoTeste(t)
+	repo := NewInscricaoRepository(db)
+	categoria := &models.Categoria{Nome: "Tecnologia"}
+	if err := db.Create(categoria).Error; err != nil {
+		t.Fatal(err)
+	}
+	futuro := cursoTeste(t, db, time.Now().AddDate(0, 1, 0))
+	daCategoria := cursoTeste(t, db, time.Now().AddDate(0, 2, 0))
+	db.Model(daCategoria).Update("categoria_id", categoria.ID)
+	passado := cursoTeste(t, db, time.Now().AddDate(0, -1, 0))
+	cancelado := cursoTeste(t, db, time.Now().AddDate(0, 1, 0))
+
+	aluno := alunoTeste(t, db, "11111111111")
+	inscreverTeste(t, db, aluno, futuro, models.StatusInscricaoAtiva)
+	inscreverTeste(t, db, aluno, daCategoria, models.StatusInscricaoAtiva)
+	inscreverTeste(t, db, aluno, passado, models.StatusInscricaoAtiva)
+	inscreverTeste(t, db, aluno, cancelado, models.StatusInscricaoCanceladaOrganizacao)
+
+	if total, err := repo.CountByAluno(aluno.ID); err != nil || total != 4 {
+		t.Errorf("CountByAluno = %d (%v), esperava todas as 4 inscrições", total, err)
+	}
+	if total, err := repo.CountAtivasFuturasByAluno(aluno.ID); err != nil || total != 2 {
+		t.Errorf("CountAtivasFuturasByAluno = %d (%v), esperava 2", total, err)
+	}
+	if total, err := repo.CountAtivasFuturasByAlunoECategoria(aluno.ID, categoria.ID); err != nil || total != 1 {
+		t.Errorf("CountAtivasFuturasByAlunoECategoria = %d (%v), esperava 1", total, err)
+	}
+}
+
+func TestCancelarPorCursoDevolveAsInscricoesAlteradas(t *testing.T) {
+	db := bancoTeste(t)
+	repo := NewInscricaoRepository(db)
+	curso := cursoTeste(t, db, time.Now().AddDate(0, 1, 0))
+	ativa := inscreverTeste(t, db, alunoTeste(t, db, "11111111111"), curso, models.StatusInscricaoAtiva)
+	inscreverTeste(t, db, alunoTeste(t, db, "22222222222"), curso, models.StatusInscricaoConcluida)
+
+	canceladas, err := repo.CancelarPorCurso(curso.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(canceladas) != 1 || canceladas[0].ID != ativa.ID || canceladas[0].AlunoID != ativa.AlunoID ||
+		canceladas[0].Status != models.StatusInscricaoCanceladaOrganizacao {
+		t.Errorf("esperava apenas a inscrição ativa, já cancelada: %+v", canceladas)
+	}
+	if canceladas, err := repo.CancelarPorCurso(curso.ID); err != nil || len(canceladas) != 0 {
+		t.Errorf("um segundo cancelamento não altera nada: %+v (%v)", canceladas, err)
+	}
+}
+
+// Reprocessar um evento da outbox não duplica as entregas; as entregas de teste, sem evento, não colidem
+func TestRegistrarEntregasIgnoraEventoJaRegistrado(t *testing.T) {
+	db := bancoTeste(t)
+	repo := NewWebhookRepository(db)
+	webhook := &models.Webhook{URL: "https://parceiro.example.com", Eventos: []string{models.EventoCursoCriado}, Segredo: "s", Ativo: true, CriadoEm: time.Now()}
+	if err := repo.Save(webhook); err != nil {
+		t.Fatal(err)
+	}
+
+	entrega := func(eventoID uint) models.EntregaWebhook {
+		return models.EntregaWebhook{WebhookID: webhook.ID, EventoID: eventoID, Evento: models.EventoCursoCriado,
+			Payload: "{}", Situacao: models.EntregaWebhookPendente, CriadaEm: time.Now()}
+	}
+	for i := 0; i < 2; i++ {
+		if err := repo.RegistrarEntregas([]models.EntregaWebhook{entrega(7)}); err != nil {
+			t.Fatal(err)
+		}
+		teste := entrega(0)
+		if err := repo.SaveEntrega(&teste); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entregas, err := repo.FindEntregasByWebhook(webhook.ID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	porEvento := map[uint]int{}
+	for _, e := range entregas {
+		porEvento[e.EventoID]++
+	}
+	if porEvento[7] != 1 || porEvento[0] != 2 {
+		t.Errorf("esperava uma entrega do evento e duas de teste, obtive %v", porEvento)
+	}
+}
+
+// O cache só pode ser descartado depois do commit; uma transação desfeita não avisa nada
+func TestAlteracoesDoCatalogoAvisamDepoisDoCommit(t *testing.T) {
+	db := bancoTeste(t)
+	avisos := 0
+	if err := ObservarAlteracoesCatalogo(db, func() { avisos++ }); err != nil {
+		t.Fatal(err)
+	}
+	transacao := NewTransacao(db)
+
+	err := transacao.Executar(func(repos Repositorios) error {
+		curso := &models.Curso{Nome: "Excel", Professor: "Ana", Data: models.CustomTime{Time: time.Now()}, VagasTotais: 5}
+		if err := repos.Cursos.Save(curso); err != nil {
+			return err
+		}
+		if avisos != 0 {
+			t.Errorf("avisou %d vez(es) antes do commit", avisos)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if avisos != 1 {
+		t.Errorf("esperava um aviso depois do commit, foram %d", avisos)
+	}
+
+	avisos = 0
+	_ = transacao.Executar(func(repos Repositorios) error {
+		curso := &models.Curso{Nome: "Word", Professor: "Ana", Data: models.CustomTime{Time: time.Now()}, VagasTotais: 5}
+		if err := repos.Cursos.Save(curso); err != nil {
+			return err
+		}
+		return errors.New("desfazer")
+	})
+	if avisos != 0 {
+		t.Errorf("a transação desfeita avisou %d vez(es)", avisos)
+	}
+
+	cursoTeste(t, db, time.Now())
+	if avisos != 1 {
+		t.Errorf("a escrita fora de transação deveria avisar de imediato, foram %d", avisos)
+	}
+}
+
+// A anonimização apaga as respostas do formulário e mantém o que as estatísticas usam
+func TestAnonimizarInscricoesDoAluno(t *testing.T) {
+	db := bancoTeste(t)
+	repo := NewInscricaoRepository(db)
+	curso := cursoTeste(t, db, time.Now())
+	maria := alunoTeste(t, db, "11111111111")
+	pedro := alunoTeste(t, db, "22222222222")
+	respostas := models.Inscricao{Escolaridade: "Superior", Trabalhando: "sim", Bairro: "Centro", EhCuidador: "não", EhPCD: "sim",
+		TipoPCD: "visual", NecessitaElevador: "sim", ComoSoube: "Instagram", AutorizaWhatsApp: "sim", LevaNotebook: "sim"}
+	for _, aluno := range []*models.Aluno{maria, pedro} {
+		inscricao := respostas
+		inscricao.AlunoID, inscricao.CursoID, inscricao.DataInscricao, inscricao.Status = aluno.ID, curso.ID, time.Now(), models.StatusInscricaoConcluida
+		if err := db.Create(&inscricao).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := repo.AnonimizarPorAluno(maria.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	anonimizada, err := repo.FindByAlunoECurso(maria.ID, curso.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if anonimizada.Status != models.StatusInscricaoConcluida || anonimizada.Escolaridade != "" || anonimizada.Trabalhando != "" || anonimizada.Bairro != "" ||
+		anonimizada.EhCuidador != "" || anonimizada.EhPCD != "" || anonimizada.TipoPCD != "" || anonimizada.NecessitaElevador != "" ||
+		anonimizada.ComoSoube != "" || anonimizada.AutorizaWhatsApp != "" || anonimizada.LevaNotebook != "" {
+		t.Errorf("respostas mantidas na inscrição anonimizada: %+v", anonimizada)
+	}
+
+	outra, err := repo.FindByAlunoECurso(pedro.ID, curso.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outra.Bairro != "Centro" || outra.TipoPCD != "visual" {
+		t.Errorf("a inscrição de outro aluno foi alterada: %+v", outra)
+	}
+}
+
+func TestAnonimizarConflitosEFusoesDoAluno(t *testing.T) {
+	db := bancoTeste(t)
+	repo := NewDuplicidadeRepository(db)
+	maria := alunoTeste(t, db, "11111111111")
+	pedro := alunoTeste(t, db, "22222222222")
+	for _, aluno := range []*models.Aluno{maria, pedro} {
+		conflito := &models.ConflitoCadastro{AlunoID: aluno.ID, Campo: "nome", ValorCadastrado: aluno.Nome, ValorInformado: "Outro nome",
+			Origem: models.OrigemConflitoInscricao, Situacao: models.ConflitoPendente, DataRegistro: time.Now()}
+		if err := repo.SaveConflito(conflito); err != nil {
+			t.Fatal(err)
+		}
+		fusao := &models.FusaoAlunos{AlunoMantidoID: aluno.ID, AlunoRemovidoID: 99, DadosAlunoRemovido: `{"nome":"Duplicado"}`, Responsavel: "admin", DataFusao: time.Now()}
+		if err := db.Create(fusao).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := repo.AnonimizarPorAluno(maria.ID, "titular"); err != nil {
+		t.Fatal(err)
+	}
+
+	conflitos, err := repo.FindConflitosByAluno(maria.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflitos) != 1 || conflitos[0].ValorCadastrado != "" || conflitos[0].ValorInformado != "" ||
+		conflitos[0].Situacao != models.ConflitoDescartado || conflitos[0].ResolvidoPor != "titular" || conflitos[0].ResolvidoEm == nil {
+		t.Errorf("conflito não anonimizado: %+v", conflitos)
+	}
+	fusoes, err := repo.FindFusoesByAluno(maria.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fusoes) != 1 || fusoes[0].DadosAlunoRemovido != "{}" {
+		t.Errorf("fusão não anonimizada: %+v", fusoes)
+	}
+
+	outros, _ := repo.FindConflitosByAluno(pedro.ID)
+	outrasFusoes, _ := repo.FindFusoesByAluno(pedro.ID)
+	if len(outros) != 1 || outros[0].ValorInformado != "Outro nome" || len(outrasFusoes) != 1 || outrasFusoes[0].DadosAlunoRemovido == "{}" {
+		t.Errorf("os registros de outro aluno foram alterados: %+v %+v", outros, outrasFusoes)
+	}
+}
@@ -0,0 +1,330 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+
+	"tvtec/models"
+	"tvtec/repository"
+)
+
+// ItemChecklist é uma providência que a coordenação precisa tomar antes do curso
+type ItemChecklist struct {
+	Providencia string `json:"providencia"`
+	Motivo      string `json:"motivo"`
+	Quantidade  int    `json:"quantidade"`
+}
+
+// InscritoAcessibilidade resume as necessidades de um inscrito para contato da coordenação
+type InscritoAcessibilidade struct {
+	InscricaoID       uint   `json:"inscricaoId"`
+	Nome              string `json:"nome"`
+	Telefone          string `json:"telefone"`
+	TipoPCD           string `json:"tipoPCD"`
+	NecessitaElevador bool   `json:"necessitaElevador"`
+	EhCuidador        bool   `json:"ehCuidador"`
+}
+
+// RelatorioAcessibilidade consolida as respostas de acessibilidade das inscrições de um curso
+type RelatorioAcessibilidade struct {
+	CursoID            uint                     `json:"cursoId"`
+	Curso              string                   `json:"curso"`
+	DataCurso          time.Time                `json:"dataCurso"`
+	Local              string                   `json:"local,omitempty"`
+	TotalInscritos     int                      `json:"totalInscritos"`
+	PCDs               int                      `json:"pcds"`
+	NecessitamElevador int                      `json:"necessitamElevador"`
+	Cuidadores         int                      `json:"cuidadores"`
+	TiposPCD           map[string]int           `json:"tiposPCD"`
+	Checklist          []ItemChecklist          `json:"checklist"`
+	Avisos             []string                 `json:"avisos"`
+	Inscritos          []InscritoAcessibilidade `json:"inscritos"`
+	GeradoEm           time.Time                `json:"geradoEm"`
+}
+
+// Providências sugeridas conforme palavras encontradas no tipo de deficiência informado
+var providenciasPorTipoPCD = []struct {
+	palavras    []string
+	providencia string
+}{
+	{[]string{"auditiva", "surd", "libras"}, "Contratar intérprete de Libras"},
+	{[]string{"visual", "cego", "cegueira", "baixa visão"}, "Preparar material ampliado ou em braile e leitor de tela"},
+	{[]string{"física", "fisica", "motora", "cadeira", "mobilidade"}, "Garantir rampa de acesso e mesa adaptada"},
+	{[]string{"intelectual", "autis", "tea", "tdah"}, "Preparar material simplificado e apoio pedagógico"},
+}
+
+// Interface para o serviço de planejamento de acessibilidade
+type AcessibilidadeService interface {
+	GerarRelatorio(cursoID uint) (*RelatorioAcessibilidade, error)
+	GerarPDF(relatorio *RelatorioAcessibilidade) ([]byte, error)
+	InscricaoRecebida(curso *models.Curso, inscricao *models.Inscricao) error
+}
+
+type acessibilidadeServiceImpl struct {
+	cursoRepo        repository.CursoRepository
+	inscricaoRepo    repository.InscricaoRepository
+	notificador      Notificador
+	emailCoordenacao string
+	diasAlerta       int
+}
+
+// Função construtora para o serviço de acessibilidade.
+// Inscrições PCD recebidas a menos de diasAlerta dias do curso geram alerta para emailCoordenacao.
+func NewAcessibilidadeService(
+	cursoRepo repository.CursoRepository,
+	inscricaoRepo repository.InscricaoRepository,
+	notificador Notificador,
+	emailCoordenacao string,
+	diasAlerta int,
+) AcessibilidadeService {
+	return &acessibilidadeServiceImpl{
+		cursoRepo:        cursoRepo,
+		inscricaoRepo:    inscricaoRepo,
+		notificador:      notificador,
+		emailCoordenacao: emailCoordenacao,
+		diasAlerta:       diasAlerta,
+	}
+}
+
+func (s *acessibilidadeServiceImpl) GerarRelatorio(cursoID uint) (*RelatorioAcessibilidade, error) {
+	curso, err := s.cursoRepo.FindByID(cursoID)
+	if err != nil {
+		return nil, err
+	}
+
+	todas, err := s.inscricaoRepo.FindByCursoWithDetails(cursoID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Inscrições canceladas pela organização ficam no histórico, mas não entram no planejamento
+	var inscricoes []models.Inscricao
+	for _, inscricao := range todas {
+		if inscricao.Status == models.StatusInscricaoAtiva || inscricao.Status == models.StatusInscricaoConcluida {
+			inscricoes = append(inscricoes, inscricao)
+		}
+	}
+
+	relatorio := &RelatorioAcessibilidade{
+		CursoID:        curso.ID,
+		Curso:          curso.Nome,
+		DataCurso:      curso.Data.Time,
+		TotalInscritos: len(inscricoes),
+		TiposPCD:       map[string]int{},
+		Checklist:      []ItemChecklist{},
+		Inscritos:      []InscritoAcessibilidade{},
+		Avisos:         avisosSala(curso, inscricoes),
+		GeradoEm:       time.Now(),
+	}
+	if curso.Sala != nil {
+		relatorio.Local = curso.Sala.Nome
+		if curso.Sala.Local != nil {
+			relatorio.Local = fmt.Sprintf("%s - %s (%s)", curso.Sala.Local.Nome, curso.Sala.Nome, curso.Sala.Local.Endereco)
+		}
+	}
+
+	providencias := map[string]int{}
+	for _, inscricao := range inscricoes {
+		ehPCD := respostaAfirmativa(inscricao.EhPCD)
+		elevador := respostaAfirmativa(inscricao.NecessitaElevador)
+		cuidador := respostaAfirmativa(inscricao.EhCuidador)
+
+		if ehPCD {
+			relatorio.PCDs++
+			tipo := strings.TrimSpace(strings.ToLower(inscricao.TipoPCD))
+			if tipo == "" {
+				tipo = "não informado"
+			}
+			relatorio.TiposPCD[tipo]++
+			for _, p := range providenciasPorTipoPCD {
+				if contemAlguma(tipo, p.palavras) {
+					providencias[p.providencia]++
+				}
+			}
+		}
+		if elevador {
+			relatorio.NecessitamElevador++
+		}
+		if cuidador {
+			relatorio.Cuidadores++
+		}
+
+		if ehPCD || elevador || cuidador {
+			relatorio.Inscritos = append(relatorio.Inscritos, InscritoAcessibilidade{
+				InscricaoID:       inscricao.ID,
+				Nome:              inscricao.Aluno.Nome,
+				Telefone:          inscricao.Aluno.Telefone,
+				TipoPCD:           inscricao.TipoPCD,
+				NecessitaElevador: elevador,
+				EhCuidador:        cuidador,
+			})
+		}
+	}
+
+	nomes := make([]string, 0, len(providencias))
+	for nome := range providencias {
+		nomes = append(nomes, nome)
+	}
+	sort.Strings(nomes)
+	for _, nome := range nomes {
+		relatorio.Checklist = append(relatorio.Checklist, ItemChecklist{
+			Providencia: nome,
+			Motivo:      "tipo de deficiência informado pelos inscritos",
+			Quantidade:  providencias[nome],
+		})
+	}
+	if relatorio.NecessitamElevador > 0 {
+		relatorio.Checklist = append(relatorio.Checklist, ItemChecklist{
+			Providencia: "Confirmar elevador em funcionamento ou sala no térreo",
+			Motivo:      "inscritos que precisam de elevador",
+			Quantidade:  relatorio.NecessitamElevador,
+		})
+	}
+	if relatorio.Cuidadores > 0 {
+		relatorio.Checklist = append(relatorio.Checklist, ItemChecklist{
+			Providencia: "Reservar lugares para acompanhantes",
+			Motivo:      "inscritos que são cuidadores",
+			Quantidade:  relatorio.Cuidadores,
+		})
+	}
+
+	return relatorio, nil
+}
+
+func (s *acessibilidadeServiceImpl) GerarPDF(relatorio *RelatorioAcessibilidade) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.Cell(0, 10, tr("Planejamento de acessibilidade"))
+	pdf.Ln(8)
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.MultiCell(0, 6, tr(fmt.Sprintf("%s - %s", relatorio.Curso, relatorio.DataCurso.Format("02/01/2006"))), "", "", false)
+	if relatorio.Local != "" {
+		pdf.MultiCell(0, 6, tr(relatorio.Local), "", "", false)
+	}
+	pdf.MultiCell(0, 6, tr(fmt.Sprintf("%d inscritos: %d PCD, %d precisam de elevador, %d cuidadores",
+		relatorio.TotalInscritos, relatorio.PCDs, relatorio.NecessitamElevador, relatorio.Cuidadores)), "", "", false)
+
+	secao := func(titulo string) {
+		pdf.Ln(4)
+		pdf.SetFont("Helvetica", "B", 12)
+		pdf.Cell(0, 8, tr(titulo))
+		pdf.Ln(8)
+		pdf.SetFont("Helvetica", "", 10)
+	}
+
+	if len(relatorio.Avisos) > 0 {
+		secao("Avisos")
+		for _, aviso := range relatorio.Avisos {
+			pdf.MultiCell(0, 6, tr("! "+aviso), "", "", false)
+		}
+	}
+
+	secao("Checklist")
+	if len(relatorio.Checklist) == 0 {
+		pdf.MultiCell(0, 6, tr("Nenhuma providência necessária."), "", "", false)
+	}
+	for _, item := range relatorio.Checklist {
+		pdf.MultiCell(0, 6, tr(fmt.Sprintf("[  ] %s (%d - %s)", item.Providencia, item.Quantidade, item.Motivo)), "", "", false)
+	}
+
+	if len(relatorio.Inscritos) > 0 {
+		secao("Inscritos com necessidades")
+		for _, inscrito := range relatorio.Inscritos {
+			var necessidades []string
+			if inscrito.TipoPCD != "" {
+				necessidades = append(necessidades, "PCD: "+inscrito.TipoPCD)
+			}
+			if inscrito.NecessitaElevador {
+				necessidades = append(necessidades, "elevador")
+			}
+			if inscrito.EhCuidador {
+				necessidades = append(necessidades, "cuidador")
+			}
+			pdf.MultiCell(0, 6, tr(fmt.Sprintf("%s (%s) - %s", inscrito.Nome, inscrito.Telefone, strings.Join(necessidades, ", "))), "", "", false)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// InscricaoRecebida é chamada pela outbox depois que a vaga é confirmada; a falha no envio do
+// alerta é devolvida para que o evento seja tentado de novo
+func (s *acessibilidadeServiceImpl) InscricaoRecebida(curso *models.Curso, inscricao *models.Inscricao) error {
+	if curso.Sala != nil && respostaAfirmativa(inscricao.NecessitaElevador) && !curso.Sala.AcessivelSemElevador() {
+		log.Printf("AVISO de acessibilidade: inscrição no curso %d precisa de elevador, mas a sala %q não tem acesso sem escadas",
+			curso.ID, curso.Sala.Nome)
+	}
+
+	if !respostaAfirmativa(inscricao.EhPCD) && !respostaAfirmativa(inscricao.NecessitaElevador) {
+		return nil
+	}
+
+	// Só alerta quando falta pouco tempo para preparar o atendimento
+	faltam := time.Until(curso.Data.Time)
+	if faltam < 0 || faltam > time.Duration(s.diasAlerta)*24*time.Hour {
+		return nil
+	}
+	if s.emailCoordenacao == "" {
+		log.Printf("AVISO: inscrição PCD no curso %d a menos de %d dias do início, mas nenhum email de coordenação está configurado",
+			curso.ID, s.diasAlerta)
+		return nil
+	}
+
+	mensagem := fmt.Sprintf("Uma nova inscrição com necessidades de acessibilidade chegou para o curso %q, que acontece em %s.\n\n"+
+		"Tipo de deficiência: %s\nNecessita elevador: %s\n\nConsulte o planejamento completo em /admin/curso/%d/acessibilidade.",
+		curso.Nome, curso.Data.Format("02/01/2006"), inscricao.TipoPCD, inscricao.NecessitaElevador, curso.ID)
+	return s.notificador.Enviar(s.emailCoordenacao, "Inscrição PCD próxima ao início do curso", mensagem)
+}
+
+// avisosSala compara as necessidades dos inscritos com as condições da sala do curso
+func avisosSala(curso *models.Curso, inscricoes []models.Inscricao) []string {
+	var precisamElevador, pcds int
+	for _, inscricao := range inscricoes {
+		if respostaAfirmativa(inscricao.NecessitaElevador) {
+			precisamElevador++
+		}
+		if respostaAfirmativa(inscricao.EhPCD) {
+			pcds++
+		}
+	}
+
+	avisos := []string{}
+	if curso.Sala == nil {
+		if precisamElevador > 0 || pcds > 0 {
+			avisos = append(avisos, "o curso tem inscritos com necessidades de acessibilidade, mas nenhuma sala foi definida")
+		}
+		return avisos
+	}
+
+	if precisamElevador > 0 && !curso.Sala.AcessivelSemElevador() {
+		avisos = append(avisos, fmt.Sprintf("%d inscrito(s) precisam de elevador, mas a sala %q fica no %dº andar de um local sem elevador",
+			precisamElevador, curso.Sala.Nome, curso.Sala.Andar))
+	}
+	if pcds > 0 && curso.Sala.Local != nil && !curso.Sala.Local.BanheiroAcessivel {
+		avisos = append(avisos, fmt.Sprintf("%d inscrito(s) PCD, mas o local %q não tem banheiro acessível",
+			pcds, curso.Sala.Local.Nome))
+	}
+	return avisos
+}
+
+func contemAlguma(texto string, palavras []string) bool {
+	for _, palavra := range palavras {
+		if strings.Contains(texto, palavra) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,9 +1,11 @@
 package service
 
 import (
-	"errors"
+	"log"
 	"time"
 
+	"tvtec/erros"
+	"tvtec/i18n"
 	"tvtec/models"
 	"tvtec/repository"
 )
@@ -15,25 +17,34 @@ type AlunoService interface {
 	CriarAluno(aluno *models.Aluno) error
 	AtualizarAluno(aluno *models.Aluno) error
 	RemoverAluno(id uint) error
-	CadastrarAlunoEInscrever(aluno *models.Aluno, inscricao *models.Inscricao) error
-	AdicionarAlunoCurso(alunoID, cursoID uint) error
-	CriarInscricaoDetalhada(inscricao *models.Inscricao) error
+	CadastrarAlunoEInscrever(aluno *models.Aluno, inscricao *models.Inscricao, autorizacao *models.Consentimento, origem string) ([]models.ConflitoCadastro, error)
+	ValidarCadastroEInscricao(aluno *models.Aluno, inscricao *models.Inscricao) ([]models.ConflitoCadastro, error)
+	AdicionarAlunoCurso(alunoID, cursoID uint, opcoes OpcoesInscricao) error
+	CriarInscricaoDetalhada(inscricao *models.Inscricao, opcoes OpcoesInscricao) error
 	ListarInscricoesAluno(alunoID uint) ([]models.Inscricao, error)
 }
 
 // Implementação do serviço de alunos
 type alunoServiceImpl struct {
-	alunoRepo     repository.AlunoRepository
-	cursoRepo     repository.CursoRepository
-	inscricaoRepo repository.InscricaoRepository
+	alunoRepo       repository.AlunoRepository
+	cursoRepo       repository.CursoRepository
+	inscricaoRepo   repository.InscricaoRepository
+	duplicidadeRepo repository.DuplicidadeRepository
+	transacao       repository.Transacao
+	eventos         PublicadorEventos
+	limites         LimitesInscricao
 }
 
 // Função construtora para o serviço de alunos
-func NewAlunoService(alunoRepo repository.AlunoRepository, cursoRepo repository.CursoRepository, inscricaoRepo repository.InscricaoRepository) AlunoService {
+func NewAlunoService(alunoRepo repository.AlunoRepository, cursoRepo repository.CursoRepository, inscricaoRepo repository.InscricaoRepository, duplicidadeRepo repository.DuplicidadeRepository, transacao repository.Transacao, eventos PublicadorEventos, limites LimitesInscricao) AlunoService {
 	return &alunoServiceImpl{
-		alunoRepo:     alunoRepo,
-		cursoRepo:     cursoRepo,
-		inscricaoRepo: inscricaoRepo,
+		alunoRepo:       alunoRepo,
+		cursoRepo:       cursoRepo,
+		inscricaoRepo:   inscricaoRepo,
+		duplicidadeRepo: duplicidadeRepo,
+		transacao:       transacao,
+		eventos:         eventos,
+		limites:         limites,
 	}
 }
 
@@ -46,7 +57,7 @@ func (s *alunoServiceImpl) ListarAlunos() ([]models.Aluno, error) {
 func (s *alunoServiceImpl) ObterAlunoPorID(id uint) (*models.Aluno, error) {
 	aluno, err := s.alunoRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("aluno não encontrado")
+		return nil, erros.ErrAlunoNaoEncontrado
 	}
 	return aluno, nil
 }
@@ -54,17 +65,17 @@ func (s *alunoServiceImpl) ObterAlunoPorID(id uint) (*models.Aluno, error) {
 func (s *alunoServiceImpl) CriarAluno(aluno *models.Aluno) error {
 	// Validações básicas
 	if aluno.Nome == "" {
-		return errors.New("nome do aluno é obrigatório")
+		return erros.ErrDadosInvalidos.ComMensagem("Nome do aluno é obrigatório")
 	}
 
 	if aluno.Email == "" {
-		return errors.New("email do aluno é obrigatório")
+		return erros.ErrDadosInvalidos.ComMensagem("Email do aluno é obrigatório")
 	}
 
 	// Verificar se já existe aluno com este email
 	existente, _ := s.alunoRepo.FindByEmail(aluno.Email)
 	if existente != nil {
-		return errors.New("já existe um aluno cadastrado com este email")
+		return erros.ErrCadastroDuplicado.ComMensagem("Já existe um aluno cadastrado com este email")
 	}
 
 	return s.alunoRepo.Save(aluno)
@@ -74,7 +85,7 @@ func (s *alunoServiceImpl) AtualizarAluno(aluno *models.Aluno) error {
 	// Verificar se o aluno existe
 	existente, err := s.alunoRepo.FindByID(aluno.ID)
 	if err != nil {
-		return errors.New("aluno não encontrado")
+		return erros.ErrAlunoNaoEncontrado
 	}
 
 	// Manter campos importantes do registro original
@@ -86,13 +97,22 @@ func (s *alunoServiceImpl) AtualizarAluno(aluno *models.Aluno) error {
 		aluno.Email = existente.Email
 	}
 
-	return s.alunoRepo.Save(aluno)
+	if aluno.Idioma == "" {
+		aluno.Idioma = existente.Idioma
+	} else {
+		aluno.Idioma = string(i18n.Normalizar(aluno.Idioma))
+	}
+
+	// O link do calendário só muda pelo próprio fluxo, não pela edição do cadastro
+	aluno.ChaveCalendario = existente.ChaveCalendario
+
+	return s.alunoRepo.Update(aluno)
 }
 
 func (s *alunoServiceImpl) RemoverAluno(id uint) error {
 	// Verificar se o aluno existe (sem armazenar o resultado)
 	if _, err := s.alunoRepo.FindByID(id); err != nil {
-		return errors.New("aluno não encontrado")
+		return erros.ErrAlunoNaoEncontrado
 	}
 
 	// Verificar se aluno tem inscrições ativas
@@ -102,79 +122,147 @@ func (s *alunoServiceImpl) RemoverAluno(id uint) error {
 	}
 
 	if len(inscricoes) > 0 {
-		return errors.New("não é possível remover aluno com inscrições ativas")
+		return erros.ErrRegistroEmUso.ComMensagem("Não é possível remover aluno com inscrições ativas")
 	}
 
 	return s.alunoRepo.Delete(id)
 }
 
-func (s *alunoServiceImpl) CadastrarAlunoEInscrever(aluno *models.Aluno, inscricao *models.Inscricao) error {
-	// Tenta encontrar o aluno pelo email
+// CadastrarAlunoEInscrever inscreve o aluno no curso, reaproveitando o cadastro existente com o mesmo
+// email ou CPF. Dados informados que divergem desse cadastro não o alteram: são devolvidos e
+// registrados como conflitos para revisão da coordenação. A autorização dada no formulário, quando
+// houver, entra no histórico de consentimentos junto com a inscrição.
+func (s *alunoServiceImpl) CadastrarAlunoEInscrever(aluno *models.Aluno, inscricao *models.Inscricao, autorizacao *models.Consentimento, origem string) (_ []models.ConflitoCadastro, err error) {
+	defer func() { contarInscricao(err) }()
+
+	aluno, _, conflitos, err := s.prepararCadastroEInscricao(aluno, inscricao)
+	if err != nil {
+		return nil, err
+	}
+
+	// Define valor padrão para campos opcionais
+	if inscricao.EhPCD == "" {
+		inscricao.EhPCD = "não"
+	}
+
+	// O aluno novo, a inscrição, o consentimento e o evento são gravados juntos
+	err = emTransacao(s.transacao, s.eventos, func(repos repository.Repositorios) error {
+		if aluno.ID == 0 {
+			if err := repos.Alunos.Save(aluno); err != nil {
+				return err
+			}
+		}
+		inscricao.AlunoID = aluno.ID
+		if err := repos.Inscricoes.Save(inscricao); err != nil {
+			return err
+		}
+		if autorizacao != nil {
+			autorizacao.ID = 0
+			autorizacao.AlunoID = aluno.ID
+			autorizacao.DataRegistro = inscricao.DataInscricao
+			if err := repos.Consentimentos.Save(autorizacao); err != nil {
+				return err
+			}
+		}
+		return registrarInscricaoCriada(s.eventos, repos, inscricao)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range conflitos {
+		conflitos[i].InscricaoID = inscricao.ID
+		conflitos[i].Origem = origem
+		conflitos[i].DataRegistro = inscricao.DataInscricao
+		if err := s.duplicidadeRepo.SaveConflito(&conflitos[i]); err != nil {
+			log.Printf("Erro ao registrar conflito de cadastro do aluno %d: %v", aluno.ID, err)
+		}
+	}
+
+	return conflitos, nil
+}
+
+// ValidarCadastroEInscricao aplica as regras de CadastrarAlunoEInscrever sem gravar nada
+func (s *alunoServiceImpl) ValidarCadastroEInscricao(aluno *models.Aluno, inscricao *models.Inscricao) ([]models.ConflitoCadastro, error) {
+	_, _, conflitos, err := s.prepararCadastroEInscricao(aluno, inscricao)
+	return conflitos, err
+}
+
+// prepararCadastroEInscricao troca o aluno pelo cadastro existente (mesmo email ou CPF), quando houver,
+// e verifica todas as regras de inscrição no curso. Também devolve as divergências entre os dados
+// informados e o cadastro existente.
+func (s *alunoServiceImpl) prepararCadastroEInscricao(aluno *models.Aluno, inscricao *models.Inscricao) (*models.Aluno, *models.Curso, []models.ConflitoCadastro, error) {
+	// Tenta encontrar o aluno pelo email e, se não encontrar, pelo CPF
 	alunoExistente, _ := s.alunoRepo.FindByEmail(aluno.Email)
+	if alunoExistente == nil {
+		alunoExistente, _ = s.alunoRepo.FindByCPF(aluno.CPF)
+	}
+	var conflitos []models.ConflitoCadastro
 	if alunoExistente != nil {
 		// O aluno já existe: usa o registro existente
+		conflitos = compararCadastro(alunoExistente, aluno)
 		aluno = alunoExistente
-	} else {
-		// Se não encontrou pelo email, pode verificar pelo CPF, se necessário
-		existenteCPF, _ := s.alunoRepo.FindByCPF(aluno.CPF)
-		if existenteCPF != nil {
-			aluno = existenteCPF
-		} else {
-			// Se o aluno não existe, salva o novo aluno
-			if err := s.alunoRepo.Save(aluno); err != nil {
-				return err
-			}
-		}
 	}
 
 	// Verifica se o curso existe
 	curso, err := s.cursoRepo.FindByID(inscricao.CursoID)
 	if err != nil {
-		return errors.New("curso não encontrado")
+		return nil, nil, nil, erros.ErrCursoNaoEncontrado
 	}
 
 	// Verifica disponibilidade de vagas
 	if curso.VagasPreenchidas >= curso.VagasTotais {
-		return errors.New("não há vagas disponíveis para este curso")
+		return nil, nil, nil, erros.ErrSemVagas
 	}
 
-	// Opcional: Verifica se já existe inscrição para este aluno e curso
-	inscricaoExistente, _ := s.inscricaoRepo.FindByAlunoECurso(aluno.ID, curso.ID)
-	if inscricaoExistente != nil {
-		return errors.New("já existe uma inscrição para este curso")
+	if err := verificarPeriodoInscricoes(curso, time.Now()); err != nil {
+		return nil, nil, nil, err
 	}
 
-	// Associa o aluno à inscrição
-	inscricao.AlunoID = aluno.ID
-
-	// Define valor padrão para campos opcionais
-	if inscricao.EhPCD == "" {
-		inscricao.EhPCD = "não"
+	// Verifica se já existe inscrição para este aluno e curso
+	if aluno.ID != 0 {
+		inscricaoExistente, _ := s.inscricaoRepo.FindByAlunoECurso(aluno.ID, curso.ID)
+		if inscricaoExistente != nil {
+			return nil, nil, nil, erros.ErrInscricaoDuplicada
+		}
 	}
 
-	// Salva a inscrição
-	if err := s.inscricaoRepo.Save(inscricao); err != nil {
-		return err
+	// Verifica os pré-requisitos, os limites e a elegibilidade antes de cadastrar um aluno novo
+	if err := verificarPreRequisitos(s.inscricaoRepo, curso, aluno.ID, OpcoesInscricao{}); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := verificarLimites(s.inscricaoRepo, curso, aluno.ID, s.limites, OpcoesInscricao{}); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := verificarElegibilidade(s.inscricaoRepo, curso, aluno, inscricao, OpcoesInscricao{}); err != nil {
+		return nil, nil, nil, err
 	}
 
-	return nil
+	return aluno, curso, conflitos, nil
 }
 
-func (s *alunoServiceImpl) AdicionarAlunoCurso(alunoID, cursoID uint) error {
+func (s *alunoServiceImpl) AdicionarAlunoCurso(alunoID, cursoID uint, opcoes OpcoesInscricao) (err error) {
+	defer func() { contarInscricao(err) }()
+
 	// Verificar se o aluno existe
-	if _, err := s.alunoRepo.FindByID(alunoID); err != nil {
-		return errors.New("aluno não encontrado")
+	aluno, err := s.alunoRepo.FindByID(alunoID)
+	if err != nil {
+		return erros.ErrAlunoNaoEncontrado
 	}
 
 	// Verificar se o curso existe e tem vagas disponíveis
 	curso, err := s.cursoRepo.FindByID(cursoID)
 	if err != nil {
-		return errors.New("curso não encontrado")
+		return erros.ErrCursoNaoEncontrado
 	}
 
 	// Verificar disponibilidade de vagas - aqui usamos a variável curso
 	if curso.VagasPreenchidas >= curso.VagasTotais {
-		return errors.New("não há vagas disponíveis para este curso")
+		return erros.ErrSemVagas
+	}
+
+	if err := verificarPeriodoInscricoes(curso, time.Now()); err != nil {
+		return err
 	}
 
 	// Verificar se o aluno já está inscrito neste curso
@@ -185,10 +273,20 @@ func (s *alunoServiceImpl) AdicionarAlunoCurso(alunoID, cursoID uint) error {
 
 	for _, inscricao := range inscricoes {
 		if inscricao.CursoID == cursoID {
-			return errors.New("aluno já está inscrito neste curso")
+			return erros.ErrInscricaoDuplicada
 		}
 	}
 
+	if err := verificarPreRequisitos(s.inscricaoRepo, curso, alunoID, opcoes); err != nil {
+		return err
+	}
+	if err := verificarLimites(s.inscricaoRepo, curso, alunoID, s.limites, opcoes); err != nil {
+		return err
+	}
+	if err := verificarElegibilidade(s.inscricaoRepo, curso, aluno, nil, opcoes); err != nil {
+		return err
+	}
+
 	// Criar uma nova inscrição
 	inscricao := &models.Inscricao{
 		AlunoID:       alunoID,
@@ -197,26 +295,37 @@ func (s *alunoServiceImpl) AdicionarAlunoCurso(alunoID, cursoID uint) error {
 		EhPCD:         "não", // Valor padrão
 	}
 
-	// Salvar a inscrição
-	return s.inscricaoRepo.Save(inscricao)
+	// Salvar a inscrição junto com o evento
+	return emTransacao(s.transacao, s.eventos, func(repos repository.Repositorios) error {
+		if err := repos.Inscricoes.Save(inscricao); err != nil {
+			return err
+		}
+		return registrarInscricaoCriada(s.eventos, repos, inscricao)
+	})
 }
 
-func (s *alunoServiceImpl) CriarInscricaoDetalhada(inscricao *models.Inscricao) error {
+func (s *alunoServiceImpl) CriarInscricaoDetalhada(inscricao *models.Inscricao, opcoes OpcoesInscricao) (err error) {
+	defer func() { contarInscricao(err) }()
+
 	// Verificar se o aluno existe
-	_, err := s.alunoRepo.FindByID(inscricao.AlunoID)
+	aluno, err := s.alunoRepo.FindByID(inscricao.AlunoID)
 	if err != nil {
-		return errors.New("aluno não encontrado")
+		return erros.ErrAlunoNaoEncontrado
 	}
 
 	// Verificar se o curso existe
 	curso, err := s.cursoRepo.FindByID(inscricao.CursoID)
 	if err != nil {
-		return errors.New("curso não encontrado")
+		return erros.ErrCursoNaoEncontrado
 	}
 
 	// Verificar disponibilidade de vagas
 	if curso.VagasPreenchidas >= curso.VagasTotais {
-		return errors.New("não há vagas disponíveis para este curso")
+		return erros.ErrSemVagas
+	}
+
+	if err := verificarPeriodoInscricoes(curso, time.Now()); err != nil {
+		return err
 	}
 
 	// Verificar se o aluno já está inscrito neste curso
@@ -227,10 +336,20 @@ func (s *alunoServiceImpl) CriarInscricaoDetalhada(inscricao *models.Inscricao)
 
 	for _, existente := range inscricoes {
 		if existente.CursoID == inscricao.CursoID {
-			return errors.New("aluno já está inscrito neste curso")
+			return erros.ErrInscricaoDuplicada
 		}
 	}
 
+	if err := verificarPreRequisitos(s.inscricaoRepo, curso, inscricao.AlunoID, opcoes); err != nil {
+		return err
+	}
+	if err := verificarLimites(s.inscricaoRepo, curso, inscricao.AlunoID, s.limites, opcoes); err != nil {
+		return err
+	}
+	if err := verificarElegibilidade(s.inscricaoRepo, curso, aluno, inscricao, opcoes); err != nil {
+		return err
+	}
+
 	// Definir valores padrão para campos opcionais se não forem informados
 	if inscricao.EhPCD == "" {
 		inscricao.EhPCD = "não"
@@ -241,15 +360,20 @@ func (s *alunoServiceImpl) CriarInscricaoDetalhada(inscricao *models.Inscricao)
 		inscricao.DataInscricao = time.Now()
 	}
 
-	// Salvar a inscrição
-	return s.inscricaoRepo.Save(inscricao)
+	// Salvar a inscrição junto com o evento
+	return emTransacao(s.transacao, s.eventos, func(repos repository.Repositorios) error {
+		if err := repos.Inscricoes.Save(inscricao); err != nil {
+			return err
+		}
+		return registrarInscricaoCriada(s.eventos, repos, inscricao)
+	})
 }
 
 func (s *alunoServiceImpl) ListarInscricoesAluno(alunoID uint) ([]models.Inscricao, error) {
 	// Verificar se o aluno existe
 	_, err := s.alunoRepo.FindByID(alunoID)
 	if err != nil {
-		return nil, errors.New("aluno não encontrado")
+		return nil, erros.ErrAlunoNaoEncontrado
 	}
 
 	// Buscar inscrições do aluno com detalhes de cursos
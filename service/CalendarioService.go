@@ -0,0 +1,285 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"tvtec/erros"
+	"tvtec/i18n"
+	"tvtec/models"
+	"tvtec/repository"
+)
+
+// Janela de cursos passados mantida no feed público
+const diasHistoricoCalendario = 90
+
+type CalendarioService interface {
+	CalendarioCurso(cursoID uint) (string, error)
+	CalendarioPublico() (string, error)
+	CalendarioAluno(alunoID uint, token string) (string, error)
+	LinkCalendarioAluno(alunoID uint) (string, error)
+	EnviarLinkCalendario(alunoID uint) error
+	RevogarLinkCalendario(alunoID uint) error
+}
+
+type calendarioService struct {
+	cursoRepo     repository.CursoRepository
+	inscricaoRepo repository.InscricaoRepository
+	alunoRepo     repository.AlunoRepository
+	notificador   Notificador
+	baseURL       string
+}
+
+func NewCalendarioService(
+	cursoRepo repository.CursoRepository,
+	inscricaoRepo repository.InscricaoRepository,
+	alunoRepo repository.AlunoRepository,
+	notificador Notificador,
+	baseURL string,
+) CalendarioService {
+	return &calendarioService{
+		cursoRepo:     cursoRepo,
+		inscricaoRepo: inscricaoRepo,
+		alunoRepo:     alunoRepo,
+		notificador:   notificador,
+		baseURL:       strings.TrimRight(baseURL, "/"),
+	}
+}
+
+func (s *calendarioService) CalendarioCurso(cursoID uint) (string, error) {
+	curso, err := s.cursoRepo.FindByID(cursoID)
+	if err != nil {
+		return "", err
+	}
+	if curso.StatusAtual() == models.StatusCursoRascunho {
+		return "", erros.ErrCursoNaoEncontrado
+	}
+
+	calendario := novoCalendario(curso.Nome)
+	calendario.adicionarCurso(curso, false)
+	return calendario.String(), nil
+}
+
+// CalendarioPublico lista os cursos publicados a partir dos últimos meses; rascunhos ficam de fora
+func (s *calendarioService) CalendarioPublico() (string, error) {
+	inicio := time.Now().AddDate(0, 0, -diasHistoricoCalendario)
+	cursos, err := s.cursoRepo.Buscar(repository.FiltroCurso{DataInicio: &inicio})
+	if err != nil {
+		return "", err
+	}
+
+	calendario := novoCalendario("Cursos TVTEC")
+	for i := range cursos {
+		calendario.adicionarCurso(&cursos[i], false)
+	}
+	return calendario.String(), nil
+}
+
+// CalendarioAluno gera o feed privado do aluno; o token, a chave de calendário do aluno, impede que
+// outra pessoa veja as inscrições
+func (s *calendarioService) CalendarioAluno(alunoID uint, token string) (string, error) {
+	aluno, err := s.alunoRepo.FindByID(alunoID)
+	if err != nil || aluno.Anonimizado || aluno.ChaveCalendario == "" ||
+		subtle.ConstantTimeCompare([]byte(aluno.ChaveCalendario), []byte(token)) != 1 {
+		return "", erros.ErrSemPermissao.ComMensagem("Link de calendário inválido")
+	}
+
+	inscricoes, err := s.inscricaoRepo.FindByAlunoWithDetails(alunoID)
+	if err != nil {
+		return "", err
+	}
+
+	calendario := novoCalendario("Meus cursos TVTEC")
+	for i := range inscricoes {
+		inscricao := &inscricoes[i]
+		if inscricao.Curso.StatusAtual() == models.StatusCursoRascunho {
+			continue
+		}
+		// Inscrições canceladas pela organização continuam no feed como eventos cancelados
+		cancelada := inscricao.Status == models.StatusInscricaoCanceladaOrganizacao
+		calendario.adicionarCurso(&inscricao.Curso, cancelada)
+	}
+	return calendario.String(), nil
+}
+
+// LinkCalendarioAluno devolve o link atual do aluno, criando a chave no primeiro pedido
+func (s *calendarioService) LinkCalendarioAluno(alunoID uint) (string, error) {
+	aluno, err := s.alunoRepo.FindByID(alunoID)
+	if err != nil {
+		return "", err
+	}
+	if aluno.ChaveCalendario == "" {
+		if err := s.trocarChave(aluno); err != nil {
+			return "", err
+		}
+	}
+	return s.linkCalendario(aluno), nil
+}
+
+// EnviarLinkCalendario gera um novo link, o que desativa o anterior, e o envia ao email cadastrado;
+// o link não é devolvido a quem fez o pedido
+func (s *calendarioService) EnviarLinkCalendario(alunoID uint) error {
+	aluno, err := s.alunoRepo.FindByID(alunoID)
+	if err != nil {
+		return err
+	}
+	if err := s.trocarChave(aluno); err != nil {
+		return err
+	}
+
+	// O link sai no idioma escolhido pelo aluno na inscrição
+	idioma := i18n.Normalizar(aluno.Idioma)
+	mensagem := i18n.Traduzir(idioma, "Olá, %s.\n\nEste é o link pessoal do seu calendário de cursos, para assinar no aplicativo de agenda:\n%s\n\nNão compartilhe este link. Um novo pedido gera outro link e desativa este.",
+		aluno.Nome, s.linkCalendario(aluno))
+	assunto := i18n.Traduzir(idioma, "Link do seu calendário de cursos")
+	if err := s.notificador.Enviar(aluno.Email, assunto, mensagem); err != nil {
+		return erros.ErrIndisponivel.ComMensagem("Não foi possível enviar o link do calendário").Envolver(err)
+	}
+	return nil
+}
+
+// RevogarLinkCalendario apaga a chave do aluno; o próximo pedido de link gera uma nova
+func (s *calendarioService) RevogarLinkCalendario(alunoID uint) error {
+	return s.alunoRepo.AtualizarChaveCalendario(alunoID, "")
+}
+
+func (s *calendarioService) trocarChave(aluno *models.Aluno) error {
+	chave := make([]byte, 32)
+	if _, err := rand.Read(chave); err != nil {
+		return fmt.Errorf("erro ao gerar chave do calendário: %w", err)
+	}
+	if err := s.alunoRepo.AtualizarChaveCalendario(aluno.ID, hex.EncodeToString(chave)); err != nil {
+		return err
+	}
+	aluno.ChaveCalendario = hex.EncodeToString(chave)
+	return nil
+}
+
+func (s *calendarioService) linkCalendario(aluno *models.Aluno) string {
+	params := url.Values{}
+	params.Set("token", aluno.ChaveCalendario)
+	return fmt.Sprintf("%s/aluno/%d/calendar.ics?%s", s.baseURL, aluno.ID, params.Encode())
+}
+
+// calendarioICS monta um arquivo iCalendar (RFC 5545) com horários no fuso de Brasília
+type calendarioICS struct {
+	linhas []string
+	agora  time.Time
+}
+
+func novoCalendario(nome string) *calendarioICS {
+	c := &calendarioICS{agora: time.Now().UTC()}
+	c.linhas = []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//TVTEC//Cursos//PT-BR",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:" + escaparTextoICS(nome),
+		"X-WR-TIMEZONE:America/Sao_Paulo",
+		// Sem horário de verão desde 2019
+		"BEGIN:VTIMEZONE",
+		"TZID:America/Sao_Paulo",
+		"BEGIN:STANDARD",
+		"DTSTART:19700101T000000",
+		"TZOFFSETFROM:-0300",
+		"TZOFFSETTO:-0300",
+		"TZNAME:-03",
+		"END:STANDARD",
+		"END:VTIMEZONE",
+	}
+	return c
+}
+
+// adicionarCurso cria um evento com o horário do curso ou, sem horário, um evento de dia inteiro
+func (c *calendarioICS) adicionarCurso(curso *models.Curso, cancelado bool) {
+	// A data do curso é gravada sem fuso; o dia vale como dia local de Brasília
+	ano, mes, dia := curso.Data.Time.UTC().Date()
+
+	evento := []string{
+		"BEGIN:VEVENT",
+		fmt.Sprintf("UID:curso-%d@tvtec", curso.ID),
+		"DTSTAMP:" + c.agora.Format("20060102T150405Z"),
+	}
+
+	inicio, fim, comHorario := intervaloCurso(curso)
+	if comHorario {
+		dataInicio := time.Date(ano, mes, dia, inicio.Hour(), inicio.Minute(), 0, 0, models.FusoHorario)
+		dataFim := time.Date(ano, mes, dia, fim.Hour(), fim.Minute(), 0, 0, models.FusoHorario)
+		evento = append(evento,
+			"DTSTART;TZID=America/Sao_Paulo:"+dataInicio.Format("20060102T150405"),
+			"DTEND;TZID=America/Sao_Paulo:"+dataFim.Format("20060102T150405"),
+		)
+	} else {
+		data := time.Date(ano, mes, dia, 0, 0, 0, 0, time.UTC)
+		evento = append(evento,
+			"DTSTART;VALUE=DATE:"+data.Format("20060102"),
+			"DTEND;VALUE=DATE:"+data.AddDate(0, 0, 1).Format("20060102"),
+		)
+	}
+
+	descricao := fmt.Sprintf("Professor: %s\nCarga horária: %dh", curso.Professor, curso.CargaHoraria)
+	evento = append(evento,
+		"SUMMARY:"+escaparTextoICS(curso.Nome),
+		"DESCRIPTION:"+escaparTextoICS(descricao),
+	)
+	if curso.Sala != nil && curso.Sala.Local != nil {
+		local := fmt.Sprintf("%s - %s, %s", curso.Sala.Local.Nome, curso.Sala.Nome, curso.Sala.Local.Endereco)
+		evento = append(evento, "LOCATION:"+escaparTextoICS(local))
+	}
+
+	if cancelado || curso.StatusAtual() == models.StatusCursoCancelado {
+		evento = append(evento, "STATUS:CANCELLED")
+	} else {
+		evento = append(evento, "STATUS:CONFIRMED")
+	}
+	evento = append(evento, "END:VEVENT")
+
+	c.linhas = append(c.linhas, evento...)
+}
+
+func (c *calendarioICS) String() string {
+	var b strings.Builder
+	for _, linha := range append(c.linhas, "END:VCALENDAR") {
+		b.WriteString(dobrarLinhaICS(linha))
+		b.WriteString("\r\n")
+	}
+	return b.String()
+}
+
+func escaparTextoICS(texto string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(texto)
+}
+
+// dobrarLinhaICS quebra linhas com mais de 75 bytes sem dividir caracteres UTF-8
+func dobrarLinhaICS(linha string) string {
+	const limite = 75
+	if len(linha) <= limite {
+		return linha
+	}
+
+	var b strings.Builder
+	tamanho := 0
+	for _, r := range linha {
+		bytesRune := len(string(r))
+		if tamanho+bytesRune > limite {
+			b.WriteString("\r\n ")
+			// O espaço de continuação conta para o limite da nova linha
+			tamanho = 1
+		}
+		b.WriteRune(r)
+		tamanho += bytesRune
+	}
+	return b.String()
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"strings"
+
+	"tvtec/erros"
+	"tvtec/models"
+	"tvtec/repository"
+)
+
+type CategoriaService interface {
+	ListarCategorias() ([]models.Categoria, error)
+	CriarCategoria(categoria *models.Categoria) error
+	AtualizarCategoria(categoria *models.Categoria) error
+	RemoverCategoria(id uint) error
+}
+
+type categoriaService struct {
+	categoriaRepo repository.CategoriaRepository
+}
+
+func NewCategoriaService(categoriaRepo repository.CategoriaRepository) CategoriaService {
+	return &categoriaService{categoriaRepo: categoriaRepo}
+}
+
+func (s *categoriaService) ListarCategorias() ([]models.Categoria, error) {
+	return s.categoriaRepo.FindAll()
+}
+
+func (s *categoriaService) CriarCategoria(categoria *models.Categoria) error {
+	categoria.ID = 0
+	return s.salvar(categoria)
+}
+
+func (s *categoriaService) AtualizarCategoria(categoria *models.Categoria) error {
+	if _, err := s.categoriaRepo.FindByID(categoria.ID); err != nil {
+		return err
+	}
+	return s.salvar(categoria)
+}
+
+func (s *categoriaService) RemoverCategoria(id uint) error {
+	return s.categoriaRepo.Delete(id)
+}
+
+func (s *categoriaService) salvar(categoria *models.Categoria) error {
+	categoria.Nome = strings.TrimSpace(categoria.Nome)
+	if categoria.Nome == "" {
+		return erros.ErrDadosInvalidos.ComMensagem("Nome da categoria é obrigatório")
+	}
+	if categoria.LimiteInscricoesAtivas < 0 {
+		return erros.ErrDadosInvalidos.ComMensagem("O limite de inscrições ativas não pode ser negativo")
+	}
+
+	// Nomes de categoria são únicos sem diferenciar maiúsculas
+	if existente, _ := s.categoriaRepo.FindByNome(categoria.Nome); existente != nil && existente.ID != categoria.ID {
+		return erros.ErrCadastroDuplicado.ComMensagem("Já existe uma categoria com este nome")
+	}
+
+	return s.categoriaRepo.Save(categoria)
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"tvtec/erros"
+	"tvtec/models"
+	"tvtec/repository"
+)
+
+// Versão usada quando nenhum termo foi publicado ainda
+const versaoTermoInicial = "1"
+
+// EstadoConsentimento resume a situação atual de um canal/finalidade
+type EstadoConsentimento struct {
+	Canal        string    `json:"canal"`
+	Finalidade   string    `json:"finalidade"`
+	Concedido    bool      `json:"concedido"`
+	VersaoTermos string    `json:"versaoTermos"`
+	DataRegistro time.Time `json:"dataRegistro"`
+}
+
+// Interface para o serviço de consentimentos
+type ConsentimentoService interface {
+	GarantirTermoInicial() error
+	TermoVigente() (*models.TermoConsentimento, error)
+	PublicarTermo(termo *models.TermoConsentimento) error
+	RegistrarConsentimento(consentimento *models.Consentimento) error
+	AutorizacaoInscricao(autorizaWhatsApp, versaoTermos, ip string) (*models.Consentimento, error)
+	Revogar(alunoID uint, canal, finalidade, origem, ip string) error
+	RevogarCanal(alunoID uint, canal, origem, ip string) error
+	RevogarTodos(alunoID uint, origem, ip string) error
+	PodeEnviar(alunoID uint, canal, finalidade string) (bool, error)
+	HistoricoAluno(alunoID uint) ([]models.Consentimento, error)
+	EstadoAtual(alunoID uint) ([]EstadoConsentimento, error)
+	GerarTokenDescadastro(alunoID uint, canal string) string
+	ValidarTokenDescadastro(alunoID uint, canal, token string) bool
+	LinkDescadastro(alunoID uint, canal string) string
+}
+
+type consentimentoServiceImpl struct {
+	consentimentoRepo repository.ConsentimentoRepository
+	segredo           []byte
+	baseURL           string
+}
+
+// Função construtora para o serviço de consentimentos.
+// O segredo assina os links de descadastro e baseURL é o endereço público da API.
+func NewConsentimentoService(consentimentoRepo repository.ConsentimentoRepository, segredo, baseURL string) ConsentimentoService {
+	return &consentimentoServiceImpl{
+		consentimentoRepo: consentimentoRepo,
+		segredo:           []byte(segredo),
+		baseURL:           strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+var canaisConsentimento = []string{models.CanalWhatsApp, models.CanalEmail, models.CanalSMS}
+var finalidadesConsentimento = []string{models.FinalidadeAvisosCursos, models.FinalidadeDivulgacao}
+
+// GarantirTermoInicial publica uma primeira versão dos termos caso o banco ainda não tenha nenhuma
+func (s *consentimentoServiceImpl) GarantirTermoInicial() error {
+	if _, err := s.consentimentoRepo.FindTermoVigente(); err == nil {
+		return nil
+	}
+
+	return s.consentimentoRepo.SaveTermo(&models.TermoConsentimento{
+		Versao:      versaoTermoInicial,
+		Texto:       "Autorizo o programa a me enviar comunicações sobre os cursos em que estou inscrito pelos canais informados.",
+		PublicadoEm: time.Now(),
+	})
+}
+
+func (s *consentimentoServiceImpl) TermoVigente() (*models.TermoConsentimento, error) {
+	return s.consentimentoRepo.FindTermoVigente()
+}
+
+func (s *consentimentoServiceImpl) PublicarTermo(termo *models.TermoConsentimento) error {
+	if termo.Versao == "" || termo.Texto == "" {
+		return erros.ErrDadosInvalidos.ComMensagem("Versão e texto dos termos são obrigatórios")
+	}
+
+	if existente, _ := s.consentimentoRepo.FindTermoByVersao(termo.Versao); existente != nil {
+		return erros.ErrCadastroDuplicado.ComMensagem("Já existe um termo publicado com esta versão")
+	}
+
+	termo.PublicadoEm = time.Now()
+	return s.consentimentoRepo.SaveTermo(termo)
+}
+
+// RegistrarConsentimento acrescenta uma entrada ao histórico do aluno
+func (s *consentimentoServiceImpl) RegistrarConsentimento(consentimento *models.Consentimento) error {
+	if consentimento.AlunoID == 0 {
+		return erros.ErrDadosInvalidos.ComMensagem("Aluno é obrigatório para registrar consentimento")
+	}
+	if !contem(canaisConsentimento, consentimento.Canal) {
+		return erros.ErrDadosInvalidos.ComMensagem("Canal de comunicação inválido: %s", consentimento.Canal)
+	}
+	if !contem(finalidadesConsentimento, consentimento.Finalidade) {
+		return erros.ErrDadosInvalidos.ComMensagem("Finalidade inválida: %s", consentimento.Finalidade)
+	}
+
+	if err := s.definirVersaoTermos(consentimento); err != nil {
+		return err
+	}
+
+	consentimento.ID = 0
+	consentimento.DataRegistro = time.Now()
+	return s.consentimentoRepo.Save(consentimento)
+}
+
+// definirVersaoTermos confere a versão informada ou, sem ela, usa a versão vigente
+func (s *consentimentoServiceImpl) definirVersaoTermos(consentimento *models.Consentimento) error {
+	if consentimento.VersaoTermos == "" {
+		termo, err := s.consentimentoRepo.FindTermoVigente()
+		if err != nil {
+			return err
+		}
+		consentimento.VersaoTermos = termo.Versao
+		return nil
+	}
+	_, err := s.consentimentoRepo.FindTermoByVersao(consentimento.VersaoTermos)
+	return err
+}
+
+// AutorizacaoInscricao converte a resposta "autorizaWhatsApp" do formulário de inscrição em um registro
+// do histórico, já validado, para ser gravado na mesma transação da inscrição
+func (s *consentimentoServiceImpl) AutorizacaoInscricao(autorizaWhatsApp, versaoTermos, ip string) (*models.Consentimento, error) {
+	consentimento := &models.Consentimento{
+		Canal:        models.CanalWhatsApp,
+		Finalidade:   models.FinalidadeAvisosCursos,
+		Concedido:    respostaAfirmativa(autorizaWhatsApp),
+		VersaoTermos: versaoTermos,
+		Origem:       models.OrigemConsentimentoInscricao,
+		IP:           ip,
+	}
+	if err := s.definirVersaoTermos(consentimento); err != nil {
+		if errors.Is(err, erros.ErrTermoNaoEncontrado) && versaoTermos != "" {
+			return nil, erros.ErrDadosInvalidos.ComMensagem("Versão dos termos desconhecida: %s", versaoTermos)
+		}
+		return nil, err
+	}
+	return consentimento, nil
+}
+
+func (s *consentimentoServiceImpl) Revogar(alunoID uint, canal, finalidade, origem, ip string) error {
+	return s.RegistrarConsentimento(&models.Consentimento{
+		AlunoID:    alunoID,
+		Canal:      canal,
+		Finalidade: finalidade,
+		Concedido:  false,
+		Origem:     origem,
+		IP:         ip,
+	})
+}
+
+// RevogarCanal revoga todas as finalidades de um canal, como no link de descadastro
+func (s *consentimentoServiceImpl) RevogarCanal(alunoID uint, canal, origem, ip string) error {
+	for _, finalidade := range finalidadesConsentimento {
+		if err := s.Revogar(alunoID, canal, finalidade, origem, ip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *consentimentoServiceImpl) RevogarTodos(alunoID uint, origem, ip string) error {
+	for _, canal := range canaisConsentimento {
+		if err := s.RevogarCanal(alunoID, canal, origem, ip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// revogarTodosNaTransacao revoga todos os consentimentos gravando pelos repositórios da transação em andamento
+func revogarTodosNaTransacao(repos repository.Repositorios, alunoID uint, origem, ip string) error {
+	servico := &consentimentoServiceImpl{consentimentoRepo: repos.Consentimentos}
+	return servico.RevogarTodos(alunoID, origem, ip)
+}
+
+// PodeEnviar deve ser consultado por toda funcionalidade de mensagens antes de contatar o aluno.
+// Sem registro de consentimento, o envio não é permitido.
+func (s *consentimentoServiceImpl) PodeEnviar(alunoID uint, canal, finalidade string) (bool, error) {
+	ultimo, err := s.consentimentoRepo.FindUltimo(alunoID, canal, finalidade)
+	if err != nil {
+		if errors.Is(err, repository.ErrConsentimentoNaoEncontrado) {
+			return false, nil
+		}
+		return false, err
+	}
+	return ultimo.Concedido, nil
+}
+
+func (s *consentimentoServiceImpl) HistoricoAluno(alunoID uint) ([]models.Consentimento, error) {
+	return s.consentimentoRepo.FindByAluno(alunoID)
+}
+
+func (s *consentimentoServiceImpl) EstadoAtual(alunoID uint) ([]EstadoConsentimento, error) {
+	historico, err := s.consentimentoRepo.FindByAluno(alunoID)
+	if err != nil {
+		return nil, err
+	}
+
+	// O histórico vem em ordem cronológica, então a última entrada de cada par prevalece
+	estados := make(map[string]EstadoConsentimento)
+	var ordem []string
+	for _, c := range historico {
+		chave := c.Canal + "/" + c.Finalidade
+		if _, ok := estados[chave]; !ok {
+			ordem = append(ordem, chave)
+		}
+		estados[chave] = EstadoConsentimento{
+			Canal:        c.Canal,
+			Finalidade:   c.Finalidade,
+			Concedido:    c.Concedido,
+			VersaoTermos: c.VersaoTermos,
+			DataRegistro: c.DataRegistro,
+		}
+	}
+
+	resultado := make([]EstadoConsentimento, 0, len(ordem))
+	for _, chave := range ordem {
+		resultado = append(resultado, estados[chave])
+	}
+	return resultado, nil
+}
+
+func (s *consentimentoServiceImpl) GerarTokenDescadastro(alunoID uint, canal string) string {
+	mac := hmac.New(sha256.New, s.segredo)
+	fmt.Fprintf(mac, "descadastro:%d:%s", alunoID, canal)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *consentimentoServiceImpl) ValidarTokenDescadastro(alunoID uint, canal, token string) bool {
+	esperado := s.GerarTokenDescadastro(alunoID, canal)
+	return hmac.Equal([]byte(esperado), []byte(token))
+}
+
+// LinkDescadastro monta a URL que acompanha as mensagens enviadas ao aluno
+func (s *consentimentoServiceImpl) LinkDescadastro(alunoID uint, canal string) string {
+	params := url.Values{}
+	params.Set("aluno", fmt.Sprint(alunoID))
+	params.Set("canal", canal)
+	params.Set("token", s.GerarTokenDescadastro(alunoID, canal))
+	return s.baseURL + "/consentimento/descadastro?" + params.Encode()
+}
+
+// respostaAfirmativa interpreta as respostas de formulário usadas pelo frontend ("sim", "S", "true")
+func respostaAfirmativa(valor string) bool {
+	switch strings.ToLower(strings.TrimSpace(valor)) {
+	case "sim", "s", "true", "1", "yes":
+		return true
+	}
+	return false
+}
+
+func contem(lista []string, valor string) bool {
+	for _, item := range lista {
+		if item == valor {
+			return true
+		}
+	}
+	return false
+}
@@ -1,49 +1,353 @@
 package service
 
 import (
+	"errors"
+	"log"
+	"time"
+
+	"tvtec/erros"
+	"tvtec/i18n"
 	"tvtec/models"
 	"tvtec/repository"
 )
 
 type CursoService interface {
 	ListarCursos() ([]models.Curso, error)
+	BuscarCursos(filtro repository.FiltroCurso) ([]models.Curso, error)
 	ObterCursoPorID(id uint) (*models.Curso, error)
-	CriarCurso(curso *models.Curso) error
-	AtualizarCurso(curso *models.Curso) error
+	ObterCursoPublico(id uint) (*models.Curso, error)
+	CriarCurso(curso *models.Curso, tags []string, preRequisitos []uint) error
+	AtualizarCurso(curso *models.Curso, tags *[]string, preRequisitos *[]uint) error
 	RemoverCurso(id uint) error
 	VerificarDisponibilidadeVagas(id uint) (int32, error)
 	ListarInscricoesCurso(cursoID uint) ([]models.Inscricao, error)
+	AvisosAcessibilidade(cursoID uint) ([]string, error)
+	MudarStatus(id uint, status string) (*models.Curso, error)
+	CancelarCurso(id uint, motivo string) (*ResumoCancelamento, error)
+	AvisarCancelamento(curso *models.Curso, aluno *models.Aluno) error
+}
+
+// ResumoCancelamento informa o que foi feito ao cancelar um curso; os avisos aos alunos
+// seguem pela outbox e não entram no resumo
+type ResumoCancelamento struct {
+	Curso                *models.Curso `json:"curso"`
+	InscricoesCanceladas int64         `json:"inscricoesCanceladas"`
 }
 
 type cursoService struct {
 	cursoRepo     repository.CursoRepository
 	inscricaoRepo repository.InscricaoRepository
+	categoriaRepo repository.CategoriaRepository
+	localRepo     repository.LocalRepository
+	professorRepo repository.ProfessorRepository
+	notificador   Notificador
+	transacao     repository.Transacao
+	eventos       PublicadorEventos
 }
 
 func NewCursoService(
 	cursoRepo repository.CursoRepository,
 	inscricaoRepo repository.InscricaoRepository,
+	categoriaRepo repository.CategoriaRepository,
+	localRepo repository.LocalRepository,
+	professorRepo repository.ProfessorRepository,
+	notificador Notificador,
+	transacao repository.Transacao,
+	eventos PublicadorEventos,
 ) CursoService {
 	return &cursoService{
 		cursoRepo:     cursoRepo,
 		inscricaoRepo: inscricaoRepo,
+		categoriaRepo: categoriaRepo,
+		localRepo:     localRepo,
+		professorRepo: professorRepo,
+		notificador:   notificador,
+		transacao:     transacao,
+		eventos:       eventos,
 	}
 }
 
 func (s *cursoService) ListarCursos() ([]models.Curso, error) {
-	return s.cursoRepo.FindAll()
+	cursos, err := s.cursoRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	preencherSituacaoInscricoes(cursos)
+	return cursos, nil
 }
 
 func (s *cursoService) ObterCursoPorID(id uint) (*models.Curso, error) {
-	return s.cursoRepo.FindByID(id)
+	curso, err := s.cursoRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	curso.PreencherSituacaoInscricoes(time.Now())
+	return curso, nil
+}
+
+// ObterCursoPublico esconde cursos em rascunho das rotas públicas
+func (s *cursoService) ObterCursoPublico(id uint) (*models.Curso, error) {
+	curso, err := s.ObterCursoPorID(id)
+	if err != nil {
+		return nil, err
+	}
+	if curso.StatusAtual() == models.StatusCursoRascunho {
+		return nil, erros.ErrCursoNaoEncontrado
+	}
+	return curso, nil
+}
+
+func (s *cursoService) BuscarCursos(filtro repository.FiltroCurso) ([]models.Curso, error) {
+	if filtro.DataInicio != nil && filtro.DataFim != nil && filtro.DataFim.Before(*filtro.DataInicio) {
+		return nil, erros.ErrDadosInvalidos.ComMensagem("A data final deve ser posterior à data inicial")
+	}
+	cursos, err := s.cursoRepo.Buscar(filtro)
+	if err != nil {
+		return nil, err
+	}
+	preencherSituacaoInscricoes(cursos)
+	return cursos, nil
+}
+
+func preencherSituacaoInscricoes(cursos []models.Curso) {
+	agora := time.Now()
+	for i := range cursos {
+		cursos[i].PreencherSituacaoInscricoes(agora)
+	}
+}
+
+// CriarCurso valida o curso e grava-o junto com as tags e os pré-requisitos numa só transação
+func (s *cursoService) CriarCurso(curso *models.Curso, tags []string, preRequisitos []uint) error {
+	// Um curso novo nasce publicado, a não ser que seja criado como rascunho
+	if curso.Status == "" {
+		curso.Status = models.StatusCursoPublicado
+	}
+	if curso.Status != models.StatusCursoRascunho && curso.Status != models.StatusCursoPublicado {
+		return erros.ErrDadosInvalidos.ComMensagem("Um curso novo deve ser criado como rascunho ou publicado")
+	}
+	if err := s.validarProfessor(curso); err != nil {
+		return err
+	}
+	if err := validarHorario(curso); err != nil {
+		return err
+	}
+	if err := validarPeriodoInscricoes(curso); err != nil {
+		return err
+	}
+	if err := s.validarCategoria(curso); err != nil {
+		return err
+	}
+	if err := s.validarSala(curso); err != nil {
+		return err
+	}
+	preRequisitos, err := s.validarPreRequisitos(curso, preRequisitos)
+	if err != nil {
+		return err
+	}
+	return emTransacao(s.transacao, s.eventos, func(repos repository.Repositorios) error {
+		if err := repos.Cursos.Save(curso); err != nil {
+			return err
+		}
+		if err := repos.Cursos.AtualizarTags(curso, tags); err != nil {
+			return err
+		}
+		if len(preRequisitos) > 0 {
+			if err := repos.Cursos.AtualizarPreRequisitos(curso, preRequisitos); err != nil {
+				return err
+			}
+		}
+		return s.eventos.Registrar(repos.Outbox, models.EventoCursoCriado, dadosEventoCurso(curso))
+	})
+}
+
+// AtualizarCurso grava o curso e, quando informadas, substitui as tags e os pré-requisitos na
+// mesma transação; nil mantém os atuais
+func (s *cursoService) AtualizarCurso(curso *models.Curso, tags *[]string, preRequisitos *[]uint) error {
+	if err := s.validarProfessor(curso); err != nil {
+		return err
+	}
+	if err := validarHorario(curso); err != nil {
+		return err
+	}
+	if err := validarPeriodoInscricoes(curso); err != nil {
+		return err
+	}
+	if err := s.validarCategoria(curso); err != nil {
+		return err
+	}
+	if err := s.validarSala(curso); err != nil {
+		return err
+	}
+	if preRequisitos != nil {
+		ids, err := s.validarPreRequisitos(curso, *preRequisitos)
+		if err != nil {
+			return err
+		}
+		preRequisitos = &ids
+	}
+	err := emTransacao(s.transacao, s.eventos, func(repos repository.Repositorios) error {
+		if err := repos.Cursos.Update(curso); err != nil {
+			return err
+		}
+		if tags != nil {
+			if err := repos.Cursos.AtualizarTags(curso, *tags); err != nil {
+				return err
+			}
+		}
+		if preRequisitos != nil {
+			if err := repos.Cursos.AtualizarPreRequisitos(curso, *preRequisitos); err != nil {
+				return err
+			}
+		}
+		return s.eventos.Registrar(repos.Outbox, models.EventoCursoAlterado, dadosEventoCurso(curso))
+	})
+	if err != nil {
+		return err
+	}
+
+	// Uma troca de sala pode deixar alunos que precisam de elevador sem acesso
+	if avisos, err := s.AvisosAcessibilidade(curso.ID); err == nil {
+		for _, aviso := range avisos {
+			log.Printf("AVISO de acessibilidade no curso %d: %s", curso.ID, aviso)
+		}
+	}
+	return nil
+}
+
+// validarPreRequisitos descarta IDs repetidos e recusa cursos inexistentes e referências
+// circulares, antes de qualquer gravação
+func (s *cursoService) validarPreRequisitos(curso *models.Curso, ids []uint) ([]uint, error) {
+	unicos := make([]uint, 0, len(ids))
+	vistos := make(map[uint]bool)
+	for _, id := range ids {
+		if vistos[id] {
+			continue
+		}
+		vistos[id] = true
+
+		if curso.ID != 0 && id == curso.ID {
+			return nil, erros.ErrDadosInvalidos.ComMensagem("Um curso não pode ser pré-requisito de si mesmo")
+		}
+		if _, err := s.cursoRepo.FindByID(id); err != nil {
+			if errors.Is(err, erros.ErrCursoNaoEncontrado) {
+				return nil, erros.ErrCursoNaoEncontrado.ComMensagem("Pré-requisito não encontrado")
+			}
+			return nil, err
+		}
+		// Um curso novo ainda não pode ser exigido por nenhum outro
+		if curso.ID != 0 && s.exigeCurso(id, curso.ID, map[uint]bool{}) {
+			return nil, erros.ErrDadosInvalidos.ComMensagem("O curso %d já depende deste curso e não pode ser seu pré-requisito", id)
+		}
+		unicos = append(unicos, id)
+	}
+	return unicos, nil
+}
+
+// exigeCurso verifica se cursoID depende, direta ou indiretamente, de alvoID
+func (s *cursoService) exigeCurso(cursoID, alvoID uint, visitados map[uint]bool) bool {
+	if visitados[cursoID] {
+		return false
+	}
+	visitados[cursoID] = true
+
+	curso, err := s.cursoRepo.FindByID(cursoID)
+	if err != nil {
+		return false
+	}
+	for _, preRequisito := range curso.PreRequisitos {
+		if preRequisito.ID == alvoID || s.exigeCurso(preRequisito.ID, alvoID, visitados) {
+			return true
+		}
+	}
+	return false
+}
+
+// AvisosAcessibilidade compara as necessidades dos inscritos com as condições da sala do curso
+func (s *cursoService) AvisosAcessibilidade(cursoID uint) ([]string, error) {
+	curso, err := s.cursoRepo.FindByID(cursoID)
+	if err != nil {
+		return nil, err
+	}
+
+	inscricoes, err := s.inscricaoRepo.FindByCurso(cursoID)
+	if err != nil {
+		return nil, err
+	}
+
+	return avisosSala(curso, inscricoes), nil
+}
+
+// validarHorario exige início e fim juntos, no formato HH:MM, com o fim depois do início
+func validarHorario(curso *models.Curso) error {
+	if curso.HoraInicio == "" && curso.HoraFim == "" {
+		return nil
+	}
+	inicio, fim, ok := intervaloCurso(curso)
+	if !ok {
+		return erros.ErrDadosInvalidos.ComMensagem("Informe horaInicio e horaFim no formato HH:MM")
+	}
+	if !fim.After(inicio) {
+		return erros.ErrDadosInvalidos.ComMensagem("A hora de término deve ser posterior à hora de início")
+	}
+	return nil
+}
+
+// validarPeriodoInscricoes garante que as inscrições encerram depois de abrir e até o início do curso
+func validarPeriodoInscricoes(curso *models.Curso) error {
+	if curso.InscricoesEncerramento != nil && curso.InscricoesEncerramento.After(curso.Inicio()) {
+		return erros.ErrDadosInvalidos.ComMensagem("O encerramento das inscrições não pode ser posterior ao início do curso")
+	}
+	if curso.InscricoesAbertura != nil && !curso.FimInscricoes().After(*curso.InscricoesAbertura) {
+		return erros.ErrDadosInvalidos.ComMensagem("O encerramento das inscrições deve ser posterior à abertura")
+	}
+	return nil
 }
 
-func (s *cursoService) CriarCurso(curso *models.Curso) error {
-	return s.cursoRepo.Save(curso)
+// validarSala garante que a sala existe e comporta o número de vagas oferecidas
+func (s *cursoService) validarSala(curso *models.Curso) error {
+	if curso.SalaID == nil {
+		curso.Sala = nil
+		return nil
+	}
+
+	sala, err := s.localRepo.FindSalaByID(*curso.SalaID)
+	if err != nil {
+		return err
+	}
+	if curso.VagasTotais > sala.Capacidade {
+		return erros.ErrDadosInvalidos.ComMensagem("O número de vagas (%d) excede a capacidade da sala %q (%d)", curso.VagasTotais, sala.Nome, sala.Capacidade)
+	}
+	curso.Sala = sala
+	return nil
 }
 
-func (s *cursoService) AtualizarCurso(curso *models.Curso) error {
-	return s.cursoRepo.Update(curso)
+// validarProfessor usa o nome do cadastro quando o curso está vinculado a um professor
+func (s *cursoService) validarProfessor(curso *models.Curso) error {
+	if curso.ProfessorID != nil {
+		professor, err := s.professorRepo.FindByID(*curso.ProfessorID)
+		if err != nil {
+			return err
+		}
+		curso.Professor = professor.Nome
+	}
+	if curso.Professor == "" {
+		return erros.ErrDadosInvalidos.ComMensagem("Informe o professor do curso")
+	}
+	return nil
+}
+
+func (s *cursoService) validarCategoria(curso *models.Curso) error {
+	if curso.CategoriaID == nil {
+		curso.Categoria = nil
+		return nil
+	}
+
+	categoria, err := s.categoriaRepo.FindByID(*curso.CategoriaID)
+	if err != nil {
+		return err
+	}
+	curso.Categoria = categoria
+	return nil
 }
 
 func (s *cursoService) RemoverCurso(id uint) error {
@@ -53,25 +357,121 @@ func (s *cursoService) RemoverCurso(id uint) error {
 		return err
 	}
 
-	// Verificamos se existem inscrições para este curso usando o método FindByCurso
-	inscricoes, err := s.inscricaoRepo.FindByCurso(id)
+	// Cursos com inscrições devem ser cancelados, preservando o histórico dos alunos
+	total, err := s.inscricaoRepo.CountByCurso(id)
 	if err != nil {
 		return err
 	}
+	if total > 0 {
+		return erros.ErrRegistroEmUso.ComMensagem("O curso possui inscrições; cancele o curso em vez de removê-lo")
+	}
 
-	// Se existem inscrições, precisamos removê-las primeiro
-	for _, inscricao := range inscricoes {
-		if err := s.inscricaoRepo.Delete(inscricao.ID); err != nil {
+	return s.cursoRepo.Delete(curso.ID)
+}
+
+// MudarStatus aplica uma transição do ciclo de vida; o cancelamento segue o fluxo de CancelarCurso
+func (s *cursoService) MudarStatus(id uint, status string) (*models.Curso, error) {
+	if !models.StatusCursoValido(status) {
+		return nil, erros.ErrDadosInvalidos.ComMensagem("Situação inválida %q", status)
+	}
+	if status == models.StatusCursoCancelado {
+		resumo, err := s.CancelarCurso(id, "")
+		if err != nil {
+			return nil, err
+		}
+		return resumo.Curso, nil
+	}
+
+	curso, err := s.cursoRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !curso.PodeMudarPara(status) {
+		return nil, erros.ErrSituacaoInvalida.ComMensagem("Não é possível passar o curso de %s para %s", curso.StatusAtual(), status)
+	}
+
+	curso.Status = status
+	err = emTransacao(s.transacao, s.eventos, func(repos repository.Repositorios) error {
+		if err := repos.Cursos.Update(curso); err != nil {
 			return err
 		}
+		return s.eventos.Registrar(repos.Outbox, models.EventoCursoAlterado, dadosEventoCurso(curso))
+	})
+	if err != nil {
+		return nil, err
 	}
+	curso.PreencherSituacaoInscricoes(time.Now())
+	return curso, nil
+}
 
-	// Finalmente remover o curso
-	return s.cursoRepo.Delete(curso.ID)
+// CancelarCurso mantém o curso e as inscrições, marcando-as como canceladas pela organização.
+// Cada inscrição cancelada vira um evento na outbox, e AssinanteCancelamentoCurso avisa o aluno por email.
+func (s *cursoService) CancelarCurso(id uint, motivo string) (*ResumoCancelamento, error) {
+	curso, err := s.cursoRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !curso.PodeMudarPara(models.StatusCursoCancelado) {
+		return nil, erros.ErrSituacaoInvalida.ComMensagem("Não é possível cancelar um curso com situação %s", curso.StatusAtual())
+	}
+
+	agora := time.Now()
+	curso.Status = models.StatusCursoCancelado
+	curso.CanceladoEm = &agora
+	curso.MotivoCancelamento = motivo
+	// O curso, as inscrições e os eventos mudam juntos
+	var canceladas int64
+	err = emTransacao(s.transacao, s.eventos, func(repos repository.Repositorios) error {
+		if err := repos.Cursos.Update(curso); err != nil {
+			return err
+		}
+		inscricoes, err := repos.Inscricoes.CancelarPorCurso(id)
+		if err != nil {
+			return err
+		}
+		canceladas = int64(len(inscricoes))
+		if err := s.eventos.Registrar(repos.Outbox, models.EventoCursoAlterado, dadosEventoCurso(curso)); err != nil {
+			return err
+		}
+		// Um evento para cada inscrição que esta transação de fato cancelou
+		for i := range inscricoes {
+			if err := s.eventos.Registrar(repos.Outbox, models.EventoInscricaoCancelada, dadosEventoInscricao(&inscricoes[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	curso.PreencherSituacaoInscricoes(agora)
+
+	log.Printf("Curso %d cancelado: %d inscrições canceladas", curso.ID, canceladas)
+	return &ResumoCancelamento{Curso: curso, InscricoesCanceladas: canceladas}, nil
+}
+
+// AvisarCancelamento envia uma mensagem de serviço, necessária para cumprir a inscrição que o aluno
+// fez; por isso não depende da autorização de avisos de cursos
+func (s *cursoService) AvisarCancelamento(curso *models.Curso, aluno *models.Aluno) error {
+	if aluno.Anonimizado || aluno.Email == "" {
+		return nil
+	}
+
+	// O aviso sai no idioma escolhido pelo aluno na inscrição
+	idioma := i18n.Normalizar(aluno.Idioma)
+	mensagem := i18n.Traduzir(idioma, "Olá, %s.\n\nInformamos que o curso %q, previsto para %s, foi cancelado pela organização.",
+		aluno.Nome, curso.Nome, i18n.FormatarData(idioma, curso.Data.Time))
+	if curso.MotivoCancelamento != "" {
+		mensagem += i18n.Traduzir(idioma, "\nMotivo: %s", curso.MotivoCancelamento)
+	}
+	mensagem += i18n.Traduzir(idioma, "\n\nSua inscrição continua registrada em nosso histórico. Pedimos desculpas pelo transtorno.")
+
+	assunto := i18n.Traduzir(idioma, "Curso cancelado: %s", curso.Nome)
+	return s.notificador.Enviar(aluno.Email, assunto, mensagem)
 }
 
 func (s *cursoService) VerificarDisponibilidadeVagas(id uint) (int32, error) {
-	curso, err := s.cursoRepo.FindByID(id)
+	curso, err := s.ObterCursoPublico(id)
 	if err != nil {
 		return 0, err
 	}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"tvtec/erros"
+	"tvtec/models"
+	"tvtec/repository"
+)
+
+// Política de novas tentativas da outbox
+const (
+	MaximoTentativasOutbox = 10
+	intervaloBaseOutbox    = 5 * time.Second
+	intervaloMaximoOutbox  = time.Hour
+	loteEventosOutbox      = 100
+)
+
+// AssinanteEventos trata eventos da outbox. Um evento pode chegar mais de uma vez (por exemplo,
+// se o processo cair depois do tratamento e antes da confirmação), então o tratamento deve tolerar repetição.
+type AssinanteEventos struct {
+	Nome    string   // identifica o assinante no controle de quem já tratou cada evento
+	Eventos []string // eventos assinados; vazio assina todos
+	Tratar  func(evento *models.EventoOutbox) error
+}
+
+// DespachanteEventos grava eventos na outbox e os entrega aos assinantes em segundo plano
+type DespachanteEventos interface {
+	PublicadorEventos
+	// Assinar deve ser chamado na inicialização, antes de Executar
+	Assinar(assinante AssinanteEventos)
+	ProcessarPendentes()
+	Executar(ctx context.Context, intervalo time.Duration)
+	TrabalhoSegundoPlano
+}
+
+type despachanteEventos struct {
+	lacoPeriodico
+	outboxRepo repository.OutboxRepository
+	assinantes []AssinanteEventos
+	aviso      chan struct{}
+}
+
+// Função construtora para o despachante de eventos
+func NewDespachanteEventos(outboxRepo repository.OutboxRepository) DespachanteEventos {
+	return &despachanteEventos{outboxRepo: outboxRepo, aviso: make(chan struct{}, 1)}
+}
+
+// Registrar grava o evento usando o repositório da outbox da transação em andamento
+func (d *despachanteEventos) Registrar(outbox repository.OutboxRepository, evento string, dados interface{}) error {
+	registro, err := novoEventoOutbox(evento, dados)
+	if err != nil {
+		return err
+	}
+	return outbox.Save(registro)
+}
+
+func (d *despachanteEventos) Avisar() {
+	select {
+	case d.aviso <- struct{}{}:
+	default:
+	}
+}
+
+func (d *despachanteEventos) Assinar(assinante AssinanteEventos) {
+	d.assinantes = append(d.assinantes, assinante)
+}
+
+// ProcessarPendentes entrega os eventos vencidos aos assinantes que ainda não os trataram.
+// Um assinante com falha não impede os demais; só ele recebe o evento de novo na próxima tentativa.
+func (d *despachanteEventos) ProcessarPendentes() {
+	eventos, err := d.outboxRepo.FindPendentes(time.Now(), loteEventosOutbox)
+	if err != nil {
+		log.Printf("Erro ao buscar eventos pendentes da outbox: %v", err)
+		return
+	}
+
+	for i := range eventos {
+		evento := &eventos[i]
+		var falhas []string
+		for _, assinante := range d.assinantes {
+			if !assinante.interessado(evento) {
+				continue
+			}
+			if err := assinante.tratarProtegido(evento); err != nil {
+				falhas = append(falhas, fmt.Sprintf("%s: %v", assinante.Nome, err))
+				continue
+			}
+			evento.AssinantesConcluidos = append(evento.AssinantesConcluidos, assinante.Nome)
+		}
+
+		evento.Tentativas++
+		agora := time.Now()
+		switch {
+		case len(falhas) == 0:
+			evento.Situacao = models.EventoOutboxProcessado
+			evento.ProcessadoEm = &agora
+			evento.UltimoErro = ""
+		case evento.Tentativas >= MaximoTentativasOutbox:
+			evento.Situacao = models.EventoOutboxFalhou
+			evento.UltimoErro = strings.Join(falhas, "; ")
+			log.Printf("Evento %d (%s) da outbox falhou após %d tentativas: %s", evento.ID, evento.Evento, evento.Tentativas, evento.UltimoErro)
+		default:
+			evento.UltimoErro = strings.Join(falhas, "; ")
+			evento.ProximaTentativa = agora.Add(intervaloExponencial(intervaloBaseOutbox, intervaloMaximoOutbox, evento.Tentativas))
+		}
+
+		if err := d.outboxRepo.Save(evento); err != nil {
+			log.Printf("Erro ao atualizar evento %d da outbox: %v", evento.ID, err)
+		}
+	}
+}
+
+// Executar processa a outbox periodicamente, e logo após cada aviso, até o contexto ser encerrado
+func (d *despachanteEventos) Executar(ctx context.Context, intervalo time.Duration) {
+	d.executar(ctx, intervalo, d.aviso, d.ProcessarPendentes)
+}
+
+func (a *AssinanteEventos) interessado(evento *models.EventoOutbox) bool {
+	for _, concluido := range evento.AssinantesConcluidos {
+		if concluido == a.Nome {
+			return false
+		}
+	}
+	if len(a.Eventos) == 0 {
+		return true
+	}
+	for _, assinado := range a.Eventos {
+		if assinado == evento.Evento {
+			return true
+		}
+	}
+	return false
+}
+
+// tratarProtegido converte um panic do assinante em falha, para não derrubar o despachante
+func (a *AssinanteEventos) tratarProtegido(evento *models.EventoOutbox) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return a.Tratar(evento)
+}
+
+// AssinanteWebhooks cria as entregas para os webhooks que assinam o evento
+func AssinanteWebhooks(webhooks WebhookService) AssinanteEventos {
+	return AssinanteEventos{
+		Nome:    "webhooks",
+		Eventos: models.EventosWebhook,
+		Tratar:  webhooks.RegistrarEntregas,
+	}
+}
+
+// AssinanteAcessibilidade envia o alerta de inscrição PCD próxima ao início do curso
+func AssinanteAcessibilidade(acessibilidade AcessibilidadeService, cursoRepo repository.CursoRepository, inscricaoRepo repository.InscricaoRepository) AssinanteEventos {
+	return AssinanteEventos{
+		Nome:    "acessibilidade",
+		Eventos: []string{models.EventoInscricaoCriada},
+		Tratar: func(evento *models.EventoOutbox) error {
+			var dados DadosEventoInscricao
+			if err := json.Unmarshal([]byte(evento.Payload), &dados); err != nil {
+				return err
+			}
+			inscricao, err := inscricaoRepo.FindByID(dados.InscricaoID)
+			if err != nil {
+				// A inscrição foi cancelada antes do despacho: não há o que avisar
+				return nil
+			}
+			curso, err := cursoRepo.FindByID(inscricao.CursoID)
+			if err != nil {
+				return err
+			}
+			return acessibilidade.InscricaoRecebida(curso, inscricao)
+		},
+	}
+}
+
+// AssinanteCancelamentoCurso avisa por email cada aluno cuja inscrição foi cancelada junto com o curso
+func AssinanteCancelamentoCurso(cursos CursoService, cursoRepo repository.CursoRepository, alunoRepo repository.AlunoRepository) AssinanteEventos {
+	return AssinanteEventos{
+		Nome:    "aviso_cancelamento",
+		Eventos: []string{models.EventoInscricaoCancelada},
+		Tratar: func(evento *models.EventoOutbox) error {
+			var dados DadosEventoInscricao
+			if err := json.Unmarshal([]byte(evento.Payload), &dados); err != nil {
+				return err
+			}
+			// Cancelamentos feitos pelo próprio aluno ou pela administração não geram aviso
+			if dados.Status != models.StatusInscricaoCanceladaOrganizacao {
+				return nil
+			}
+			curso, err := cursoRepo.FindByID(dados.CursoID)
+			if err != nil {
+				return err
+			}
+			aluno, err := alunoRepo.FindByID(dados.AlunoID)
+			if err != nil {
+				if errors.Is(err, erros.ErrAlunoNaoEncontrado) {
+					// O aluno foi removido antes do despacho: não há a quem avisar
+					return nil
+				}
+				return err
+			}
+			return cursos.AvisarCancelamento(curso, aluno)
+		},
+	}
+}
+
+// AssinanteAuditoria registra no log cada evento de domínio
+func AssinanteAuditoria() AssinanteEventos {
+	return AssinanteEventos{
+		Nome: "auditoria",
+		Tratar: func(evento *models.EventoOutbox) error {
+			log.Printf("Evento %d %s em %s: %s", evento.ID, evento.Evento, evento.CriadoEm.Format(time.RFC3339), evento.Payload)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,376 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"tvtec/erros"
+	"tvtec/models"
+	"tvtec/repository"
+)
+
+// Critérios que apontam dois cadastros como possíveis duplicados
+const (
+	CriterioDuplicidadeCPF            = "cpf"
+	CriterioDuplicidadeEmail          = "email"
+	CriterioDuplicidadeTelefone       = "telefone"
+	CriterioDuplicidadeNomeNascimento = "nome_data_nascimento"
+)
+
+// Similaridade mínima entre nomes (0 a 1) para, junto da mesma data de nascimento, indicar duplicidade
+const similaridadeMinimaNome = 0.85
+
+// CandidatoDuplicidade é um par de cadastros que provavelmente pertencem à mesma pessoa
+type CandidatoDuplicidade struct {
+	AlunoA           models.Aluno
+	AlunoB           models.Aluno
+	Criterios        []string
+	SimilaridadeNome float64
+}
+
+// Interface para o serviço de identificação e fusão de alunos duplicados
+type DuplicidadeService interface {
+	BuscarCandidatos() ([]CandidatoDuplicidade, error)
+	Mesclar(mantidoID, removidoID uint, motivo, responsavel, ip string) (*models.FusaoAlunos, error)
+	ListarFusoes() ([]models.FusaoAlunos, error)
+	ListarConflitos(situacao string) ([]models.ConflitoCadastro, error)
+	ResolverConflito(id uint, aplicar bool, responsavel string) (*models.ConflitoCadastro, error)
+}
+
+type duplicidadeServiceImpl struct {
+	alunoRepo       repository.AlunoRepository
+	duplicidadeRepo repository.DuplicidadeRepository
+	transacao       repository.Transacao
+	eventos         PublicadorEventos
+}
+
+// Função construtora para o serviço de duplicidade
+func NewDuplicidadeService(alunoRepo repository.AlunoRepository, duplicidadeRepo repository.DuplicidadeRepository, transacao repository.Transacao, eventos PublicadorEventos) DuplicidadeService {
+	return &duplicidadeServiceImpl{alunoRepo: alunoRepo, duplicidadeRepo: duplicidadeRepo, transacao: transacao, eventos: eventos}
+}
+
+// BuscarCandidatos compara todos os cadastros ativos por CPF, email e telefone normalizados e,
+// entre alunos com a mesma data de nascimento, pela semelhança dos nomes
+func (s *duplicidadeServiceImpl) BuscarCandidatos() ([]CandidatoDuplicidade, error) {
+	todos, err := s.alunoRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	alunos := make([]models.Aluno, 0, len(todos))
+	for _, aluno := range todos {
+		if !aluno.Anonimizado {
+			alunos = append(alunos, aluno)
+		}
+	}
+
+	pares := map[[2]int]*CandidatoDuplicidade{}
+	adicionar := func(i, j int, criterio string) *CandidatoDuplicidade {
+		if alunos[i].ID > alunos[j].ID {
+			i, j = j, i
+		}
+		chave := [2]int{i, j}
+		par, ok := pares[chave]
+		if !ok {
+			par = &CandidatoDuplicidade{AlunoA: alunos[i], AlunoB: alunos[j]}
+			pares[chave] = par
+		}
+		par.Criterios = append(par.Criterios, criterio)
+		return par
+	}
+
+	// Critérios exatos: agrupa pelo valor normalizado e liga todos os alunos do mesmo grupo
+	chaves := map[string]func(aluno *models.Aluno) string{
+		CriterioDuplicidadeCPF:      func(aluno *models.Aluno) string { return somenteDigitos(aluno.CPF) },
+		CriterioDuplicidadeEmail:    func(aluno *models.Aluno) string { return strings.ToLower(strings.TrimSpace(aluno.Email)) },
+		CriterioDuplicidadeTelefone: func(aluno *models.Aluno) string { return telefoneNormalizado(aluno.Telefone) },
+	}
+	for _, criterio := range []string{CriterioDuplicidadeCPF, CriterioDuplicidadeEmail, CriterioDuplicidadeTelefone} {
+		grupos := map[string][]int{}
+		for i := range alunos {
+			if valor := chaves[criterio](&alunos[i]); valor != "" {
+				grupos[valor] = append(grupos[valor], i)
+			}
+		}
+		for _, grupo := range grupos {
+			for a := 0; a < len(grupo); a++ {
+				for b := a + 1; b < len(grupo); b++ {
+					adicionar(grupo[a], grupo[b], criterio)
+				}
+			}
+		}
+	}
+
+	// Nome parecido só é comparado entre alunos nascidos no mesmo dia
+	porNascimento := map[string][]int{}
+	for i, aluno := range alunos {
+		if !aluno.DataNascto.IsZero() {
+			dia := aluno.DataNascto.Format("2006-01-02")
+			porNascimento[dia] = append(porNascimento[dia], i)
+		}
+	}
+	for _, grupo := range porNascimento {
+		for a := 0; a < len(grupo); a++ {
+			for b := a + 1; b < len(grupo); b++ {
+				similaridade := similaridadeNomes(alunos[grupo[a]].Nome, alunos[grupo[b]].Nome)
+				if similaridade >= similaridadeMinimaNome {
+					adicionar(grupo[a], grupo[b], CriterioDuplicidadeNomeNascimento).SimilaridadeNome = similaridade
+				}
+			}
+		}
+	}
+
+	candidatos := make([]CandidatoDuplicidade, 0, len(pares))
+	for _, par := range pares {
+		if par.SimilaridadeNome == 0 {
+			par.SimilaridadeNome = similaridadeNomes(par.AlunoA.Nome, par.AlunoB.Nome)
+		}
+		sort.Strings(par.Criterios)
+		candidatos = append(candidatos, *par)
+	}
+	// Pares com mais critérios coincidentes primeiro
+	sort.Slice(candidatos, func(i, j int) bool {
+		if len(candidatos[i].Criterios) != len(candidatos[j].Criterios) {
+			return len(candidatos[i].Criterios) > len(candidatos[j].Criterios)
+		}
+		if candidatos[i].AlunoA.ID != candidatos[j].AlunoA.ID {
+			return candidatos[i].AlunoA.ID < candidatos[j].AlunoA.ID
+		}
+		return candidatos[i].AlunoB.ID < candidatos[j].AlunoB.ID
+	})
+	return candidatos, nil
+}
+
+// Mesclar incorpora o aluno removido ao aluno mantido: inscrições, presenças, consentimentos,
+// solicitações LGPD e conflitos passam para o mantido e o cadastro removido é apagado.
+// A fusão fica registrada com uma cópia do cadastro removido.
+func (s *duplicidadeServiceImpl) Mesclar(mantidoID, removidoID uint, motivo, responsavel, ip string) (*models.FusaoAlunos, error) {
+	if mantidoID == removidoID {
+		return nil, erros.ErrDadosInvalidos.ComMensagem("Não é possível mesclar um aluno com ele mesmo")
+	}
+
+	mantido, err := s.alunoRepo.FindByID(mantidoID)
+	if err != nil {
+		return nil, erros.ErrAlunoNaoEncontrado.ComMensagem("Aluno mantido não encontrado")
+	}
+	removido, err := s.alunoRepo.FindByID(removidoID)
+	if err != nil {
+		return nil, erros.ErrAlunoNaoEncontrado.ComMensagem("Aluno duplicado não encontrado")
+	}
+	if mantido.Anonimizado || removido.Anonimizado {
+		return nil, erros.ErrDadosEliminados.ComMensagem("Alunos com dados eliminados (LGPD) não podem ser mesclados")
+	}
+
+	dadosRemovido, err := json.Marshal(removido)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao registrar dados do aluno duplicado: %w", err)
+	}
+
+	// Dados ausentes no cadastro mantido são completados com os do duplicado
+	if mantido.Telefone == "" {
+		mantido.Telefone = removido.Telefone
+	}
+	if mantido.Sexo == "" {
+		mantido.Sexo = removido.Sexo
+	}
+
+	fusao := &models.FusaoAlunos{
+		AlunoMantidoID:     mantido.ID,
+		AlunoRemovidoID:    removido.ID,
+		DadosAlunoRemovido: string(dadosRemovido),
+		Motivo:             motivo,
+		Responsavel:        responsavel,
+		IP:                 ip,
+		DataFusao:          time.Now(),
+	}
+	err = emTransacao(s.transacao, s.eventos, func(repos repository.Repositorios) error {
+		descartadas, err := repos.Duplicidades.Mesclar(mantido, removido.ID, fusao)
+		if err != nil {
+			return fmt.Errorf("erro ao mesclar alunos: %w", err)
+		}
+
+		// As inscrições duplicadas deixam de existir; os assinantes as tratam como canceladas. As
+		// canceladas pela organização já tiveram o seu evento no cancelamento do curso.
+		for i := range descartadas {
+			if descartadas[i].Status == models.StatusInscricaoCanceladaOrganizacao {
+				continue
+			}
+			descartadas[i].Status = models.StatusInscricaoCancelada
+			if err := s.eventos.Registrar(repos.Outbox, models.EventoInscricaoCancelada, dadosEventoInscricao(&descartadas[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fusao, nil
+}
+
+func (s *duplicidadeServiceImpl) ListarFusoes() ([]models.FusaoAlunos, error) {
+	return s.duplicidadeRepo.FindFusoes()
+}
+
+func (s *duplicidadeServiceImpl) ListarConflitos(situacao string) ([]models.ConflitoCadastro, error) {
+	switch situacao {
+	case "", models.ConflitoPendente, models.ConflitoAplicado, models.ConflitoDescartado:
+		return s.duplicidadeRepo.FindConflitos(situacao)
+	default:
+		return nil, erros.ErrDadosInvalidos.ComMensagem("Situação de conflito inválida")
+	}
+}
+
+// ResolverConflito aplica o valor informado ao cadastro do aluno ou descarta o conflito,
+// mantendo o valor cadastrado
+func (s *duplicidadeServiceImpl) ResolverConflito(id uint, aplicar bool, responsavel string) (*models.ConflitoCadastro, error) {
+	conflito, err := s.duplicidadeRepo.FindConflitoByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if conflito.Situacao != models.ConflitoPendente {
+		return nil, erros.ErrSituacaoInvalida.ComMensagem("Conflito já resolvido")
+	}
+
+	var aluno *models.Aluno
+	conflito.Situacao = models.ConflitoDescartado
+	if aplicar {
+		aluno, err = s.alunoRepo.FindByID(conflito.AlunoID)
+		if err != nil {
+			return nil, erros.ErrAlunoNaoEncontrado
+		}
+		if err := s.aplicarValor(aluno, conflito.Campo, conflito.ValorInformado); err != nil {
+			return nil, err
+		}
+		conflito.Situacao = models.ConflitoAplicado
+	}
+
+	agora := time.Now()
+	conflito.ResolvidoPor = responsavel
+	conflito.ResolvidoEm = &agora
+	if err := s.duplicidadeRepo.ResolverConflito(conflito, aluno); err != nil {
+		return nil, err
+	}
+	return conflito, nil
+}
+
+func (s *duplicidadeServiceImpl) aplicarValor(aluno *models.Aluno, campo, valor string) error {
+	switch campo {
+	case "nome":
+		aluno.Nome = valor
+	case "sexo":
+		aluno.Sexo = valor
+	case "telefone":
+		aluno.Telefone = valor
+	case "dataNascto":
+		data, err := time.Parse("02/01/2006", valor)
+		if err != nil {
+			return erros.ErrDadosInvalidos.ComMensagem("Data de nascimento informada inválida")
+		}
+		aluno.DataNascto = data
+	case "cpf":
+		if outro, _ := s.alunoRepo.FindByCPF(valor); outro != nil && outro.ID != aluno.ID {
+			return erros.ErrCadastroDuplicado.ComMensagem("O CPF informado pertence ao aluno %d; mescle os cadastros", outro.ID)
+		}
+		aluno.CPF = valor
+	case "email":
+		if outro, _ := s.alunoRepo.FindByEmail(valor); outro != nil && outro.ID != aluno.ID {
+			return erros.ErrCadastroDuplicado.ComMensagem("O email informado pertence ao aluno %d; mescle os cadastros", outro.ID)
+		}
+		aluno.Email = valor
+	default:
+		return erros.ErrDadosInvalidos.ComMensagem("Campo de conflito desconhecido: %s", campo)
+	}
+	return nil
+}
+
+// compararCadastro lista os dados informados que divergem do cadastro existente.
+// Campos não informados não geram conflito.
+func compararCadastro(existente, informado *models.Aluno) []models.ConflitoCadastro {
+	var conflitos []models.ConflitoCadastro
+	comparar := func(campo, cadastrado, novo string, normalizar func(string) string) {
+		if strings.TrimSpace(novo) == "" || normalizar(cadastrado) == normalizar(novo) {
+			return
+		}
+		conflitos = append(conflitos, models.ConflitoCadastro{
+			AlunoID:         existente.ID,
+			Campo:           campo,
+			ValorCadastrado: cadastrado,
+			ValorInformado:  strings.TrimSpace(novo),
+		})
+	}
+
+	comparar("nome", existente.Nome, informado.Nome, normalizarNome)
+	comparar("cpf", existente.CPF, informado.CPF, somenteDigitos)
+	comparar("email", existente.Email, informado.Email, func(valor string) string {
+		return strings.ToLower(strings.TrimSpace(valor))
+	})
+	comparar("telefone", existente.Telefone, informado.Telefone, telefoneNormalizado)
+	comparar("sexo", existente.Sexo, informado.Sexo, normalizarNome)
+	if !informado.DataNascto.IsZero() && !mesmaData(existente.DataNascto, informado.DataNascto) {
+		comparar("dataNascto", existente.DataNascto.Format("02/01/2006"), informado.DataNascto.Format("02/01/2006"), strings.TrimSpace)
+	}
+	return conflitos
+}
+
+// CamposEmConflito lista apenas os nomes dos campos divergentes, sem os valores cadastrados
+func CamposEmConflito(conflitos []models.ConflitoCadastro) []string {
+	var campos []string
+	for _, conflito := range conflitos {
+		campos = append(campos, conflito.Campo)
+	}
+	return campos
+}
+
+// telefoneNormalizado mantém só os dígitos, sem o código do país; números curtos demais são ignorados
+func telefoneNormalizado(telefone string) string {
+	digitos := somenteDigitos(telefone)
+	if len(digitos) >= 12 && strings.HasPrefix(digitos, "55") {
+		digitos = digitos[2:]
+	}
+	if len(digitos) < 8 {
+		return ""
+	}
+	return digitos
+}
+
+// normalizarNome ignora maiúsculas, acentos e espaços repetidos
+func normalizarNome(nome string) string {
+	return strings.Join(strings.Fields(normalizarValorRegra("", nome)), " ")
+}
+
+// similaridadeNomes devolve 1 para nomes iguais e se aproxima de 0 conforme a distância de edição cresce
+func similaridadeNomes(a, b string) float64 {
+	ra, rb := []rune(normalizarNome(a)), []rune(normalizarNome(b))
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+	maior := len(ra)
+	if len(rb) > maior {
+		maior = len(rb)
+	}
+	return 1 - float64(distanciaEdicao(ra, rb))/float64(maior)
+}
+
+// distanciaEdicao calcula a distância de Levenshtein entre duas sequências
+func distanciaEdicao(a, b []rune) int {
+	anterior := make([]int, len(b)+1)
+	atual := make([]int, len(b)+1)
+	for j := range anterior {
+		anterior[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		atual[0] = i
+		for j := 1; j <= len(b); j++ {
+			custo := 1
+			if a[i-1] == b[j-1] {
+				custo = 0
+			}
+			atual[j] = min(anterior[j]+1, atual[j-1]+1, anterior[j-1]+custo)
+		}
+		anterior, atual = atual, anterior
+	}
+	return anterior[len(b)]
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"tvtec/models"
+	"tvtec/repository"
+)
+
+// ResultadoElegibilidadeAluno mostra se um aluno cadastrado poderia se inscrever no curso
+type ResultadoElegibilidadeAluno struct {
+	AlunoID  uint                    `json:"alunoId"`
+	Nome     string                  `json:"nome"`
+	Elegivel bool                    `json:"elegivel"`
+	Motivos  []MotivoInelegibilidade `json:"motivos"`
+}
+
+// PreviaElegibilidade resume a aplicação das regras aos alunos já cadastrados
+type PreviaElegibilidade struct {
+	CursoID     uint                          `json:"cursoId"`
+	Regras      []models.RegraElegibilidade   `json:"regras"`
+	TotalAlunos int                           `json:"totalAlunos"`
+	Elegiveis   int                           `json:"elegiveis"`
+	Inelegiveis int                           `json:"inelegiveis"`
+	Alunos      []ResultadoElegibilidadeAluno `json:"alunos"`
+}
+
+type ElegibilidadeService interface {
+	ListarRegras(cursoID uint) ([]models.RegraElegibilidade, error)
+	DefinirRegras(cursoID uint, regras []models.RegraElegibilidade) error
+	Previa(cursoID uint, regras []models.RegraElegibilidade) (*PreviaElegibilidade, error)
+}
+
+type elegibilidadeService struct {
+	cursoRepo     repository.CursoRepository
+	alunoRepo     repository.AlunoRepository
+	inscricaoRepo repository.InscricaoRepository
+}
+
+func NewElegibilidadeService(
+	cursoRepo repository.CursoRepository,
+	alunoRepo repository.AlunoRepository,
+	inscricaoRepo repository.InscricaoRepository,
+) ElegibilidadeService {
+	return &elegibilidadeService{
+		cursoRepo:     cursoRepo,
+		alunoRepo:     alunoRepo,
+		inscricaoRepo: inscricaoRepo,
+	}
+}
+
+func (s *elegibilidadeService) ListarRegras(cursoID uint) ([]models.RegraElegibilidade, error) {
+	curso, err := s.cursoRepo.FindByID(cursoID)
+	if err != nil {
+		return nil, err
+	}
+	return curso.RegrasElegibilidade, nil
+}
+
+func (s *elegibilidadeService) DefinirRegras(cursoID uint, regras []models.RegraElegibilidade) error {
+	if _, err := s.cursoRepo.FindByID(cursoID); err != nil {
+		return err
+	}
+	if err := validarRegrasElegibilidade(regras); err != nil {
+		return err
+	}
+	return s.cursoRepo.SubstituirRegrasElegibilidade(cursoID, regras)
+}
+
+// Previa aplica as regras informadas (ou, se vazias, as já salvas) a todos os alunos cadastrados
+func (s *elegibilidadeService) Previa(cursoID uint, regras []models.RegraElegibilidade) (*PreviaElegibilidade, error) {
+	curso, err := s.cursoRepo.FindByID(cursoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(regras) == 0 {
+		regras = curso.RegrasElegibilidade
+	} else if err := validarRegrasElegibilidade(regras); err != nil {
+		return nil, err
+	}
+
+	alunos, err := s.alunoRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	// Carrega as inscrições de uma vez para não consultar o banco por aluno
+	inscricoes, err := s.inscricaoRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	porAluno := make(map[uint][]models.Inscricao)
+	for _, inscricao := range inscricoes {
+		porAluno[inscricao.AlunoID] = append(porAluno[inscricao.AlunoID], inscricao)
+	}
+
+	previa := &PreviaElegibilidade{
+		CursoID: cursoID,
+		Regras:  regras,
+		Alunos:  []ResultadoElegibilidadeAluno{},
+	}
+	for i := range alunos {
+		aluno := &alunos[i]
+		if aluno.Anonimizado {
+			continue
+		}
+
+		perfil := montarPerfilElegibilidade(aluno, nil, porAluno[aluno.ID])
+		motivos := avaliarElegibilidade(regras, perfil, curso.Data.Time)
+		resultado := ResultadoElegibilidadeAluno{
+			AlunoID:  aluno.ID,
+			Nome:     aluno.Nome,
+			Elegivel: len(motivos) == 0,
+			Motivos:  motivos,
+		}
+
+		previa.TotalAlunos++
+		if resultado.Elegivel {
+			previa.Elegiveis++
+		} else {
+			previa.Inelegiveis++
+		}
+		previa.Alunos = append(previa.Alunos, resultado)
+	}
+
+	return previa, nil
+}
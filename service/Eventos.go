@@ -0,0 +1,111 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"tvtec/models"
+	"tvtec/repository"
+)
+
+// PublicadorEventos grava eventos de domínio na outbox. Registrar deve ser chamado dentro da
+// transação da alteração que gerou o evento; Avisar, depois da confirmação, adianta o despacho.
+type PublicadorEventos interface {
+	Registrar(outbox repository.OutboxRepository, evento string, dados interface{}) error
+	Avisar()
+}
+
+// DadosEventoInscricao é o conteúdo dos eventos de inscrição; dados pessoais do aluno não são enviados
+type DadosEventoInscricao struct {
+	InscricaoID   uint      `json:"inscricaoId"`
+	AlunoID       uint      `json:"alunoId"`
+	CursoID       uint      `json:"cursoId"`
+	Status        string    `json:"status"`
+	DataInscricao time.Time `json:"dataInscricao"`
+}
+
+// DadosEventoCurso é o conteúdo dos eventos de curso
+type DadosEventoCurso struct {
+	CursoID          uint      `json:"cursoId"`
+	Nome             string    `json:"nome"`
+	Status           string    `json:"status"`
+	Data             time.Time `json:"data"`
+	VagasTotais      int32     `json:"vagasTotais"`
+	VagasPreenchidas int32     `json:"vagasPreenchidas"`
+}
+
+func dadosEventoInscricao(inscricao *models.Inscricao) DadosEventoInscricao {
+	return DadosEventoInscricao{
+		InscricaoID:   inscricao.ID,
+		AlunoID:       inscricao.AlunoID,
+		CursoID:       inscricao.CursoID,
+		Status:        inscricao.Status,
+		DataInscricao: inscricao.DataInscricao,
+	}
+}
+
+func dadosEventoCurso(curso *models.Curso) DadosEventoCurso {
+	return DadosEventoCurso{
+		CursoID:          curso.ID,
+		Nome:             curso.Nome,
+		Status:           curso.StatusAtual(),
+		Data:             curso.Data.Time,
+		VagasTotais:      curso.VagasTotais,
+		VagasPreenchidas: curso.VagasPreenchidas,
+	}
+}
+
+// novoEventoOutbox prepara o registro da outbox com os dados serializados
+func novoEventoOutbox(evento string, dados interface{}) (*models.EventoOutbox, error) {
+	payload, err := json.Marshal(dados)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar evento %s: %w", evento, err)
+	}
+	agora := time.Now()
+	return &models.EventoOutbox{
+		Evento:           evento,
+		Payload:          string(payload),
+		Situacao:         models.EventoOutboxPendente,
+		ProximaTentativa: agora,
+		CriadoEm:         agora,
+	}, nil
+}
+
+// emTransacao executa a operação numa transação e, confirmada, adianta o despacho dos eventos gravados
+func emTransacao(transacao repository.Transacao, eventos PublicadorEventos, operacao func(repos repository.Repositorios) error) error {
+	if err := transacao.Executar(operacao); err != nil {
+		return err
+	}
+	eventos.Avisar()
+	return nil
+}
+
+// registrarInscricaoCriada grava a nova inscrição e, se ela ocupou a última vaga, que o curso lotou
+func registrarInscricaoCriada(eventos PublicadorEventos, repos repository.Repositorios, inscricao *models.Inscricao) error {
+	if err := eventos.Registrar(repos.Outbox, models.EventoInscricaoCriada, dadosEventoInscricao(inscricao)); err != nil {
+		return err
+	}
+
+	// O repositório atualiza as vagas na mesma transação; relê o curso para saber se lotou
+	curso, err := repos.Cursos.FindByID(inscricao.CursoID)
+	if err != nil {
+		return err
+	}
+	if curso.VagasPreenchidas >= curso.VagasTotais {
+		return eventos.Registrar(repos.Outbox, models.EventoCursoLotado, dadosEventoCurso(curso))
+	}
+	return nil
+}
+
+// intervaloExponencial dobra a espera a cada tentativa, a partir de base e limitada a maximo
+func intervaloExponencial(base, maximo time.Duration, tentativas int) time.Duration {
+	intervalo := base
+	for i := 1; i < tentativas && intervalo < maximo; i++ {
+		intervalo *= 2
+	}
+	if intervalo > maximo {
+		intervalo = maximo
+	}
+	return intervalo
+}
@@ -0,0 +1,406 @@
+package service
+
+import (
+	"fmt"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"tvtec/erros"
+	"tvtec/models"
+	"tvtec/repository"
+)
+
+// Campos que podem ser preenchidos por uma coluna da planilha de importação
+const (
+	CampoImportacaoNome              = "nome"
+	CampoImportacaoCPF               = "cpf"
+	CampoImportacaoEmail             = "email"
+	CampoImportacaoSexo              = "sexo"
+	CampoImportacaoDataNascto        = "dataNascto"
+	CampoImportacaoTelefone          = "telefone"
+	CampoImportacaoCurso             = "curso"
+	CampoImportacaoEscolaridade      = "escolaridade"
+	CampoImportacaoTrabalhando       = "trabalhando"
+	CampoImportacaoBairro            = "bairro"
+	CampoImportacaoEhCuidador        = "ehCuidador"
+	CampoImportacaoEhPCD             = "ehPCD"
+	CampoImportacaoTipoPCD           = "tipoPCD"
+	CampoImportacaoNecessitaElevador = "necessitaElevador"
+	CampoImportacaoComoSoube         = "comoSoube"
+)
+
+var camposImportacao = []string{
+	CampoImportacaoNome, CampoImportacaoCPF, CampoImportacaoEmail, CampoImportacaoSexo,
+	CampoImportacaoDataNascto, CampoImportacaoTelefone, CampoImportacaoCurso,
+	CampoImportacaoEscolaridade, CampoImportacaoTrabalhando, CampoImportacaoBairro,
+	CampoImportacaoEhCuidador, CampoImportacaoEhPCD, CampoImportacaoTipoPCD,
+	CampoImportacaoNecessitaElevador, CampoImportacaoComoSoube,
+}
+
+// Situação de cada linha no relatório de importação
+const (
+	LinhaImportacaoValida    = "valida"    // passou na simulação
+	LinhaImportacaoImportada = "importada" // aluno e inscrição gravados
+	LinhaImportacaoErro      = "erro"
+)
+
+// Limite de linhas por arquivo, para que a importação caiba em uma requisição
+const MaximoLinhasImportacao = 2000
+
+// OpcoesImportacao define como a planilha é lida e se os dados são gravados
+type OpcoesImportacao struct {
+	// Campo -> nome da coluna na planilha; campos sem mapeamento usam a coluna de mesmo nome
+	Mapeamento map[string]string
+	// Curso de todas as linhas; quando zero, cada linha informa o curso na coluna "curso"
+	CursoID uint
+	// Efetivar grava os alunos e inscrições; sem ele a importação é só uma simulação
+	Efetivar bool
+}
+
+// LinhaImportacao é o resultado de uma linha da planilha
+type LinhaImportacao struct {
+	Linha       int      `json:"linha"` // número da linha no arquivo, contando o cabeçalho
+	Nome        string   `json:"nome"`
+	CPF         string   `json:"cpf"`
+	Email       string   `json:"email"`
+	CursoID     uint     `json:"cursoId,omitempty"`
+	Situacao    string   `json:"situacao"`
+	Erros       []string `json:"erros,omitempty"`
+	InscricaoID uint     `json:"inscricaoId,omitempty"`
+	// Campos em que a linha diverge do cadastro já existente do aluno, que não é alterado
+	Conflitos []string `json:"conflitos,omitempty"`
+}
+
+// RelatorioImportacao resume a importação linha a linha
+type RelatorioImportacao struct {
+	Arquivo      string            `json:"arquivo"`
+	Efetivada    bool              `json:"efetivada"`
+	Mapeamento   map[string]string `json:"mapeamento"`
+	TotalLinhas  int               `json:"totalLinhas"`
+	Validas      int               `json:"validas"`
+	Importadas   int               `json:"importadas"`
+	ComErro      int               `json:"comErro"`
+	Linhas       []LinhaImportacao `json:"linhas"`
+	ProcessadoEm time.Time         `json:"processadoEm"`
+}
+
+type ImportacaoService interface {
+	Importar(arquivo string, planilha [][]string, opcoes OpcoesImportacao) (*RelatorioImportacao, error)
+}
+
+type importacaoServiceImpl struct {
+	alunoService AlunoService
+	cursoRepo    repository.CursoRepository
+}
+
+func NewImportacaoService(alunoService AlunoService, cursoRepo repository.CursoRepository) ImportacaoService {
+	return &importacaoServiceImpl{alunoService: alunoService, cursoRepo: cursoRepo}
+}
+
+// registroImportacao guarda os dados convertidos de uma linha válida
+type registroImportacao struct {
+	indice    int // posição da linha no relatório
+	aluno     *models.Aluno
+	inscricao *models.Inscricao
+}
+
+// Importar valida todas as linhas e, quando solicitado, cadastra e inscreve os alunos das linhas válidas
+// pelas mesmas regras do formulário público de inscrição
+func (s *importacaoServiceImpl) Importar(arquivo string, planilha [][]string, opcoes OpcoesImportacao) (*RelatorioImportacao, error) {
+	if len(planilha) < 2 {
+		return nil, erros.ErrDadosInvalidos.ComMensagem("A planilha precisa de um cabeçalho e ao menos uma linha")
+	}
+	if len(planilha)-1 > MaximoLinhasImportacao {
+		return nil, erros.ErrDadosInvalidos.ComMensagem("A planilha tem mais de %d linhas", MaximoLinhasImportacao)
+	}
+
+	colunas, mapeamento, err := mapearColunas(planilha[0], opcoes)
+	if err != nil {
+		return nil, err
+	}
+
+	relatorio := &RelatorioImportacao{
+		Arquivo:      arquivo,
+		Efetivada:    opcoes.Efetivar,
+		Mapeamento:   mapeamento,
+		Linhas:       []LinhaImportacao{},
+		ProcessadoEm: time.Now(),
+	}
+
+	var registros []registroImportacao
+	cpfs := map[string]int{}
+	emails := map[string]int{}
+	reservas := map[uint]int32{} // vagas já usadas por linhas anteriores do arquivo
+	cursos := map[uint]*models.Curso{}
+
+	for i, valores := range planilha[1:] {
+		numero := i + 2
+		if linhaVazia(valores) {
+			continue
+		}
+		valor := func(campo string) string {
+			indice, ok := colunas[campo]
+			if !ok || indice >= len(valores) {
+				return ""
+			}
+			return strings.TrimSpace(valores[indice])
+		}
+
+		resultado := LinhaImportacao{
+			Linha:    numero,
+			Nome:     valor(CampoImportacaoNome),
+			CPF:      valor(CampoImportacaoCPF),
+			Email:    strings.ToLower(valor(CampoImportacaoEmail)),
+			Situacao: LinhaImportacaoValida,
+		}
+		erro := func(mensagem string) {
+			resultado.Erros = append(resultado.Erros, mensagem)
+		}
+
+		if resultado.Nome == "" {
+			erro("nome é obrigatório")
+		}
+		if !cpfValido(resultado.CPF) {
+			erro("CPF inválido")
+		}
+		if _, err := mail.ParseAddress(resultado.Email); err != nil || !strings.Contains(resultado.Email, ".") {
+			erro("email inválido")
+		}
+		dataNascto, err := dataDaPlanilha(valor(CampoImportacaoDataNascto))
+		if err != nil {
+			erro("data de nascimento inválida. Use DD/MM/AAAA")
+		}
+
+		resultado.CursoID = opcoes.CursoID
+		if resultado.CursoID == 0 {
+			cursoID, err := strconv.ParseUint(valor(CampoImportacaoCurso), 10, 32)
+			if err != nil || cursoID == 0 {
+				erro("curso inválido")
+			}
+			resultado.CursoID = uint(cursoID)
+		}
+
+		// Duplicidade dentro do próprio arquivo
+		if cpf := somenteDigitos(resultado.CPF); cpf != "" {
+			if anterior, ok := cpfs[cpf]; ok {
+				erro(fmt.Sprintf("CPF repetido na linha %d", anterior))
+			} else {
+				cpfs[cpf] = numero
+			}
+		}
+		if resultado.Email != "" {
+			if anterior, ok := emails[resultado.Email]; ok {
+				erro(fmt.Sprintf("email repetido na linha %d", anterior))
+			} else {
+				emails[resultado.Email] = numero
+			}
+		}
+
+		var registro *registroImportacao
+		if len(resultado.Erros) == 0 {
+			aluno := &models.Aluno{
+				Nome:       resultado.Nome,
+				CPF:        resultado.CPF,
+				Email:      resultado.Email,
+				Sexo:       valor(CampoImportacaoSexo),
+				Telefone:   valor(CampoImportacaoTelefone),
+				DataNascto: dataNascto,
+			}
+			inscricao := &models.Inscricao{
+				CursoID:           resultado.CursoID,
+				DataInscricao:     time.Now(),
+				Escolaridade:      valor(CampoImportacaoEscolaridade),
+				Trabalhando:       valor(CampoImportacaoTrabalhando),
+				Bairro:            valor(CampoImportacaoBairro),
+				EhCuidador:        valor(CampoImportacaoEhCuidador),
+				EhPCD:             valor(CampoImportacaoEhPCD),
+				TipoPCD:           valor(CampoImportacaoTipoPCD),
+				NecessitaElevador: valor(CampoImportacaoNecessitaElevador),
+				ComoSoube:         valor(CampoImportacaoComoSoube),
+			}
+
+			// Mesmas regras do formulário: curso, período, duplicidade no banco, pré-requisitos, limites e elegibilidade
+			conflitos, err := s.alunoService.ValidarCadastroEInscricao(aluno, inscricao)
+			resultado.Conflitos = CamposEmConflito(conflitos)
+			if err != nil {
+				erro(err.Error())
+			} else if !s.reservarVaga(cursos, reservas, resultado.CursoID) {
+				erro("não há vagas suficientes no curso para esta linha")
+			} else {
+				registro = &registroImportacao{aluno: aluno, inscricao: inscricao}
+			}
+		}
+
+		if len(resultado.Erros) > 0 {
+			resultado.Situacao = LinhaImportacaoErro
+		}
+		relatorio.Linhas = append(relatorio.Linhas, resultado)
+		if registro != nil {
+			registro.indice = len(relatorio.Linhas) - 1
+			registros = append(registros, *registro)
+		}
+	}
+
+	if opcoes.Efetivar {
+		for _, registro := range registros {
+			resultado := &relatorio.Linhas[registro.indice]
+			conflitos, err := s.alunoService.CadastrarAlunoEInscrever(registro.aluno, registro.inscricao, nil, models.OrigemConflitoImportacao)
+			if err != nil {
+				resultado.Situacao = LinhaImportacaoErro
+				resultado.Erros = append(resultado.Erros, err.Error())
+				continue
+			}
+			resultado.Situacao = LinhaImportacaoImportada
+			resultado.InscricaoID = registro.inscricao.ID
+			resultado.Conflitos = CamposEmConflito(conflitos)
+		}
+	}
+
+	for _, linha := range relatorio.Linhas {
+		relatorio.TotalLinhas++
+		switch linha.Situacao {
+		case LinhaImportacaoValida:
+			relatorio.Validas++
+		case LinhaImportacaoImportada:
+			relatorio.Validas++
+			relatorio.Importadas++
+		default:
+			relatorio.ComErro++
+		}
+	}
+	return relatorio, nil
+}
+
+// reservarVaga conta as linhas válidas de cada curso para não aprovar mais alunos do que as vagas restantes
+func (s *importacaoServiceImpl) reservarVaga(cursos map[uint]*models.Curso, reservas map[uint]int32, cursoID uint) bool {
+	curso, ok := cursos[cursoID]
+	if !ok {
+		var err error
+		curso, err = s.cursoRepo.FindByID(cursoID)
+		if err != nil {
+			return false
+		}
+		cursos[cursoID] = curso
+	}
+	if curso.VagasPreenchidas+reservas[cursoID] >= curso.VagasTotais {
+		return false
+	}
+	reservas[cursoID]++
+	return true
+}
+
+// mapearColunas resolve o índice de cada campo no cabeçalho e devolve o mapeamento efetivo
+func mapearColunas(cabecalho []string, opcoes OpcoesImportacao) (map[string]int, map[string]string, error) {
+	indices := map[string]int{}
+	for i, nome := range cabecalho {
+		indices[normalizarCabecalho(nome)] = i
+	}
+
+	for campo := range opcoes.Mapeamento {
+		if !campoImportacaoValido(campo) {
+			return nil, nil, erros.ErrDadosInvalidos.ComMensagem("Campo de mapeamento desconhecido: %s", campo)
+		}
+	}
+
+	colunas := map[string]int{}
+	mapeamento := map[string]string{}
+	for _, campo := range camposImportacao {
+		coluna, informado := opcoes.Mapeamento[campo]
+		if !informado {
+			coluna = campo
+		}
+		indice, ok := indices[normalizarCabecalho(coluna)]
+		if !ok {
+			if informado {
+				return nil, nil, erros.ErrDadosInvalidos.ComMensagem("Coluna %q não encontrada na planilha", coluna)
+			}
+			continue
+		}
+		colunas[campo] = indice
+		mapeamento[campo] = cabecalho[indice]
+	}
+
+	obrigatorios := []string{CampoImportacaoNome, CampoImportacaoCPF, CampoImportacaoEmail, CampoImportacaoDataNascto}
+	if opcoes.CursoID == 0 {
+		obrigatorios = append(obrigatorios, CampoImportacaoCurso)
+	}
+	var faltantes []string
+	for _, campo := range obrigatorios {
+		if _, ok := colunas[campo]; !ok {
+			faltantes = append(faltantes, campo)
+		}
+	}
+	if len(faltantes) > 0 {
+		return nil, nil, erros.ErrDadosInvalidos.ComMensagem("Colunas obrigatórias ausentes: %s", strings.Join(faltantes, ", "))
+	}
+	return colunas, mapeamento, nil
+}
+
+func campoImportacaoValido(campo string) bool {
+	for _, valido := range camposImportacao {
+		if valido == campo {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizarCabecalho(nome string) string {
+	return strings.ToLower(strings.TrimSpace(nome))
+}
+
+func linhaVazia(valores []string) bool {
+	for _, valor := range valores {
+		if strings.TrimSpace(valor) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// dataDaPlanilha aceita DD/MM/AAAA ou o número de série de datas do Excel
+func dataDaPlanilha(valor string) (time.Time, error) {
+	if data, err := time.Parse("02/01/2006", valor); err == nil {
+		return data, nil
+	}
+	serie, err := strconv.ParseFloat(valor, 64)
+	if err != nil || serie < 1 || serie > 100000 {
+		return time.Time{}, erros.ErrDadosInvalidos.ComMensagem("Data inválida")
+	}
+	// O Excel conta dias a partir de 30/12/1899 (considerando o inexistente 29/02/1900)
+	return time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC).AddDate(0, 0, int(serie)), nil
+}
+
+func somenteDigitos(texto string) string {
+	var b strings.Builder
+	for _, r := range texto {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// cpfValido confere os dois dígitos verificadores do CPF
+func cpfValido(cpf string) bool {
+	digitos := somenteDigitos(cpf)
+	if len(digitos) != 11 || strings.Count(digitos, digitos[:1]) == 11 {
+		return false
+	}
+	for _, tamanho := range []int{9, 10} {
+		soma := 0
+		for i := 0; i < tamanho; i++ {
+			soma += int(digitos[i]-'0') * (tamanho + 1 - i)
+		}
+		verificador := soma * 10 % 11
+		if verificador == 10 {
+			verificador = 0
+		}
+		if verificador != int(digitos[tamanho]-'0') {
+			return false
+		}
+	}
+	return true
+}
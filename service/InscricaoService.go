@@ -1,9 +1,9 @@
 package service
 
 import (
-	"errors"
 	"log"
 	"time"
+	"tvtec/erros"
 	"tvtec/models"
 	"tvtec/repository"
 )
@@ -12,8 +12,9 @@ import (
 type InscricaoService interface {
 	ListarInscricoesDetalhadas() ([]models.Inscricao, error)
 	ObterInscricaoPorID(id uint) (*models.Inscricao, error)
-	CriarInscricao(inscricao *models.Inscricao) error
+	CriarInscricao(inscricao *models.Inscricao, opcoes OpcoesInscricao) error
 	CancelarInscricao(id uint) error
+	ConcluirInscricao(id uint) (*models.Inscricao, error)
 	ListarInscricoesPorAluno(alunoID uint) ([]models.Inscricao, error)
 	ListarInscricoesPorCurso(cursoID uint) ([]models.Inscricao, error)
 	GerarRelatorio(dados []map[string]interface{}) error
@@ -23,13 +24,21 @@ type InscricaoService interface {
 type inscricaoServiceImpl struct {
 	inscricaoRepo repository.InscricaoRepository
 	cursoRepo     repository.CursoRepository // Novo campo para acesso ao curso
+	alunoRepo     repository.AlunoRepository
+	transacao     repository.Transacao
+	eventos       PublicadorEventos
+	limites       LimitesInscricao
 }
 
 // Modifique o construtor para receber também o cursoRepo.
-func NewInscricaoService(inscricaoRepo repository.InscricaoRepository, cursoRepo repository.CursoRepository) InscricaoService {
+func NewInscricaoService(inscricaoRepo repository.InscricaoRepository, cursoRepo repository.CursoRepository, alunoRepo repository.AlunoRepository, transacao repository.Transacao, eventos PublicadorEventos, limites LimitesInscricao) InscricaoService {
 	return &inscricaoServiceImpl{
 		inscricaoRepo: inscricaoRepo,
 		cursoRepo:     cursoRepo,
+		alunoRepo:     alunoRepo,
+		transacao:     transacao,
+		eventos:       eventos,
+		limites:       limites,
 	}
 }
 
@@ -37,7 +46,7 @@ func NewInscricaoService(inscricaoRepo repository.InscricaoRepository, cursoRepo
 func (s *inscricaoServiceImpl) ListarInscricoesDetalhadas() ([]models.Inscricao, error) {
 	inscricoes, err := s.inscricaoRepo.FindAllWithDetails()
 	if err != nil {
-		return nil, errors.New("falha ao recuperar inscrições detalhadas")
+		return nil, erros.ErrInterno.ComMensagem("Falha ao recuperar inscrições detalhadas").Envolver(err)
 	}
 
 	return inscricoes, nil
@@ -47,28 +56,44 @@ func (s *inscricaoServiceImpl) ListarInscricoesDetalhadas() ([]models.Inscricao,
 func (s *inscricaoServiceImpl) ObterInscricaoPorID(id uint) (*models.Inscricao, error) {
 	inscricao, err := s.inscricaoRepo.FindByIDWithDetails(id)
 	if err != nil {
-		return nil, errors.New("inscrição não encontrada")
+		return nil, erros.ErrInscricaoNaoEncontrada
 	}
 
 	return inscricao, nil
 }
 
 // CriarInscricao registra uma nova inscrição no sistema
-func (s *inscricaoServiceImpl) CriarInscricao(inscricao *models.Inscricao) error {
+func (s *inscricaoServiceImpl) CriarInscricao(inscricao *models.Inscricao, opcoes OpcoesInscricao) (err error) {
+	defer func() { contarInscricao(err) }()
+
 	if inscricao.AlunoID == 0 || inscricao.CursoID == 0 {
-		return errors.New("aluno e curso são obrigatórios para uma inscrição")
+		return erros.ErrDadosInvalidos.ComMensagem("Aluno e curso são obrigatórios para uma inscrição")
 	}
 
 	// Busca os detalhes do curso para validar a data
 	curso, err := s.cursoRepo.FindByID(inscricao.CursoID)
 	if err != nil {
-		return errors.New("curso não encontrado")
+		return erros.ErrCursoNaoEncontrado
+	}
+
+	// Verifica se o período de inscrições está aberto (por padrão, até o dia do curso)
+	if err := verificarPeriodoInscricoes(curso, time.Now()); err != nil {
+		return err
+	}
+
+	aluno, err := s.alunoRepo.FindByID(inscricao.AlunoID)
+	if err != nil {
+		return erros.ErrAlunoNaoEncontrado
 	}
 
-	// Verifica se a data do curso já passou
-	// Aqui consideramos que o curso.Data é do tipo CustomTime (embora o campo encapsule um time.Time)
-	if curso.Data.Before(time.Now()) {
-		return errors.New("não é possível se inscrever, o dia do curso já passou")
+	if err := verificarPreRequisitos(s.inscricaoRepo, curso, inscricao.AlunoID, opcoes); err != nil {
+		return err
+	}
+	if err := verificarLimites(s.inscricaoRepo, curso, inscricao.AlunoID, s.limites, opcoes); err != nil {
+		return err
+	}
+	if err := verificarElegibilidade(s.inscricaoRepo, curso, aluno, inscricao, opcoes); err != nil {
+		return err
 	}
 
 	// Definir valores padrão para campos opcionais se não forem informados
@@ -83,33 +108,77 @@ func (s *inscricaoServiceImpl) CriarInscricao(inscricao *models.Inscricao) error
 
 	// Validações específicas para os novos campos
 	if inscricao.EhPCD == "S" && inscricao.TipoPCD == "" {
-		return errors.New("quando marcado como PCD, o tipo de deficiência deve ser informado")
+		return erros.ErrDadosInvalidos.ComMensagem("Quando marcado como PCD, o tipo de deficiência deve ser informado")
 	}
 
 	if inscricao.LevaNotebook == "" {
 		inscricao.LevaNotebook = "N"
 	}
 
-	return s.inscricaoRepo.Save(inscricao)
+	return emTransacao(s.transacao, s.eventos, func(repos repository.Repositorios) error {
+		if err := repos.Inscricoes.Save(inscricao); err != nil {
+			return err
+		}
+		return registrarInscricaoCriada(s.eventos, repos, inscricao)
+	})
 }
 
 // CancelarInscricao remove uma inscrição e atualiza vagas do curso
 func (s *inscricaoServiceImpl) CancelarInscricao(id uint) error {
-	// Verificar se a inscrição existe
-	_, err := s.inscricaoRepo.FindByID(id)
+	// Deletar pelo ID, não pelo objeto, gravando o evento na mesma transação
+	return emTransacao(s.transacao, s.eventos, func(repos repository.Repositorios) error {
+		// Lida na transação, para ver a situação deixada por um cancelamento do curso nesse meio-tempo
+		inscricao, err := repos.Inscricoes.FindByID(id)
+		if err != nil {
+			return erros.ErrInscricaoNaoEncontrada
+		}
+		if err := repos.Inscricoes.Delete(id); err != nil {
+			return err
+		}
+		// A inscrição cancelada junto com o curso já gerou seu evento e o aviso ao aluno;
+		// removê-la apenas apaga o histórico
+		if inscricao.Status == models.StatusInscricaoCanceladaOrganizacao {
+			return nil
+		}
+		dados := dadosEventoInscricao(inscricao)
+		dados.Status = models.StatusInscricaoCancelada
+		return s.eventos.Registrar(repos.Outbox, models.EventoInscricaoCancelada, dados)
+	})
+}
+
+// ConcluirInscricao registra que o aluno concluiu o curso, liberando os cursos que o exigem como pré-requisito
+func (s *inscricaoServiceImpl) ConcluirInscricao(id uint) (*models.Inscricao, error) {
+	inscricao, err := s.inscricaoRepo.FindByID(id)
 	if err != nil {
-		return errors.New("inscrição não encontrada")
+		return nil, erros.ErrInscricaoNaoEncontrada
+	}
+
+	if inscricao.Status != models.StatusInscricaoAtiva {
+		return nil, erros.ErrSituacaoInvalida.ComMensagem("Não é possível concluir uma inscrição com situação %s", inscricao.Status)
 	}
 
-	// Deletar pelo ID, não pelo objeto
-	return s.inscricaoRepo.Delete(id)
+	curso, err := s.cursoRepo.FindByID(inscricao.CursoID)
+	if err != nil {
+		return nil, err
+	}
+	agora := time.Now()
+	if !curso.IniciadoEm(agora) {
+		return nil, erros.ErrRegraNegocio.ComMensagem("Não é possível concluir a inscrição antes do início do curso")
+	}
+
+	inscricao.Status = models.StatusInscricaoConcluida
+	inscricao.DataConclusao = &agora
+	if err := s.inscricaoRepo.Save(inscricao); err != nil {
+		return nil, err
+	}
+	return inscricao, nil
 }
 
 // ListarInscricoesPorAluno retorna todas as inscrições de um aluno específico
 func (s *inscricaoServiceImpl) ListarInscricoesPorAluno(alunoID uint) ([]models.Inscricao, error) {
 	inscricoes, err := s.inscricaoRepo.FindByAlunoWithDetails(alunoID)
 	if err != nil {
-		return nil, errors.New("falha ao recuperar inscrições do aluno")
+		return nil, erros.ErrInterno.ComMensagem("Falha ao recuperar inscrições do aluno").Envolver(err)
 	}
 
 	return inscricoes, nil
@@ -119,7 +188,7 @@ func (s *inscricaoServiceImpl) ListarInscricoesPorAluno(alunoID uint) ([]models.
 func (s *inscricaoServiceImpl) ListarInscricoesPorCurso(cursoID uint) ([]models.Inscricao, error) {
 	inscricoes, err := s.inscricaoRepo.FindByCursoWithDetails(cursoID)
 	if err != nil {
-		return nil, errors.New("falha ao recuperar inscrições do curso")
+		return nil, erros.ErrInterno.ComMensagem("Falha ao recuperar inscrições do curso").Envolver(err)
 	}
 
 	return inscricoes, nil
@@ -129,7 +198,7 @@ func (s *inscricaoServiceImpl) ListarInscricoesPorCurso(cursoID uint) ([]models.
 func (s *inscricaoServiceImpl) GerarRelatorio(dados []map[string]interface{}) error {
 	// Implementação simplificada - você pode expandir conforme necessário
 	if len(dados) == 0 {
-		return errors.New("nenhum dado fornecido para gerar relatório")
+		return erros.ErrDadosInvalidos.ComMensagem("Nenhum dado fornecido para gerar relatório")
 	}
 
 	// Aqui seria possível implementar um relatório mais elaborado, com estatísticas
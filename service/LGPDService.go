@@ -0,0 +1,532 @@
+package service
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+
+	"tvtec/erros"
+	"tvtec/i18n"
+	"tvtec/models"
+	"tvtec/repository"
+)
+
+// Proteções do autoatendimento: falhas de identificação bloqueiam o CPF e o IP por um tempo, e os
+// pedidos de exportação e eliminação só são atendidos com o código enviado ao email cadastrado
+const (
+	MaximoFalhasIdentificacao  = 5
+	JanelaFalhasIdentificacao  = 15 * time.Minute
+	ValidadeCodigoLGPD         = 15 * time.Minute
+	MaximoTentativasCodigoLGPD = 5
+)
+
+// PacoteDadosAluno reúne tudo o que é mantido sobre um aluno, para atender ao direito de acesso da LGPD
+type PacoteDadosAluno struct {
+	GeradoEm          time.Time                 `json:"geradoEm"`
+	Aluno             models.Aluno              `json:"aluno"`
+	Inscricoes        []models.Inscricao        `json:"inscricoes"`
+	Consentimentos    []models.Consentimento    `json:"consentimentos"`
+	ConflitosCadastro []models.ConflitoCadastro `json:"conflitosCadastro"`
+	Fusoes            []models.FusaoAlunos      `json:"fusoes"`
+	Solicitacoes      []models.SolicitacaoLGPD  `json:"solicitacoes"`
+}
+
+// Interface para o serviço de direitos do titular (LGPD)
+type LGPDService interface {
+	IdentificarTitular(cpf, email string, dataNascto time.Time, ip string) (*models.Aluno, error)
+	SolicitarConfirmacao(alunoID uint, tipo, formato, ip string) error
+	ExportarDados(alunoID uint, formato, solicitante, ip string) (*PacoteDadosAluno, error)
+	ConfirmarExportacao(alunoID uint, codigo string) (*PacoteDadosAluno, string, error)
+	GerarPDF(pacote *PacoteDadosAluno) ([]byte, error)
+	EliminarDados(alunoID uint, solicitante, ip string) error
+	ConfirmarEliminacao(alunoID uint, codigo string) error
+	ConfirmarConsentimento(alunoID uint, codigo string, consentimento *models.Consentimento) error
+	ListarSolicitacoes() ([]models.SolicitacaoLGPD, error)
+}
+
+type lgpdServiceImpl struct {
+	alunoRepo       repository.AlunoRepository
+	inscricaoRepo   repository.InscricaoRepository
+	solicitacaoRepo repository.SolicitacaoLGPDRepository
+	duplicidadeRepo repository.DuplicidadeRepository
+	consentimentos  ConsentimentoService
+	transacao       repository.Transacao
+	notificador     Notificador
+	segredo         []byte
+	falhas          *limiteTentativas
+}
+
+// Função construtora para o serviço LGPD. O segredo assina os códigos de confirmação enviados ao titular.
+func NewLGPDService(
+	alunoRepo repository.AlunoRepository,
+	inscricaoRepo repository.InscricaoRepository,
+	solicitacaoRepo repository.SolicitacaoLGPDRepository,
+	duplicidadeRepo repository.DuplicidadeRepository,
+	consentimentos ConsentimentoService,
+	transacao repository.Transacao,
+	notificador Notificador,
+	segredo string,
+) LGPDService {
+	return &lgpdServiceImpl{
+		alunoRepo:       alunoRepo,
+		inscricaoRepo:   inscricaoRepo,
+		solicitacaoRepo: solicitacaoRepo,
+		duplicidadeRepo: duplicidadeRepo,
+		consentimentos:  consentimentos,
+		transacao:       transacao,
+		notificador:     notificador,
+		segredo:         []byte(segredo),
+		falhas:          newLimiteTentativas(MaximoFalhasIdentificacao, JanelaFalhasIdentificacao),
+	}
+}
+
+// IdentificarTitular confirma a identidade do aluno no autoatendimento cruzando CPF, email e data de nascimento.
+// Depois de MaximoFalhasIdentificacao falhas na janela, o CPF e o IP ficam bloqueados até as falhas expirarem.
+func (s *lgpdServiceImpl) IdentificarTitular(cpf, email string, dataNascto time.Time, ip string) (*models.Aluno, error) {
+	chaves := []string{"cpf:" + cpf, "ip:" + ip}
+	if s.falhas.bloqueada(chaves...) {
+		return nil, erros.ErrMuitasTentativas
+	}
+
+	aluno, err := s.alunoRepo.FindByCPF(cpf)
+	if err != nil || aluno.Anonimizado ||
+		!strings.EqualFold(aluno.Email, email) || !mesmaData(aluno.DataNascto, dataNascto) {
+		s.falhas.registrar(chaves...)
+		return nil, erros.ErrIdentidadeNaoConfirmada
+	}
+
+	return aluno, nil
+}
+
+// SolicitarConfirmacao registra o pedido do titular e envia ao email cadastrado o código que o confirma.
+// Um novo pedido do mesmo tipo invalida o código anterior.
+func (s *lgpdServiceImpl) SolicitarConfirmacao(alunoID uint, tipo, formato, ip string) error {
+	aluno, err := s.alunoRepo.FindByID(alunoID)
+	if err != nil {
+		return erros.ErrAlunoNaoEncontrado
+	}
+
+	if anterior, err := s.solicitacaoRepo.FindAguardandoConfirmacao(alunoID, tipo); err == nil {
+		s.encerrarCodigo(anterior, "código substituído por um novo pedido")
+	}
+
+	codigo, err := gerarCodigoConfirmacao()
+	if err != nil {
+		return err
+	}
+	expiraEm := time.Now().Add(ValidadeCodigoLGPD)
+	solicitacao := &models.SolicitacaoLGPD{
+		AlunoID:           alunoID,
+		Tipo:              tipo,
+		Formato:           formato,
+		Solicitante:       "titular",
+		IP:                ip,
+		DataSolicitacao:   time.Now(),
+		CodigoConfirmacao: s.hashCodigo(alunoID, tipo, codigo),
+		CodigoExpiraEm:    &expiraEm,
+	}
+	if err := s.solicitacaoRepo.Save(solicitacao); err != nil {
+		return err
+	}
+
+	// O código sai no idioma escolhido pelo aluno na inscrição
+	idioma := i18n.Normalizar(aluno.Idioma)
+	var mensagem string
+	switch tipo {
+	case models.SolicitacaoLGPDEliminacao:
+		mensagem = i18n.Traduzir(idioma, "Olá, %s.\n\nRecebemos um pedido para eliminar os seus dados pessoais. Para confirmá-lo, informe o código %s.", aluno.Nome, codigo)
+	case models.SolicitacaoLGPDConsentimento:
+		mensagem = i18n.Traduzir(idioma, "Olá, %s.\n\nRecebemos um pedido para alterar os seus consentimentos de comunicação. Para confirmá-lo, informe o código %s.", aluno.Nome, codigo)
+	default:
+		mensagem = i18n.Traduzir(idioma, "Olá, %s.\n\nRecebemos um pedido para exportar os seus dados pessoais. Para confirmá-lo, informe o código %s.", aluno.Nome, codigo)
+	}
+	mensagem += i18n.Traduzir(idioma, "\nO código vale por %d minutos e só pode ser usado uma vez. Se você não fez este pedido, ignore esta mensagem.",
+		int(ValidadeCodigoLGPD/time.Minute))
+
+	assunto := i18n.Traduzir(idioma, "Confirmação do pedido sobre seus dados pessoais")
+	if err := s.notificador.Enviar(aluno.Email, assunto, mensagem); err != nil {
+		s.encerrarCodigo(solicitacao, err.Error())
+		return erros.ErrIndisponivel.ComMensagem("Não foi possível enviar o código de confirmação").Envolver(err)
+	}
+	return nil
+}
+
+// ExportarDados monta o pacote com o perfil, as inscrições, os consentimentos, os conflitos e fusões de
+// cadastro e o histórico de solicitações do aluno
+func (s *lgpdServiceImpl) ExportarDados(alunoID uint, formato, solicitante, ip string) (*PacoteDadosAluno, error) {
+	return s.exportar(s.registrarSolicitacao(alunoID, models.SolicitacaoLGPDExportacao, formato, solicitante, ip))
+}
+
+// ConfirmarExportacao atende o pedido de exportação do titular com o código recebido por email e
+// devolve o pacote no formato pedido
+func (s *lgpdServiceImpl) ConfirmarExportacao(alunoID uint, codigo string) (*PacoteDadosAluno, string, error) {
+	solicitacao, err := s.confirmarCodigo(alunoID, models.SolicitacaoLGPDExportacao, codigo)
+	if err != nil {
+		return nil, "", err
+	}
+	pacote, err := s.exportar(solicitacao)
+	return pacote, solicitacao.Formato, err
+}
+
+func (s *lgpdServiceImpl) exportar(solicitacao *models.SolicitacaoLGPD) (*PacoteDadosAluno, error) {
+	alunoID := solicitacao.AlunoID
+	aluno, err := s.alunoRepo.FindByID(alunoID)
+	if err != nil {
+		s.concluirSolicitacao(solicitacao, err)
+		return nil, erros.ErrAlunoNaoEncontrado
+	}
+
+	inscricoes, err := s.inscricaoRepo.FindByAlunoWithDetails(alunoID)
+	if err != nil {
+		s.concluirSolicitacao(solicitacao, err)
+		return nil, erros.ErrInterno.ComMensagem("Falha ao recuperar inscrições do aluno").Envolver(err)
+	}
+
+	// Evita repetir os dados do aluno dentro de cada inscrição
+	for i := range inscricoes {
+		inscricoes[i].Aluno = models.Aluno{}
+	}
+
+	consentimentos, err := s.consentimentos.HistoricoAluno(alunoID)
+	if err != nil {
+		s.concluirSolicitacao(solicitacao, err)
+		return nil, erros.ErrInterno.ComMensagem("Falha ao recuperar consentimentos do aluno").Envolver(err)
+	}
+
+	// Conflitos e fusões guardam dados informados pelo aluno e o cadastro duplicado removido
+	conflitos, err := s.duplicidadeRepo.FindConflitosByAluno(alunoID)
+	if err != nil {
+		s.concluirSolicitacao(solicitacao, err)
+		return nil, erros.ErrInterno.ComMensagem("Falha ao recuperar conflitos de cadastro do aluno").Envolver(err)
+	}
+	fusoes, err := s.duplicidadeRepo.FindFusoesByAluno(alunoID)
+	if err != nil {
+		s.concluirSolicitacao(solicitacao, err)
+		return nil, erros.ErrInterno.ComMensagem("Falha ao recuperar fusões de cadastro do aluno").Envolver(err)
+	}
+
+	s.concluirSolicitacao(solicitacao, nil)
+
+	solicitacoes, err := s.solicitacaoRepo.FindByAluno(alunoID)
+	if err != nil {
+		return nil, erros.ErrInterno.ComMensagem("Falha ao recuperar histórico de solicitações").Envolver(err)
+	}
+
+	return &PacoteDadosAluno{
+		GeradoEm:          time.Now(),
+		Aluno:             *aluno,
+		Inscricoes:        inscricoes,
+		Consentimentos:    consentimentos,
+		ConflitosCadastro: conflitos,
+		Fusoes:            fusoes,
+		Solicitacoes:      solicitacoes,
+	}, nil
+}
+
+// GerarPDF produz uma versão legível do pacote de dados para entrega ao titular
+func (s *lgpdServiceImpl) GerarPDF(pacote *PacoteDadosAluno) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.Cell(0, 10, tr("Dados pessoais mantidos pelo programa"))
+	pdf.Ln(8)
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.Cell(0, 6, tr("Gerado em "+pacote.GeradoEm.Format("02/01/2006 15:04")))
+	pdf.Ln(10)
+
+	linha := func(rotulo, valor string) {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(50, 6, tr(rotulo), "", 0, "", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.MultiCell(0, 6, tr(valor), "", "", false)
+	}
+	secao := func(titulo string) {
+		pdf.Ln(4)
+		pdf.SetFont("Helvetica", "B", 12)
+		pdf.Cell(0, 8, tr(titulo))
+		pdf.Ln(8)
+	}
+
+	aluno := pacote.Aluno
+	secao("Perfil")
+	linha("Nome", aluno.Nome)
+	linha("CPF", aluno.CPF)
+	linha("Email", aluno.Email)
+	linha("Sexo", aluno.Sexo)
+	linha("Telefone", aluno.Telefone)
+	linha("Data de nascimento", aluno.DataNascto.Format("02/01/2006"))
+
+	secao(fmt.Sprintf("Inscrições (%d)", len(pacote.Inscricoes)))
+	for _, inscricao := range pacote.Inscricoes {
+		linha("Curso", fmt.Sprintf("%s (#%d)", inscricao.Curso.Nome, inscricao.CursoID))
+		linha("Data da inscrição", inscricao.DataInscricao.Format("02/01/2006 15:04"))
+		linha("Escolaridade", inscricao.Escolaridade)
+		linha("Trabalhando", inscricao.Trabalhando)
+		linha("Bairro", inscricao.Bairro)
+		linha("Cuidador", inscricao.EhCuidador)
+		linha("PCD", strings.TrimSpace(inscricao.EhPCD+" "+inscricao.TipoPCD))
+		linha("Necessita elevador", inscricao.NecessitaElevador)
+		linha("Como soube", inscricao.ComoSoube)
+		linha("Autoriza WhatsApp", inscricao.AutorizaWhatsApp)
+		pdf.Ln(3)
+	}
+
+	secao("Consentimentos")
+	for _, consentimento := range pacote.Consentimentos {
+		situacao := "revogado"
+		if consentimento.Concedido {
+			situacao = "concedido"
+		}
+		linha(consentimento.DataRegistro.Format("02/01/2006 15:04"),
+			fmt.Sprintf("%s / %s: %s (termos v%s, origem %s)", consentimento.Canal, consentimento.Finalidade,
+				situacao, consentimento.VersaoTermos, consentimento.Origem))
+	}
+
+	secao("Conflitos de cadastro")
+	for _, conflito := range pacote.ConflitosCadastro {
+		linha(conflito.DataRegistro.Format("02/01/2006 15:04"),
+			fmt.Sprintf("%s: informado %q, cadastrado %q (%s)", conflito.Campo, conflito.ValorInformado,
+				conflito.ValorCadastrado, conflito.Situacao))
+	}
+
+	secao("Cadastros duplicados mesclados")
+	for _, fusao := range pacote.Fusoes {
+		linha(fusao.DataFusao.Format("02/01/2006 15:04"), fusao.DadosAlunoRemovido)
+	}
+
+	secao("Solicitações LGPD")
+	for _, solicitacao := range pacote.Solicitacoes {
+		linha(solicitacao.DataSolicitacao.Format("02/01/2006 15:04"),
+			fmt.Sprintf("%s por %s", solicitacao.Tipo, solicitacao.Solicitante))
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// EliminarDados anonimiza o aluno, preservando as inscrições para que as estatísticas continuem válidas
+func (s *lgpdServiceImpl) EliminarDados(alunoID uint, solicitante, ip string) error {
+	return s.eliminar(s.registrarSolicitacao(alunoID, models.SolicitacaoLGPDEliminacao, "", solicitante, ip))
+}
+
+// ConfirmarEliminacao atende o pedido de eliminação do titular com o código recebido por email
+func (s *lgpdServiceImpl) ConfirmarEliminacao(alunoID uint, codigo string) error {
+	solicitacao, err := s.confirmarCodigo(alunoID, models.SolicitacaoLGPDEliminacao, codigo)
+	if err != nil {
+		return err
+	}
+	return s.eliminar(solicitacao)
+}
+
+// ConfirmarConsentimento registra a concessão ou revogação pedida pelo titular, com o código recebido por email
+func (s *lgpdServiceImpl) ConfirmarConsentimento(alunoID uint, codigo string, consentimento *models.Consentimento) error {
+	solicitacao, err := s.confirmarCodigo(alunoID, models.SolicitacaoLGPDConsentimento, codigo)
+	if err != nil {
+		return err
+	}
+	consentimento.AlunoID = alunoID
+	err = s.consentimentos.RegistrarConsentimento(consentimento)
+	s.concluirSolicitacao(solicitacao, err)
+	return err
+}
+
+func (s *lgpdServiceImpl) eliminar(solicitacao *models.SolicitacaoLGPD) error {
+	aluno, err := s.alunoRepo.FindByID(solicitacao.AlunoID)
+	if err != nil {
+		s.concluirSolicitacao(solicitacao, err)
+		return erros.ErrAlunoNaoEncontrado
+	}
+
+	// O aluno, as respostas das inscrições, os conflitos e fusões de cadastro e a revogação dos
+	// consentimentos são gravados juntos. Um aluno já anonimizado só tem inscrições, conflitos e
+	// fusões revistos, o que completa eliminações antigas.
+	err = s.transacao.Executar(func(repos repository.Repositorios) error {
+		if err := repos.Inscricoes.AnonimizarPorAluno(aluno.ID); err != nil {
+			return err
+		}
+		if err := repos.Duplicidades.AnonimizarPorAluno(aluno.ID, solicitacao.Solicitante); err != nil {
+			return err
+		}
+		if aluno.Anonimizado {
+			return nil
+		}
+
+		agora := time.Now()
+		aluno.Nome = "Titular anonimizado"
+		aluno.CPF = fmt.Sprintf("anonimizado-%d", aluno.ID)
+		aluno.Email = fmt.Sprintf("anonimizado-%d@anonimizado.invalid", aluno.ID)
+		aluno.Telefone = ""
+		// Mantém apenas o ano de nascimento, suficiente para estatísticas por faixa etária
+		aluno.DataNascto = time.Date(aluno.DataNascto.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+		aluno.Anonimizado = true
+		aluno.AnonimizadoEm = &agora
+		if err := repos.Alunos.Update(aluno); err != nil {
+			return err
+		}
+
+		// Sem dados de contato não há mais base para comunicações; o histórico de consentimentos é mantido como prova
+		return revogarTodosNaTransacao(repos, aluno.ID, models.OrigemConsentimentoEliminacao, solicitacao.IP)
+	})
+	s.concluirSolicitacao(solicitacao, err)
+	return err
+}
+
+func (s *lgpdServiceImpl) ListarSolicitacoes() ([]models.SolicitacaoLGPD, error) {
+	return s.solicitacaoRepo.FindAll()
+}
+
+// registrarSolicitacao grava o pedido antes de processá-lo, para que toda solicitação fique registrada
+func (s *lgpdServiceImpl) registrarSolicitacao(alunoID uint, tipo, formato, solicitante, ip string) *models.SolicitacaoLGPD {
+	solicitacao := &models.SolicitacaoLGPD{
+		AlunoID:         alunoID,
+		Tipo:            tipo,
+		Formato:         formato,
+		Solicitante:     solicitante,
+		IP:              ip,
+		DataSolicitacao: time.Now(),
+	}
+	if err := s.solicitacaoRepo.Save(solicitacao); err != nil {
+		log.Printf("Erro ao registrar solicitação LGPD do aluno %d: %v", alunoID, err)
+	}
+	return solicitacao
+}
+
+// confirmarCodigo confere o código do pedido pendente do titular; o código vale uma única vez e é
+// descartado ao expirar ou depois de MaximoTentativasCodigoLGPD erros
+func (s *lgpdServiceImpl) confirmarCodigo(alunoID uint, tipo, codigo string) (*models.SolicitacaoLGPD, error) {
+	solicitacao, err := s.solicitacaoRepo.FindAguardandoConfirmacao(alunoID, tipo)
+	if err != nil {
+		return nil, err
+	}
+
+	agora := time.Now()
+	if solicitacao.CodigoExpiraEm == nil || agora.After(*solicitacao.CodigoExpiraEm) {
+		s.encerrarCodigo(solicitacao, "código expirado")
+		return nil, erros.ErrCodigoConfirmacao
+	}
+	if !hmac.Equal([]byte(s.hashCodigo(alunoID, tipo, codigo)), []byte(solicitacao.CodigoConfirmacao)) {
+		solicitacao.TentativasCodigo++
+		if solicitacao.TentativasCodigo >= MaximoTentativasCodigoLGPD {
+			s.encerrarCodigo(solicitacao, "tentativas de confirmação esgotadas")
+		} else if err := s.solicitacaoRepo.Save(solicitacao); err != nil {
+			return nil, err
+		}
+		return nil, erros.ErrCodigoConfirmacao
+	}
+
+	solicitacao.CodigoConfirmacao = ""
+	solicitacao.ConfirmadaEm = &agora
+	if err := s.solicitacaoRepo.Save(solicitacao); err != nil {
+		return nil, err
+	}
+	return solicitacao, nil
+}
+
+// encerrarCodigo descarta o código de um pedido que não será mais atendido, registrando o motivo
+func (s *lgpdServiceImpl) encerrarCodigo(solicitacao *models.SolicitacaoLGPD, motivo string) {
+	solicitacao.CodigoConfirmacao = ""
+	solicitacao.Erro = motivo
+	if err := s.solicitacaoRepo.Save(solicitacao); err != nil {
+		log.Printf("Erro ao atualizar solicitação LGPD %d: %v", solicitacao.ID, err)
+	}
+}
+
+func (s *lgpdServiceImpl) hashCodigo(alunoID uint, tipo, codigo string) string {
+	mac := hmac.New(sha256.New, s.segredo)
+	fmt.Fprintf(mac, "lgpd:%d:%s:%s", alunoID, tipo, codigo)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// gerarCodigoConfirmacao sorteia um código numérico de seis dígitos
+func gerarCodigoConfirmacao() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+func (s *lgpdServiceImpl) concluirSolicitacao(solicitacao *models.SolicitacaoLGPD, causa error) {
+	solicitacao.Concluida = causa == nil
+	if causa != nil {
+		solicitacao.Erro = causa.Error()
+	}
+	if err := s.solicitacaoRepo.Save(solicitacao); err != nil {
+		log.Printf("Erro ao atualizar solicitação LGPD %d: %v", solicitacao.ID, err)
+	}
+}
+
+func mesmaData(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// limiteTentativas conta as falhas recentes por chave e bloqueia as chaves que chegam ao máximo na janela
+type limiteTentativas struct {
+	mu     sync.Mutex
+	maximo int
+	janela time.Duration
+	falhas map[string][]time.Time
+}
+
+func newLimiteTentativas(maximo int, janela time.Duration) *limiteTentativas {
+	return &limiteTentativas{maximo: maximo, janela: janela, falhas: make(map[string][]time.Time)}
+}
+
+// bloqueada informa se alguma das chaves atingiu o máximo de falhas dentro da janela
+func (l *limiteTentativas) bloqueada(chaves ...string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	limite := time.Now().Add(-l.janela)
+	for _, chave := range chaves {
+		if l.recentes(chave, limite) >= l.maximo {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *limiteTentativas) registrar(chaves ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	agora := time.Now()
+	// Descarta as falhas vencidas de todas as chaves para o mapa não crescer sem limite
+	limite := agora.Add(-l.janela)
+	for chave := range l.falhas {
+		l.recentes(chave, limite)
+	}
+	for _, chave := range chaves {
+		l.falhas[chave] = append(l.falhas[chave], agora)
+	}
+}
+
+// recentes remove as falhas anteriores ao limite e devolve quantas restam; chamar com o mutex travado
+func (l *limiteTentativas) recentes(chave string, limite time.Time) int {
+	falhas := l.falhas[chave]
+	i := 0
+	for i < len(falhas) && falhas[i].Before(limite) {
+		i++
+	}
+	if i == len(falhas) {
+		delete(l.falhas, chave)
+		return 0
+	}
+	l.falhas[chave] = falhas[i:]
+	return len(falhas) - i
+}
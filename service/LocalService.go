@@ -0,0 +1,107 @@
+package service
+
+import (
+	"tvtec/erros"
+	"tvtec/models"
+	"tvtec/repository"
+)
+
+type LocalService interface {
+	ListarLocais() ([]models.Local, error)
+	ObterLocalPorID(id uint) (*models.Local, error)
+	CriarLocal(local *models.Local) error
+	AtualizarLocal(local *models.Local) error
+	RemoverLocal(id uint) error
+	CriarSala(sala *models.Sala) error
+	AtualizarSala(sala *models.Sala) error
+	RemoverSala(id uint) error
+}
+
+type localService struct {
+	localRepo repository.LocalRepository
+	cursoRepo repository.CursoRepository
+}
+
+func NewLocalService(localRepo repository.LocalRepository, cursoRepo repository.CursoRepository) LocalService {
+	return &localService{
+		localRepo: localRepo,
+		cursoRepo: cursoRepo,
+	}
+}
+
+func (s *localService) ListarLocais() ([]models.Local, error) {
+	return s.localRepo.FindAll()
+}
+
+func (s *localService) ObterLocalPorID(id uint) (*models.Local, error) {
+	return s.localRepo.FindByID(id)
+}
+
+func (s *localService) CriarLocal(local *models.Local) error {
+	if local.Nome == "" || local.Endereco == "" {
+		return erros.ErrDadosInvalidos.ComMensagem("Nome e endereço do local são obrigatórios")
+	}
+	local.ID = 0
+	return s.localRepo.Save(local)
+}
+
+func (s *localService) AtualizarLocal(local *models.Local) error {
+	if _, err := s.localRepo.FindByID(local.ID); err != nil {
+		return err
+	}
+	if local.Nome == "" || local.Endereco == "" {
+		return erros.ErrDadosInvalidos.ComMensagem("Nome e endereço do local são obrigatórios")
+	}
+	return s.localRepo.Save(local)
+}
+
+func (s *localService) RemoverLocal(id uint) error {
+	return s.localRepo.Delete(id)
+}
+
+func (s *localService) CriarSala(sala *models.Sala) error {
+	if err := s.validarSala(sala); err != nil {
+		return err
+	}
+	sala.ID = 0
+	return s.localRepo.SaveSala(sala)
+}
+
+func (s *localService) AtualizarSala(sala *models.Sala) error {
+	if _, err := s.localRepo.FindSalaByID(sala.ID); err != nil {
+		return err
+	}
+	if err := s.validarSala(sala); err != nil {
+		return err
+	}
+
+	// A nova capacidade precisa comportar as vagas dos cursos já vinculados à sala
+	cursos, err := s.cursoRepo.FindBySala(sala.ID)
+	if err != nil {
+		return err
+	}
+	for _, curso := range cursos {
+		if curso.VagasTotais > sala.Capacidade {
+			return erros.ErrRegistroEmUso.ComMensagem("O curso %q oferece %d vagas, acima da nova capacidade da sala", curso.Nome, curso.VagasTotais)
+		}
+	}
+
+	return s.localRepo.SaveSala(sala)
+}
+
+func (s *localService) RemoverSala(id uint) error {
+	return s.localRepo.DeleteSala(id)
+}
+
+func (s *localService) validarSala(sala *models.Sala) error {
+	if sala.Nome == "" {
+		return erros.ErrDadosInvalidos.ComMensagem("Nome da sala é obrigatório")
+	}
+	if sala.Capacidade <= 0 {
+		return erros.ErrDadosInvalidos.ComMensagem("A capacidade da sala deve ser maior que zero")
+	}
+	if _, err := s.localRepo.FindByID(sala.LocalID); err != nil {
+		return err
+	}
+	return nil
+}
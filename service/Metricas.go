@@ -0,0 +1,129 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"tvtec/erros"
+	"tvtec/models"
+	"tvtec/repository"
+)
+
+// Motivos de recusa de inscrição usados como rótulo das métricas
+const (
+	MotivoRecusaSemVagas       = "sem_vagas"
+	MotivoRecusaPeriodo        = "periodo"
+	MotivoRecusaDuplicada      = "duplicada"
+	MotivoRecusaPreRequisitos  = "pre_requisitos"
+	MotivoRecusaLimites        = "limites"
+	MotivoRecusaElegibilidade  = "elegibilidade"
+	MotivoRecusaNaoEncontrado  = "nao_encontrado"
+	MotivoRecusaDadosInvalidos = "dados_invalidos"
+	MotivoRecusaOutro          = "outro"
+)
+
+var (
+	inscricoesCriadas = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "tvtec_inscricoes_criadas_total",
+		Help: "Inscrições criadas desde o início do processo.",
+	})
+	inscricoesRecusadas = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "tvtec_inscricoes_recusadas_total",
+		Help: "Inscrições recusadas, por motivo.",
+	}, []string{"motivo"})
+)
+
+// contarInscricao registra o resultado de uma tentativa de inscrição nas métricas
+func contarInscricao(err error) {
+	if err == nil {
+		inscricoesCriadas.Inc()
+		return
+	}
+	inscricoesRecusadas.WithLabelValues(motivoRecusa(err)).Inc()
+}
+
+// motivoRecusa classifica o erro de uma inscrição recusada pelo código do erro de domínio
+func motivoRecusa(err error) string {
+	switch {
+	case errors.Is(err, erros.ErrLimiteInscricoes), errors.Is(err, erros.ErrConflitoHorario):
+		return MotivoRecusaLimites
+	case errors.Is(err, erros.ErrPreRequisitos):
+		return MotivoRecusaPreRequisitos
+	case errors.Is(err, erros.ErrAlunoInelegivel):
+		return MotivoRecusaElegibilidade
+	case errors.Is(err, erros.ErrSemVagas):
+		return MotivoRecusaSemVagas
+	case errors.Is(err, erros.ErrInscricoesFechadas):
+		return MotivoRecusaPeriodo
+	case errors.Is(err, erros.ErrInscricaoDuplicada):
+		return MotivoRecusaDuplicada
+	}
+
+	var dominio *erros.Erro
+	if errors.As(err, &dominio) {
+		switch dominio.Tipo {
+		case erros.TipoNaoEncontrado:
+			return MotivoRecusaNaoEncontrado
+		case erros.TipoInvalido:
+			return MotivoRecusaDadosInvalidos
+		}
+	}
+	return MotivoRecusaOutro
+}
+
+// coletorCursos calcula os indicadores por curso a cada coleta, direto do banco
+type coletorCursos struct {
+	cursoRepo   repository.CursoRepository
+	inscritos   *prometheus.Desc
+	restantes   *prometheus.Desc
+	falhaColeta *prometheus.Desc
+}
+
+// NewColetorCursos expõe inscritos e vagas restantes dos cursos que ainda recebem ou têm alunos ativos
+func NewColetorCursos(cursoRepo repository.CursoRepository) prometheus.Collector {
+	rotulos := []string{"curso_id", "curso"}
+	return &coletorCursos{
+		cursoRepo:   cursoRepo,
+		inscritos:   prometheus.NewDesc("tvtec_curso_inscritos", "Vagas preenchidas no curso.", rotulos, nil),
+		restantes:   prometheus.NewDesc("tvtec_curso_vagas_restantes", "Vagas ainda disponíveis no curso.", rotulos, nil),
+		falhaColeta: prometheus.NewDesc("tvtec_curso_coleta_falhou", "1 quando a última leitura dos cursos falhou.", nil, nil),
+	}
+}
+
+func (c *coletorCursos) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.inscritos
+	ch <- c.restantes
+	ch <- c.falhaColeta
+}
+
+func (c *coletorCursos) Collect(ch chan<- prometheus.Metric) {
+	cursos, err := c.cursoRepo.FindAll()
+	if err != nil {
+		log.Printf("Erro ao coletar métricas de cursos: %v", err)
+		ch <- prometheus.MustNewConstMetric(c.falhaColeta, prometheus.GaugeValue, 1)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.falhaColeta, prometheus.GaugeValue, 0)
+
+	for i := range cursos {
+		curso := &cursos[i]
+		// Rascunhos, concluídos e cancelados ficam de fora para limitar a quantidade de séries
+		switch curso.StatusAtual() {
+		case models.StatusCursoPublicado, models.StatusCursoInscricoesEncerradas, models.StatusCursoEmAndamento:
+		default:
+			continue
+		}
+
+		id := strconv.FormatUint(uint64(curso.ID), 10)
+		restantes := curso.VagasTotais - curso.VagasPreenchidas
+		if restantes < 0 {
+			restantes = 0
+		}
+		ch <- prometheus.MustNewConstMetric(c.inscritos, prometheus.GaugeValue, float64(curso.VagasPreenchidas), id, curso.Nome)
+		ch <- prometheus.MustNewConstMetric(c.restantes, prometheus.GaugeValue, float64(restantes), id, curso.Nome)
+	}
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"net/smtp"
+	"strings"
+)
+
+// Notificador envia mensagens para pessoas (equipe ou alunos).
+// Mensagens a alunos devem passar antes por ConsentimentoService.PodeEnviar.
+type Notificador interface {
+	Enviar(destinatario, assunto, mensagem string) error
+}
+
+// ConfigSMTP reúne os dados de acesso ao servidor de email
+type ConfigSMTP struct {
+	Host      string
+	Porta     string
+	Usuario   string
+	Senha     string
+	Remetente string
+}
+
+// NewNotificador retorna um notificador por email, ou um que apenas registra no log quando o SMTP não está configurado
+func NewNotificador(config ConfigSMTP) Notificador {
+	if config.Host == "" {
+		log.Println("SMTP não configurado. Notificações serão apenas registradas no log.")
+		return &notificadorLog{}
+	}
+	if config.Porta == "" {
+		config.Porta = "587"
+	}
+	return &notificadorSMTP{config: config}
+}
+
+type notificadorLog struct{}
+
+func (n *notificadorLog) Enviar(destinatario, assunto, mensagem string) error {
+	log.Printf("Notificação para %s: %s\n%s", destinatario, assunto, mensagem)
+	return nil
+}
+
+type notificadorSMTP struct {
+	config ConfigSMTP
+}
+
+func (n *notificadorSMTP) Enviar(destinatario, assunto, mensagem string) error {
+	var auth smtp.Auth
+	if n.config.Usuario != "" {
+		auth = smtp.PlainAuth("", n.config.Usuario, n.config.Senha, n.config.Host)
+	}
+
+	corpo := strings.Join([]string{
+		"From: " + n.config.Remetente,
+		"To: " + destinatario,
+		"Subject: " + assunto,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		mensagem,
+	}, "\r\n")
+
+	endereco := fmt.Sprintf("%s:%s", n.config.Host, n.config.Porta)
+	if err := smtp.SendMail(endereco, auth, n.config.Remetente, []string{destinatario}, []byte(corpo)); err != nil {
+		return fmt.Errorf("falha ao enviar email para %s: %w", destinatario, err)
+	}
+	return nil
+}
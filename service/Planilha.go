@@ -0,0 +1,229 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"tvtec/erros"
+)
+
+// LerPlanilha converte um arquivo CSV ou XLSX em linhas de texto; a primeira linha é o cabeçalho.
+// No XLSX apenas a primeira aba é lida.
+func LerPlanilha(nomeArquivo string, conteudo []byte) ([][]string, error) {
+	var linhas [][]string
+	var err error
+	switch strings.ToLower(path.Ext(nomeArquivo)) {
+	case ".csv", ".txt":
+		linhas, err = lerCSV(conteudo)
+	case ".xlsx":
+		linhas, err = lerXLSX(conteudo)
+	default:
+		return nil, erros.ErrDadosInvalidos.ComMensagem("Formato de arquivo não suportado. Envie CSV ou XLSX")
+	}
+	if err != nil {
+		return nil, erros.Classificar(err, erros.ErrDadosInvalidos.ComMensagem("Planilha inválida"))
+	}
+	return linhas, nil
+}
+
+func lerCSV(conteudo []byte) ([][]string, error) {
+	conteudo = bytes.TrimPrefix(conteudo, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(conteudo) {
+		conteudo = latin1ParaUTF8(conteudo)
+	}
+
+	// Planilhas exportadas pelo Excel em português costumam usar ponto e vírgula
+	cabecalho := conteudo
+	if fim := bytes.IndexByte(conteudo, '\n'); fim >= 0 {
+		cabecalho = conteudo[:fim]
+	}
+	leitor := csv.NewReader(bytes.NewReader(conteudo))
+	if bytes.Count(cabecalho, []byte(";")) > bytes.Count(cabecalho, []byte(",")) {
+		leitor.Comma = ';'
+	}
+	leitor.FieldsPerRecord = -1
+	leitor.TrimLeadingSpace = true
+
+	linhas, err := leitor.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler CSV: %w", err)
+	}
+	return linhas, nil
+}
+
+// latin1ParaUTF8 trata arquivos salvos em Windows-1252/ISO-8859-1, comuns em exportações antigas
+func latin1ParaUTF8(conteudo []byte) []byte {
+	runas := make([]rune, len(conteudo))
+	for i, b := range conteudo {
+		runas[i] = rune(b)
+	}
+	return []byte(string(runas))
+}
+
+// Estruturas mínimas do formato XLSX (Office Open XML)
+type xlsxWorkbook struct {
+	Sheets []struct {
+		ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelacoes struct {
+	Relacoes []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxTextos struct {
+	Itens []xlsxTextoRico `xml:"si"`
+}
+
+type xlsxTextoRico struct {
+	Texto   string `xml:"t"`
+	Trechos []struct {
+		Texto string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxTextoRico) String() string {
+	if len(t.Trechos) == 0 {
+		return t.Texto
+	}
+	var b strings.Builder
+	for _, trecho := range t.Trechos {
+		b.WriteString(trecho.Texto)
+	}
+	return b.String()
+}
+
+type xlsxAba struct {
+	Linhas []struct {
+		Celulas []struct {
+			Referencia string        `xml:"r,attr"`
+			Tipo       string        `xml:"t,attr"`
+			Valor      string        `xml:"v"`
+			Inline     xlsxTextoRico `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func lerXLSX(conteudo []byte) ([][]string, error) {
+	arquivo, err := zip.NewReader(bytes.NewReader(conteudo), int64(len(conteudo)))
+	if err != nil {
+		return nil, erros.ErrDadosInvalidos.ComMensagem("Arquivo XLSX inválido")
+	}
+
+	var workbook xlsxWorkbook
+	if err := lerXMLDoZip(arquivo, "xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	}
+	if len(workbook.Sheets) == 0 {
+		return nil, erros.ErrDadosInvalidos.ComMensagem("Planilha sem abas")
+	}
+
+	// Localiza o arquivo da primeira aba pelas relações do workbook
+	var relacoes xlsxRelacoes
+	if err := lerXMLDoZip(arquivo, "xl/_rels/workbook.xml.rels", &relacoes); err != nil {
+		return nil, err
+	}
+	caminhoAba := ""
+	for _, relacao := range relacoes.Relacoes {
+		if relacao.ID == workbook.Sheets[0].ID {
+			caminhoAba = relacao.Target
+		}
+	}
+	if caminhoAba == "" {
+		return nil, erros.ErrDadosInvalidos.ComMensagem("Primeira aba da planilha não encontrada")
+	}
+	if strings.HasPrefix(caminhoAba, "/") {
+		caminhoAba = strings.TrimPrefix(caminhoAba, "/")
+	} else {
+		caminhoAba = path.Join("xl", caminhoAba)
+	}
+
+	// Textos compartilhados são opcionais (planilhas só com números ou textos inline)
+	var textos xlsxTextos
+	if err := lerXMLDoZip(arquivo, "xl/sharedStrings.xml", &textos); err != nil && !errors.Is(err, errArquivoAusente) {
+		return nil, err
+	}
+
+	var aba xlsxAba
+	if err := lerXMLDoZip(arquivo, caminhoAba, &aba); err != nil {
+		return nil, err
+	}
+
+	linhas := make([][]string, 0, len(aba.Linhas))
+	for _, linhaXML := range aba.Linhas {
+		var linha []string
+		for i, celula := range linhaXML.Celulas {
+			coluna := colunaDaReferencia(celula.Referencia)
+			if coluna < 0 {
+				coluna = i
+			}
+			for len(linha) <= coluna {
+				linha = append(linha, "")
+			}
+
+			switch celula.Tipo {
+			case "s":
+				indice, err := strconv.Atoi(celula.Valor)
+				if err != nil || indice < 0 || indice >= len(textos.Itens) {
+					return nil, erros.ErrDadosInvalidos.ComMensagem("Planilha com referência de texto inválida")
+				}
+				linha[coluna] = textos.Itens[indice].String()
+			case "inlineStr":
+				linha[coluna] = celula.Inline.String()
+			default:
+				linha[coluna] = celula.Valor
+			}
+		}
+		linhas = append(linhas, linha)
+	}
+	return linhas, nil
+}
+
+var errArquivoAusente = errors.New("arquivo ausente no XLSX")
+
+func lerXMLDoZip(arquivo *zip.Reader, nome string, destino interface{}) error {
+	for _, f := range arquivo.File {
+		if f.Name != nome {
+			continue
+		}
+		leitor, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("erro ao abrir %s: %w", nome, err)
+		}
+		defer leitor.Close()
+		if err := xml.NewDecoder(io.LimitReader(leitor, 50<<20)).Decode(destino); err != nil {
+			return fmt.Errorf("erro ao ler %s: %w", nome, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: %s", errArquivoAusente, nome)
+}
+
+// colunaDaReferencia converte "C7" em 2 (coluna zero-based); -1 quando a referência não existe
+func colunaDaReferencia(referencia string) int {
+	coluna := 0
+	letras := 0
+	for _, r := range referencia {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		coluna = coluna*26 + int(r-'A'+1)
+		letras++
+	}
+	if letras == 0 {
+		return -1
+	}
+	return coluna - 1
+}
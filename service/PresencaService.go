@@ -0,0 +1,149 @@
+package service
+
+import (
+	"time"
+
+	"tvtec/erros"
+	"tvtec/models"
+	"tvtec/repository"
+)
+
+// InscritoTurma é a visão da turma disponível ao professor, sem documentos ou contato do aluno
+type InscritoTurma struct {
+	InscricaoID       uint   `json:"inscricaoId"`
+	AlunoID           uint   `json:"alunoId"`
+	Nome              string `json:"nome"`
+	Status            string `json:"status"`
+	EhPCD             string `json:"ehPCD"`
+	TipoPCD           string `json:"tipoPCD"`
+	NecessitaElevador string `json:"necessitaElevador"`
+	LevaNotebook      string `json:"levaNotebook"`
+}
+
+// ItemPresenca é a marcação de um inscrito na chamada do dia
+type ItemPresenca struct {
+	InscricaoID uint `json:"inscricaoId" binding:"required"`
+	Presente    bool `json:"presente"`
+}
+
+type PresencaService interface {
+	CursosDoProfessor(professorID uint) ([]models.Curso, error)
+	Turma(professorID, cursoID uint) ([]InscritoTurma, error)
+	ListarPresencas(professorID, cursoID uint) ([]models.Presenca, error)
+	RegistrarPresencas(professorID, cursoID uint, data time.Time, itens []ItemPresenca, registradoPor string) ([]models.Presenca, error)
+}
+
+type presencaService struct {
+	presencaRepo  repository.PresencaRepository
+	cursoRepo     repository.CursoRepository
+	inscricaoRepo repository.InscricaoRepository
+}
+
+func NewPresencaService(
+	presencaRepo repository.PresencaRepository,
+	cursoRepo repository.CursoRepository,
+	inscricaoRepo repository.InscricaoRepository,
+) PresencaService {
+	return &presencaService{
+		presencaRepo:  presencaRepo,
+		cursoRepo:     cursoRepo,
+		inscricaoRepo: inscricaoRepo,
+	}
+}
+
+func (s *presencaService) CursosDoProfessor(professorID uint) ([]models.Curso, error) {
+	return s.cursoRepo.FindByProfessor(professorID)
+}
+
+func (s *presencaService) Turma(professorID, cursoID uint) ([]InscritoTurma, error) {
+	if _, err := s.cursoDoProfessor(professorID, cursoID); err != nil {
+		return nil, err
+	}
+
+	inscricoes, err := s.inscricaoRepo.FindByCursoWithDetails(cursoID)
+	if err != nil {
+		return nil, err
+	}
+
+	turma := make([]InscritoTurma, 0, len(inscricoes))
+	for _, inscricao := range inscricoes {
+		turma = append(turma, InscritoTurma{
+			InscricaoID:       inscricao.ID,
+			AlunoID:           inscricao.AlunoID,
+			Nome:              inscricao.Aluno.Nome,
+			Status:            inscricao.Status,
+			EhPCD:             inscricao.EhPCD,
+			TipoPCD:           inscricao.TipoPCD,
+			NecessitaElevador: inscricao.NecessitaElevador,
+			LevaNotebook:      inscricao.LevaNotebook,
+		})
+	}
+	return turma, nil
+}
+
+func (s *presencaService) ListarPresencas(professorID, cursoID uint) ([]models.Presenca, error) {
+	if _, err := s.cursoDoProfessor(professorID, cursoID); err != nil {
+		return nil, err
+	}
+	return s.presencaRepo.FindByCurso(cursoID)
+}
+
+// RegistrarPresencas grava a chamada de um dia; só inscrições ativas ou concluídas do curso são aceitas
+func (s *presencaService) RegistrarPresencas(professorID, cursoID uint, data time.Time, itens []ItemPresenca, registradoPor string) ([]models.Presenca, error) {
+	curso, err := s.cursoDoProfessor(professorID, cursoID)
+	if err != nil {
+		return nil, err
+	}
+	if status := curso.StatusAtual(); status == models.StatusCursoRascunho || status == models.StatusCursoCancelado {
+		return nil, erros.ErrSituacaoInvalida.ComMensagem("Não é possível registrar presença em um curso com situação %s", status)
+	}
+	if len(itens) == 0 {
+		return nil, erros.ErrDadosInvalidos.ComMensagem("Informe ao menos uma presença")
+	}
+	if data.After(time.Now()) {
+		return nil, erros.ErrRegraNegocio.ComMensagem("Não é possível registrar presença em data futura")
+	}
+
+	inscricoes, err := s.inscricaoRepo.FindByCurso(cursoID)
+	if err != nil {
+		return nil, err
+	}
+	validas := make(map[uint]bool, len(inscricoes))
+	for _, inscricao := range inscricoes {
+		if inscricao.Status == models.StatusInscricaoAtiva || inscricao.Status == models.StatusInscricaoConcluida {
+			validas[inscricao.ID] = true
+		}
+	}
+
+	agora := time.Now()
+	presencas := make([]models.Presenca, 0, len(itens))
+	for _, item := range itens {
+		if !validas[item.InscricaoID] {
+			return nil, erros.ErrDadosInvalidos.ComMensagem("A inscrição %d não pertence à turma deste curso", item.InscricaoID)
+		}
+		presencas = append(presencas, models.Presenca{
+			InscricaoID:   item.InscricaoID,
+			Data:          data,
+			Presente:      item.Presente,
+			RegistradoPor: registradoPor,
+			RegistradoEm:  agora,
+		})
+	}
+
+	if err := s.presencaRepo.SaveAll(presencas); err != nil {
+		return nil, err
+	}
+	return presencas, nil
+}
+
+// cursoDoProfessor garante que o professor só acesse os próprios cursos
+func (s *presencaService) cursoDoProfessor(professorID, cursoID uint) (*models.Curso, error) {
+	curso, err := s.cursoRepo.FindByID(cursoID)
+	if err != nil {
+		return nil, err
+	}
+	if curso.ProfessorID == nil || *curso.ProfessorID != professorID {
+		return nil, erros.ErrCursoNaoEncontrado.ComMensagem("Curso não encontrado entre os cursos do professor")
+	}
+	return curso, nil
+}
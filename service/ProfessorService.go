@@ -0,0 +1,162 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"tvtec/erros"
+	"tvtec/models"
+	"tvtec/repository"
+)
+
+type ProfessorService interface {
+	ListarProfessores() ([]models.Professor, error)
+	ObterProfessorPorID(id uint) (*models.Professor, error)
+	CriarProfessor(professor *models.Professor) error
+	AtualizarProfessor(professor *models.Professor) error
+	RemoverProfessor(id uint) error
+	DefinirSenha(id uint, senha string) error
+	Autenticar(email, senha string) (*models.Professor, error)
+	PerfilPublico(id uint) (*models.Professor, []models.Curso, error)
+}
+
+type professorService struct {
+	professorRepo repository.ProfessorRepository
+	cursoRepo     repository.CursoRepository
+}
+
+func NewProfessorService(professorRepo repository.ProfessorRepository, cursoRepo repository.CursoRepository) ProfessorService {
+	return &professorService{
+		professorRepo: professorRepo,
+		cursoRepo:     cursoRepo,
+	}
+}
+
+func (s *professorService) ListarProfessores() ([]models.Professor, error) {
+	return s.professorRepo.FindAll()
+}
+
+func (s *professorService) ObterProfessorPorID(id uint) (*models.Professor, error) {
+	return s.professorRepo.FindByID(id)
+}
+
+func (s *professorService) CriarProfessor(professor *models.Professor) error {
+	professor.ID = 0
+	professor.SenhaHash = ""
+	professor.Ativo = true
+	return s.salvar(professor)
+}
+
+func (s *professorService) AtualizarProfessor(professor *models.Professor) error {
+	existente, err := s.professorRepo.FindByID(professor.ID)
+	if err != nil {
+		return err
+	}
+
+	// A senha só muda por DefinirSenha
+	professor.SenhaHash = existente.SenhaHash
+	if err := s.salvar(professor); err != nil {
+		return err
+	}
+
+	// Mantém o nome exibido nos cursos igual ao do cadastro
+	if professor.Nome != existente.Nome {
+		cursos, err := s.cursoRepo.FindByProfessor(professor.ID)
+		if err != nil {
+			return err
+		}
+		for i := range cursos {
+			cursos[i].Professor = professor.Nome
+			if err := s.cursoRepo.Update(&cursos[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *professorService) RemoverProfessor(id uint) error {
+	return s.professorRepo.Delete(id)
+}
+
+func (s *professorService) DefinirSenha(id uint, senha string) error {
+	if len(senha) < 8 {
+		return erros.ErrDadosInvalidos.ComMensagem("A senha deve ter pelo menos 8 caracteres")
+	}
+	if len(senha) > 72 {
+		// O bcrypt recusa senhas maiores que 72 bytes
+		return erros.ErrDadosInvalidos.ComMensagem("A senha deve ter no máximo 72 bytes")
+	}
+
+	professor, err := s.professorRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(senha), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	professor.SenhaHash = string(hash)
+	return s.professorRepo.Save(professor)
+}
+
+// Autenticar confere email e senha de um professor ativo; a mensagem de erro não indica qual dos dois falhou
+func (s *professorService) Autenticar(email, senha string) (*models.Professor, error) {
+	credenciaisInvalidas := erros.ErrCredenciaisInvalidas
+
+	professor, err := s.professorRepo.FindByEmail(strings.TrimSpace(email))
+	if err != nil || !professor.Ativo || professor.SenhaHash == "" {
+		return nil, credenciaisInvalidas
+	}
+	if bcrypt.CompareHashAndPassword([]byte(professor.SenhaHash), []byte(senha)) != nil {
+		return nil, credenciaisInvalidas
+	}
+	return professor, nil
+}
+
+// PerfilPublico mostra o professor e seus cursos publicados que ainda não aconteceram
+func (s *professorService) PerfilPublico(id uint) (*models.Professor, []models.Curso, error) {
+	professor, err := s.professorRepo.FindByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !professor.Ativo {
+		return nil, nil, erros.ErrProfessorNaoEncontrado
+	}
+
+	cursos, err := s.cursoRepo.FindByProfessor(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hoje := models.DiaLocal(time.Now())
+	proximos := []models.Curso{}
+	for _, curso := range cursos {
+		if curso.StatusAtual() == models.StatusCursoRascunho || curso.StatusAtual() == models.StatusCursoCancelado {
+			continue
+		}
+		if curso.Data.Before(hoje) {
+			continue
+		}
+		curso.PreencherSituacaoInscricoes(time.Now())
+		proximos = append(proximos, curso)
+	}
+	return professor, proximos, nil
+}
+
+func (s *professorService) salvar(professor *models.Professor) error {
+	professor.Nome = strings.TrimSpace(professor.Nome)
+	professor.Email = strings.TrimSpace(professor.Email)
+	if professor.Nome == "" || professor.Email == "" {
+		return erros.ErrDadosInvalidos.ComMensagem("Nome e email do professor são obrigatórios")
+	}
+
+	if existente, _ := s.professorRepo.FindByEmail(professor.Email); existente != nil && existente.ID != professor.ID {
+		return erros.ErrCadastroDuplicado.ComMensagem("Já existe um professor com este email")
+	}
+
+	return s.professorRepo.Save(professor)
+}
@@ -0,0 +1,370 @@
+package service
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+
+	"tvtec/erros"
+	"tvtec/i18n"
+	"tvtec/models"
+	"tvtec/repository"
+)
+
+// OpcoesInscricao permite que um administrador ignore regras de inscrição específicas
+type OpcoesInscricao struct {
+	IgnorarPreRequisitos bool `json:"ignorarPreRequisitos"`
+	IgnorarElegibilidade bool `json:"ignorarElegibilidade"`
+	IgnorarLimites       bool `json:"ignorarLimites"`
+}
+
+// LimitesInscricao define quantas inscrições um aluno pode manter ao mesmo tempo
+type LimitesInscricao struct {
+	// Máximo de inscrições ativas em cursos futuros; 0 significa sem limite
+	MaximoAtivas int
+	// Recusa inscrições em cursos com horário sobreposto ao de outra inscrição ativa
+	BloquearConflitoHorario bool
+}
+
+// CursoConflitante identifica um curso em que o aluno já está inscrito no mesmo horário
+type CursoConflitante struct {
+	CursoID    uint   `json:"cursoId"`
+	Nome       string `json:"nome"`
+	Data       string `json:"data"`
+	HoraInicio string `json:"horaInicio,omitempty"`
+	HoraFim    string `json:"horaFim,omitempty"`
+}
+
+// PreRequisitoPendente identifica um curso que o aluno ainda precisa concluir
+type PreRequisitoPendente struct {
+	CursoID uint   `json:"cursoId"`
+	Nome    string `json:"nome"`
+}
+
+// verificarPeriodoInscricoes recusa inscrições antes da abertura ou depois do encerramento
+func verificarPeriodoInscricoes(curso *models.Curso, agora time.Time) error {
+	if status := curso.StatusAtual(); status != models.StatusCursoPublicado {
+		return erros.ErrInscricoesFechadas.ComMensagem("O curso não está recebendo inscrições (situação: %s)", status)
+	}
+	switch curso.SituacaoInscricoesEm(agora) {
+	case models.InscricoesAguardando:
+		return erros.ErrInscricoesFechadas.ComMensagem("As inscrições para este curso abrem em %s",
+			curso.InscricoesAbertura.In(models.FusoHorario).Format("02/01/2006 15:04"))
+	case models.InscricoesEncerradas:
+		return erros.ErrInscricoesFechadas.ComMensagem("As inscrições para este curso estão encerradas")
+	}
+	return nil
+}
+
+// verificarPreRequisitos confere se o aluno tem inscrição concluída em cada pré-requisito do curso
+func verificarPreRequisitos(inscricaoRepo repository.InscricaoRepository, curso *models.Curso, alunoID uint, opcoes OpcoesInscricao) error {
+	if len(curso.PreRequisitos) == 0 || opcoes.IgnorarPreRequisitos {
+		return nil
+	}
+
+	// Um aluno recém-cadastrado ainda não tem inscrições
+	concluidos := map[uint]bool{}
+	if alunoID != 0 {
+		inscricoes, err := inscricaoRepo.FindByAluno(alunoID)
+		if err != nil {
+			return err
+		}
+		for _, inscricao := range inscricoes {
+			if inscricao.Status == models.StatusInscricaoConcluida {
+				concluidos[inscricao.CursoID] = true
+			}
+		}
+	}
+
+	var faltantes []PreRequisitoPendente
+	for _, preRequisito := range curso.PreRequisitos {
+		if !concluidos[preRequisito.ID] {
+			faltantes = append(faltantes, PreRequisitoPendente{CursoID: preRequisito.ID, Nome: preRequisito.Nome})
+		}
+	}
+
+	if len(faltantes) > 0 {
+		nomes := make([]string, len(faltantes))
+		for i, faltante := range faltantes {
+			nomes[i] = faltante.Nome
+		}
+		return erros.ErrPreRequisitos.
+			ComMensagem("O aluno precisa concluir antes: %s", strings.Join(nomes, ", ")).
+			ComDetalhes(map[string]interface{}{"preRequisitosFaltantes": faltantes})
+	}
+	return nil
+}
+
+// verificarLimites aplica os limites global e por categoria e recusa horários sobrepostos
+func verificarLimites(inscricaoRepo repository.InscricaoRepository, curso *models.Curso, alunoID uint, limites LimitesInscricao, opcoes OpcoesInscricao) error {
+	// Um aluno recém-cadastrado ainda não tem inscrições
+	if opcoes.IgnorarLimites || alunoID == 0 {
+		return nil
+	}
+
+	if limites.MaximoAtivas > 0 {
+		total, err := inscricaoRepo.CountAtivasFuturasByAluno(alunoID)
+		if err != nil {
+			return err
+		}
+		if total >= int64(limites.MaximoAtivas) {
+			return erros.ErrLimiteInscricoes.ComMensagem("O aluno já possui %d inscrições ativas, o máximo permitido é %d", total, limites.MaximoAtivas)
+		}
+	}
+
+	if curso.CategoriaID != nil && curso.Categoria != nil && curso.Categoria.LimiteInscricoesAtivas > 0 {
+		total, err := inscricaoRepo.CountAtivasFuturasByAlunoECategoria(alunoID, *curso.CategoriaID)
+		if err != nil {
+			return err
+		}
+		if total >= int64(curso.Categoria.LimiteInscricoesAtivas) {
+			return erros.ErrLimiteInscricoes.ComMensagem("O aluno já possui %d inscrições ativas em cursos de %s, o máximo permitido é %d",
+				total, curso.Categoria.Nome, curso.Categoria.LimiteInscricoesAtivas)
+		}
+	}
+
+	if !limites.BloquearConflitoHorario {
+		return nil
+	}
+
+	inscricoes, err := inscricaoRepo.FindByAlunoWithDetails(alunoID)
+	if err != nil {
+		return err
+	}
+	var conflitos []CursoConflitante
+	for _, inscricao := range inscricoes {
+		if inscricao.Status != models.StatusInscricaoAtiva || inscricao.CursoID == curso.ID {
+			continue
+		}
+		if horariosConflitam(curso, &inscricao.Curso) {
+			conflitos = append(conflitos, CursoConflitante{
+				CursoID:    inscricao.Curso.ID,
+				Nome:       inscricao.Curso.Nome,
+				Data:       inscricao.Curso.Data.Format("02/01/2006"),
+				HoraInicio: inscricao.Curso.HoraInicio,
+				HoraFim:    inscricao.Curso.HoraFim,
+			})
+		}
+	}
+	if len(conflitos) > 0 {
+		return erros.ErrConflitoHorario.ComDetalhes(map[string]interface{}{"cursosConflitantes": conflitos})
+	}
+	return nil
+}
+
+// horariosConflitam indica se dois cursos acontecem no mesmo dia com horários sobrepostos.
+// Só há conflito quando os dois cursos têm horário definido; cursos antigos, sem horário,
+// não bloqueiam inscrições.
+func horariosConflitam(a, b *models.Curso) bool {
+	if !mesmaData(a.Data.Time, b.Data.Time) {
+		return false
+	}
+	inicioA, fimA, okA := intervaloCurso(a)
+	inicioB, fimB, okB := intervaloCurso(b)
+	if !okA || !okB {
+		return false
+	}
+	return inicioA.Before(fimB) && inicioB.Before(fimA)
+}
+
+func intervaloCurso(curso *models.Curso) (time.Time, time.Time, bool) {
+	inicio, errInicio := time.Parse("15:04", curso.HoraInicio)
+	fim, errFim := time.Parse("15:04", curso.HoraFim)
+	if errInicio != nil || errFim != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	return inicio, fim, true
+}
+
+// MotivoInelegibilidade explica qual regra de elegibilidade o aluno não atende
+type MotivoInelegibilidade struct {
+	Campo          string `json:"campo"`
+	Regra          string `json:"regra"`
+	ValorInformado string `json:"valorInformado"`
+
+	descricao i18n.Mensagem
+}
+
+// PerfilElegibilidade reúne os dados avaliados pelas regras de elegibilidade
+type PerfilElegibilidade struct {
+	DataNascto   time.Time
+	Bairro       string
+	Escolaridade string
+	Trabalhando  string
+}
+
+// montarPerfilElegibilidade usa as respostas da inscrição atual e, quando ausentes,
+// as respostas mais recentes de inscrições anteriores do aluno
+func montarPerfilElegibilidade(aluno *models.Aluno, inscricao *models.Inscricao, anteriores []models.Inscricao) PerfilElegibilidade {
+	perfil := PerfilElegibilidade{DataNascto: aluno.DataNascto}
+	if inscricao != nil {
+		perfil.Bairro = inscricao.Bairro
+		perfil.Escolaridade = inscricao.Escolaridade
+		perfil.Trabalhando = inscricao.Trabalhando
+	}
+
+	ordenadas := append([]models.Inscricao(nil), anteriores...)
+	sort.Slice(ordenadas, func(i, j int) bool {
+		return ordenadas[i].DataInscricao.After(ordenadas[j].DataInscricao)
+	})
+	for _, anterior := range ordenadas {
+		if perfil.Bairro == "" {
+			perfil.Bairro = anterior.Bairro
+		}
+		if perfil.Escolaridade == "" {
+			perfil.Escolaridade = anterior.Escolaridade
+		}
+		if perfil.Trabalhando == "" {
+			perfil.Trabalhando = anterior.Trabalhando
+		}
+	}
+	return perfil
+}
+
+// validarRegrasElegibilidade confere campos, operadores e valores antes de salvar as regras
+func validarRegrasElegibilidade(regras []models.RegraElegibilidade) error {
+	for i, regra := range regras {
+		if len(regra.Valores) == 0 {
+			return erros.ErrDadosInvalidos.ComMensagem("Regra %d: ao menos um valor deve ser informado", i+1)
+		}
+
+		switch regra.Campo {
+		case models.CampoElegibilidadeIdade:
+			if regra.Operador != models.OperadorElegibilidadeMinimo && regra.Operador != models.OperadorElegibilidadeMaximo {
+				return erros.ErrDadosInvalidos.ComMensagem("Regra %d: idade aceita apenas os operadores min e max", i+1)
+			}
+			if _, err := strconv.Atoi(regra.Valores[0]); err != nil {
+				return erros.ErrDadosInvalidos.ComMensagem("Regra %d: idade deve ser um número inteiro", i+1)
+			}
+		case models.CampoElegibilidadeBairro, models.CampoElegibilidadeEscolaridade, models.CampoElegibilidadeTrabalhando:
+			if regra.Operador != models.OperadorElegibilidadeEm && regra.Operador != models.OperadorElegibilidadeNaoEm {
+				return erros.ErrDadosInvalidos.ComMensagem("Regra %d: %s aceita apenas os operadores em e nao_em", i+1, regra.Campo)
+			}
+		default:
+			return erros.ErrDadosInvalidos.ComMensagem("Regra %d: campo inválido %q", i+1, regra.Campo)
+		}
+	}
+	return nil
+}
+
+// avaliarElegibilidade retorna os motivos pelos quais o perfil não atende às regras; vazio significa elegível
+func avaliarElegibilidade(regras []models.RegraElegibilidade, perfil PerfilElegibilidade, dataCurso time.Time) []MotivoInelegibilidade {
+	motivos := []MotivoInelegibilidade{}
+	for _, regra := range regras {
+		if motivo, ok := avaliarRegra(regra, perfil, dataCurso); !ok {
+			motivos = append(motivos, motivo)
+		}
+	}
+	return motivos
+}
+
+func avaliarRegra(regra models.RegraElegibilidade, perfil PerfilElegibilidade, dataCurso time.Time) (MotivoInelegibilidade, bool) {
+	descricao := descreverRegra(regra)
+	motivo := MotivoInelegibilidade{Campo: regra.Campo, Regra: descricao.String(), descricao: descricao}
+
+	if regra.Campo == models.CampoElegibilidadeIdade {
+		limite, _ := strconv.Atoi(regra.Valores[0])
+		idade := idadeEm(perfil.DataNascto, dataCurso)
+		motivo.ValorInformado = strconv.Itoa(idade)
+		if regra.Operador == models.OperadorElegibilidadeMinimo {
+			return motivo, idade >= limite
+		}
+		return motivo, idade <= limite
+	}
+
+	var valor string
+	switch regra.Campo {
+	case models.CampoElegibilidadeBairro:
+		valor = perfil.Bairro
+	case models.CampoElegibilidadeEscolaridade:
+		valor = perfil.Escolaridade
+	case models.CampoElegibilidadeTrabalhando:
+		valor = perfil.Trabalhando
+	}
+	motivo.ValorInformado = valor
+
+	presente := false
+	for _, permitido := range regra.Valores {
+		if normalizarValorRegra(regra.Campo, permitido) == normalizarValorRegra(regra.Campo, valor) {
+			presente = true
+			break
+		}
+	}
+	if regra.Operador == models.OperadorElegibilidadeEm {
+		return motivo, valor != "" && presente
+	}
+	return motivo, !presente
+}
+
+func descreverRegra(regra models.RegraElegibilidade) i18n.Mensagem {
+	switch regra.Operador {
+	case models.OperadorElegibilidadeMinimo:
+		return i18n.NovaMensagem("idade mínima de %s anos na data do curso", regra.Valores[0])
+	case models.OperadorElegibilidadeMaximo:
+		return i18n.NovaMensagem("idade máxima de %s anos na data do curso", regra.Valores[0])
+	case models.OperadorElegibilidadeEm:
+		return i18n.NovaMensagem("%s deve ser: %s", regra.Campo, strings.Join(regra.Valores, ", "))
+	default:
+		return i18n.NovaMensagem("%s não pode ser: %s", regra.Campo, strings.Join(regra.Valores, ", "))
+	}
+}
+
+// normalizarValorRegra compara respostas sem diferenciar maiúsculas, acentos ou as variações sim/S
+func normalizarValorRegra(campo, valor string) string {
+	if campo == models.CampoElegibilidadeTrabalhando {
+		if respostaAfirmativa(valor) {
+			return "sim"
+		}
+		return "nao"
+	}
+
+	semAcento, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), valor)
+	if err != nil {
+		semAcento = valor
+	}
+	return strings.ToLower(strings.TrimSpace(semAcento))
+}
+
+// idadeEm calcula a idade completa na data de referência
+func idadeEm(nascimento, referencia time.Time) int {
+	idade := referencia.Year() - nascimento.Year()
+	if referencia.Month() < nascimento.Month() ||
+		(referencia.Month() == nascimento.Month() && referencia.Day() < nascimento.Day()) {
+		idade--
+	}
+	return idade
+}
+
+// verificarElegibilidade avalia as regras do curso antes de a vaga ser ocupada
+func verificarElegibilidade(inscricaoRepo repository.InscricaoRepository, curso *models.Curso, aluno *models.Aluno, inscricao *models.Inscricao, opcoes OpcoesInscricao) error {
+	if len(curso.RegrasElegibilidade) == 0 || opcoes.IgnorarElegibilidade {
+		return nil
+	}
+
+	var anteriores []models.Inscricao
+	if aluno.ID != 0 {
+		var err error
+		if anteriores, err = inscricaoRepo.FindByAluno(aluno.ID); err != nil {
+			return err
+		}
+	}
+
+	perfil := montarPerfilElegibilidade(aluno, inscricao, anteriores)
+	if motivos := avaliarElegibilidade(curso.RegrasElegibilidade, perfil, curso.Data.Time); len(motivos) > 0 {
+		// As regras entram na mensagem sem formatar, para serem traduzidas junto com ela
+		regras := i18n.Lista{Separador: "; "}
+		for _, motivo := range motivos {
+			regras.Itens = append(regras.Itens, motivo.descricao)
+		}
+		return erros.ErrAlunoInelegivel.
+			ComMensagem("O aluno não atende aos critérios do curso: %s", regras).
+			ComDetalhes(map[string]interface{}{"motivosInelegibilidade": motivos})
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Tempo máximo para o banco responder à verificação de prontidão
+const timeoutVerificacaoBanco = 2 * time.Second
+
+// BancoDados é a parte da conexão usada pela verificação de prontidão (atendida por *sql.DB)
+type BancoDados interface {
+	PingContext(ctx context.Context) error
+}
+
+// TrabalhoMonitorado associa um laço de segundo plano ao nome mostrado na prontidão
+type TrabalhoMonitorado struct {
+	Nome     string
+	Trabalho TrabalhoSegundoPlano
+}
+
+// VerificacaoProntidao é o resultado de um componente na verificação de prontidão
+type VerificacaoProntidao struct {
+	Componente  string     `json:"componente"`
+	Pronto      bool       `json:"pronto"`
+	Detalhe     string     `json:"detalhe,omitempty"`
+	UltimoCiclo *time.Time `json:"ultimoCiclo,omitempty"`
+}
+
+// RelatorioProntidao indica se a API pode receber tráfego e por quê
+type RelatorioProntidao struct {
+	Pronto       bool                   `json:"pronto"`
+	Encerrando   bool                   `json:"encerrando"`
+	Verificacoes []VerificacaoProntidao `json:"verificacoes"`
+}
+
+// Interface para o serviço de saúde da API
+type SaudeService interface {
+	Prontidao(ctx context.Context) RelatorioProntidao
+	// IniciarEncerramento faz a prontidão falhar, para o balanceador parar de enviar requisições
+	IniciarEncerramento()
+}
+
+type saudeService struct {
+	banco      BancoDados
+	trabalhos  []TrabalhoMonitorado
+	encerrando atomic.Bool
+}
+
+// Função construtora para o serviço de saúde
+func NewSaudeService(banco BancoDados, trabalhos ...TrabalhoMonitorado) SaudeService {
+	return &saudeService{banco: banco, trabalhos: trabalhos}
+}
+
+// Prontidao confere o banco e os laços de segundo plano
+func (s *saudeService) Prontidao(ctx context.Context) RelatorioProntidao {
+	relatorio := RelatorioProntidao{Pronto: true, Encerrando: s.encerrando.Load()}
+	if relatorio.Encerrando {
+		relatorio.Pronto = false
+	}
+
+	ctx, cancelar := context.WithTimeout(ctx, timeoutVerificacaoBanco)
+	defer cancelar()
+	banco := VerificacaoProntidao{Componente: "banco", Pronto: true}
+	if err := s.banco.PingContext(ctx); err != nil {
+		banco.Pronto = false
+		banco.Detalhe = err.Error()
+		relatorio.Pronto = false
+	}
+	relatorio.Verificacoes = append(relatorio.Verificacoes, banco)
+
+	for _, monitorado := range s.trabalhos {
+		situacao := monitorado.Trabalho.Situacao()
+		verificacao := VerificacaoProntidao{
+			Componente:  monitorado.Nome,
+			Pronto:      situacao.Saudavel,
+			UltimoCiclo: situacao.UltimoCiclo,
+		}
+		if !situacao.Executando {
+			verificacao.Detalhe = "parado"
+		} else if !situacao.Saudavel {
+			verificacao.Detalhe = "sem ciclo recente"
+		}
+		if !verificacao.Pronto {
+			relatorio.Pronto = false
+		}
+		relatorio.Verificacoes = append(relatorio.Verificacoes, verificacao)
+	}
+	return relatorio
+}
+
+func (s *saudeService) IniciarEncerramento() {
+	s.encerrando.Store(true)
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SituacaoTrabalho resume o estado de um laço de segundo plano para a verificação de prontidão
+type SituacaoTrabalho struct {
+	Executando  bool       `json:"executando"`
+	UltimoCiclo *time.Time `json:"ultimoCiclo,omitempty"` // fim do último ciclo concluído
+	Saudavel    bool       `json:"saudavel"`
+}
+
+// TrabalhoSegundoPlano é um laço periódico acompanhado pela prontidão da API
+type TrabalhoSegundoPlano interface {
+	Situacao() SituacaoTrabalho
+}
+
+// lacoPeriodico executa um ciclo a cada intervalo, ou logo após um aviso, e guarda quando rodou
+type lacoPeriodico struct {
+	mu          sync.Mutex
+	intervalo   time.Duration
+	executando  bool
+	emCiclo     bool
+	ultimoCiclo time.Time
+}
+
+// executar roda até o contexto ser encerrado. Um ciclo em andamento termina antes da saída,
+// para que o encerramento da API não interrompa uma entrega pela metade.
+func (l *lacoPeriodico) executar(ctx context.Context, intervalo time.Duration, aviso <-chan struct{}, ciclo func()) {
+	l.mu.Lock()
+	l.intervalo = intervalo
+	l.executando = true
+	l.mu.Unlock()
+	defer func() {
+		l.mu.Lock()
+		l.executando = false
+		l.mu.Unlock()
+	}()
+
+	ticker := time.NewTicker(intervalo)
+	defer ticker.Stop()
+	for {
+		l.marcarCiclo(true)
+		ciclo()
+		l.marcarCiclo(false)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-aviso:
+		}
+	}
+}
+
+func (l *lacoPeriodico) marcarCiclo(inicio bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.emCiclo = inicio
+	if !inicio {
+		l.ultimoCiclo = time.Now()
+	}
+}
+
+// Situacao considera saudável o laço que está num ciclo ou concluiu um há menos de dois intervalos
+func (l *lacoPeriodico) Situacao() SituacaoTrabalho {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	situacao := SituacaoTrabalho{Executando: l.executando}
+	if !l.ultimoCiclo.IsZero() {
+		ultimo := l.ultimoCiclo
+		situacao.UltimoCiclo = &ultimo
+	}
+	situacao.Saudavel = l.executando && (l.emCiclo || (!l.ultimoCiclo.IsZero() && time.Since(l.ultimoCiclo) <= 2*l.intervalo))
+	return situacao
+}
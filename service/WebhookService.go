@@ -0,0 +1,346 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"tvtec/erros"
+	"tvtec/models"
+	"tvtec/repository"
+)
+
+// Cabeçalhos enviados em cada entrega. A assinatura é o HMAC-SHA256, com o segredo do webhook,
+// de "<timestamp>.<corpo>", no formato "sha256=<hex>".
+const (
+	CabecalhoWebhookEvento     = "X-TVTEC-Evento"
+	CabecalhoWebhookEntrega    = "X-TVTEC-Entrega"
+	CabecalhoWebhookTimestamp  = "X-TVTEC-Timestamp"
+	CabecalhoWebhookAssinatura = "X-TVTEC-Assinatura"
+)
+
+// Política de novas tentativas: o intervalo dobra a cada falha, começando em intervaloBaseWebhook
+const (
+	MaximoTentativasWebhook = 8
+	intervaloBaseWebhook    = 30 * time.Second
+	intervaloMaximoWebhook  = 6 * time.Hour
+	timeoutEntregaWebhook   = 10 * time.Second
+	loteEntregasWebhook     = 50
+)
+
+// PayloadWebhook é o corpo JSON enviado aos webhooks. O ID identifica o evento e se repete
+// se ele for entregue mais de uma vez, permitindo ao parceiro descartar duplicatas.
+type PayloadWebhook struct {
+	ID         uint        `json:"id,omitempty"`
+	Evento     string      `json:"evento"`
+	OcorridoEm time.Time   `json:"ocorridoEm"`
+	Dados      interface{} `json:"dados"`
+}
+
+// Interface para o serviço de webhooks
+type WebhookService interface {
+	RegistrarEntregas(evento *models.EventoOutbox) error
+	ListarWebhooks() ([]models.Webhook, error)
+	ObterWebhook(id uint) (*models.Webhook, error)
+	CriarWebhook(webhook *models.Webhook) error
+	AtualizarWebhook(webhook *models.Webhook) error
+	RemoverWebhook(id uint) error
+	ListarEntregas(webhookID uint) ([]models.EntregaWebhook, error)
+	EnviarTeste(webhookID uint) (*models.EntregaWebhook, error)
+	ProcessarPendentes()
+	Executar(ctx context.Context, intervalo time.Duration)
+	TrabalhoSegundoPlano
+}
+
+type webhookServiceImpl struct {
+	lacoPeriodico
+	webhookRepo repository.WebhookRepository
+	cliente     *http.Client
+	aviso       chan struct{} // acorda o processamento quando há entregas novas
+}
+
+// Função construtora para o serviço de webhooks
+func NewWebhookService(webhookRepo repository.WebhookRepository) WebhookService {
+	return &webhookServiceImpl{
+		webhookRepo: webhookRepo,
+		cliente:     &http.Client{Timeout: timeoutEntregaWebhook},
+		aviso:       make(chan struct{}, 1),
+	}
+}
+
+// RegistrarEntregas cria uma entrega pendente do evento da outbox para cada webhook ativo que o assina
+func (s *webhookServiceImpl) RegistrarEntregas(evento *models.EventoOutbox) error {
+	webhooks, err := s.webhookRepo.FindAtivos()
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	var entregas []models.EntregaWebhook
+	agora := time.Now()
+	for _, webhook := range webhooks {
+		if !assinaEvento(&webhook, evento.Evento) {
+			continue
+		}
+		if payload == nil {
+			payload, err = json.Marshal(PayloadWebhook{
+				ID:         evento.ID,
+				Evento:     evento.Evento,
+				OcorridoEm: evento.CriadoEm,
+				Dados:      json.RawMessage(evento.Payload),
+			})
+			if err != nil {
+				return fmt.Errorf("erro ao gerar payload do evento %s: %w", evento.Evento, err)
+			}
+		}
+
+		entregas = append(entregas, models.EntregaWebhook{
+			WebhookID:        webhook.ID,
+			EventoID:         evento.ID,
+			Evento:           evento.Evento,
+			Payload:          string(payload),
+			Situacao:         models.EntregaWebhookPendente,
+			ProximaTentativa: &agora,
+			CriadaEm:         agora,
+		})
+	}
+	// O despachante repete o evento se falhar antes de marcá-lo como processado; o repositório ignora
+	// as entregas já registradas
+	if err := s.webhookRepo.RegistrarEntregas(entregas); err != nil {
+		return fmt.Errorf("erro ao registrar entregas do evento %d: %w", evento.ID, err)
+	}
+
+	if payload != nil {
+		select {
+		case s.aviso <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+func (s *webhookServiceImpl) ListarWebhooks() ([]models.Webhook, error) {
+	return s.webhookRepo.FindAll()
+}
+
+func (s *webhookServiceImpl) ObterWebhook(id uint) (*models.Webhook, error) {
+	return s.webhookRepo.FindByID(id)
+}
+
+// CriarWebhook valida o endereço e os eventos e gera o segredo de assinatura
+func (s *webhookServiceImpl) CriarWebhook(webhook *models.Webhook) error {
+	if err := validarWebhook(webhook); err != nil {
+		return err
+	}
+
+	segredo := make([]byte, 32)
+	if _, err := rand.Read(segredo); err != nil {
+		return fmt.Errorf("erro ao gerar segredo do webhook: %w", err)
+	}
+	webhook.ID = 0
+	webhook.Segredo = hex.EncodeToString(segredo)
+	webhook.CriadoEm = time.Now()
+	return s.webhookRepo.Save(webhook)
+}
+
+// AtualizarWebhook altera endereço, descrição, eventos e situação, mantendo o segredo
+func (s *webhookServiceImpl) AtualizarWebhook(webhook *models.Webhook) error {
+	existente, err := s.webhookRepo.FindByID(webhook.ID)
+	if err != nil {
+		return err
+	}
+	if err := validarWebhook(webhook); err != nil {
+		return err
+	}
+
+	webhook.Segredo = existente.Segredo
+	webhook.CriadoEm = existente.CriadoEm
+	return s.webhookRepo.Update(webhook)
+}
+
+func (s *webhookServiceImpl) RemoverWebhook(id uint) error {
+	return s.webhookRepo.Delete(id)
+}
+
+func (s *webhookServiceImpl) ListarEntregas(webhookID uint) ([]models.EntregaWebhook, error) {
+	if _, err := s.webhookRepo.FindByID(webhookID); err != nil {
+		return nil, err
+	}
+	return s.webhookRepo.FindEntregasByWebhook(webhookID, 100)
+}
+
+// EnviarTeste faz uma única tentativa imediata com um evento de teste, registrada no histórico de entregas
+func (s *webhookServiceImpl) EnviarTeste(webhookID uint) (*models.EntregaWebhook, error) {
+	webhook, err := s.webhookRepo.FindByID(webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	agora := time.Now()
+	payload, err := json.Marshal(PayloadWebhook{
+		Evento:     models.EventoWebhookTeste,
+		OcorridoEm: agora,
+		Dados:      map[string]interface{}{"webhookId": webhook.ID, "mensagem": "Entrega de teste"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entrega := &models.EntregaWebhook{
+		WebhookID: webhook.ID,
+		Evento:    models.EventoWebhookTeste,
+		Payload:   string(payload),
+		Situacao:  models.EntregaWebhookPendente,
+		CriadaEm:  agora,
+	}
+	if err := s.webhookRepo.SaveEntrega(entrega); err != nil {
+		return nil, err
+	}
+
+	// A entrega de teste não é repetida: o resultado é mostrado na hora
+	s.tentarEntrega(webhook, entrega)
+	if entrega.Situacao == models.EntregaWebhookPendente {
+		entrega.Situacao = models.EntregaWebhookFalhou
+		entrega.ProximaTentativa = nil
+	}
+	if err := s.webhookRepo.SaveEntrega(entrega); err != nil {
+		return nil, err
+	}
+	return entrega, nil
+}
+
+// ProcessarPendentes tenta as entregas cuja próxima tentativa já venceu
+func (s *webhookServiceImpl) ProcessarPendentes() {
+	entregas, err := s.webhookRepo.FindEntregasPendentes(time.Now(), loteEntregasWebhook)
+	if err != nil {
+		log.Printf("Erro ao buscar entregas de webhook pendentes: %v", err)
+		return
+	}
+
+	webhooks := map[uint]*models.Webhook{}
+	for i := range entregas {
+		entrega := &entregas[i]
+		webhook, ok := webhooks[entrega.WebhookID]
+		if !ok {
+			webhook, _ = s.webhookRepo.FindByID(entrega.WebhookID)
+			webhooks[entrega.WebhookID] = webhook
+		}
+
+		if webhook == nil || !webhook.Ativo {
+			entrega.Situacao = models.EntregaWebhookFalhou
+			entrega.UltimoErro = "webhook removido ou desativado"
+			entrega.ProximaTentativa = nil
+		} else {
+			s.tentarEntrega(webhook, entrega)
+		}
+		if err := s.webhookRepo.SaveEntrega(entrega); err != nil {
+			log.Printf("Erro ao atualizar entrega %d do webhook %d: %v", entrega.ID, entrega.WebhookID, err)
+		}
+	}
+}
+
+// Executar processa as entregas periodicamente, e logo após novos registros, até o contexto ser encerrado
+func (s *webhookServiceImpl) Executar(ctx context.Context, intervalo time.Duration) {
+	s.executar(ctx, intervalo, s.aviso, s.ProcessarPendentes)
+}
+
+// tentarEntrega envia o payload assinado e atualiza a entrega com o resultado e a próxima tentativa
+func (s *webhookServiceImpl) tentarEntrega(webhook *models.Webhook, entrega *models.EntregaWebhook) {
+	inicio := time.Now()
+	entrega.Tentativas++
+	status, resposta, err := s.enviar(webhook, entrega, inicio)
+	entrega.DuracaoMs = time.Since(inicio).Milliseconds()
+	entrega.UltimoStatus = status
+	entrega.RespostaResumo = resposta
+
+	if err == nil {
+		entrega.Situacao = models.EntregaWebhookEntregue
+		entrega.UltimoErro = ""
+		entrega.EntregueEm = &inicio
+		entrega.ProximaTentativa = nil
+		return
+	}
+
+	entrega.UltimoErro = err.Error()
+	if entrega.Tentativas >= MaximoTentativasWebhook {
+		entrega.Situacao = models.EntregaWebhookFalhou
+		entrega.ProximaTentativa = nil
+		log.Printf("Entrega %d do webhook %d falhou após %d tentativas: %v", entrega.ID, webhook.ID, entrega.Tentativas, err)
+		return
+	}
+	proxima := inicio.Add(intervaloExponencial(intervaloBaseWebhook, intervaloMaximoWebhook, entrega.Tentativas))
+	entrega.ProximaTentativa = &proxima
+}
+
+func (s *webhookServiceImpl) enviar(webhook *models.Webhook, entrega *models.EntregaWebhook, momento time.Time) (int, string, error) {
+	corpo := []byte(entrega.Payload)
+	timestamp := strconv.FormatInt(momento.Unix(), 10)
+
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(corpo))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "TVTEC-Webhooks/1.0")
+	req.Header.Set(CabecalhoWebhookEvento, entrega.Evento)
+	req.Header.Set(CabecalhoWebhookEntrega, strconv.FormatUint(uint64(entrega.ID), 10))
+	req.Header.Set(CabecalhoWebhookTimestamp, timestamp)
+	req.Header.Set(CabecalhoWebhookAssinatura, AssinarWebhook(webhook.Segredo, timestamp, corpo))
+
+	resp, err := s.cliente.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	inicioResposta, _ := io.ReadAll(io.LimitReader(resp.Body, 500))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, string(inicioResposta), fmt.Errorf("resposta HTTP %d", resp.StatusCode)
+	}
+	return resp.StatusCode, string(inicioResposta), nil
+}
+
+// AssinarWebhook calcula o valor do cabeçalho de assinatura; os parceiros fazem o mesmo cálculo para conferir
+func AssinarWebhook(segredo, timestamp string, corpo []byte) string {
+	mac := hmac.New(sha256.New, []byte(segredo))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(corpo)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func assinaEvento(webhook *models.Webhook, evento string) bool {
+	for _, assinado := range webhook.Eventos {
+		if assinado == evento {
+			return true
+		}
+	}
+	return false
+}
+
+func validarWebhook(webhook *models.Webhook) error {
+	endereco, err := url.Parse(webhook.URL)
+	if err != nil || (endereco.Scheme != "http" && endereco.Scheme != "https") || endereco.Host == "" {
+		return erros.ErrDadosInvalidos.ComMensagem("URL do webhook inválida. Use um endereço http ou https completo")
+	}
+	if len(webhook.Eventos) == 0 {
+		return erros.ErrDadosInvalidos.ComMensagem("Informe ao menos um evento para o webhook")
+	}
+	for _, evento := range webhook.Eventos {
+		if !models.EventoWebhookValido(evento) {
+			return erros.ErrDadosInvalidos.ComMensagem("Evento desconhecido: %s", evento)
+		}
+	}
+	return nil
+}
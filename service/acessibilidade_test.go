@@ -0,0 +1,51 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"tvtec/models"
+)
+
+// Só as inscrições ativas e concluídas entram no planejamento de acessibilidade do curso
+func TestRelatorioAcessibilidadeIgnoraInscricoesCanceladas(t *testing.T) {
+	banco, curso := bancoComCurso(t, 5)
+	ativo := novoAluno(t, banco, "11111111111")
+	cancelado := novoAluno(t, banco, "22222222222")
+	inscricao := &models.Inscricao{AlunoID: ativo.ID, CursoID: curso.ID, Status: models.StatusInscricaoAtiva, EhPCD: "sim", TipoPCD: "visual"}
+	if err := banco.Inscricoes().Save(inscricao); err != nil {
+		t.Fatal(err)
+	}
+	if err := banco.Inscricoes().Save(&models.Inscricao{AlunoID: cancelado.ID, CursoID: curso.ID, Status: models.StatusInscricaoCanceladaOrganizacao, EhPCD: "sim", NecessitaElevador: "sim"}); err != nil {
+		t.Fatal(err)
+	}
+
+	servico := NewAcessibilidadeService(banco.Cursos(), banco.Inscricoes(), nil, "", 0)
+	relatorio, err := servico.GerarRelatorio(curso.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if relatorio.TotalInscritos != 1 || relatorio.PCDs != 1 || relatorio.NecessitamElevador != 0 {
+		t.Errorf("a inscrição cancelada entrou no relatório: total=%d pcds=%d elevador=%d",
+			relatorio.TotalInscritos, relatorio.PCDs, relatorio.NecessitamElevador)
+	}
+	if len(relatorio.Inscritos) != 1 || relatorio.Inscritos[0].InscricaoID != inscricao.ID {
+		t.Errorf("inscritos inesperados: %+v", relatorio.Inscritos)
+	}
+}
+
+// A falha no alerta à coordenação volta para a outbox, que tenta o envio de novo
+func TestAlertaDeAcessibilidadeDevolveAFalhaDeEnvio(t *testing.T) {
+	curso := &models.Curso{ID: 1, Nome: "Informática básica", Data: models.CustomTime{Time: time.Now().AddDate(0, 0, 2)}}
+	notificador := &notificadorMemoria{falha: errors.New("servidor SMTP fora do ar")}
+	servico := NewAcessibilidadeService(nil, nil, notificador, "coordenacao@example.com", 7)
+
+	if err := servico.InscricaoRecebida(curso, &models.Inscricao{EhPCD: "sim", TipoPCD: "visual"}); err == nil {
+		t.Error("a falha no envio do alerta foi descartada")
+	}
+	notificador.falha = nil
+	if err := servico.InscricaoRecebida(curso, &models.Inscricao{EhPCD: "sim", TipoPCD: "visual"}); err != nil || len(notificador.enviados) != 1 {
+		t.Errorf("esperava o alerta enviado: err=%v enviados=%d", err, len(notificador.enviados))
+	}
+}
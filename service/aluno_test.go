@@ -0,0 +1,98 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"tvtec/erros"
+	"tvtec/models"
+)
+
+// AtualizarAluno grava sobre o cadastro existente em vez de tentar inserir outro com o mesmo ID
+func TestAtualizarAlunoAlteraOCadastroExistente(t *testing.T) {
+	banco, _ := bancoComCurso(t, 10)
+	servico := NewAlunoService(banco.Alunos(), banco.Cursos(), banco.Inscricoes(), nil, banco.Transacao(), NewDespachanteEventos(banco.Outbox()), LimitesInscricao{})
+	aluno := novoAluno(t, banco, "11111111111")
+
+	alteracao := models.Aluno{ID: aluno.ID, CPF: aluno.CPF, DataNascto: aluno.DataNascto, Telefone: "(21) 99999-0000"}
+	if err := servico.AtualizarAluno(&alteracao); err != nil {
+		t.Fatal(err)
+	}
+
+	atual, err := banco.Alunos().FindByID(aluno.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if atual.Telefone != "(21) 99999-0000" || atual.Nome != aluno.Nome || atual.Email != aluno.Email {
+		t.Errorf("cadastro não atualizado como esperado: %+v", atual)
+	}
+}
+
+// A autorização do formulário é validada antes da inscrição e gravada na mesma transação que ela
+func TestInscricaoRegistraAutorizacaoJuntoComAInscricao(t *testing.T) {
+	banco, curso := bancoComCurso(t, 10)
+	if err := banco.Consentimentos().SaveTermo(&models.TermoConsentimento{Versao: "2026.1", Texto: "Termos de uso"}); err != nil {
+		t.Fatal(err)
+	}
+	consentimentos := NewConsentimentoService(banco.Consentimentos(), "segredo", "")
+	servico := NewAlunoService(banco.Alunos(), banco.Cursos(), banco.Inscricoes(), nil, banco.Transacao(), NewDespachanteEventos(banco.Outbox()), LimitesInscricao{})
+
+	if _, err := consentimentos.AutorizacaoInscricao("sim", "1999.9", "127.0.0.1"); !errors.Is(err, erros.ErrDadosInvalidos) {
+		t.Errorf("versão desconhecida deveria ser dado inválido, recebeu %v", err)
+	}
+
+	autorizacao, err := consentimentos.AutorizacaoInscricao("sim", "2026.1", "127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	aluno := &models.Aluno{Nome: "Maria", CPF: "11111111111", Email: "maria@example.com", DataNascto: time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)}
+	inscricao := &models.Inscricao{CursoID: curso.ID, DataInscricao: time.Now()}
+	if _, err := servico.CadastrarAlunoEInscrever(aluno, inscricao, autorizacao, models.OrigemConflitoInscricao); err != nil {
+		t.Fatal(err)
+	}
+
+	registrados, err := banco.Consentimentos().FindByAluno(aluno.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(registrados) != 1 || !registrados[0].Concedido || registrados[0].VersaoTermos != "2026.1" ||
+		registrados[0].Origem != models.OrigemConsentimentoInscricao {
+		t.Errorf("consentimento da inscrição não registrado como esperado: %+v", registrados)
+	}
+}
+
+// A inscrição duplicada descartada na fusão sai da outbox como cancelada, junto com a fusão
+func TestMesclarRegistraCancelamentoDasInscricoesDescartadas(t *testing.T) {
+	banco, curso := bancoComCurso(t, 5)
+	mantido := novoAluno(t, banco, "11111111111")
+	duplicado := novoAluno(t, banco, "22222222222")
+	inscricoes := inscricaoService(banco, NewDespachanteEventos(banco.Outbox()), LimitesInscricao{})
+	for _, aluno := range []*models.Aluno{mantido, duplicado} {
+		if err := inscricoes.CriarInscricao(&models.Inscricao{AlunoID: aluno.ID, CursoID: curso.ID}, OpcoesInscricao{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	servico := NewDuplicidadeService(banco.Alunos(), banco.Duplicidades(), banco.Transacao(), NewDespachanteEventos(banco.Outbox()))
+	fusao, err := servico.Mesclar(mantido.ID, duplicado.ID, "mesma pessoa", "admin", "127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fusao.InscricoesDescartadas != 1 {
+		t.Fatalf("esperava uma inscrição descartada: %+v", fusao)
+	}
+
+	evento := eventoDoTipo(t, banco.Eventos(), models.EventoInscricaoCancelada)
+	var dados DadosEventoInscricao
+	if err := json.Unmarshal([]byte(evento.Payload), &dados); err != nil {
+		t.Fatal(err)
+	}
+	if dados.AlunoID != duplicado.ID || dados.CursoID != curso.ID || dados.Status != models.StatusInscricaoCancelada {
+		t.Errorf("evento de cancelamento inesperado: %+v", dados)
+	}
+	if gravado, _ := banco.Cursos().FindByID(curso.ID); gravado.VagasPreenchidas != 1 {
+		t.Errorf("a vaga da inscrição descartada deveria ser liberada: %d", gravado.VagasPreenchidas)
+	}
+}
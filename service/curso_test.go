@@ -0,0 +1,196 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"tvtec/erros"
+	"tvtec/models"
+)
+
+// notificadorMemoria guarda os emails em vez de enviá-los; com falha, recusa todos os envios
+type notificadorMemoria struct {
+	enviados []emailEnviado
+	falha    error
+}
+
+type emailEnviado struct {
+	destinatario, assunto, mensagem string
+}
+
+func (n *notificadorMemoria) Enviar(destinatario, assunto, mensagem string) error {
+	if n.falha != nil {
+		return n.falha
+	}
+	n.enviados = append(n.enviados, emailEnviado{destinatario, assunto, mensagem})
+	return nil
+}
+
+// Sem encerramento informado, as inscrições fecham no início do curso no horário de Brasília
+func TestFimInscricoesPadraoNoInicioDoCurso(t *testing.T) {
+	curso := &models.Curso{Data: models.CustomTime{Time: time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)}}
+	if fim := curso.FimInscricoes(); !fim.Equal(time.Date(2026, 3, 10, 0, 0, 0, 0, models.FusoHorario)) {
+		t.Errorf("sem horário, esperava meia-noite de Brasília do dia do curso, recebeu %s", fim)
+	}
+
+	curso.HoraInicio, curso.HoraFim = "19:00", "21:00"
+	if fim := curso.FimInscricoes(); !fim.Equal(time.Date(2026, 3, 10, 19, 0, 0, 0, models.FusoHorario)) {
+		t.Errorf("esperava o horário de início do curso, recebeu %s", fim)
+	}
+}
+
+// O driver do PostgreSQL devolve a data no fuso local do servidor; o dia do curso continua o mesmo
+func TestInicioDoCursoLidoEmOutroFuso(t *testing.T) {
+	gravada := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	curso := &models.Curso{Data: models.CustomTime{Time: gravada.In(time.FixedZone("UTC-3", -3*60*60))}, HoraInicio: "19:00"}
+	if inicio := curso.Inicio(); !inicio.Equal(time.Date(2026, 3, 10, 19, 0, 0, 0, models.FusoHorario)) {
+		t.Errorf("esperava 10/03 às 19:00 de Brasília, recebeu %s", inicio)
+	}
+
+	// 22h de 09/03 em Brasília já é 10/03 em UTC, mas o dia local ainda é 09/03
+	if dia := models.DiaLocal(time.Date(2026, 3, 10, 1, 0, 0, 0, time.UTC)); !dia.Equal(time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("esperava o dia 09/03, recebeu %s", dia)
+	}
+}
+
+func TestEncerramentoDasInscricoesAteOInicioDoCurso(t *testing.T) {
+	curso := &models.Curso{
+		Data:       models.CustomTime{Time: time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)},
+		HoraInicio: "19:00",
+		HoraFim:    "21:00",
+	}
+
+	noInicio := time.Date(2026, 3, 10, 19, 0, 0, 0, models.FusoHorario)
+	curso.InscricoesEncerramento = &noInicio
+	if err := validarPeriodoInscricoes(curso); err != nil {
+		t.Errorf("encerrar no início do curso deveria ser aceito: %v", err)
+	}
+
+	depois := noInicio.Add(time.Minute)
+	curso.InscricoesEncerramento = &depois
+	if err := validarPeriodoInscricoes(curso); err == nil {
+		t.Error("aceitou encerramento das inscrições depois do início do curso")
+	}
+}
+
+// O aviso de cancelamento é mensagem de serviço: chega ao aluno inscrito normalmente, sem autorização
+// de avisos. Ele sai pela outbox, que tenta de novo quando o envio falha.
+func TestCancelarCursoAvisaOsInscritos(t *testing.T) {
+	banco, curso := bancoComCurso(t, 10)
+	despachante := NewDespachanteEventos(banco.Outbox())
+	aluno := novoAluno(t, banco, "11111111111")
+	if err := inscricaoService(banco, despachante, LimitesInscricao{}).CriarInscricao(&models.Inscricao{AlunoID: aluno.ID, CursoID: curso.ID}, OpcoesInscricao{}); err != nil {
+		t.Fatal(err)
+	}
+
+	notificador := &notificadorMemoria{falha: errors.New("servidor SMTP fora do ar")}
+	servico := NewCursoService(banco.Cursos(), banco.Inscricoes(), nil, nil, nil, notificador, banco.Transacao(), despachante)
+	despachante.Assinar(AssinanteCancelamentoCurso(servico, banco.Cursos(), banco.Alunos()))
+	resumo, err := servico.CancelarCurso(curso.ID, "Professor indisponível")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumo.InscricoesCanceladas != 1 {
+		t.Errorf("resumo inesperado: %+v", resumo)
+	}
+
+	despachante.ProcessarPendentes()
+	for _, evento := range banco.Eventos() {
+		if evento.Evento == models.EventoInscricaoCancelada && (evento.Situacao != models.EventoOutboxPendente || evento.UltimoErro == "") {
+			t.Errorf("a falha no envio deveria manter o evento pendente para nova tentativa: %+v", evento)
+		}
+	}
+
+	notificador.falha = nil
+	if err := AssinanteCancelamentoCurso(servico, banco.Cursos(), banco.Alunos()).Tratar(eventoDoTipo(t, banco.Eventos(), models.EventoInscricaoCancelada)); err != nil {
+		t.Fatal(err)
+	}
+	if len(notificador.enviados) != 1 || notificador.enviados[0].destinatario != aluno.Email ||
+		!strings.Contains(notificador.enviados[0].mensagem, "Professor indisponível") {
+		t.Errorf("o aluno inscrito deveria receber o aviso de cancelamento: %+v", notificador.enviados)
+	}
+}
+
+// Remover a inscrição de um curso cancelado não repete o aviso nem mexe na contagem de vagas
+func TestRemoverInscricaoDeCursoCancelado(t *testing.T) {
+	banco, curso := bancoComCurso(t, 10)
+	despachante := NewDespachanteEventos(banco.Outbox())
+	inscricoes := inscricaoService(banco, despachante, LimitesInscricao{})
+	for _, cpf := range []string{"11111111111", "22222222222"} {
+		if err := inscricoes.CriarInscricao(&models.Inscricao{AlunoID: novoAluno(t, banco, cpf).ID, CursoID: curso.ID}, OpcoesInscricao{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	servico := NewCursoService(banco.Cursos(), banco.Inscricoes(), nil, nil, nil, &notificadorMemoria{}, banco.Transacao(), despachante)
+	if _, err := servico.CancelarCurso(curso.ID, "Professor indisponível"); err != nil {
+		t.Fatal(err)
+	}
+	if total := contarEventos(banco.Eventos(), models.EventoInscricaoCancelada); total != 2 {
+		t.Fatalf("esperava um evento por inscrição cancelada, recebeu %d", total)
+	}
+
+	canceladas, _ := banco.Inscricoes().FindByCurso(curso.ID)
+	if err := inscricoes.CancelarInscricao(canceladas[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if total := contarEventos(banco.Eventos(), models.EventoInscricaoCancelada); total != 2 {
+		t.Errorf("a remoção da inscrição cancelada pela organização não deveria gerar outro evento, total %d", total)
+	}
+	if atual, _ := banco.Cursos().FindByID(curso.ID); atual.VagasPreenchidas != 2 {
+		t.Errorf("o curso cancelado deveria manter as vagas preenchidas, recebeu %d", atual.VagasPreenchidas)
+	}
+}
+
+// Curso, tags e pré-requisitos são gravados juntos: um pré-requisito inválido não deixa curso para trás
+func TestCriarCursoComPreRequisitos(t *testing.T) {
+	banco, basico := bancoComCurso(t, 10)
+	servico := NewCursoService(banco.Cursos(), banco.Inscricoes(), nil, nil, nil, &notificadorMemoria{}, banco.Transacao(), NewDespachanteEventos(banco.Outbox()))
+	novoCurso := func() *models.Curso {
+		return &models.Curso{Nome: "Excel avançado", Professor: "Ana", Data: models.CustomTime{Time: time.Now().AddDate(0, 2, 0)}, VagasTotais: 10}
+	}
+
+	invalido := novoCurso()
+	err := servico.CriarCurso(invalido, []string{"planilhas"}, []uint{basico.ID, basico.ID + 100})
+	if !errors.Is(err, erros.ErrCursoNaoEncontrado) {
+		t.Fatalf("esperava pré-requisito não encontrado, recebeu %v", err)
+	}
+	if cursos, _ := banco.Cursos().FindAll(); len(cursos) != 1 || len(banco.Eventos()) != 0 {
+		t.Errorf("o curso com pré-requisito inválido não deveria ser gravado: %d cursos, %d eventos", len(cursos), len(banco.Eventos()))
+	}
+
+	curso := novoCurso()
+	if err := servico.CriarCurso(curso, []string{"planilhas"}, []uint{basico.ID, basico.ID}); err != nil {
+		t.Fatalf("IDs repetidos deveriam ser aceitos uma única vez: %v", err)
+	}
+	gravado, err := banco.Cursos().FindByID(curso.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gravado.PreRequisitos) != 1 || gravado.PreRequisitos[0].ID != basico.ID || len(gravado.Tags) != 1 {
+		t.Errorf("esperava um pré-requisito e uma tag: %+v %+v", gravado.PreRequisitos, gravado.Tags)
+	}
+}
+
+func contarEventos(eventos []models.EventoOutbox, tipo string) int {
+	total := 0
+	for _, evento := range eventos {
+		if evento.Evento == tipo {
+			total++
+		}
+	}
+	return total
+}
+
+func eventoDoTipo(t *testing.T, eventos []models.EventoOutbox, tipo string) *models.EventoOutbox {
+	t.Helper()
+	for i := range eventos {
+		if eventos[i].Evento == tipo {
+			return &eventos[i]
+		}
+	}
+	t.Fatalf("nenhum evento %s na outbox", tipo)
+	return nil
+}
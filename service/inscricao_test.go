@@ -0,0 +1,210 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"tvtec/erros"
+	"tvtec/models"
+	"tvtec/repository"
+	"tvtec/repository/memoria"
+)
+
+// publicadorFalho simula uma falha ao gravar o evento na outbox
+type publicadorFalho struct{}
+
+func (publicadorFalho) Registrar(repository.OutboxRepository, string, interface{}) error {
+	return errors.New("outbox indisponível")
+}
+
+func (publicadorFalho) Avisar() {}
+
+func bancoComCurso(t *testing.T, vagas int32) (*memoria.Banco, *models.Curso) {
+	t.Helper()
+	banco := memoria.NewBanco()
+	curso := &models.Curso{
+		Nome:        "Informática básica",
+		Professor:   "João",
+		Data:        models.CustomTime{Time: time.Now().AddDate(0, 1, 0)},
+		VagasTotais: vagas,
+	}
+	if err := banco.Cursos().Save(curso); err != nil {
+		t.Fatal(err)
+	}
+	return banco, curso
+}
+
+func novoAluno(t *testing.T, banco *memoria.Banco, cpf string) *models.Aluno {
+	t.Helper()
+	aluno := &models.Aluno{Nome: "Aluno " + cpf, CPF: cpf, Email: cpf + "@example.com", DataNascto: time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)}
+	if err := banco.Alunos().Save(aluno); err != nil {
+		t.Fatal(err)
+	}
+	return aluno
+}
+
+func inscricaoService(banco *memoria.Banco, eventos PublicadorEventos, limites LimitesInscricao) InscricaoService {
+	return NewInscricaoService(banco.Inscricoes(), banco.Cursos(), banco.Alunos(), banco.Transacao(), eventos, limites)
+}
+
+func TestCriarInscricaoOcupaVagaERegistraEventos(t *testing.T) {
+	banco, curso := bancoComCurso(t, 1)
+	servico := inscricaoService(banco, NewDespachanteEventos(banco.Outbox()), LimitesInscricao{})
+
+	primeiro := novoAluno(t, banco, "11111111111")
+	if err := servico.CriarInscricao(&models.Inscricao{AlunoID: primeiro.ID, CursoID: curso.ID}, OpcoesInscricao{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var eventos []string
+	for _, evento := range banco.Eventos() {
+		eventos = append(eventos, evento.Evento)
+	}
+	if len(eventos) != 2 || eventos[0] != models.EventoInscricaoCriada || eventos[1] != models.EventoCursoLotado {
+		t.Errorf("eventos inesperados na outbox: %v", eventos)
+	}
+
+	segundo := novoAluno(t, banco, "22222222222")
+	err := servico.CriarInscricao(&models.Inscricao{AlunoID: segundo.ID, CursoID: curso.ID}, OpcoesInscricao{})
+	if err == nil || motivoRecusa(err) != MotivoRecusaSemVagas {
+		t.Fatalf("esperava recusa por falta de vagas, recebeu %v", err)
+	}
+	if atual, _ := banco.Cursos().FindByID(curso.ID); atual.VagasPreenchidas != 1 {
+		t.Errorf("vagas preenchidas = %d, esperava 1", atual.VagasPreenchidas)
+	}
+}
+
+func TestCancelarInscricaoPublicaSituacaoCancelada(t *testing.T) {
+	banco, curso := bancoComCurso(t, 10)
+	servico := inscricaoService(banco, NewDespachanteEventos(banco.Outbox()), LimitesInscricao{})
+	inscricao := &models.Inscricao{AlunoID: novoAluno(t, banco, "11111111111").ID, CursoID: curso.ID}
+	if err := servico.CriarInscricao(inscricao, OpcoesInscricao{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := servico.CancelarInscricao(inscricao.ID); err != nil {
+		t.Fatal(err)
+	}
+	var dados DadosEventoInscricao
+	if err := json.Unmarshal([]byte(eventoDoTipo(t, banco.Eventos(), models.EventoInscricaoCancelada).Payload), &dados); err != nil {
+		t.Fatal(err)
+	}
+	if dados.InscricaoID != inscricao.ID || dados.Status != models.StatusInscricaoCancelada {
+		t.Errorf("o evento deveria publicar a inscrição como cancelada: %+v", dados)
+	}
+	if atual, _ := banco.Cursos().FindByID(curso.ID); atual.VagasPreenchidas != 0 {
+		t.Errorf("a vaga deveria ter sido liberada, preenchidas = %d", atual.VagasPreenchidas)
+	}
+}
+
+func TestCriarInscricaoDesfeitaQuandoOEventoFalha(t *testing.T) {
+	banco, curso := bancoComCurso(t, 10)
+	servico := inscricaoService(banco, publicadorFalho{}, LimitesInscricao{})
+	aluno := novoAluno(t, banco, "11111111111")
+
+	if err := servico.CriarInscricao(&models.Inscricao{AlunoID: aluno.ID, CursoID: curso.ID}, OpcoesInscricao{}); err == nil {
+		t.Fatal("esperava erro ao gravar o evento")
+	}
+	if inscricoes, _ := banco.Inscricoes().FindAll(); len(inscricoes) != 0 {
+		t.Errorf("a inscrição deveria ter sido desfeita: %+v", inscricoes)
+	}
+	if atual, _ := banco.Cursos().FindByID(curso.ID); atual.VagasPreenchidas != 0 {
+		t.Errorf("a vaga deveria ter sido liberada, preenchidas = %d", atual.VagasPreenchidas)
+	}
+}
+
+func TestCriarInscricaoRespeitaLimiteDeAtivas(t *testing.T) {
+	banco, primeiro := bancoComCurso(t, 10)
+	segundo := &models.Curso{Nome: "Excel", Professor: "Ana", Data: models.CustomTime{Time: time.Now().AddDate(0, 2, 0)}, VagasTotais: 10}
+	if err := banco.Cursos().Save(segundo); err != nil {
+		t.Fatal(err)
+	}
+	servico := inscricaoService(banco, NewDespachanteEventos(banco.Outbox()), LimitesInscricao{MaximoAtivas: 1})
+	aluno := novoAluno(t, banco, "11111111111")
+
+	if err := servico.CriarInscricao(&models.Inscricao{AlunoID: aluno.ID, CursoID: primeiro.ID}, OpcoesInscricao{}); err != nil {
+		t.Fatal(err)
+	}
+	err := servico.CriarInscricao(&models.Inscricao{AlunoID: aluno.ID, CursoID: segundo.ID}, OpcoesInscricao{})
+	if !errors.Is(err, erros.ErrLimiteInscricoes) {
+		t.Fatalf("esperava ErrLimiteInscricoes, recebeu %v", err)
+	}
+
+	// A administração pode liberar a inscrição acima do limite
+	if err := servico.CriarInscricao(&models.Inscricao{AlunoID: aluno.ID, CursoID: segundo.ID}, OpcoesInscricao{IgnorarLimites: true}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConflitoDeHorarioSoComHorariosDefinidos(t *testing.T) {
+	banco, primeiro := bancoComCurso(t, 10)
+	segundo := &models.Curso{Nome: "Excel", Professor: "Ana", Data: primeiro.Data, VagasTotais: 10}
+	if err := banco.Cursos().Save(segundo); err != nil {
+		t.Fatal(err)
+	}
+	servico := inscricaoService(banco, NewDespachanteEventos(banco.Outbox()), LimitesInscricao{BloquearConflitoHorario: true})
+	aluno := novoAluno(t, banco, "11111111111")
+
+	// Cursos sem horário, criados antes da verificação, não se bloqueiam
+	if err := servico.CriarInscricao(&models.Inscricao{AlunoID: aluno.ID, CursoID: primeiro.ID}, OpcoesInscricao{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := servico.CriarInscricao(&models.Inscricao{AlunoID: aluno.ID, CursoID: segundo.ID}, OpcoesInscricao{}); err != nil {
+		t.Fatalf("cursos sem horário no mesmo dia não deveriam conflitar: %v", err)
+	}
+
+	manha := &models.Curso{Nome: "Word", Professor: "Ana", Data: primeiro.Data, HoraInicio: "09:00", HoraFim: "12:00", VagasTotais: 10}
+	sobreposto := &models.Curso{Nome: "PowerPoint", Professor: "Ana", Data: primeiro.Data, HoraInicio: "11:00", HoraFim: "13:00", VagasTotais: 10}
+	for _, curso := range []*models.Curso{manha, sobreposto} {
+		if err := banco.Cursos().Save(curso); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := servico.CriarInscricao(&models.Inscricao{AlunoID: aluno.ID, CursoID: manha.ID}, OpcoesInscricao{}); err != nil {
+		t.Fatal(err)
+	}
+	err := servico.CriarInscricao(&models.Inscricao{AlunoID: aluno.ID, CursoID: sobreposto.ID}, OpcoesInscricao{})
+	if !errors.Is(err, erros.ErrConflitoHorario) {
+		t.Fatalf("esperava ErrConflitoHorario, recebeu %v", err)
+	}
+}
+
+// Só inscrições ativas em cursos que já começaram podem ser concluídas
+func TestConcluirInscricaoExigeInscricaoAtivaECursoIniciado(t *testing.T) {
+	banco, curso := bancoComCurso(t, 10)
+	servico := inscricaoService(banco, NewDespachanteEventos(banco.Outbox()), LimitesInscricao{})
+	aluno := novoAluno(t, banco, "11111111111")
+	inscricao := &models.Inscricao{AlunoID: aluno.ID, CursoID: curso.ID}
+	if err := servico.CriarInscricao(inscricao, OpcoesInscricao{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := servico.ConcluirInscricao(inscricao.ID); !errors.Is(err, erros.ErrRegraNegocio) {
+		t.Fatalf("curso ainda não começou: esperava ErrRegraNegocio, recebeu %v", err)
+	}
+
+	curso.Status = models.StatusCursoEmAndamento
+	if err := banco.Cursos().Update(curso); err != nil {
+		t.Fatal(err)
+	}
+	concluida, err := servico.ConcluirInscricao(inscricao.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if concluida.Status != models.StatusInscricaoConcluida || concluida.DataConclusao == nil {
+		t.Errorf("inscrição não foi concluída: %+v", concluida)
+	}
+
+	if _, err := servico.ConcluirInscricao(inscricao.ID); !errors.Is(err, erros.ErrSituacaoInvalida) {
+		t.Errorf("inscrição já concluída: esperava ErrSituacaoInvalida, recebeu %v", err)
+	}
+	cancelada := &models.Inscricao{AlunoID: novoAluno(t, banco, "22222222222").ID, CursoID: curso.ID, Status: models.StatusInscricaoCanceladaOrganizacao}
+	if err := banco.Inscricoes().Save(cancelada); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := servico.ConcluirInscricao(cancelada.ID); !errors.Is(err, erros.ErrSituacaoInvalida) {
+		t.Errorf("inscrição cancelada: esperava ErrSituacaoInvalida, recebeu %v", err)
+	}
+}
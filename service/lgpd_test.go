@@ -0,0 +1,152 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"tvtec/erros"
+	"tvtec/models"
+	"tvtec/repository"
+)
+
+// Depois de várias falhas de identificação, o CPF e o IP ficam bloqueados mesmo com os dados corretos
+func TestIdentificarTitularBloqueiaDepoisDeFalhasSeguidas(t *testing.T) {
+	banco, _ := bancoComCurso(t, 10)
+	maria := novoAluno(t, banco, "11111111111")
+	pedro := novoAluno(t, banco, "22222222222")
+	servico := NewLGPDService(banco.Alunos(), banco.Inscricoes(), nil, nil, nil, banco.Transacao(), nil, "segredo")
+
+	for i := 0; i < MaximoFalhasIdentificacao; i++ {
+		if _, err := servico.IdentificarTitular(maria.CPF, "outro@example.com", maria.DataNascto, "10.0.0.1"); !errors.Is(err, erros.ErrIdentidadeNaoConfirmada) {
+			t.Fatalf("tentativa %d: esperava identidade não confirmada, recebeu %v", i+1, err)
+		}
+	}
+
+	if _, err := servico.IdentificarTitular(maria.CPF, maria.Email, maria.DataNascto, "10.0.0.2"); !errors.Is(err, erros.ErrMuitasTentativas) {
+		t.Errorf("o CPF deveria estar bloqueado em outro IP, recebeu %v", err)
+	}
+	if _, err := servico.IdentificarTitular(pedro.CPF, pedro.Email, pedro.DataNascto, "10.0.0.1"); !errors.Is(err, erros.ErrMuitasTentativas) {
+		t.Errorf("o IP deveria estar bloqueado para outro CPF, recebeu %v", err)
+	}
+	if aluno, err := servico.IdentificarTitular(pedro.CPF, pedro.Email, pedro.DataNascto, "10.0.0.2"); err != nil || aluno.ID != pedro.ID {
+		t.Errorf("outro titular em outro IP deveria ser identificado: %v", err)
+	}
+}
+
+// solicitacoesMemoria guarda o registro das solicitações LGPD dos testes
+type solicitacoesMemoria struct {
+	repository.SolicitacaoLGPDRepository
+	registradas []models.SolicitacaoLGPD
+}
+
+func (r *solicitacoesMemoria) Save(solicitacao *models.SolicitacaoLGPD) error {
+	if solicitacao.ID == 0 {
+		solicitacao.ID = uint(len(r.registradas) + 1)
+		r.registradas = append(r.registradas, *solicitacao)
+	} else {
+		r.registradas[solicitacao.ID-1] = *solicitacao
+	}
+	return nil
+}
+
+// A eliminação anonimiza o aluno, apaga as respostas das inscrições e revoga os consentimentos de uma vez:
+// se a revogação falha, nada é gravado
+func TestEliminarDadosGravaTudoNaMesmaTransacao(t *testing.T) {
+	banco, curso := bancoComCurso(t, 10)
+	aluno := novoAluno(t, banco, "11111111111")
+	inscricao := &models.Inscricao{AlunoID: aluno.ID, CursoID: curso.ID, Bairro: "Centro", EhPCD: "sim", TipoPCD: "visual", Escolaridade: "Superior"}
+	if err := banco.Inscricoes().Save(inscricao); err != nil {
+		t.Fatal(err)
+	}
+	autorizacao := &models.Consentimento{AlunoID: aluno.ID, Canal: models.CanalEmail, Finalidade: models.FinalidadeAvisosCursos, Concedido: true, VersaoTermos: "2026.1"}
+	if err := banco.Consentimentos().Save(autorizacao); err != nil {
+		t.Fatal(err)
+	}
+	solicitacoes := &solicitacoesMemoria{}
+	consentimentos := NewConsentimentoService(banco.Consentimentos(), "segredo", "")
+	servico := NewLGPDService(banco.Alunos(), banco.Inscricoes(), solicitacoes, banco.Duplicidades(), consentimentos, banco.Transacao(), nil, "segredo")
+
+	// Sem termos publicados a revogação não pode ser registrada
+	if err := servico.EliminarDados(aluno.ID, "admin", "127.0.0.1"); err == nil {
+		t.Fatal("a eliminação deveria falhar junto com a revogação dos consentimentos")
+	}
+	if atual, _ := banco.Alunos().FindByID(aluno.ID); atual.Anonimizado || atual.CPF != aluno.CPF {
+		t.Errorf("o aluno foi anonimizado apesar da falha: %+v", atual)
+	}
+	if atual, _ := banco.Inscricoes().FindByID(inscricao.ID); atual.Bairro != "Centro" {
+		t.Errorf("a inscrição foi alterada apesar da falha: %+v", atual)
+	}
+
+	if err := banco.Consentimentos().SaveTermo(&models.TermoConsentimento{Versao: "2026.1", Texto: "Termos de uso"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := servico.EliminarDados(aluno.ID, "admin", "127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	if atual, _ := banco.Alunos().FindByID(aluno.ID); !atual.Anonimizado || atual.CPF == aluno.CPF {
+		t.Errorf("aluno não anonimizado: %+v", atual)
+	}
+	if atual, _ := banco.Inscricoes().FindByID(inscricao.ID); atual.Bairro != "" || atual.EhPCD != "" || atual.TipoPCD != "" || atual.Escolaridade != "" {
+		t.Errorf("respostas pessoais mantidas na inscrição: %+v", atual)
+	}
+	if pode, _ := consentimentos.PodeEnviar(aluno.ID, models.CanalEmail, models.FinalidadeAvisosCursos); pode {
+		t.Error("os consentimentos deveriam estar revogados")
+	}
+	if ultima := solicitacoes.registradas[len(solicitacoes.registradas)-1]; !ultima.Concluida {
+		t.Errorf("solicitação não concluída: %+v", ultima)
+	}
+}
+
+func (r *solicitacoesMemoria) FindByAluno(alunoID uint) ([]models.SolicitacaoLGPD, error) {
+	var solicitacoes []models.SolicitacaoLGPD
+	for _, solicitacao := range r.registradas {
+		if solicitacao.AlunoID == alunoID {
+			solicitacoes = append(solicitacoes, solicitacao)
+		}
+	}
+	return solicitacoes, nil
+}
+
+// Os conflitos e as fusões de cadastro guardam dados pessoais: entram na exportação e são apagados na eliminação
+func TestLGPDCobreConflitosEFusoesDeCadastro(t *testing.T) {
+	banco, _ := bancoComCurso(t, 10)
+	aluno := novoAluno(t, banco, "11111111111")
+	duplicidades := banco.Duplicidades()
+	conflito := &models.ConflitoCadastro{AlunoID: aluno.ID, Campo: "telefone", ValorCadastrado: "(21) 98888-0000",
+		ValorInformado: "(21) 97777-0000", Origem: models.OrigemConflitoInscricao, Situacao: models.ConflitoPendente, DataRegistro: time.Now()}
+	if err := duplicidades.SaveConflito(conflito); err != nil {
+		t.Fatal(err)
+	}
+	duplicado := novoAluno(t, banco, "22222222222")
+	if _, err := duplicidades.Mesclar(aluno, duplicado.ID, &models.FusaoAlunos{AlunoMantidoID: aluno.ID, AlunoRemovidoID: duplicado.ID,
+		DadosAlunoRemovido: `{"cpf":"22222222222"}`, Responsavel: "admin", DataFusao: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	if err := banco.Consentimentos().SaveTermo(&models.TermoConsentimento{Versao: "2026.1", Texto: "Termos de uso"}); err != nil {
+		t.Fatal(err)
+	}
+	consentimentos := NewConsentimentoService(banco.Consentimentos(), "segredo", "")
+	servico := NewLGPDService(banco.Alunos(), banco.Inscricoes(), &solicitacoesMemoria{}, duplicidades, consentimentos, banco.Transacao(), nil, "segredo")
+
+	pacote, err := servico.ExportarDados(aluno.ID, "json", "admin", "127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pacote.ConflitosCadastro) != 1 || pacote.ConflitosCadastro[0].ValorInformado != "(21) 97777-0000" ||
+		len(pacote.Fusoes) != 1 || pacote.Fusoes[0].DadosAlunoRemovido != `{"cpf":"22222222222"}` {
+		t.Errorf("conflitos e fusões fora da exportação: %+v %+v", pacote.ConflitosCadastro, pacote.Fusoes)
+	}
+
+	if err := servico.EliminarDados(aluno.ID, "admin", "127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	conflitos, _ := duplicidades.FindConflitosByAluno(aluno.ID)
+	if len(conflitos) != 1 || conflitos[0].ValorCadastrado != "" || conflitos[0].ValorInformado != "" || conflitos[0].Situacao != models.ConflitoDescartado {
+		t.Errorf("conflito mantém dados pessoais ou segue pendente: %+v", conflitos)
+	}
+	fusoes, _ := duplicidades.FindFusoesByAluno(aluno.ID)
+	if len(fusoes) != 1 || fusoes[0].DadosAlunoRemovido != "{}" {
+		t.Errorf("fusão mantém os dados do cadastro removido: %+v", fusoes)
+	}
+}